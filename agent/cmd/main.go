@@ -28,13 +28,18 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/controllers"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/redaction"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/jobs"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -84,6 +89,16 @@ func doMain(ctx context.Context, agentConfig *configs.AgentConfig, restConfig *r
 		go utils.StartDefaultPprofServer()
 	}
 
+	shutdownTracing, err := tracing.InitTracerProvider(ctx, "multicluster-global-hub-agent", agentConfig.TracingConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.DefaultZapLogger().Warnw("failed to shutdown the tracing", "error", err)
+		}
+	}()
+
 	// init manager
 	mgr, err := createManager(restConfig, agentConfig)
 	if err != nil {
@@ -130,6 +145,7 @@ func parseFlags() *configs.AgentConfig {
 			// EnableDatabaseOffset affects only the manager, deciding if consumption starts from a database-stored offset
 			EnableDatabaseOffset: false,
 		},
+		TracingConfig: &tracing.TracingConfig{},
 	}
 
 	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
@@ -142,6 +158,28 @@ func parseFlags() *configs.AgentConfig {
 		"The goroutine number to propagate the bundles on managed cluster.")
 	pflag.IntVar(&agentConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar((*string)(&agentConfig.TransportConfig.CompressionType), "transport-compression",
+		string(compressor.NoOp), "The codec to compress the transport payloads: no-op, gzip, zstd or snappy.")
+	pflag.StringVar(&agentConfig.TransportConfig.SigningKeyFile, "transport-signing-key-file", "",
+		"The private key to sign the status events, which is issued for the hub by the global hub operator.")
+	pflag.StringVar(&agentConfig.TransportConfig.SigningCertFile, "transport-signing-cert-file", "",
+		"The certificate of the key to sign the status events.")
+	pflag.StringVar(&agentConfig.TransportConfig.OutboxDir, "transport-outbox-dir", "",
+		"The directory of the outbox which persists the status events until they're delivered to the transport, "+
+			"the events are sent to the transport directly if it's empty.")
+	pflag.IntVar(&agentConfig.TransportConfig.OutboxMaxBytes, "transport-outbox-max-bytes", producer.DefaultOutboxMaxBytes,
+		"The size limit of the pending events in the transport outbox.")
+	pflag.StringVar((*string)(&agentConfig.TransportConfig.KafkaClientType), "transport-kafka-client",
+		string(transport.ConfluentKafkaClient), "The client library of the kafka transport: confluent or sarama.")
+	pflag.DurationVar(&agentConfig.TransportConfig.ChunkCollectionTTL, "transport-chunk-ttl",
+		genericconsumer.DefaultChunkCollectionTTL,
+		"The max duration to wait for the next chunk of an incomplete spec event before it's evicted.")
+	pflag.IntVar(&agentConfig.TransportConfig.ChunkMemoryLimit, "transport-chunk-memory-limit",
+		genericconsumer.DefaultAssemblerMemoryLimit,
+		"The size limit of the chunks buffered by the consumer, the least recently updated events are evicted over it.")
+	pflag.StringVar(&agentConfig.TransportConfig.TransportType, "transport-type", string(transport.Kafka),
+		"The transport type to exchange the events with the manager: kafka or rest. The rest transport posts the "+
+			"status events to and long polls the spec events from the manager with the rest.yaml credential.")
 	pflag.BoolVar(&agentConfig.SpecEnforceHohRbac, "enforce-hoh-rbac", false,
 		"enable hoh RBAC or not, default false")
 	pflag.IntVar(&agentConfig.StatusDeltaCountSwitchFactor,
@@ -170,6 +208,15 @@ func parseFlags() *configs.AgentConfig {
 		"The interval between each StackRox polling")
 	pflag.StringVar(&agentConfig.EventMode, "event-send-mode", string(constants.EventSendModeBatch),
 		"Event sending mode: batch or single")
+	pflag.StringVar(&agentConfig.TracingConfig.OTLPEndpoint, "tracing-otlp-endpoint", "",
+		"The host:port of the OTLP gRPC receiver to export the trace spans, the tracing is disabled if neither the "+
+			"endpoint nor the tracing-file is specified.")
+	pflag.BoolVar(&agentConfig.TracingConfig.OTLPInsecure, "tracing-otlp-insecure", false,
+		"Connect the OTLP receiver without TLS.")
+	pflag.StringVar(&agentConfig.TracingConfig.FilePath, "tracing-file", "",
+		"The file to write the trace spans as JSON lines, it's used for testing.")
+	pflag.Float64Var(&agentConfig.TracingConfig.SampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of the sampled traces started by the agent, the spec events follow the sampling of the manager.")
 	pflag.Parse()
 
 	return agentConfig
@@ -236,12 +283,22 @@ func createManager(restConfig *rest.Config, agentConfig *configs.AgentConfig) (
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new manager: %w", err)
 	}
+	genericconsumer.RegisterMetrics()
+	producer.RegisterMetrics()
+	redaction.RegisterMetrics()
 	return mgr, nil
 }
 
 // if the transport consumer and producer is ready then the func will be invoked by the transport controller
 func transportCallback(mgr ctrl.Manager, agentConfig *configs.AgentConfig) controller.TransportCallback {
 	return func(transportClient transport.TransportClient) error {
+		// the events are redacted by the rules of the agent configmap before they leave the hub, load the rules before
+		// the syncers are started, otherwise the events are blocked until the configmap controller loads them
+		if err := configmap.LoadRedactionRules(context.Background(), mgr.GetAPIReader(),
+			agentConfig.PodNamespace); err != nil {
+			logger.DefaultZapLogger().Errorw("failed to load the redaction rules", "error", err)
+		}
+		transportClient = redaction.WrapTransportClient(transportClient)
 		if err := controllers.AddInitController(mgr, mgr.GetConfig(), agentConfig, transportClient); err != nil {
 			return fmt.Errorf("failed to add crd controller: %w", err)
 		}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -28,6 +29,7 @@ type AgentConfig struct {
 	EnableStackroxIntegration    bool
 	StackroxPollInterval         time.Duration
 	EventMode                    string
+	TracingConfig                *tracing.TracingConfig
 }
 
 func SetAgentConfig(agentConfig *AgentConfig) {
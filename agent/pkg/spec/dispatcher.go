@@ -3,13 +3,16 @@ package spec
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -90,12 +93,17 @@ func (d *genericDispatcher) dispatch(ctx context.Context) {
 					"syncer", syncer, "event", evt)
 				continue
 			}
-			if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-				if err := syncer.Sync(ctx, evt); err != nil {
+			// continue the trace of the spec syncer of the manager, the syncer applies the objects with the span
+			syncCtx, span := tracing.StartEventSpan(ctx, evt, "sync "+enum.ShortenEventType(evt.Type()),
+				trace.WithSpanKind(trace.SpanKindConsumer))
+			err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+				if err := syncer.Sync(syncCtx, evt); err != nil {
 					return err
 				}
 				return nil
-			}); err != nil {
+			})
+			tracing.EndSpan(span, err)
+			if err != nil {
 				d.log.Errorw("sync failed", "type", evt.Type(), "error", err)
 			}
 		}
@@ -6,6 +6,9 @@ import (
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -15,6 +18,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/spec/workers"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -43,13 +47,13 @@ func (syncer *genericBundleSyncer) Sync(ctx context.Context, evt *cloudevents.Ev
 	}
 
 	syncer.bundleProcessingWaitingGroup.Add(len(genericBundle.Objects) + len(genericBundle.DeletedObjects))
-	syncer.syncObjects(genericBundle.Objects)
-	syncer.syncDeletedObjects(genericBundle.DeletedObjects)
+	syncer.syncObjects(ctx, genericBundle.Objects)
+	syncer.syncDeletedObjects(ctx, genericBundle.DeletedObjects)
 	syncer.bundleProcessingWaitingGroup.Wait()
 	return nil
 }
 
-func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstructured) {
+func (s *genericBundleSyncer) syncObjects(bundleCtx context.Context, bundleObjects []*unstructured.Unstructured) {
 	for _, bundleObject := range bundleObjects {
 		if !s.enforceHohRbac { // if rbac not enforced, use controller's identity.
 			bundleObject = s.anonymize(bundleObject) // anonymize removes the user identity from the obj if exists
@@ -62,8 +66,12 @@ func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstruct
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
 
+			ctx, span := startObjectSpan(ctx, bundleCtx, "apply", unstructuredObject)
+			var err error
+			defer func() { tracing.EndSpan(span, err) }()
+
 			if !s.enforceHohRbac { // if rbac not enforced, create missing namespaces.
-				if err := utils.CreateNamespaceIfNotExist(ctx, k8sClient,
+				if err = utils.CreateNamespaceIfNotExist(ctx, k8sClient,
 					unstructuredObject.GetNamespace()); err != nil {
 					s.log.Error(err, "failed to create namespace", unstructuredObject.GetNamespace())
 					return
@@ -87,7 +95,7 @@ func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstruct
 			}
 
 			delete(unstructuredObject.Object, "status")
-			err := utils.UpdateObject(ctx, k8sClient, unstructuredObject)
+			err = utils.UpdateObject(ctx, k8sClient, unstructuredObject)
 			if err != nil {
 				s.log.Error(err, "failed to update object", "name", unstructuredObject.GetName(),
 					"namespace", unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
@@ -95,11 +103,15 @@ func (s *genericBundleSyncer) syncObjects(bundleObjects []*unstructured.Unstruct
 			}
 			s.log.Debug("object updated", "name", unstructuredObject.GetName(), "namespace",
 				unstructuredObject.GetNamespace(), "kind", unstructuredObject.GetKind())
+			// the status emitters of the object continue the trace of the apply
+			tracing.RecordObject(ctx, unstructuredObject)
 		}))
 	}
 }
 
-func (s *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.Unstructured) {
+func (s *genericBundleSyncer) syncDeletedObjects(bundleCtx context.Context,
+	deletedObjects []*unstructured.Unstructured,
+) {
 	for _, deletedBundleObj := range deletedObjects {
 		if !s.enforceHohRbac { // if rbac not enforced, use controller's identity.
 			deletedBundleObj = s.anonymize(deletedBundleObj) // anonymize removes the user identity from the obj if exists
@@ -112,8 +124,11 @@ func (s *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.
 
 			unstructuredObject, _ := obj.(*unstructured.Unstructured)
 
+			ctx, span := startObjectSpan(ctx, bundleCtx, "delete", unstructuredObject)
 			// syncer.deleteObject(ctx, k8sClient, obj.(*unstructured.Unstructured))
-			if deleted, err := utils.DeleteObject(ctx, k8sClient, unstructuredObject); err != nil {
+			deleted, err := utils.DeleteObject(ctx, k8sClient, unstructuredObject)
+			tracing.EndSpan(span, err)
+			if err != nil {
 				s.log.Error("failed to delete object",
 					"error", err,
 					"name", unstructuredObject.GetName(),
@@ -127,6 +142,18 @@ func (s *genericBundleSyncer) syncDeletedObjects(deletedObjects []*unstructured.
 	}
 }
 
+// startObjectSpan starts the span of the object in the worker, it's the child of the span syncing the bundle
+func startObjectSpan(ctx, bundleCtx context.Context, operation string, obj *unstructured.Unstructured) (
+	context.Context, trace.Span,
+) {
+	ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(bundleCtx))
+	return tracing.Tracer().Start(ctx, operation+" "+obj.GetKind(), trace.WithAttributes(
+		attribute.String("k8s.object.kind", obj.GetKind()),
+		semconv.K8SNamespaceName(obj.GetNamespace()),
+		attribute.String("k8s.object.name", obj.GetName()),
+	))
+}
+
 func (syncer *genericBundleSyncer) anonymize(obj *unstructured.Unstructured) *unstructured.Unstructured {
 	annotations := obj.GetAnnotations()
 	delete(annotations, rbac.UserIdentityAnnotation)
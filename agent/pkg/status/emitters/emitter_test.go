@@ -31,6 +31,17 @@ func (m *MockProducer) Reconnect(config *transport.TransportInternalConfig, clie
 	return nil
 }
 
+// mock producer which records the events sent with the delivery reports
+type MockSyncProducer struct {
+	MockProducer
+	syncEvents []cloudevents.Event
+}
+
+func (m *MockSyncProducer) SendEventSync(ctx context.Context, evt cloudevents.Event) error {
+	m.syncEvents = append(m.syncEvents, evt)
+	return nil
+}
+
 func TestObjectEmitter_BundleOperations(t *testing.T) {
 	producer := &MockProducer{}
 	eventType := enum.EventType("test-event")
@@ -211,3 +222,116 @@ func TestObjectEmitter_WithTweakFunction(t *testing.T) {
 	// assert the object in the bundle is tweaked
 	require.Equal(t, "tweaked-original", emitter.bundle.Update[0].GetName())
 }
+
+func TestObjectEmitter_TweakOnUpdate(t *testing.T) {
+	tweakFunc := func(obj client.Object) {
+		obj.(*corev1.ConfigMap).Data = nil
+	}
+	newConfigMap := func(value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", UID: "uid1", ResourceVersion: value},
+			Data:       map[string]string{"key": value},
+		}
+	}
+
+	// the object replacing the existing one in the bundle is also tweaked
+	emitter := NewObjectEmitter(enum.EventType("test-event"), &MockProducer{}, WithTweakFunc(tweakFunc))
+	require.NoError(t, emitter.Update(newConfigMap("1")))
+	require.NoError(t, emitter.Update(newConfigMap("2")))
+	require.Len(t, emitter.bundle.Update, 1)
+	require.Nil(t, emitter.bundle.Update[0].(*corev1.ConfigMap).Data)
+	require.Equal(t, "2", emitter.bundle.Update[0].GetResourceVersion())
+}
+
+func TestObjectEmitter_WithSyncDelivery(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "test-leaf-hub"})
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "obj1", UID: "uid1"}}
+
+	// the bundle is only enqueued into the producer by default
+	producer := &MockSyncProducer{}
+	emitter := NewObjectEmitter(enum.EventType("test-event"), producer)
+	require.NoError(t, emitter.Update(obj))
+	require.NoError(t, emitter.Send())
+	require.Len(t, producer.events, 1)
+	require.Empty(t, producer.syncEvents)
+
+	// it waits for the delivery with the option
+	producer = &MockSyncProducer{}
+	emitter = NewObjectEmitter(enum.EventType("test-event"), producer, WithSyncDelivery())
+	require.NoError(t, emitter.Update(obj))
+	require.NoError(t, emitter.Send())
+	require.Empty(t, producer.events)
+	require.Len(t, producer.syncEvents, 1)
+}
+
+func TestObjectEmitter_MergePatch(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "test-leaf-hub"})
+	producer := &MockProducer{}
+	enabled := true
+	emitter := NewObjectEmitter(enum.EventType("test-event"), producer,
+		WithMergePatch(func() bool { return enabled }))
+
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "obj1", UID: "uid1", ResourceVersion: "1", Labels: map[string]string{"app": "test"},
+		},
+		Data: map[string]string{"key1": "value1", "key2": "value2"},
+	}
+
+	// the object is sent as a whole for the first time
+	require.NoError(t, emitter.Update(obj))
+	require.Len(t, emitter.bundle.Update, 1)
+	require.Empty(t, emitter.bundle.Patch)
+	require.NoError(t, emitter.Send())
+
+	// the update of the acknowledged object is sent as the patch against its resource version
+	obj.Data["key1"] = "changed"
+	require.NoError(t, emitter.Update(obj))
+	require.Empty(t, emitter.bundle.Update)
+	require.Len(t, emitter.bundle.Patch, 1)
+	require.JSONEq(t, `{"data":{"key1":"changed"}}`, string(emitter.bundle.Patch[0].Patch))
+	require.Equal(t, "1", emitter.bundle.Patch[0].BaseResourceVersion)
+
+	// the patch is against the acknowledged object, so it replaces the previous one in the bundle
+	delete(obj.Data, "key2")
+	require.NoError(t, emitter.Update(obj))
+	require.Len(t, emitter.bundle.Patch, 1)
+	require.JSONEq(t, `{"data":{"key1":"changed","key2":null}}`, string(emitter.bundle.Patch[0].Patch))
+
+	// the patch is removed if the object is changed back
+	obj.Data = map[string]string{"key1": "value1", "key2": "value2"}
+	require.NoError(t, emitter.Update(obj))
+	require.True(t, emitter.bundle.IsEmpty())
+
+	obj.Data["key1"] = "changed"
+	require.NoError(t, emitter.Update(obj))
+	require.NoError(t, emitter.Send())
+	bundle := genericbundle.GenericBundle[*corev1.ConfigMap]{}
+	require.NoError(t, json.Unmarshal(producer.events[len(producer.events)-1].Data(), &bundle))
+	require.Len(t, bundle.Patch, 1)
+	require.Equal(t, "uid1", bundle.Patch[0].ID)
+
+	// the next patch is against the delivered one
+	obj.ResourceVersion = "2"
+	obj.Data["key2"] = "changed"
+	require.NoError(t, emitter.Update(obj))
+	require.JSONEq(t, `{"metadata":{"resourceVersion":"2"},"data":{"key2":"changed"}}`,
+		string(emitter.bundle.Patch[0].Patch))
+	require.Equal(t, "1", emitter.bundle.Patch[0].BaseResourceVersion)
+
+	// the whole object supersedes the patch if the merge patch is disabled
+	enabled = false
+	obj.Labels["app"] = "changed"
+	require.NoError(t, emitter.Update(obj))
+	require.Len(t, emitter.bundle.Update, 1)
+	require.Empty(t, emitter.bundle.Patch)
+	require.NoError(t, emitter.Send())
+
+	// the deleted object is sent as a whole after it's recreated
+	enabled = true
+	require.NoError(t, emitter.Delete(obj))
+	require.NoError(t, emitter.Send())
+	require.NoError(t, emitter.Update(obj))
+	require.Len(t, emitter.bundle.Update, 1)
+	require.Empty(t, emitter.bundle.Patch)
+}
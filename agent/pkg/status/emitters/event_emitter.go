@@ -8,6 +8,7 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
@@ -15,6 +16,7 @@ import (
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -32,6 +34,8 @@ type EventEmitter struct {
 	events    []interface{}
 	version   *eventversion.Version
 	mu        sync.Mutex
+	// spans of the applied objects which emit the events, e.g. the root policies of the replicated policy events
+	spans tracing.BundleSpans
 }
 
 func NewEventEmitter(
@@ -85,6 +89,7 @@ func (e *EventEmitter) Update(obj client.Object) error {
 	if event != nil {
 		e.events = append(e.events, toSlice(event)...)
 		e.version.Incr()
+		e.spans.Add(obj)
 	}
 	return nil
 }
@@ -129,13 +134,16 @@ func (e *EventEmitter) sendEvents() error {
 	}
 	log.Debugw("before send events", "events", e.events, "version", e.version.String())
 
+	ctx, span := e.spans.Start(context.Background(), "emit "+enum.ShortenEventType(string(e.eventType)),
+		trace.WithSpanKind(trace.SpanKindProducer))
 	var err error
 	switch configs.GetAgentConfig().EventMode {
 	case string(constants.EventSendModeSingle):
-		err = e.sendEventsIndividually()
+		err = e.sendEventsIndividually(ctx)
 	default: // batch is default
-		err = e.sendEventBundle()
+		err = e.sendEventBundle(ctx)
 	}
+	tracing.EndSpan(span, err)
 	if err != nil {
 		return err
 	}
@@ -150,12 +158,13 @@ func (e *EventEmitter) sendEvents() error {
 	// Clear events after successful send and postSend
 	e.events = e.events[:0]
 	e.version.Next()
+	e.spans.Reset()
 
 	log.Debugw("after send events", "events", e.events, "version", e.version.String())
 	return nil
 }
 
-func (e *EventEmitter) sendEventBundle() error {
+func (e *EventEmitter) sendEventBundle(ctx context.Context) error {
 	evt := e.createCloudEvent(constants.EventSendModeBatch)
 
 	if err := evt.SetData(cloudevents.ApplicationJSON, e.events); err != nil {
@@ -163,8 +172,7 @@ func (e *EventEmitter) sendEventBundle() error {
 		return fmt.Errorf("failed to set event data for bundle: %w", err)
 	}
 
-	ctx := e.createContext()
-	if err := e.producer.SendEvent(ctx, evt); err != nil {
+	if err := e.producer.SendEvent(e.createContext(ctx), evt); err != nil {
 		log.Errorw("failed to send event bundle", "error", err)
 		return fmt.Errorf("failed to send event bundle: %w", err)
 	}
@@ -176,8 +184,8 @@ func (e *EventEmitter) sendEventBundle() error {
 	return nil
 }
 
-func (e *EventEmitter) sendEventsIndividually() error {
-	ctx := e.createContext()
+func (e *EventEmitter) sendEventsIndividually(ctx context.Context) error {
+	ctx = e.createContext(ctx)
 	sentCount := 0
 
 	for _, event := range e.events {
@@ -210,12 +218,12 @@ func (e *EventEmitter) createCloudEvent(mode constants.EventSendMode) cloudevent
 	evt.SetType(string(e.eventType))
 	evt.SetExtension(eventversion.ExtVersion, e.version.String())
 	evt.SetExtension(constants.CloudEventExtensionSendMode, string(mode))
+	transport.SetEmitTime(&evt)
 	return evt
 }
 
 // createContext creates a context with topic if configured
-func (e *EventEmitter) createContext() context.Context {
-	ctx := context.Background()
+func (e *EventEmitter) createContext(ctx context.Context) context.Context {
 	if e.topic != "" {
 		ctx = cecontext.WithTopic(ctx, e.topic)
 	}
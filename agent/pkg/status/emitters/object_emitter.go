@@ -8,6 +8,8 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	jsonpatch "github.com/evanphx/json-patch"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -17,6 +19,7 @@ import (
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -43,6 +46,16 @@ type ObjectEmitter struct {
 	version      *eventversion.Version
 	mu           sync.Mutex
 	keyFunc      func(client.Object) string
+	// spans of the applied objects in the bundle, the bundle continues the trace of the spec changes
+	spans tracing.BundleSpans
+	// mergePatch enables sending the updates as the JSON merge patches against the acknowledged objects
+	mergePatch func() bool
+	// acked are the objects delivered by the previous bundles, and pending are the objects in the current bundle,
+	// they're keyed by the keyFunc and tracked only if the merge patch option is set
+	acked   map[string][]byte
+	pending map[string][]byte
+	// syncDelivery waits for the delivery of the bundle, so it's kept and resent if it isn't landed in the transport
+	syncDelivery bool
 }
 
 // NewObjectEmitter creates a new ObjectEmitter with the provided event type and producer.
@@ -109,16 +122,116 @@ func (e *ObjectEmitter) Update(obj client.Object) error {
 		}
 	}
 
+	e.spans.Add(obj)
+
+	if e.mergePatch != nil {
+		tweaked, err := applyTweak(obj, e.tweakFunc)
+		if err != nil {
+			return err
+		}
+		if patched, err := e.updatePatch(tweaked); patched || err != nil {
+			return err
+		}
+	}
+
 	// if the object is in update array, update it
 	for i, existingObj := range e.bundle.Update {
 		if e.keyFunc(existingObj) == e.keyFunc(obj) {
-			e.bundle.Update[i] = obj
+			tweaked, err := applyTweak(obj, e.tweakFunc)
+			if err != nil {
+				return err
+			}
+			e.bundle.Update[i] = tweaked
+			e.trackPending(tweaked)
 			e.version.Incr()
 			return nil
 		}
 	}
 
-	return e.handleDeltaEvent(obj, e.bundle.AddUpdate)
+	return e.handleDeltaEvent(obj, func(tweaked client.Object) (bool, error) {
+		added, err := e.bundle.AddUpdate(tweaked)
+		if added {
+			e.trackPending(tweaked)
+		}
+		return added, err
+	})
+}
+
+// updatePatch adds the merge patch of the object against the acknowledged one into the bundle, it returns false if
+// the object isn't acknowledged or the merge patch is disabled, then the whole object is sent.
+func (e *ObjectEmitter) updatePatch(tweaked client.Object) (bool, error) {
+	key := e.keyFunc(tweaked)
+	acked, found := e.acked[key]
+	if !found || !e.mergePatch() {
+		return false, nil
+	}
+
+	current, err := json.Marshal(tweaked)
+	if err != nil {
+		return true, fmt.Errorf("failed to marshal object: %v", err)
+	}
+	patch, err := jsonpatch.CreateMergePatch(acked, current)
+	if err != nil {
+		return true, fmt.Errorf("failed to create merge patch: %v", err)
+	}
+	metadata := e.objectMetadata(tweaked)
+
+	// the object is changed back to the acknowledged one
+	if string(patch) == "{}" {
+		e.bundle.RemovePatch(*metadata)
+		delete(e.pending, key)
+		return true, nil
+	}
+
+	added, err := e.bundle.AddPatch(genericbundle.ObjectPatch{
+		ObjectMetadata:      *metadata,
+		BaseResourceVersion: genericbundle.PayloadResourceVersion(acked),
+		Patch:               patch,
+	})
+	if err != nil {
+		return true, err
+	}
+	if !added {
+		log.Info("Patch bundle is full, sending current bundle before adding new patch")
+		if err := e.sendBundle(); err != nil {
+			return true, err
+		}
+		// the acknowledged object might be changed by the sent bundle
+		return e.updatePatch(tweaked)
+	}
+	e.pending[key] = current
+	e.version.Incr()
+	return true, nil
+}
+
+// trackPending records the whole object in the current bundle, it supersedes the patch of the object in the bundle,
+// and becomes the base of the merge patches once the bundle is delivered
+func (e *ObjectEmitter) trackPending(tweaked client.Object) {
+	if e.mergePatch == nil {
+		return
+	}
+	e.bundle.RemovePatch(*e.objectMetadata(tweaked))
+	key := e.keyFunc(tweaked)
+	current, err := json.Marshal(tweaked)
+	if err != nil {
+		// send the whole object next time
+		log.Warnw("failed to marshal object", "key", key, "error", err)
+		delete(e.acked, key)
+		delete(e.pending, key)
+		return
+	}
+	e.pending[key] = current
+}
+
+func (e *ObjectEmitter) objectMetadata(tweaked client.Object) *genericbundle.ObjectMetadata {
+	if e.metadataFunc != nil {
+		return e.metadataFunc(tweaked)
+	}
+	return &genericbundle.ObjectMetadata{
+		ID:        string(tweaked.GetUID()),
+		Namespace: tweaked.GetNamespace(),
+		Name:      tweaked.GetName(),
+	}
 }
 
 // Delete removes the bundle associated with the provided object.
@@ -174,6 +287,12 @@ func (e *ObjectEmitter) remove(obj client.Object) error {
 		metadata = e.metadataFunc(tweaked)
 	}
 
+	if e.mergePatch != nil {
+		delete(e.acked, e.keyFunc(tweaked))
+		delete(e.pending, e.keyFunc(tweaked))
+		e.bundle.RemovePatch(*metadata)
+	}
+
 	added, err := e.bundle.AddDelete(*metadata)
 	if err != nil {
 		return fmt.Errorf("failed to add delete event to bundle: %v", err)
@@ -253,6 +372,8 @@ func (e *ObjectEmitter) Resync(objects []client.Object) error {
 			tmp = *e.metadataFunc(tweaked)
 		}
 		metadataList = append(metadataList, tmp)
+
+		e.trackPending(tweaked)
 	}
 
 	if err := e.sendBundle(); err != nil {
@@ -267,7 +388,7 @@ func (e *ObjectEmitter) Resync(objects []client.Object) error {
 
 // Send triggers the emission of an event.
 // It sends the current bundle as a CloudEvent and increments the version.
-// Returns an error if sending fails.
+// Returns an error if sending fails, the bundle and version are kept until it's delivered.
 // Example cloudevents:
 //
 //	{
@@ -314,6 +435,7 @@ func (e *ObjectEmitter) sendBundle() error {
 	evt.SetSource(configs.GetLeafHubName())
 	evt.SetType(string(e.eventType))
 	evt.SetExtension(eventversion.ExtVersion, e.version.String())
+	transport.SetEmitTime(&evt)
 
 	payload, err := json.Marshal(e.bundle)
 	if err != nil {
@@ -326,11 +448,18 @@ func (e *ObjectEmitter) sendBundle() error {
 
 	log.Debugf("sending cloudevents: %s", evt)
 
-	ctx := context.Background()
+	ctx, span := e.spans.Start(context.Background(), "emit "+enum.ShortenEventType(string(e.eventType)),
+		trace.WithSpanKind(trace.SpanKindProducer))
 	if e.topic != "" {
 		ctx = cecontext.WithTopic(ctx, e.topic)
 	}
-	if err = e.producer.SendEvent(ctx, evt); err != nil {
+	if e.syncDelivery {
+		err = transport.SendEventSync(ctx, e.producer, evt)
+	} else {
+		err = e.producer.SendEvent(ctx, evt)
+	}
+	tracing.EndSpan(span, err)
+	if err != nil {
 		return fmt.Errorf("failed to send event: %v", err)
 	}
 	log.Debugw("sending",
@@ -339,8 +468,14 @@ func (e *ObjectEmitter) sendBundle() error {
 		"update", len(e.bundle.Update),
 		"delete", len(e.bundle.Delete),
 		"resync", len(e.bundle.Resync),
-		"resync_metadata", len(e.bundle.ResyncMetadata))
+		"resync_metadata", len(e.bundle.ResyncMetadata),
+		"patch", len(e.bundle.Patch))
 	e.bundle.Clean()
+	for key, obj := range e.pending {
+		e.acked[key] = obj
+		delete(e.pending, key)
+	}
+	e.spans.Reset()
 	return nil
 }
 
@@ -358,6 +493,14 @@ func WithTweakFunc(tweakFunc func(client.Object)) EmitterOption {
 	}
 }
 
+// WithSyncDelivery waits for the delivery reports of the bundle before it's cleaned, the bundle is only enqueued into
+// the producer by default.
+func WithSyncDelivery() EmitterOption {
+	return func(e *ObjectEmitter) {
+		e.syncDelivery = true
+	}
+}
+
 func WithPredicateFunc(eventFilter predicate.Predicate) EmitterOption {
 	return func(e *ObjectEmitter) {
 		e.objectPredicate = eventFilter
@@ -378,6 +521,24 @@ func WithShouldDeleteFunc(shouldDeleteFunc func(client.Object) bool) EmitterOpti
 	}
 }
 
+// WithMergePatch sends the updates of the acknowledged objects as the JSON merge patches if it's enabled, the objects
+// are sent as a whole for the first time and by the resync. The handler of the event type must apply the patches.
+// It's used with WithSyncDelivery, so that the objects are acknowledged only once they're delivered.
+func WithMergePatch(enabled func() bool) EmitterOption {
+	return func(e *ObjectEmitter) {
+		e.mergePatch = enabled
+		e.acked = map[string][]byte{}
+		e.pending = map[string][]byte{}
+	}
+}
+
+// WithKeyFunc sets the function to identify the object in the bundle, it's the namespace and name by default.
+func WithKeyFunc(keyFunc func(client.Object) string) EmitterOption {
+	return func(e *ObjectEmitter) {
+		e.keyFunc = keyFunc
+	}
+}
+
 func WithTopic(topic string) EmitterOption {
 	return func(e *ObjectEmitter) {
 		e.topic = topic
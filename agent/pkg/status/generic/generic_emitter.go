@@ -8,6 +8,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 var _ interfaces.Emitter = &genericEmitter{}
@@ -21,6 +22,8 @@ type genericEmitter struct {
 	dependencyVersion *eventversion.Version
 
 	postSend func(interface{})
+	// syncDelivery waits for the delivery of the event before the PostSend
+	syncDelivery bool
 }
 
 func NewGenericEmitter(
@@ -57,6 +60,11 @@ func (h *genericEmitter) PostSend(data interface{}) {
 	h.lastSentVersion = *h.currentVersion
 }
 
+// SyncDelivery returns true if the event is sent with the delivery reports
+func (h *genericEmitter) SyncDelivery() bool {
+	return h.syncDelivery
+}
+
 func (h *genericEmitter) Topic() string {
 	return h.topic
 }
@@ -73,6 +81,7 @@ func (g *genericEmitter) ToCloudEvent(payload interface{}) (*cloudevents.Event,
 	if g.dependencyVersion != nil {
 		e.SetExtension(eventversion.ExtDependencyVersion, g.dependencyVersion.String())
 	}
+	transport.SetEmitTime(&e)
 	err := e.SetData(cloudevents.ApplicationJSON, payload)
 	return &e, err
 }
@@ -98,6 +107,14 @@ func WithVersion(version *eventversion.Version) EmitterOption {
 	}
 }
 
+// WithSyncDelivery waits for the delivery reports of the event before the PostSend updates the version, the event is
+// only enqueued into the producer by default
+func WithSyncDelivery() EmitterOption {
+	return func(g *genericEmitter) {
+		g.syncDelivery = true
+	}
+}
+
 func WithPostSend(postSend func(interface{})) EmitterOption {
 	return func(g *genericEmitter) {
 		g.postSend = postSend
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -15,7 +16,9 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -39,6 +42,8 @@ type multiEventSyncer struct {
 type EmitterHandler struct {
 	interfaces.Emitter
 	interfaces.Handler
+	// spans of the applied objects updated into the event, e.g. the policies of the compliance
+	spans tracing.BundleSpans
 }
 
 // LaunchMultiEventSyncer is used to send multi event(by the eventEmitter) by a specific client.Object
@@ -114,6 +119,7 @@ func (c *multiEventSyncer) updateObject(object client.Object) {
 		// update in each handler from the collection according to their order.
 		if eventEmitter.Update(object) {
 			eventEmitter.PostUpdate()
+			eventEmitter.spans.Add(object)
 		}
 	}
 }
@@ -163,15 +169,19 @@ func (c *multiEventSyncer) syncEvents() {
 			}
 			evt.SetSource(c.leafHubName)
 
-			ctx := context.TODO()
+			ctx, span := emitter.spans.Start(context.TODO(), "emit "+enum.ShortenEventType(evt.Type()),
+				trace.WithSpanKind(trace.SpanKindProducer))
 			if emitter.Topic() != "" {
 				ctx = cecontext.WithTopic(ctx, emitter.Topic())
 			}
-			if err := c.producer.SendEvent(ctx, *evt); err != nil {
+			err = c.producer.SendEvent(ctx, *evt)
+			tracing.EndSpan(span, err)
+			if err != nil {
 				c.log.Error(err, "failed to send event", "evt", evt)
 				continue
 			}
 			emitter.PostSend(emitter.Get())
+			emitter.spans.Reset()
 		}
 	}
 }
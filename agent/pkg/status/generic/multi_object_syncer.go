@@ -9,6 +9,7 @@ import (
 	"time"
 
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -18,7 +19,9 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -37,6 +40,11 @@ type multiObjectSyncer struct {
 	lock *sync.Mutex
 }
 
+// syncDeliveryEmitter is implemented by the emitter which can wait for the delivery of the event
+type syncDeliveryEmitter interface {
+	SyncDelivery() bool
+}
+
 type ControllerHandler struct {
 	interfaces.Controller
 	interfaces.Handler
@@ -111,11 +119,19 @@ func (s *multiObjectSyncer) syncEvent() {
 			return
 		}
 
-		ctx := context.TODO()
+		ctx, span := tracing.Tracer().Start(context.TODO(), "emit "+enum.ShortenEventType(evt.Type()),
+			trace.WithSpanKind(trace.SpanKindProducer))
 		if s.emitter.Topic() != "" {
 			ctx = cecontext.WithTopic(ctx, s.emitter.Topic())
 		}
-		if err := s.producer.SendEvent(ctx, *evt); err != nil {
+		// the PostSend updates the version of the emitter, so the emitter might wait for the delivery
+		if emitter, ok := s.emitter.(syncDeliveryEmitter); ok && emitter.SyncDelivery() {
+			err = transport.SendEventSync(ctx, s.producer, *evt)
+		} else {
+			err = s.producer.SendEvent(ctx, *evt)
+		}
+		tracing.EndSpan(span, err)
+		if err != nil {
 			s.log.Error(err, "failed to send event", "evt", evt)
 			return
 		}
@@ -6,11 +6,15 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
@@ -35,13 +39,7 @@ func AddSyncCtrl(mgr ctrl.Manager, ctrlName string, instanceFunc func() client.O
 		return fmt.Errorf("at least one emitter is required")
 	}
 
-	syncer := &syncController{
-		client:        mgr.GetClient(),
-		emitters:      objectEmitters,
-		instance:      instanceFunc,
-		leafHubName:   configs.GetLeafHubName(),
-		finalizerName: constants.GlobalHubCleanupFinalizer,
-	}
+	syncer := newSyncController(mgr, instanceFunc, objectEmitters...)
 
 	// Create combined event filter using OR relationship for all methods
 	combinedFilter := createCombinedFilter(objectEmitters...)
@@ -50,6 +48,42 @@ func AddSyncCtrl(mgr ctrl.Manager, ctrlName string, instanceFunc func() client.O
 		Named(ctrlName).Complete(syncer)
 }
 
+// NewUnmanagedSyncCtrl creates the controller like AddSyncCtrl, but it isn't added into the manager. The caller starts
+// it and stops it by canceling the context, e.g. the watch of the kind which is removed from the agent configmap.
+func NewUnmanagedSyncCtrl(mgr ctrl.Manager, ctrlName string, instanceFunc func() client.Object,
+	objectEmitters ...emitters.Emitter,
+) (controller.Controller, error) {
+	if len(objectEmitters) == 0 {
+		return nil, fmt.Errorf("at least one emitter is required")
+	}
+
+	// the controller of the same name might be created again once the previous one is stopped
+	c, err := controller.NewUnmanaged(ctrlName, mgr, controller.Options{
+		Reconciler:         newSyncController(mgr, instanceFunc, objectEmitters...),
+		SkipNameValidation: ptr.To(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), instanceFunc(), &handler.EnqueueRequestForObject{},
+		createCombinedFilter(objectEmitters...))); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newSyncController(mgr ctrl.Manager, instanceFunc func() client.Object,
+	objectEmitters ...emitters.Emitter,
+) *syncController {
+	return &syncController{
+		client:        mgr.GetClient(),
+		emitters:      objectEmitters,
+		instance:      instanceFunc,
+		leafHubName:   configs.GetLeafHubName(),
+		finalizerName: constants.GlobalHubCleanupFinalizer,
+	}
+}
+
 // createCombinedFilter creates a combined filter using OR relationship for all event types
 func createCombinedFilter(emitters ...emitters.Emitter) predicate.Funcs {
 	return predicate.Funcs{
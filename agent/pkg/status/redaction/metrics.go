@@ -0,0 +1,33 @@
+package redaction
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var dryRunObjectCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_redaction_dry_run_objects_total",
+		Help: "The number of the objects in the sent events which would be redacted by the dry run rules.",
+	},
+	[]string{
+		"type",  // The short event type, e.g. managedcluster.
+		"rule",  // The name of the dry run rule.
+		"field", // The field matched by the rule, e.g. metadata.labels[vendor].
+	},
+)
+
+var blockedEventCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_agent_redaction_blocked_events_total",
+		Help: "The number of the events which aren't sent because the redaction rules are not loaded.",
+	},
+	[]string{
+		"type", // The short event type, e.g. managedcluster.
+	},
+)
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(dryRunObjectCounter, blockedEventCounter)
+}
@@ -0,0 +1,369 @@
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var log = logger.DefaultZapLogger()
+
+// patchKey is the field of the JSON merge patch in the generic bundle, the patch is redacted as the object
+const patchKey = "patch"
+
+// ErrRulesNotLoaded means the redaction rules aren't loaded from the agent configmap yet, the event isn't sent until
+// they're loaded, so that the fields never leave the hub before the rules are applied
+var ErrRulesNotLoaded = errors.New("the redaction rules are not loaded")
+
+// transportClient returns the producer redacting the events with the rules of the agent configmap
+type transportClient struct {
+	transport.TransportClient
+}
+
+// WrapTransportClient wraps the producer of the client, so that all the events sent by the agent are redacted, no
+// matter which syncer sends them
+func WrapTransportClient(client transport.TransportClient) transport.TransportClient {
+	return &transportClient{TransportClient: client}
+}
+
+func (c *transportClient) GetProducer() transport.Producer {
+	producer := c.TransportClient.GetProducer()
+	if producer == nil {
+		return nil
+	}
+	return NewProducer(producer)
+}
+
+// Producer strips the fields of the objects in the events with the redaction rules before sending them
+type Producer struct {
+	producer transport.Producer
+}
+
+func NewProducer(producer transport.Producer) *Producer {
+	return &Producer{producer: producer}
+}
+
+func (p *Producer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if err := Redact(&evt); err != nil {
+		return err
+	}
+	return p.producer.SendEvent(ctx, evt)
+}
+
+// SendEventSync waits for the delivery of the redacted event if the underlying producer supports it
+func (p *Producer) SendEventSync(ctx context.Context, evt cloudevents.Event) error {
+	if err := Redact(&evt); err != nil {
+		return err
+	}
+	return transport.SendEventSync(ctx, p.producer, evt)
+}
+
+func (p *Producer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return p.producer.Reconnect(config, topic)
+}
+
+// Redact removes the fields matched by the rules of the event type from the JSON payload of the event. The event
+// isn't sent if its payload can't be redacted, so that the redacted fields never leave the hub.
+func Redact(evt *cloudevents.Event) error {
+	if !configmap.RedactionRulesLoaded() {
+		blockedEventCounter.WithLabelValues(enum.ShortenEventType(evt.Type())).Inc()
+		return fmt.Errorf("failed to redact %s: %w", enum.ShortenEventType(evt.Type()), ErrRulesNotLoaded)
+	}
+	var rules []configmap.RedactionRule
+	for _, rule := range configmap.GetRedactionRules() {
+		if rule.AppliesTo(evt.Type()) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 || len(evt.Data()) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(evt.Data()))
+	// keep the precision of the numbers, e.g. the generation and the timestamps
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return fmt.Errorf("failed to decode the payload of %s to redact: %w", enum.ShortenEventType(evt.Type()), err)
+	}
+
+	r := newRedactor(rules)
+	if !r.walk(payload) {
+		// the payload of the event emitters is the list of the events, or a single event in the single send mode, e.g.
+		// the managed cluster events, these events have no metadata, so the items are redacted as the objects
+		r.redactItems(payload)
+	}
+	r.report(evt.Type())
+	if r.removed == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode the redacted payload of %s: %w", enum.ShortenEventType(evt.Type()), err)
+	}
+	return evt.SetData(cloudevents.ApplicationJSON, data)
+}
+
+// compiledRule is the configmap.RedactionRule with the parsed paths
+type compiledRule struct {
+	configmap.RedactionRule
+	paths []compiledPath
+}
+
+type compiledPath struct {
+	path     string
+	segments []string
+}
+
+// redactor applies the rules to the objects of a payload, and records the fields matched by the dry run rules
+type redactor struct {
+	rules   []compiledRule
+	removed int
+	// dryRun is the objects matched by the dry run rules, keyed by the rule name and the field
+	dryRun map[dryRunField][]string
+}
+
+type dryRunField struct {
+	rule  string
+	field string
+}
+
+func newRedactor(rules []configmap.RedactionRule) *redactor {
+	r := &redactor{dryRun: map[dryRunField][]string{}}
+	for _, rule := range rules {
+		compiled := compiledRule{RedactionRule: rule}
+		for _, path := range rule.Paths {
+			// the paths are validated when the configmap is loaded
+			segments, err := configmap.ParseRedactionPath(path)
+			if err != nil {
+				log.Warnw("skip the invalid path of the redaction rule", "rule", rule.Name, "path", path)
+				continue
+			}
+			compiled.paths = append(compiled.paths, compiledPath{path: path, segments: segments})
+		}
+		r.rules = append(r.rules, compiled)
+	}
+	return r
+}
+
+// walk finds the objects in the payload, the object is the map with the metadata or the merge patch of an object.
+// The fields of the object aren't walked, so the nested objects, like the templates of the policy, are kept. It
+// returns false if there is no object in the payload.
+func (r *redactor) walk(value interface{}) bool {
+	found := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if _, ok := v["metadata"].(map[string]interface{}); ok {
+			r.redact(v)
+			return true
+		}
+		for key, field := range v {
+			if patch, ok := field.(map[string]interface{}); ok && key == patchKey {
+				r.redact(patch)
+				found = true
+				continue
+			}
+			found = r.walk(field) || found
+		}
+	case []interface{}:
+		for _, item := range v {
+			found = r.walk(item) || found
+		}
+	}
+	return found
+}
+
+// redactItems redacts the payload as an object, or the items of the payload as the objects if it's a list
+func (r *redactor) redactItems(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		r.redact(v)
+	case []interface{}:
+		for _, item := range v {
+			if obj, ok := item.(map[string]interface{}); ok {
+				r.redact(obj)
+			}
+		}
+	}
+}
+
+func (r *redactor) redact(obj map[string]interface{}) {
+	name := objectName(obj)
+	for _, rule := range r.rules {
+		var fields []string
+		for _, path := range rule.paths {
+			if removeField(obj, path.segments, rule.DryRun) {
+				fields = append(fields, path.path)
+			}
+		}
+		fields = append(fields, removeKeys(obj, "annotations", rule.Annotations, rule.AnnotationPrefixes,
+			rule.DryRun)...)
+		fields = append(fields, removeKeys(obj, "labels", rule.Labels, rule.LabelPrefixes, rule.DryRun)...)
+
+		if rule.DryRun {
+			for _, field := range fields {
+				key := dryRunField{rule: rule.Name, field: field}
+				r.dryRun[key] = append(r.dryRun[key], name)
+			}
+			continue
+		}
+		r.removed += len(fields)
+	}
+}
+
+// report counts the objects which would be redacted by the dry run rules into the metric, the field is logged when
+// it's found in the event type for the first time, so the periodic resyncs don't flood the log
+func (r *redactor) report(eventType string) {
+	if len(r.dryRun) == 0 {
+		return
+	}
+	eventType = enum.ShortenEventType(eventType)
+	fields := make([]dryRunField, 0, len(r.dryRun))
+	for field := range r.dryRun {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].rule != fields[j].rule {
+			return fields[i].rule < fields[j].rule
+		}
+		return fields[i].field < fields[j].field
+	})
+	for _, field := range fields {
+		objects := r.dryRun[field]
+		dryRunObjectCounter.WithLabelValues(eventType, field.rule, field.field).Add(float64(len(objects)))
+		if reportedFields.add(eventType, field) {
+			log.Infow("dry run: the field would be redacted", "type", eventType, "rule", field.rule,
+				"field", field.field, "objects", objects)
+		} else {
+			log.Debugw("dry run: the field would be redacted", "type", eventType, "rule", field.rule,
+				"field", field.field, "objects", objects)
+		}
+	}
+}
+
+// removeField removes the field of the path from the object, it returns true if the field is found
+func removeField(value interface{}, path []string, dryRun bool) bool {
+	if len(path) == 0 {
+		return false
+	}
+	if configmap.IsListWildcard(path[0]) {
+		items, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		found := false
+		for _, item := range items {
+			found = removeField(item, path[1:], dryRun) || found
+		}
+		return found
+	}
+
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	field, found := obj[path[0]]
+	if !found {
+		return false
+	}
+	if len(path) > 1 {
+		return removeField(field, path[1:], dryRun)
+	}
+	if !dryRun {
+		delete(obj, path[0])
+	}
+	return true
+}
+
+// removeKeys removes the annotations or labels matching the keys or the prefixes, and returns the removed fields
+func removeKeys(obj map[string]interface{}, field string, keys, prefixes []string, dryRun bool) []string {
+	if len(keys)+len(prefixes) == 0 {
+		return nil
+	}
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	values, ok := metadata[field].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var removed []string
+	for key := range values {
+		if !matchKey(key, keys, prefixes) {
+			continue
+		}
+		removed = append(removed, fmt.Sprintf("metadata.%s[%s]", field, key))
+		if !dryRun {
+			delete(values, key)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+func matchKey(key string, keys, prefixes []string) bool {
+	for _, k := range keys {
+		if key == k {
+			return true
+		}
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// objectName returns the namespace and name of the object for the report, the patch might not have the name
+func objectName(obj map[string]interface{}) string {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return "<patch>"
+	}
+	name, _ := metadata["name"].(string)
+	if namespace, _ := metadata["namespace"].(string); namespace != "" {
+		return namespace + "/" + name
+	}
+	if name == "" {
+		return "<patch>"
+	}
+	return name
+}
+
+// reportedFields are the dry run fields which have been logged since the agent is started
+var reportedFields = &fieldSet{fields: map[string]map[dryRunField]bool{}}
+
+type fieldSet struct {
+	mu     sync.Mutex
+	fields map[string]map[dryRunField]bool
+}
+
+// add returns true if the field of the event type isn't added before
+func (s *fieldSet) add(eventType string, field dryRunField) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fields[eventType] == nil {
+		s.fields[eventType] = map[dryRunField]bool{}
+	}
+	if s.fields[eventType][field] {
+		return false
+	}
+	s.fields[eventType][field] = true
+	return true
+}
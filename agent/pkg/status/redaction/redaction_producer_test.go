@@ -0,0 +1,165 @@
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeProducer struct {
+	events []cloudevents.Event
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *fakeProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+const payload = `{
+  "create": [{
+    "apiVersion": "cluster.open-cluster-management.io/v1",
+    "kind": "ManagedCluster",
+    "metadata": {
+      "name": "cluster1",
+      "generation": 9007199254740993,
+      "annotations": {"internal.example.com/host": "api.hub.internal", "keep": "true"},
+      "labels": {"vendor": "OpenShift", "internal.example.com/zone": "a"},
+      "managedFields": [{"manager": "registration"}]
+    },
+    "status": {"conditions": [{"type": "Available", "message": "hub.internal"}, {"type": "Joined"}]}
+  }],
+  "patch": [{
+    "id": "1234",
+    "name": "cluster2",
+    "patch": {"metadata": {"annotations": {"internal.example.com/host": "api2.hub.internal"}}}
+  }]
+}`
+
+func newEvent(t *testing.T, eventType enum.EventType) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(eventType))
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(payload)))
+	return evt
+}
+
+func TestProducer(t *testing.T) {
+	defer configmap.SetRedactionRules(nil)
+
+	fake := &fakeProducer{}
+	producer := NewProducer(fake)
+
+	// the rules aren't loaded, the event is blocked
+	err := producer.SendEvent(context.Background(), newEvent(t, enum.ManagedClusterType))
+	assert.ErrorIs(t, err, ErrRulesNotLoaded)
+	assert.Empty(t, fake.events)
+	assert.Equal(t, float64(1), testutil.ToFloat64(blockedEventCounter.WithLabelValues("managedcluster")))
+	configmap.SetRedactionRules(nil)
+
+	// no rules, the payload is sent as it is
+	require.NoError(t, producer.SendEvent(context.Background(), newEvent(t, enum.ManagedClusterType)))
+	require.Len(t, fake.events, 1)
+	assert.JSONEq(t, payload, string(fake.events[0].Data()))
+
+	rules, err := configmap.ParseRedactionRules(`
+- name: hub-local
+  annotationPrefixes: [internal.example.com/]
+  labelPrefixes: [internal.example.com/]
+- name: noise
+  eventTypes: [managedcluster]
+  paths: [metadata.managedFields, "status.conditions[*].message"]
+- name: preview
+  eventTypes: [managedcluster]
+  labels: [vendor]
+  dryRun: true
+- name: other
+  eventTypes: [genericresource]
+  paths: [status]
+`)
+	require.NoError(t, err)
+	configmap.SetRedactionRules(rules)
+
+	require.NoError(t, transport.SendEventSync(context.Background(), producer, newEvent(t, enum.ManagedClusterType)))
+	require.Len(t, fake.events, 2)
+
+	var bundle struct {
+		Create []map[string]interface{} `json:"create"`
+		Patch  []map[string]interface{} `json:"patch"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(fake.events[1].Data()))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&bundle))
+
+	cluster := bundle.Create[0]
+	metadata := cluster["metadata"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"keep": "true"}, metadata["annotations"])
+	assert.Equal(t, map[string]interface{}{"vendor": "OpenShift"}, metadata["labels"])
+	assert.NotContains(t, metadata, "managedFields")
+	assert.Equal(t, json.Number("9007199254740993"), metadata["generation"])
+	assert.Equal(t, map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Available"},
+			map[string]interface{}{"type": "Joined"},
+		},
+	}, cluster["status"])
+
+	patch := bundle.Patch[0]["patch"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{}, patch["metadata"].(map[string]interface{})["annotations"])
+	assert.Equal(t, "1234", bundle.Patch[0]["id"])
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		dryRunObjectCounter.WithLabelValues("managedcluster", "preview", "metadata.labels[vendor]")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(
+		dryRunObjectCounter.WithLabelValues("genericresource", "preview", "metadata.labels[vendor]")))
+}
+
+func TestRedactEvents(t *testing.T) {
+	defer configmap.SetRedactionRules(nil)
+	configmap.SetRedactionRules([]configmap.RedactionRule{
+		{Name: "event", EventTypes: []string{"event.managedcluster"}, Paths: []string{"message", "reportingInstance"}},
+	})
+
+	// the events of the event emitter have no metadata, the items of the batch are redacted
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(enum.ManagedClusterEventType))
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`[
+	  {"eventName": "event1", "clusterName": "cluster1", "message": "api.hub.internal", "reportingInstance": "hub"},
+	  {"eventName": "event2", "clusterName": "cluster2", "message": "api.hub.internal"}
+	]`)))
+	require.NoError(t, Redact(&evt))
+	assert.JSONEq(t, `[
+	  {"eventName": "event1", "clusterName": "cluster1"},
+	  {"eventName": "event2", "clusterName": "cluster2"}
+	]`, string(evt.Data()))
+
+	// the single event
+	evt = cloudevents.NewEvent()
+	evt.SetType(string(enum.ManagedClusterEventType))
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON,
+		[]byte(`{"eventName": "event1", "message": "api.hub.internal"}`)))
+	require.NoError(t, Redact(&evt))
+	assert.JSONEq(t, `{"eventName": "event1"}`, string(evt.Data()))
+}
+
+func TestRedactInvalidPayload(t *testing.T) {
+	defer configmap.SetRedactionRules(nil)
+	configmap.SetRedactionRules([]configmap.RedactionRule{{Name: "all", Paths: []string{"status"}}})
+
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(enum.ManagedClusterType))
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte("not json")))
+	assert.Error(t, Redact(&evt))
+}
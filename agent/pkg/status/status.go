@@ -15,9 +15,15 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/filter"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/apps"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/certificate"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/events"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/genericresource"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/hive"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedcluster"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedclusteraddon"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedclusterinfo"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/managedhub"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/manifestwork"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/placement"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/policies"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -54,6 +60,36 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 		return fmt.Errorf("failed to launch managedcluster syncer: %w", err)
 	}
 
+	// managed cluster addons
+	if err := managedclusteraddon.AddManagedClusterAddOnSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add managedclusteraddon syncer: %w", err)
+	}
+
+	// managed cluster infos, the capacity and the version inventory of the managed clusters
+	if err := managedclusterinfo.AddManagedClusterInfoSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add managedclusterinfo syncer: %w", err)
+	}
+
+	// argocd applications and applicationsets, they're synced once the openshift gitops is installed
+	if err := apps.AddArgoCDApplicationSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add argocd application syncer: %w", err)
+	}
+
+	// manifestworks, the rollout status of the workloads delivered to the managed clusters
+	if err := manifestwork.AddManifestWorkSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add manifestwork syncer: %w", err)
+	}
+
+	// hive clusterdeployments and clusterpools, they're synced once the hive is installed
+	if err := hive.AddHiveSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add hive syncer: %w", err)
+	}
+
+	// generic resources configured in the agent configmap
+	if err := genericresource.AddGenericResourceSyncer(ctx, mgr, producer, periodicSyncer); err != nil {
+		return fmt.Errorf("failed to add generic resource syncer: %w", err)
+	}
+
 	// event syncer
 	err = events.AddEventSyncer(ctx, mgr, producer, periodicSyncer)
 	if err != nil {
@@ -80,6 +116,12 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 		return fmt.Errorf("failed to launch hub cluster heartbeat syncer: %w", err)
 	}
 
+	// certificate expiry of the klusterlet bootstrap, api server and transport
+	err = certificate.LaunchCertificateSyncer(mgr, producer)
+	if err != nil {
+		return fmt.Errorf("failed to launch certificate syncer: %w", err)
+	}
+
 	if agentConfig.EnableGlobalResource {
 		// placement
 		if err := placement.LaunchPlacementSyncer(ctx, mgr, agentConfig, producer); err != nil {
@@ -0,0 +1,245 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// crdCheckInterval is the interval to check whether the Argo CD CRDs are installed, e.g. the OpenShift GitOps
+// operator is installed after the agent
+const crdCheckInterval = 30 * time.Second
+
+var log = logger.DefaultZapLogger()
+
+var (
+	argoApplicationGVK    = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"}
+	argoApplicationSetGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"}
+)
+
+var addedArgoCDApplicationSyncer = false
+
+// argoCDApplicationSyncer syncs the Argo CD Applications and ApplicationSets with a single emitter, the kinds are
+// watched once their CRDs are installed
+type argoCDApplicationSyncer struct {
+	mgr     ctrl.Manager
+	emitter *emitters.ObjectEmitter
+
+	mu      sync.RWMutex
+	watched map[schema.GroupVersionKind]bool
+}
+
+func AddArgoCDApplicationSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedArgoCDApplicationSyncer {
+		return nil
+	}
+	s := &argoCDApplicationSyncer{
+		mgr:     mgr,
+		watched: map[schema.GroupVersionKind]bool{},
+	}
+
+	// 1. define a emitter for the applications and applicationsets, they're identified by the kind, namespace and name
+	s.emitter = emitters.NewObjectEmitter(
+		enum.ArgoCDApplicationType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(argoCDTweakFunc), // only keep the source, destination and status of the application
+		emitters.WithKeyFunc(func(obj client.Object) string {
+			return obj.GetObjectKind().GroupVersionKind().Kind + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		}),
+		emitters.WithMetadataFunc(func(obj client.Object) *genericbundle.ObjectMetadata {
+			gvk := obj.GetObjectKind().GroupVersionKind()
+			return &genericbundle.ObjectMetadata{
+				ID:        string(obj.GetUID()),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				GVK:       gvk.GroupVersion().String() + "/" + gvk.Kind,
+			}
+		}),
+	)
+
+	// 2. the controllers are added once the CRDs are installed
+	if err := mgr.Add(s); err != nil {
+		return err
+	}
+
+	// 3. register the emitter to periodic syncer
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var objects []client.Object
+			for _, gvk := range s.watchedKinds() {
+				list := &unstructured.UnstructuredList{}
+				list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+				if err := mgr.GetClient().List(ctx, list); err != nil {
+					return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+				}
+				for i := range list.Items {
+					objects = append(objects, &list.Items[i])
+				}
+			}
+			return objects, nil
+		},
+		Emitter: s.emitter,
+	})
+
+	addedArgoCDApplicationSyncer = true
+	return nil
+}
+
+func (s *argoCDApplicationSyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(crdCheckInterval)
+	defer ticker.Stop()
+	for {
+		if s.watch() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watch adds the controllers of the kinds whose CRDs are installed, it returns true if all the kinds are watched
+func (s *argoCDApplicationSyncer) watch() bool {
+	done := true
+	for _, gvk := range []schema.GroupVersionKind{argoApplicationGVK, argoApplicationSetGVK} {
+		if s.isWatched(gvk) {
+			continue
+		}
+		if _, err := s.mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			log.Debugw("skip watching the argocd resource", "kind", gvk.Kind, "error", err)
+			done = false
+			continue
+		}
+		if err := generic.AddSyncCtrl(s.mgr, "argocd-"+strings.ToLower(gvk.Kind), func() client.Object {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			return obj
+		}, s.emitter); err != nil {
+			log.Errorw("failed to watch the argocd resource", "kind", gvk.Kind, "error", err)
+			done = false
+			continue
+		}
+		s.mu.Lock()
+		s.watched[gvk] = true
+		s.mu.Unlock()
+		log.Infow("watching the argocd resource", "kind", gvk.Kind)
+	}
+	return done
+}
+
+func (s *argoCDApplicationSyncer) isWatched(gvk schema.GroupVersionKind) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watched[gvk]
+}
+
+func (s *argoCDApplicationSyncer) watchedKinds() []schema.GroupVersionKind {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	kinds := make([]schema.GroupVersionKind, 0, len(s.watched))
+	for gvk := range s.watched {
+		kinds = append(kinds, gvk)
+	}
+	return kinds
+}
+
+// argoCDTweakFunc keeps the identity, the sources, the destination and the status summary of the Application. The
+// ApplicationSet keeps the same fields of its template, and the sync and health status of the generated Applications.
+// The resources and the history of the Application are dropped, they're large and change on every sync.
+func argoCDTweakFunc(object client.Object) {
+	u, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an unstructured object: %v", object)
+		return
+	}
+
+	projected := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	projected.SetAPIVersion(u.GetAPIVersion())
+	projected.SetKind(u.GetKind())
+	projected.SetNamespace(u.GetNamespace())
+	projected.SetName(u.GetName())
+	projected.SetUID(u.GetUID())
+	projected.SetResourceVersion(u.GetResourceVersion())
+	projected.SetGeneration(u.GetGeneration())
+	projected.SetLabels(u.GetLabels())
+
+	switch u.GetKind() {
+	case argoApplicationSetGVK.Kind:
+		if template, found, _ := unstructured.NestedMap(u.Object, "spec", "template", "spec"); found {
+			_ = unstructured.SetNestedField(projected.Object, applicationSpec(template), "spec", "template", "spec")
+		}
+		copyField(u.Object, projected.Object, "status", "conditions")
+		if resources, found, _ := unstructured.NestedSlice(u.Object, "status", "resources"); found {
+			_ = unstructured.SetNestedSlice(projected.Object, applicationSetResources(resources), "status",
+				"resources")
+		}
+	default:
+		if spec, found, _ := unstructured.NestedMap(u.Object, "spec"); found {
+			_ = unstructured.SetNestedField(projected.Object, applicationSpec(spec), "spec")
+		}
+		for _, field := range []string{"sync", "health", "conditions", "summary", "reconciledAt"} {
+			copyField(u.Object, projected.Object, "status", field)
+		}
+		copyField(u.Object, projected.Object, "status", "operationState", "phase")
+		copyField(u.Object, projected.Object, "status", "operationState", "finishedAt")
+	}
+	u.Object = projected.Object
+}
+
+// applicationSpec keeps the project, sources and destination of the Application spec
+func applicationSpec(spec map[string]interface{}) map[string]interface{} {
+	projected := map[string]interface{}{}
+	for _, field := range []string{"project", "source", "sources", "destination"} {
+		if value, found := spec[field]; found {
+			projected[field] = value
+		}
+	}
+	return projected
+}
+
+// applicationSetResources keeps the name, sync and health status of the Applications generated by the ApplicationSet
+func applicationSetResources(resources []interface{}) []interface{} {
+	projected := make([]interface{}, 0, len(resources))
+	for _, resource := range resources {
+		r, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := map[string]interface{}{}
+		copyField(r, item, "namespace")
+		copyField(r, item, "name")
+		copyField(r, item, "status")
+		copyField(r, item, "health", "status")
+		projected = append(projected, item)
+	}
+	return projected
+}
+
+func copyField(from, to map[string]interface{}, fields ...string) {
+	value, found, err := unstructured.NestedFieldNoCopy(from, fields...)
+	if err != nil || !found {
+		return
+	}
+	// the value is deep copied by the setter
+	_ = unstructured.SetNestedField(to, value, fields...)
+}
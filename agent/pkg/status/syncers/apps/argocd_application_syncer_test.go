@@ -0,0 +1,85 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestArgoCDTweakFunc(t *testing.T) {
+	app := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]interface{}{
+			"namespace":     "openshift-gitops",
+			"name":          "guestbook",
+			"managedFields": []interface{}{map[string]interface{}{"manager": "argocd"}},
+		},
+		"spec": map[string]interface{}{
+			"project":     "default",
+			"source":      map[string]interface{}{"repoURL": "https://repo"},
+			"destination": map[string]interface{}{"name": "cluster1"},
+			"syncPolicy":  map[string]interface{}{"automated": map[string]interface{}{}},
+		},
+		"status": map[string]interface{}{
+			"sync":      map[string]interface{}{"status": "OutOfSync"},
+			"health":    map[string]interface{}{"status": "Healthy"},
+			"resources": []interface{}{map[string]interface{}{"kind": "Deployment"}},
+			"history":   []interface{}{map[string]interface{}{"id": int64(1)}},
+			"operationState": map[string]interface{}{
+				"phase":      "Succeeded",
+				"syncResult": map[string]interface{}{"revision": "abc"},
+			},
+		},
+	}}
+
+	argoCDTweakFunc(app)
+
+	assert.Equal(t, "guestbook", app.GetName())
+	assert.Nil(t, app.GetManagedFields())
+	assert.Equal(t, map[string]interface{}{
+		"project":     "default",
+		"source":      map[string]interface{}{"repoURL": "https://repo"},
+		"destination": map[string]interface{}{"name": "cluster1"},
+	}, app.Object["spec"])
+	assert.Equal(t, map[string]interface{}{
+		"sync":           map[string]interface{}{"status": "OutOfSync"},
+		"health":         map[string]interface{}{"status": "Healthy"},
+		"operationState": map[string]interface{}{"phase": "Succeeded"},
+	}, app.Object["status"])
+
+	appSet := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "ApplicationSet",
+		"metadata":   map[string]interface{}{"namespace": "openshift-gitops", "name": "guestbook"},
+		"spec": map[string]interface{}{
+			"generators": []interface{}{map[string]interface{}{"clusters": map[string]interface{}{}}},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "{{name}}-guestbook"},
+				"spec":     map[string]interface{}{"project": "default", "syncPolicy": map[string]interface{}{}},
+			},
+		},
+		"status": map[string]interface{}{
+			"resources": []interface{}{map[string]interface{}{
+				"kind":   "Application",
+				"name":   "cluster1-guestbook",
+				"status": "Synced",
+				"health": map[string]interface{}{"status": "Healthy", "message": "ok"},
+			}},
+		},
+	}}
+
+	argoCDTweakFunc(appSet)
+
+	assert.Equal(t, map[string]interface{}{
+		"template": map[string]interface{}{"spec": map[string]interface{}{"project": "default"}},
+	}, appSet.Object["spec"])
+	assert.Equal(t, map[string]interface{}{
+		"resources": []interface{}{map[string]interface{}{
+			"name":   "cluster1-guestbook",
+			"status": "Synced",
+			"health": map[string]interface{}{"status": "Healthy"},
+		}},
+	}, appSet.Object["status"])
+}
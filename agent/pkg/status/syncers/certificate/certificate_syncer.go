@@ -0,0 +1,274 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	klusterletNamespace        = "open-cluster-management-agent"
+	bootstrapHubKubeconfigName = "bootstrap-hub-kubeconfig"
+	hubKubeconfigSecretName    = "hub-kubeconfig-secret"
+	importSecretSuffix         = "-import"
+	importSecretKey            = "import.yaml"
+
+	dialTimeout = 10 * time.Second
+)
+
+var log = logger.DefaultZapLogger()
+
+var launchedCertificateSyncer = false
+
+// certificateSyncer scans the certificates and the tokens of the hub periodically, and sends them to the global hub
+// once they're changed. The scanned credentials are:
+//   - the bootstrap kubeconfig in the import secret of each managed cluster, it's applied by the klusterlet to
+//     register the cluster, so the cluster can't be imported or re-bootstrapped once it's expired
+//   - the bootstrap and hub kubeconfig of the klusterlet of the hub itself
+//   - the serving certificate of the API server, both the in-cluster one and the ones in the scanned kubeconfigs
+//   - the client certificate and the CA of the transport of the agent
+//
+// The secrets are read with the secret access of the agent, the secret which can't be read, e.g. it's forbidden, is
+// skipped in the scan.
+type certificateSyncer struct {
+	client     client.Client
+	reader     client.Reader
+	host       string
+	producer   transport.Producer
+	emitter    interfaces.Emitter
+	lastSynced []wiremodels.CertificateExpiry
+}
+
+func LaunchCertificateSyncer(mgr ctrl.Manager, producer transport.Producer) error {
+	if launchedCertificateSyncer {
+		return nil
+	}
+	s := &certificateSyncer{
+		client:   mgr.GetClient(),
+		reader:   mgr.GetAPIReader(), // the secrets aren't cached
+		host:     mgr.GetConfig().Host,
+		producer: producer,
+		emitter:  generic.NewGenericEmitter(enum.CertificateExpiryType),
+	}
+	if err := mgr.Add(s); err != nil {
+		return err
+	}
+	launchedCertificateSyncer = true
+	return nil
+}
+
+func (s *certificateSyncer) Start(ctx context.Context) error {
+	interval := configmap.GetSyncInterval(enum.CertificateExpiryType)
+	log.Infow("certificate scan interval", "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// reset ticker if the interval has changed
+		if resolved := configmap.GetSyncInterval(enum.CertificateExpiryType); resolved != interval {
+			interval = resolved
+			ticker.Reset(interval)
+			log.Infow("certificate scan interval has been reset", "interval", interval)
+		}
+	}
+}
+
+func (s *certificateSyncer) sync(ctx context.Context) {
+	certificates := s.scan(ctx)
+	if !reflect.DeepEqual(certificates, s.lastSynced) {
+		s.emitter.PostUpdate()
+	}
+	if !s.emitter.ShouldSend() {
+		return
+	}
+
+	data := &wiremodels.CertificateExpiries{Certificates: certificates}
+	evt, err := s.emitter.ToCloudEvent(data)
+	if err != nil {
+		log.Errorw("failed to get the certificate expiry event", "error", err)
+		return
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "emit "+enum.ShortenEventType(evt.Type()),
+		trace.WithSpanKind(trace.SpanKindProducer))
+	err = transport.SendEventSync(ctx, s.producer, *evt)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Errorw("failed to send the certificate expiry event", "error", err)
+		return
+	}
+	s.emitter.PostSend(data)
+	s.lastSynced = certificates
+}
+
+// scan returns the scanned certificates sorted by the identity, the failure of a source is logged and skipped, so the
+// others are still reported
+func (s *certificateSyncer) scan(ctx context.Context) []wiremodels.CertificateExpiry {
+	var certificates []wiremodels.CertificateExpiry
+	servers := []string{s.host}
+
+	for _, item := range []struct {
+		source string
+		name   string
+	}{
+		{wiremodels.CertificateSourceKlusterletBootstrap, bootstrapHubKubeconfigName},
+		{wiremodels.CertificateSourceHubKubeconfig, hubKubeconfigSecretName},
+	} {
+		secret, err := s.getSecret(ctx, klusterletNamespace, item.name)
+		if err != nil {
+			log.Warnw("failed to get the klusterlet secret", "name", item.name, "error", err)
+			continue
+		}
+		if secret == nil {
+			continue
+		}
+		scanned, secretServers := scanSecret(item.source, secret)
+		certificates = append(certificates, scanned...)
+		servers = append(servers, secretServers...)
+	}
+
+	clusters := &clusterv1.ManagedClusterList{}
+	if err := s.client.List(ctx, clusters); err != nil {
+		log.Warnw("failed to list the managed clusters", "error", err)
+	}
+	for _, cluster := range clusters.Items {
+		secret, err := s.getSecret(ctx, cluster.Name, cluster.Name+importSecretSuffix)
+		if err != nil {
+			log.Warnw("failed to get the import secret", "cluster", cluster.Name, "error", err)
+			continue
+		}
+		if secret == nil {
+			continue
+		}
+		scanned, importServers := scanImportSecret(secret)
+		certificates = append(certificates, scanned...)
+		servers = append(servers, importServers...)
+	}
+
+	for _, server := range unique(servers) {
+		scanned, err := scanServer(ctx, server)
+		if err != nil {
+			log.Warnw("failed to get the serving certificate of the api server", "server", server, "error", err)
+			continue
+		}
+		certificates = append(certificates, scanned...)
+	}
+
+	if agentConfig := configs.GetAgentConfig(); agentConfig != nil && agentConfig.TransportConfig != nil {
+		certificates = append(certificates, scanTransport(agentConfig.PodNamespace, agentConfig.TransportConfig)...)
+	}
+
+	sort.Slice(certificates, func(i, j int) bool {
+		a, b := certificates[i], certificates[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Key < b.Key
+	})
+	return certificates
+}
+
+// getSecret returns nil if the secret doesn't exist
+func (s *certificateSyncer) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	err := s.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// scanServer returns the serving certificate of the api server, the server isn't verified since only the certificate
+// is read
+func scanServer(ctx context.Context, server string) ([]wiremodels.CertificateExpiry, error) {
+	address, err := serverAddress(server)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: dialTimeout},
+		Config:    &tls.Config{InsecureSkipVerify: true}, // #nosec G402
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, fmt.Errorf("the connection to %s isn't a tls connection", address)
+	}
+	peers := tlsConn.ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("no serving certificate is presented by %s", address)
+	}
+	return []wiremodels.CertificateExpiry{
+		toCertificateExpiry(wiremodels.CertificateSourceAPIServer, "", address, "serving", peers[0]),
+	}, nil
+}
+
+// serverAddress returns the host and port of the server url, the port is 443 if it isn't specified
+func serverAddress(server string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid server: %s", server)
+	}
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), "443"), nil
+	}
+	return u.Host, nil
+}
+
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package certificate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+func TestScanForbiddenSecrets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+
+	expiry := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	hubKubeconfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: klusterletNamespace, Name: hubKubeconfigSecretName},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: newCertificate(t, "system:open-cluster-management:hub1:agent", expiry),
+		},
+	}
+	cluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	// the bootstrap kubeconfig and the import secret are forbidden
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hubKubeconfig, cluster).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object,
+				opts ...client.GetOption,
+			) error {
+				if key.Name == bootstrapHubKubeconfigName || key.Name == cluster.Name+importSecretSuffix {
+					return apierrors.NewForbidden(corev1.Resource("secrets"), key.Name, nil)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+
+	s := &certificateSyncer{client: c, reader: c}
+	certificates := s.scan(t.Context())
+	require.Len(t, certificates, 1)
+	assert.Equal(t, wiremodels.CertificateSourceHubKubeconfig, certificates[0].Source)
+	assert.Equal(t, hubKubeconfigSecretName, certificates[0].Name)
+	assert.Equal(t, expiry, certificates[0].NotAfter)
+}
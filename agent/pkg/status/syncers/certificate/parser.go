@@ -0,0 +1,228 @@
+package certificate
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const kubeconfigKey = "kubeconfig"
+
+// scanSecret returns the certificates of the tls.crt, ca.crt and the kubeconfig of the secret, and the servers of
+// the kubeconfig
+func scanSecret(source string, secret *corev1.Secret) ([]wiremodels.CertificateExpiry, []string) {
+	var certificates []wiremodels.CertificateExpiry
+	for _, key := range []string{corev1.TLSCertKey, corev1.ServiceAccountRootCAKey} {
+		certificates = append(certificates,
+			parseCertificates(source, secret.Namespace, secret.Name, key, secret.Data[key])...)
+	}
+	kubeconfig, ok := secret.Data[kubeconfigKey]
+	if !ok {
+		return certificates, nil
+	}
+	scanned, servers, err := parseKubeconfig(source, secret.Namespace, secret.Name, kubeconfigKey, kubeconfig)
+	if err != nil {
+		log.Warnw("failed to parse the kubeconfig", "namespace", secret.Namespace, "name", secret.Name, "error", err)
+	}
+	return append(certificates, scanned...), servers
+}
+
+// scanImportSecret returns the certificates and the token of the bootstrap kubeconfig in the import.yaml of the
+// import secret, and the servers of the kubeconfig
+func scanImportSecret(secret *corev1.Secret) ([]wiremodels.CertificateExpiry, []string) {
+	kubeconfig, err := bootstrapKubeconfig(secret.Data[importSecretKey])
+	if err != nil {
+		log.Warnw("failed to get the bootstrap kubeconfig from the import secret", "namespace", secret.Namespace,
+			"name", secret.Name, "error", err)
+		return nil, nil
+	}
+	if kubeconfig == nil {
+		return nil, nil
+	}
+	certificates, servers, err := parseKubeconfig(wiremodels.CertificateSourceKlusterletBootstrap, secret.Namespace,
+		secret.Name, importSecretKey, kubeconfig)
+	if err != nil {
+		log.Warnw("failed to parse the bootstrap kubeconfig of the import secret", "namespace", secret.Namespace,
+			"name", secret.Name, "error", err)
+	}
+	return certificates, servers
+}
+
+// bootstrapKubeconfig returns the kubeconfig of the bootstrap secret in the manifests of the import.yaml
+func bootstrapKubeconfig(manifests []byte) ([]byte, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	for {
+		manifest := &corev1.Secret{}
+		err := decoder.Decode(manifest)
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if manifest.Kind == "Secret" && manifest.Name == bootstrapHubKubeconfigName {
+			return manifest.Data[kubeconfigKey], nil
+		}
+	}
+}
+
+// parseKubeconfig returns the CA and the client certificates, and the token of the kubeconfig, the token without the
+// expiration is skipped
+func parseKubeconfig(source, namespace, name, key string, data []byte,
+) ([]wiremodels.CertificateExpiry, []string, error) {
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var certificates []wiremodels.CertificateExpiry
+	var servers []string
+	for _, clusterName := range sortedKeys(config.Clusters) {
+		cluster := config.Clusters[clusterName]
+		certificates = append(certificates,
+			parseCertificates(source, namespace, name, key+"/ca", cluster.CertificateAuthorityData)...)
+		servers = append(servers, cluster.Server)
+	}
+	for _, userName := range sortedKeys(config.AuthInfos) {
+		authInfo := config.AuthInfos[userName]
+		certificates = append(certificates,
+			parseCertificates(source, namespace, name, key+"/client", authInfo.ClientCertificateData)...)
+		if authInfo.Token == "" {
+			continue
+		}
+		token, err := parseToken(source, namespace, name, key+"/token", authInfo.Token)
+		if err != nil {
+			return certificates, servers, err
+		}
+		if token != nil {
+			certificates = append(certificates, *token)
+		}
+	}
+	return certificates, servers, nil
+}
+
+// parseCertificates returns the certificates of the pem data, the certificates after the first one are suffixed by
+// their index in the key
+func parseCertificates(source, namespace, name, key string, data []byte) []wiremodels.CertificateExpiry {
+	var certificates []wiremodels.CertificateExpiry
+	for rest := data; len(rest) > 0; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			log.Warnw("failed to parse the certificate", "namespace", namespace, "name", name, "key", key,
+				"error", err)
+			continue
+		}
+		certKey := key
+		if len(certificates) > 0 {
+			certKey = fmt.Sprintf("%s#%d", key, len(certificates))
+		}
+		certificates = append(certificates, toCertificateExpiry(source, namespace, name, certKey, cert))
+	}
+	return certificates
+}
+
+func toCertificateExpiry(source, namespace, name, key string, cert *x509.Certificate) wiremodels.CertificateExpiry {
+	return wiremodels.CertificateExpiry{
+		Source:    source,
+		Kind:      wiremodels.CredentialKindCertificate,
+		Namespace: namespace,
+		Name:      name,
+		Key:       key,
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore.UTC(),
+		NotAfter:  cert.NotAfter.UTC(),
+	}
+}
+
+type tokenClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+	Expiry    int64  `json:"exp"`
+}
+
+// parseToken returns the expiry of the jwt token by its claims, the signature isn't verified. It returns nil if the
+// token isn't a jwt or it never expires, e.g. the legacy service account token.
+func parseToken(source, namespace, name, key, token string) (*wiremodels.CertificateExpiry, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the token claims: %w", err)
+	}
+	claims := &tokenClaims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the token claims: %w", err)
+	}
+	if claims.Expiry == 0 {
+		return nil, nil
+	}
+	notBefore := claims.NotBefore
+	if notBefore == 0 {
+		notBefore = claims.IssuedAt
+	}
+	return &wiremodels.CertificateExpiry{
+		Source:    source,
+		Kind:      wiremodels.CredentialKindToken,
+		Namespace: namespace,
+		Name:      name,
+		Key:       key,
+		Subject:   claims.Subject,
+		Issuer:    claims.Issuer,
+		NotBefore: time.Unix(notBefore, 0).UTC(),
+		NotAfter:  time.Unix(claims.Expiry, 0).UTC(),
+	}, nil
+}
+
+// scanTransport returns the client certificate and the CA of the kafka or the restful transport
+func scanTransport(namespace string, config *transport.TransportInternalConfig) []wiremodels.CertificateExpiry {
+	var conn transport.TransportCerticiate
+	switch {
+	case config.KafkaCredential != nil:
+		conn = config.KafkaCredential
+	case config.RestfulCredential != nil:
+		conn = config.RestfulCredential
+	default:
+		return nil
+	}
+	source := wiremodels.CertificateSourceTransport
+	certificates := parseCertificates(source, namespace, config.TransportType, "client.crt",
+		[]byte(conn.GetClientCert()))
+	return append(certificates, parseCertificates(source, namespace, config.TransportType, "ca.crt",
+		[]byte(conn.GetCACert()))...)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
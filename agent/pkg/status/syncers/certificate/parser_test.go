@@ -0,0 +1,167 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+func newCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func newToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"RS256"}`)) + "." + encode([]byte(claims)) + "." + encode([]byte("signature"))
+}
+
+func newKubeconfig(ca []byte, token string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://api.hub.example.com:6443
+    certificate-authority-data: %s
+users:
+- name: bootstrap
+  user:
+    token: %s
+contexts:
+- name: bootstrap
+  context:
+    cluster: hub
+    user: bootstrap
+current-context: bootstrap
+`, base64.StdEncoding.EncodeToString(ca), token)
+}
+
+func TestScanImportSecret(t *testing.T) {
+	expiry := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	ca := newCertificate(t, "kube-apiserver-lb-signer", expiry)
+	token := newToken(fmt.Sprintf(`{"iss":"https://kubernetes.default.svc","sub":"system:serviceaccount:`+
+		`open-cluster-management-agent:cluster1-bootstrap-sa","iat":%d,"exp":%d}`,
+		expiry.Add(-24*time.Hour).Unix(), expiry.Unix()))
+	kubeconfig := base64.StdEncoding.EncodeToString([]byte(newKubeconfig(ca, token)))
+	importYAML := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: open-cluster-management-agent
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bootstrap-hub-kubeconfig
+  namespace: open-cluster-management-agent
+type: Opaque
+data:
+  kubeconfig: ` + kubeconfig + "\n"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "cluster1-import"},
+		Data:       map[string][]byte{importSecretKey: []byte(importYAML)},
+	}
+	certificates, servers := scanImportSecret(secret)
+	assert.Equal(t, []string{"https://api.hub.example.com:6443"}, servers)
+	require.Len(t, certificates, 2)
+
+	assert.Equal(t, wiremodels.CertificateSourceKlusterletBootstrap, certificates[0].Source)
+	assert.Equal(t, wiremodels.CredentialKindCertificate, certificates[0].Kind)
+	assert.Equal(t, "cluster1", certificates[0].Namespace)
+	assert.Equal(t, "cluster1-import", certificates[0].Name)
+	assert.Equal(t, "import.yaml/ca", certificates[0].Key)
+	assert.Equal(t, "CN=kube-apiserver-lb-signer", certificates[0].Subject)
+	assert.Equal(t, expiry, certificates[0].NotAfter)
+
+	assert.Equal(t, wiremodels.CredentialKindToken, certificates[1].Kind)
+	assert.Equal(t, "import.yaml/token", certificates[1].Key)
+	assert.Equal(t, "system:serviceaccount:open-cluster-management-agent:cluster1-bootstrap-sa",
+		certificates[1].Subject)
+	assert.Equal(t, expiry.Add(-24*time.Hour), certificates[1].NotBefore)
+	assert.Equal(t, expiry, certificates[1].NotAfter)
+}
+
+func TestScanSecret(t *testing.T) {
+	expiry := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	legacyToken := newToken(`{"iss":"kubernetes/serviceaccount","sub":"system:serviceaccount:default:legacy"}`)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: klusterletNamespace, Name: hubKubeconfigSecretName},
+		Data: map[string][]byte{
+			corev1.TLSCertKey: append(newCertificate(t, "system:open-cluster-management:cluster1:agent", expiry),
+				newCertificate(t, "intermediate", expiry.Add(time.Hour))...),
+			kubeconfigKey: []byte(newKubeconfig(newCertificate(t, "ca", expiry.Add(2*time.Hour)), legacyToken)),
+		},
+	}
+	certificates, servers := scanSecret(wiremodels.CertificateSourceHubKubeconfig, secret)
+	assert.Equal(t, []string{"https://api.hub.example.com:6443"}, servers)
+
+	// the legacy token never expires, so it's skipped
+	require.Len(t, certificates, 3)
+	assert.Equal(t, "tls.crt", certificates[0].Key)
+	assert.Equal(t, expiry, certificates[0].NotAfter)
+	assert.Equal(t, "tls.crt#1", certificates[1].Key)
+	assert.Equal(t, "CN=intermediate", certificates[1].Subject)
+	assert.Equal(t, "kubeconfig/ca", certificates[2].Key)
+}
+
+func TestScanTransport(t *testing.T) {
+	expiry := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	certificates := scanTransport("multicluster-global-hub-agent", &transport.TransportInternalConfig{
+		TransportType: string(transport.Kafka),
+		KafkaCredential: &transport.KafkaConfig{
+			ClientCert: string(newCertificate(t, "hub1-kafka-user", expiry)),
+			CACert:     string(newCertificate(t, "cluster-ca", expiry.Add(time.Hour))),
+		},
+	})
+	require.Len(t, certificates, 2)
+	assert.Equal(t, wiremodels.CertificateSourceTransport, certificates[0].Source)
+	assert.Equal(t, "kafka", certificates[0].Name)
+	assert.Equal(t, "client.crt", certificates[0].Key)
+	assert.Equal(t, "CN=hub1-kafka-user", certificates[0].Subject)
+	assert.Equal(t, "ca.crt", certificates[1].Key)
+}
+
+func TestScanServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	certificates, err := scanServer(context.Background(), server.URL)
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, wiremodels.CertificateSourceAPIServer, certificates[0].Source)
+	assert.Equal(t, server.Listener.Addr().String(), certificates[0].Name)
+	assert.Equal(t, server.Certificate().NotAfter.UTC(), certificates[0].NotAfter)
+
+	address, err := serverAddress("https://api.hub.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "api.hub.example.com:443", address)
+}
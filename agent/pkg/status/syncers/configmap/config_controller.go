@@ -76,9 +76,36 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ManagedClusterEventType))
 	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ManagedClusterEventType))
 
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.GenericResourceType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.GenericResourceType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ManagedClusterAddOnType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ManagedClusterAddOnType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ManagedClusterInfoType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ManagedClusterInfoType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ArgoCDApplicationType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ArgoCDApplicationType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ManifestWorkType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ManifestWorkType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ClusterDeploymentType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ClusterDeploymentType))
+
+	c.setSyncInterval(agentConfigMap, GetResyncKey(enum.ClusterPoolType))
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.ClusterPoolType))
+
+	c.setSyncInterval(agentConfigMap, GetSyncKey(enum.CertificateExpiryType))
+
 	// Set the agent configs
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
+	c.setAgentConfig(agentConfigMap, MergePatchKey)
+
+	c.setGenericResources(agentConfigMap)
+	c.setRedactionRules(agentConfigMap)
 
 	logLevel := agentConfigMap.Data[string(AgentLogLevelKey)]
 	if logLevel != "" {
@@ -105,11 +132,36 @@ func (c *hubOfHubsConfigController) setSyncInterval(configMap *corev1.ConfigMap,
 	SetInterval(key, interval)
 }
 
+func (c *hubOfHubsConfigController) setGenericResources(configMap *corev1.ConfigMap) {
+	data, found := configMap.Data[GenericResourcesKey]
+	if !found {
+		SetGenericResources(nil)
+		return
+	}
+	resources, err := ParseGenericResources(data)
+	if err != nil {
+		// keep syncing the previous resources until the configmap is fixed
+		c.log.Errorf("failed to parse %s: %v", GenericResourcesKey, err)
+		return
+	}
+	SetGenericResources(resources)
+}
+
+func (c *hubOfHubsConfigController) setRedactionRules(configMap *corev1.ConfigMap) {
+	// keep redacting with the previous rules until the configmap is fixed, the events aren't sent if there is no
+	// previous rule
+	if err := setRedactionRules(configMap); err != nil {
+		c.log.Errorf("failed to set the redaction rules: %v", err)
+	}
+}
+
 func (c *hubOfHubsConfigController) setAgentConfig(configMap *corev1.ConfigMap, configKey string) {
 	val, found := configMap.Data[string(configKey)]
 	if !found {
 		c.log.Info(fmt.Sprintf("%s not defined in agentConfig, using default value", configKey))
 		return
 	}
+	agentConfigsMutex.Lock()
+	defer agentConfigsMutex.Unlock()
 	agentConfigs[configKey] = AgentConfigValue(val)
 }
@@ -19,6 +19,7 @@ var (
 		GetSyncKey(enum.HubClusterInfoType):      60 * time.Second,
 		GetSyncKey(enum.HubClusterHeartbeatType): 60 * time.Second,
 		GetSyncKey(enum.ManagedClusterEventType): defaultSyncInterval,
+		GetSyncKey(enum.CertificateExpiryType):   time.Hour,
 	}
 
 	// Default resync intervals for various event types.
@@ -33,16 +34,22 @@ var (
 	agentConfigs = map[string]AgentConfigValue{
 		AgentAggregationKey:  AggregationFull,
 		EnableLocalPolicyKey: EnableLocalPolicyTrue,
+		MergePatchKey:        MergePatchFalse,
 	}
 
 	// Mutex to protect concurrent access to the intervals maps
 	intervalsMutex sync.RWMutex
+	// Mutex to protect concurrent access to the agent configs, they're read by the emitters
+	agentConfigsMutex sync.RWMutex
 )
 
 const (
 	AgentAggregationKey  = "aggregationLevel"
 	EnableLocalPolicyKey = "enableLocalPolicies"
 	AgentLogLevelKey     = "logLevel"
+	// MergePatchKey enables sending the updates of the managed clusters and generic resources as the JSON merge
+	// patches, instead of the whole objects
+	MergePatchKey = "mergePatch"
 )
 
 type AgentConfigValue string
@@ -52,6 +59,8 @@ const (
 	AggregationMinimal     AgentConfigValue = "minimal"
 	EnableLocalPolicyTrue  AgentConfigValue = "true"
 	EnableLocalPolicyFalse AgentConfigValue = "false"
+	MergePatchTrue         AgentConfigValue = "true"
+	MergePatchFalse        AgentConfigValue = "false"
 )
 
 // ResolveSyncIntervalFunc is a function for resolving corresponding sync interval from SyncIntervals data structure.
@@ -91,13 +100,24 @@ func GetEventDuration() time.Duration {
 }
 
 func GetAggregationLevel() AgentConfigValue {
+	agentConfigsMutex.RLock()
+	defer agentConfigsMutex.RUnlock()
 	return agentConfigs[AgentAggregationKey]
 }
 
 func GetEnableLocalPolicy() AgentConfigValue {
+	agentConfigsMutex.RLock()
+	defer agentConfigsMutex.RUnlock()
 	return agentConfigs[EnableLocalPolicyKey]
 }
 
+// IsMergePatchEnabled returns true if the updates are sent as the JSON merge patches
+func IsMergePatchEnabled() bool {
+	agentConfigsMutex.RLock()
+	defer agentConfigsMutex.RUnlock()
+	return agentConfigs[MergePatchKey] == MergePatchTrue
+}
+
 func GetResyncInterval(eventType enum.EventType) time.Duration {
 	intervalsMutex.RLock()
 	defer intervalsMutex.RUnlock()
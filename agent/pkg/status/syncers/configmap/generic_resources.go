@@ -0,0 +1,100 @@
+package configmap
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// GenericResourcesKey is the key of the custom resources synced to the global hub in the agent configmap, e.g.
+//
+//	genericResources: |
+//	  - group: cert-manager.io
+//	    version: v1
+//	    kind: Certificate
+//	    labelSelector: app=frontend
+//	    fields:
+//	      ready: .status.conditions[?(@.type=="Ready")].status
+//	      notAfter: .status.notAfter
+const GenericResourcesKey = "genericResources"
+
+// GenericResource selects the objects of a kind synced by the generic resource syncer
+type GenericResource struct {
+	Group   string `json:"group,omitempty"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	// LabelSelector filters the objects by the labels, all the objects of the kind are synced if it's empty
+	LabelSelector string `json:"labelSelector,omitempty"`
+	// Fields projects the objects into the named values of the JSONPath expressions, the whole status is synced if
+	// it's empty
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func (r GenericResource) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: r.Group, Version: r.Version, Kind: r.Kind}
+}
+
+var (
+	genericResources      []GenericResource
+	genericResourcesMutex sync.RWMutex
+)
+
+// GetGenericResources returns the custom resources configured in the agent configmap
+func GetGenericResources() []GenericResource {
+	genericResourcesMutex.RLock()
+	defer genericResourcesMutex.RUnlock()
+	return append([]GenericResource{}, genericResources...)
+}
+
+func SetGenericResources(resources []GenericResource) {
+	genericResourcesMutex.Lock()
+	defer genericResourcesMutex.Unlock()
+	genericResources = resources
+}
+
+// ParseGenericResources parses and validates the custom resources of the agent configmap
+func ParseGenericResources(data string) ([]GenericResource, error) {
+	resources := []GenericResource{}
+	if err := yaml.Unmarshal([]byte(data), &resources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the generic resources: %w", err)
+	}
+
+	existing := map[schema.GroupVersionKind]bool{}
+	for _, resource := range resources {
+		gvk := resource.GroupVersionKind()
+		if resource.Version == "" || resource.Kind == "" {
+			return nil, fmt.Errorf("the version and kind of the generic resource are required: %s", gvk)
+		}
+		if existing[gvk] {
+			return nil, fmt.Errorf("the generic resource is duplicated: %s", gvk)
+		}
+		existing[gvk] = true
+
+		if _, err := labels.Parse(resource.LabelSelector); err != nil {
+			return nil, fmt.Errorf("invalid label selector of the generic resource %s: %w", gvk, err)
+		}
+		for name, path := range resource.Fields {
+			if _, err := ParseFieldPath(name, path); err != nil {
+				return nil, fmt.Errorf("invalid field %s of the generic resource %s: %w", name, gvk, err)
+			}
+		}
+	}
+	return resources, nil
+}
+
+// ParseFieldPath parses the JSONPath expression of the field, the braces are optional, e.g. ".status.phase"
+func ParseFieldPath(name, path string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(path, "{") {
+		path = "{" + path + "}"
+	}
+	j := jsonpath.New(name).AllowMissingKeys(true)
+	if err := j.Parse(path); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
@@ -0,0 +1,43 @@
+package configmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGenericResources(t *testing.T) {
+	resources, err := ParseGenericResources(`
+- group: cert-manager.io
+  version: v1
+  kind: Certificate
+  labelSelector: app=frontend
+  fields:
+    ready: .status.conditions[?(@.type=="Ready")].status
+    notAfter: "{.status.notAfter}"
+- version: v1
+  kind: ConfigMap
+`)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "cert-manager.io/v1, Kind=Certificate", resources[0].GroupVersionKind().String())
+	assert.Len(t, resources[0].Fields, 2)
+	assert.Empty(t, resources[1].LabelSelector)
+
+	SetGenericResources(resources)
+	assert.Equal(t, resources, GetGenericResources())
+	SetGenericResources(nil)
+	assert.Empty(t, GetGenericResources())
+
+	cases := map[string]string{
+		"missing kind":   "- version: v1",
+		"duplicated":     "- {version: v1, kind: Secret}\n- {version: v1, kind: Secret}",
+		"label selector": "- {version: v1, kind: Secret, labelSelector: 'app in ('}",
+		"field":          "- {version: v1, kind: Secret, fields: {type: '.type[?('}}",
+	}
+	for name, data := range cases {
+		_, err := ParseGenericResources(data)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,187 @@
+package configmap
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// RedactionRulesKey is the key of the rules to strip the fields of the objects before they are sent to the global hub
+// in the agent configmap, e.g.
+//
+//	redactionRules: |
+//	  - name: hub-local
+//	    annotationPrefixes:
+//	    - internal.example.com/
+//	  - name: noise
+//	    eventTypes: [managedcluster, genericresource]
+//	    paths:
+//	    - metadata.managedFields
+//	    - status.conditions[*].message
+//	    dryRun: true
+const RedactionRulesKey = "redactionRules"
+
+// listWildcard is the path segment matching all the items of a list
+const listWildcard = "[*]"
+
+// RedactionRule removes the fields of the objects in the events, the object is any value with the metadata in the
+// event payload, or the JSON merge patch of the object
+type RedactionRule struct {
+	// Name identifies the rule in the dry run report
+	Name string `json:"name,omitempty"`
+	// EventTypes are the event types applied with the rule, e.g. "managedcluster", all the event types if it's empty
+	EventTypes []string `json:"eventTypes,omitempty"`
+	// Paths are the dot separated fields of the object, the "[*]" suffix matches all the items of the list, e.g.
+	// "status.conditions[*].message"
+	Paths              []string `json:"paths,omitempty"`
+	Annotations        []string `json:"annotations,omitempty"`
+	AnnotationPrefixes []string `json:"annotationPrefixes,omitempty"`
+	Labels             []string `json:"labels,omitempty"`
+	LabelPrefixes      []string `json:"labelPrefixes,omitempty"`
+	// DryRun only reports the fields matched by the rule, instead of removing them
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// AppliesTo returns true if the rule is applied to the event type, the type could be the full or short name
+func (r RedactionRule) AppliesTo(eventType string) bool {
+	if len(r.EventTypes) == 0 {
+		return true
+	}
+	shortType := enum.ShortenEventType(eventType)
+	for _, t := range r.EventTypes {
+		if t == eventType || t == shortType {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	redactionRules []RedactionRule
+	// redactionRulesLoaded is false until the rules are loaded from the agent configmap, or the configmap is found
+	// without the rules
+	redactionRulesLoaded bool
+	redactionRulesMutex  sync.RWMutex
+)
+
+// GetRedactionRules returns the redaction rules configured in the agent configmap
+func GetRedactionRules() []RedactionRule {
+	redactionRulesMutex.RLock()
+	defer redactionRulesMutex.RUnlock()
+	return append([]RedactionRule{}, redactionRules...)
+}
+
+// RedactionRulesLoaded returns true once the rules are loaded, the events mustn't be sent before it, otherwise the
+// fields might leave the hub unredacted
+func RedactionRulesLoaded() bool {
+	redactionRulesMutex.RLock()
+	defer redactionRulesMutex.RUnlock()
+	return redactionRulesLoaded
+}
+
+func SetRedactionRules(rules []RedactionRule) {
+	redactionRulesMutex.Lock()
+	defer redactionRulesMutex.Unlock()
+	redactionRules = rules
+	redactionRulesLoaded = true
+}
+
+// LoadRedactionRules reads the rules from the agent configmap directly, so that they're loaded before the syncers
+// send any event, rather than waiting for the first reconcile of the configmap controller. There is no rule if the
+// configmap doesn't exist.
+func LoadRedactionRules(ctx context.Context, c client.Reader, namespace string) error {
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: constants.GHAgentConfigCMName}, configMap)
+	if apierrors.IsNotFound(err) {
+		SetRedactionRules(nil)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the agent configmap: %w", err)
+	}
+	return setRedactionRules(configMap)
+}
+
+// setRedactionRules keeps the previous rules if the rules of the configmap are invalid, so the fields aren't leaked
+func setRedactionRules(configMap *corev1.ConfigMap) error {
+	data, found := configMap.Data[RedactionRulesKey]
+	if !found {
+		SetRedactionRules(nil)
+		return nil
+	}
+	rules, err := ParseRedactionRules(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", RedactionRulesKey, err)
+	}
+	SetRedactionRules(rules)
+	return nil
+}
+
+// ParseRedactionRules parses and validates the redaction rules of the agent configmap
+func ParseRedactionRules(data string) ([]RedactionRule, error) {
+	rules := []RedactionRule{}
+	if err := yaml.Unmarshal([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the redaction rules: %w", err)
+	}
+
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rules[i].Name = fmt.Sprintf("rule-%d", i)
+		}
+		if len(rule.Paths)+len(rule.Annotations)+len(rule.AnnotationPrefixes)+len(rule.Labels)+
+			len(rule.LabelPrefixes) == 0 {
+			return nil, fmt.Errorf("the redaction rule %s doesn't remove any field", rules[i].Name)
+		}
+		for _, path := range rule.Paths {
+			if _, err := ParseRedactionPath(path); err != nil {
+				return nil, fmt.Errorf("invalid path of the redaction rule %s: %w", rules[i].Name, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// ParseRedactionPath splits the path into the field names and the list wildcards, e.g. "status.conditions[*].message"
+// is ["status", "conditions", "[*]", "message"]. The leading "." and the JSONPath braces are optional.
+func ParseRedactionPath(path string) ([]string, error) {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(path), "{"), "}")
+	trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "$"), ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("the path is empty: %q", path)
+	}
+
+	segments := []string{}
+	for _, field := range strings.Split(trimmed, ".") {
+		name := field
+		wildcards := 0
+		for strings.HasSuffix(name, listWildcard) {
+			name = strings.TrimSuffix(name, listWildcard)
+			wildcards++
+		}
+		if name == "" || strings.ContainsAny(name, "[]*?@") {
+			return nil, fmt.Errorf("only the field names and the list wildcards are supported: %q", path)
+		}
+		segments = append(segments, name)
+		for j := 0; j < wildcards; j++ {
+			segments = append(segments, listWildcard)
+		}
+	}
+	if segments[len(segments)-1] == listWildcard {
+		return nil, fmt.Errorf("the path must end with a field name: %q", path)
+	}
+	return segments, nil
+}
+
+// IsListWildcard returns true if the segment of the parsed path matches all the items of a list
+func IsListWildcard(segment string) bool {
+	return segment == listWildcard
+}
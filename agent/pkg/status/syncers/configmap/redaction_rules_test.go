@@ -0,0 +1,88 @@
+package configmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestParseRedactionRules(t *testing.T) {
+	rules, err := ParseRedactionRules(`
+- name: hub-local
+  annotationPrefixes:
+  - internal.example.com/
+- eventTypes: [managedcluster]
+  paths:
+  - metadata.managedFields
+  - "{.status.conditions[*].message}"
+  dryRun: true
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "hub-local", rules[0].Name)
+	assert.Equal(t, "rule-1", rules[1].Name)
+	assert.True(t, rules[1].DryRun)
+
+	assert.True(t, rules[0].AppliesTo(string(enum.GenericResourceType)))
+	assert.True(t, rules[1].AppliesTo(string(enum.ManagedClusterType)))
+	assert.False(t, rules[1].AppliesTo(string(enum.GenericResourceType)))
+
+	SetRedactionRules(rules)
+	assert.Equal(t, rules, GetRedactionRules())
+	SetRedactionRules(nil)
+	assert.Empty(t, GetRedactionRules())
+
+	cases := map[string]string{
+		"no field":      "- name: empty",
+		"empty path":    "- paths: ['.']",
+		"filter":        "- paths: ['.status.conditions[?(@.type==\"Ready\")].message']",
+		"wildcard end":  "- paths: ['.status.conditions[*]']",
+		"invalid field": "- paths: ['status..phase']",
+	}
+	for name, data := range cases {
+		_, err := ParseRedactionRules(data)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseRedactionPath(t *testing.T) {
+	segments, err := ParseRedactionPath("$.status.conditions[*].message")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"status", "conditions", "[*]", "message"}, segments)
+	assert.True(t, IsListWildcard(segments[2]))
+
+	segments, err = ParseRedactionPath("metadata.managedFields")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"metadata", "managedFields"}, segments)
+}
+
+func TestLoadRedactionRules(t *testing.T) {
+	defer SetRedactionRules(nil)
+	ctx := context.Background()
+
+	// the configmap doesn't exist, there is no rule
+	require.NoError(t, LoadRedactionRules(ctx, fake.NewClientBuilder().Build(), "default"))
+	assert.True(t, RedactionRulesLoaded())
+	assert.Empty(t, GetRedactionRules())
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: constants.GHAgentConfigCMName},
+		Data:       map[string]string{RedactionRulesKey: "- name: noise\n  paths: [metadata.managedFields]"},
+	}
+	require.NoError(t, LoadRedactionRules(ctx, fake.NewClientBuilder().WithObjects(configMap).Build(), "default"))
+	require.Len(t, GetRedactionRules(), 1)
+	assert.Equal(t, "noise", GetRedactionRules()[0].Name)
+
+	// the invalid rules don't replace the previous ones
+	configMap.Data[RedactionRulesKey] = "- name: empty"
+	assert.Error(t, LoadRedactionRules(ctx, fake.NewClientBuilder().WithObjects(configMap).Build(), "default"))
+	require.Len(t, GetRedactionRules(), 1)
+}
@@ -0,0 +1,382 @@
+package genericresource
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// refreshInterval is the interval to load the generic resources from the agent configmap, and to retry watching the
+// kinds whose CRDs aren't installed yet
+const refreshInterval = 10 * time.Second
+
+var log = logger.DefaultZapLogger()
+
+var addedGenericResourceSyncer = false
+
+// resourceRule is the compiled configmap.GenericResource
+type resourceRule struct {
+	gvk      schema.GroupVersionKind
+	selector labels.Selector
+	fields   map[string]*jsonpath.JSONPath
+}
+
+// genericResourceSyncer syncs the custom resources configured in the agent configmap with a single emitter. The kind
+// is watched once it's configured, its CRD is installed and the agent is allowed to read it. The watch of the removed
+// kind is stopped, and its objects are deleted from the database by the next resync.
+type genericResourceSyncer struct {
+	mgr     ctrl.Manager
+	emitter *emitters.ObjectEmitter
+
+	mu         sync.RWMutex
+	configured []configmap.GenericResource
+	rules      map[schema.GroupVersionKind]*resourceRule
+	// watched are the kinds being watched, the watch is stopped by the cancel function
+	watched map[schema.GroupVersionKind]context.CancelFunc
+}
+
+func AddGenericResourceSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedGenericResourceSyncer {
+		return nil
+	}
+	s := &genericResourceSyncer{
+		mgr:     mgr,
+		rules:   map[schema.GroupVersionKind]*resourceRule{},
+		watched: map[schema.GroupVersionKind]context.CancelFunc{},
+	}
+
+	// 1. define a emitter for all the kinds, the objects are identified by the kind, namespace and name
+	s.emitter = newGenericResourceEmitter(s, p)
+
+	// 2. the controllers of the kinds are added by the syncer once they are configured
+	if err := mgr.Add(s); err != nil {
+		return err
+	}
+
+	// 3. register the emitter to periodic syncer
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var objects []client.Object
+			for _, rule := range s.watchedRules() {
+				list := &unstructured.UnstructuredList{}
+				list.SetGroupVersionKind(rule.gvk.GroupVersion().WithKind(rule.gvk.Kind + "List"))
+				if err := mgr.GetClient().List(ctx, list, client.MatchingLabelsSelector{Selector: rule.selector}); err != nil {
+					return nil, fmt.Errorf("failed to list %s: %w", rule.gvk, err)
+				}
+				for i := range list.Items {
+					objects = append(objects, &list.Items[i])
+				}
+			}
+			return objects, nil
+		},
+		Emitter: s.emitter,
+	})
+
+	addedGenericResourceSyncer = true
+	return nil
+}
+
+func newGenericResourceEmitter(s *genericResourceSyncer, p transport.Producer) *emitters.ObjectEmitter {
+	return emitters.NewObjectEmitter(
+		enum.GenericResourceType,
+		p,
+		// the updates are also passed if the object isn't selected anymore, so that it's deleted from the bundle
+		emitters.WithPredicateFunc(predicate.Funcs{
+			CreateFunc:  func(e event.CreateEvent) bool { return s.selected(e.Object) },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return s.selected(e.ObjectOld) || s.selected(e.ObjectNew) },
+			DeleteFunc:  func(e event.DeleteEvent) bool { return s.selected(e.Object) },
+			GenericFunc: func(e event.GenericEvent) bool { return s.selected(e.Object) },
+		}),
+		// the deleted object might only have the namespace and name, so the target is all the objects of the kind
+		emitters.WithTargetFunc(func(obj client.Object) bool { return s.rule(obj) != nil }),
+		emitters.WithShouldDeleteFunc(func(obj client.Object) bool { return s.rule(obj) != nil && !s.selected(obj) }),
+		emitters.WithTweakFunc(s.project),
+		// the stored updates are projected as well, the bundle only keeps the configured fields of the objects
+		emitters.WithMergePatch(configmap.IsMergePatchEnabled),
+		emitters.WithSyncDelivery(),
+		emitters.WithKeyFunc(func(obj client.Object) string {
+			return gvkString(obj.GetObjectKind().GroupVersionKind()) + "/" + obj.GetNamespace() + "/" + obj.GetName()
+		}),
+		emitters.WithMetadataFunc(func(obj client.Object) *genericbundle.ObjectMetadata {
+			return &genericbundle.ObjectMetadata{
+				ID:        string(obj.GetUID()),
+				Namespace: obj.GetNamespace(),
+				Name:      obj.GetName(),
+				GVK:       gvkString(obj.GetObjectKind().GroupVersionKind()),
+			}
+		}),
+	)
+}
+
+func (s *genericResourceSyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+	for {
+		s.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh compiles the rules if the generic resources are changed, stops watching the removed kinds and watches the
+// configured kinds
+func (s *genericResourceSyncer) refresh(ctx context.Context) {
+	unwatched, removed := s.updateRules(configmap.GetGenericResources())
+	for _, gvk := range removed {
+		s.unwatch(ctx, gvk)
+	}
+	for _, gvk := range unwatched {
+		mapping, err := s.mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			log.Debugw("skip watching the generic resource", "gvk", gvk, "error", err)
+			continue
+		}
+		allowed, err := accessible(ctx, s.mgr.GetClient(), mapping.Resource)
+		if err != nil {
+			log.Warnw("failed to review the access to the generic resource", "gvk", gvk, "error", err)
+			continue
+		}
+		if !allowed {
+			log.Warnw("skip watching the generic resource, the agent isn't allowed to get, list and watch it",
+				"gvk", gvk, "resource", mapping.Resource)
+			continue
+		}
+		if err := s.watch(ctx, gvk); err != nil {
+			log.Errorw("failed to watch the generic resource", "gvk", gvk, "error", err)
+			continue
+		}
+		log.Infow("watching the generic resource", "gvk", gvk)
+	}
+}
+
+// watch starts the controller of the kind, it's stopped by unwatch
+func (s *genericResourceSyncer) watch(ctx context.Context, gvk schema.GroupVersionKind) error {
+	c, err := generic.NewUnmanagedSyncCtrl(s.mgr, controllerName(gvk), func() client.Object {
+		return newObject(gvk)
+	}, s.emitter)
+	if err != nil {
+		return err
+	}
+	watchCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		if err := c.Start(watchCtx); err != nil {
+			log.Errorw("the watch of the generic resource is stopped", "gvk", gvk, "error", err)
+		}
+	}()
+	s.mu.Lock()
+	s.watched[gvk] = cancel
+	s.mu.Unlock()
+	return nil
+}
+
+// unwatch stops the controller of the kind and removes its informer from the cache
+func (s *genericResourceSyncer) unwatch(ctx context.Context, gvk schema.GroupVersionKind) {
+	s.mu.Lock()
+	cancel, found := s.watched[gvk]
+	delete(s.watched, gvk)
+	s.mu.Unlock()
+	if !found {
+		return
+	}
+	cancel()
+	if err := s.mgr.GetCache().RemoveInformer(ctx, newObject(gvk)); err != nil {
+		log.Warnw("failed to remove the informer of the generic resource", "gvk", gvk, "error", err)
+	}
+	log.Infow("stopped watching the generic resource", "gvk", gvk)
+}
+
+// updateRules compiles the rules of the resources if they are changed, and returns the kinds which aren't watched and
+// the watched kinds which are removed
+func (s *genericResourceSyncer) updateRules(resources []configmap.GenericResource) (
+	unwatched, removed []schema.GroupVersionKind,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !reflect.DeepEqual(resources, s.configured) {
+		rules := map[schema.GroupVersionKind]*resourceRule{}
+		for _, resource := range resources {
+			rule, err := newResourceRule(resource)
+			if err != nil {
+				log.Errorw("failed to compile the generic resource", "gvk", resource.GroupVersionKind(), "error", err)
+				continue
+			}
+			rules[rule.gvk] = rule
+		}
+		s.rules = rules
+		s.configured = resources
+		log.Infow("generic resources are updated", "count", len(rules))
+	}
+	for gvk := range s.rules {
+		if _, found := s.watched[gvk]; !found {
+			unwatched = append(unwatched, gvk)
+		}
+	}
+	for gvk := range s.watched {
+		if _, found := s.rules[gvk]; !found {
+			removed = append(removed, gvk)
+		}
+	}
+	return unwatched, removed
+}
+
+func (s *genericResourceSyncer) watchedRules() []*resourceRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rules := make([]*resourceRule, 0, len(s.rules))
+	for gvk, rule := range s.rules {
+		if _, found := s.watched[gvk]; found {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (s *genericResourceSyncer) rule(obj client.Object) *resourceRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules[obj.GetObjectKind().GroupVersionKind()]
+}
+
+func (s *genericResourceSyncer) selected(obj client.Object) bool {
+	rule := s.rule(obj)
+	return rule != nil && rule.selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// project replaces the object with its identity, labels and the projected fields, or the whole status if the fields
+// aren't configured. It's invoked by the emitter with the lock, so the JSONPath parsers aren't shared by goroutines.
+func (s *genericResourceSyncer) project(obj client.Object) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an unstructured object: %v", obj)
+		return
+	}
+
+	projected := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	projected.SetAPIVersion(u.GetAPIVersion())
+	projected.SetKind(u.GetKind())
+	projected.SetNamespace(u.GetNamespace())
+	projected.SetName(u.GetName())
+	projected.SetUID(u.GetUID())
+	projected.SetResourceVersion(u.GetResourceVersion())
+	projected.SetGeneration(u.GetGeneration())
+	projected.SetLabels(u.GetLabels())
+
+	rule := s.rule(u)
+	if rule != nil && len(rule.fields) > 0 {
+		projected.Object["fields"] = rule.project(u.Object)
+	} else if status, found := u.Object["status"]; found {
+		projected.Object["status"] = status
+	}
+	u.Object = projected.Object
+}
+
+func newResourceRule(resource configmap.GenericResource) (*resourceRule, error) {
+	selector, err := labels.Parse(resource.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	rule := &resourceRule{
+		gvk:      resource.GroupVersionKind(),
+		selector: selector,
+		fields:   map[string]*jsonpath.JSONPath{},
+	}
+	for name, path := range resource.Fields {
+		j, err := configmap.ParseFieldPath(name, path)
+		if err != nil {
+			return nil, err
+		}
+		rule.fields[name] = j
+	}
+	return rule, nil
+}
+
+// project evaluates the fields of the object, the field is a list if the JSONPath matches multiple values, and it's
+// omitted if nothing is matched
+func (r *resourceRule) project(obj map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for name, j := range r.fields {
+		results, err := j.FindResults(obj)
+		if err != nil {
+			log.Debugw("failed to evaluate the field", "gvk", r.gvk, "field", name, "error", err)
+			continue
+		}
+		values := []interface{}{}
+		for _, result := range results {
+			for _, value := range result {
+				values = append(values, value.Interface())
+			}
+		}
+		switch len(values) {
+		case 0:
+		case 1:
+			fields[name] = values[0]
+		default:
+			fields[name] = values
+		}
+	}
+	return fields
+}
+
+// accessible reviews whether the agent is allowed to get, list and watch the resource
+func accessible(ctx context.Context, c client.Client, resource schema.GroupVersionResource) (bool, error) {
+	for _, verb := range []string{"get", "list", "watch"} {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Group:    resource.Group,
+					Version:  resource.Version,
+					Resource: resource.Resource,
+				},
+			},
+		}
+		if err := c.Create(ctx, review); err != nil {
+			return false, err
+		}
+		if !review.Status.Allowed {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func newObject(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	return obj
+}
+
+// gvkString formats the kind as the apiVersion and kind, e.g. "cert-manager.io/v1/Certificate" and "v1/ConfigMap"
+func gvkString(gvk schema.GroupVersionKind) string {
+	return gvk.GroupVersion().String() + "/" + gvk.Kind
+}
+
+func controllerName(gvk schema.GroupVersionKind) string {
+	return strings.ToLower(fmt.Sprintf("genericresource-%s.%s-%s", gvk.Kind, gvk.Group, gvk.Version))
+}
@@ -0,0 +1,143 @@
+package genericresource
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
+)
+
+func newCertificate(name string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"secretName": name},
+		"status": map[string]interface{}{
+			"notAfter": "2026-01-01T00:00:00Z",
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Issuing", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"})
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetUID(types.UID("uid-" + name))
+	obj.SetResourceVersion("1")
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestGenericResourceSyncer(t *testing.T) {
+	configmap.SetGenericResources([]configmap.GenericResource{
+		{
+			Group:         "cert-manager.io",
+			Version:       "v1",
+			Kind:          "Certificate",
+			LabelSelector: "app=frontend",
+			Fields: map[string]string{
+				"ready":    `.status.conditions[?(@.type=="Ready")].status`,
+				"types":    `.status.conditions[*].type`,
+				"notAfter": `{.status.notAfter}`,
+				"missing":  `.status.renewalTime`,
+			},
+		},
+		{Version: "v1", Kind: "ConfigMap"},
+	})
+	defer configmap.SetGenericResources(nil)
+
+	s := &genericResourceSyncer{
+		rules:   map[schema.GroupVersionKind]*resourceRule{},
+		watched: map[schema.GroupVersionKind]context.CancelFunc{},
+	}
+	unwatched, removed := s.updateRules(configmap.GetGenericResources())
+	assert.Len(t, unwatched, 2)
+	assert.Empty(t, removed)
+	for _, gvk := range unwatched {
+		s.watched[gvk] = func() {}
+	}
+	unwatched, _ = s.updateRules(configmap.GetGenericResources())
+	assert.Empty(t, unwatched)
+	assert.Len(t, s.watchedRules(), 2)
+
+	selected := newCertificate("frontend", map[string]string{"app": "frontend"})
+	unselected := newCertificate("backend", map[string]string{"app": "backend"})
+	assert.True(t, s.selected(selected))
+	assert.False(t, s.selected(unselected))
+
+	secret := &unstructured.Unstructured{}
+	secret.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "Secret"})
+	assert.Nil(t, s.rule(secret))
+
+	// the object is projected into the configured fields
+	projected := selected.DeepCopy()
+	s.project(projected)
+	assert.Equal(t, "cert-manager.io/v1", projected.GetAPIVersion())
+	assert.Equal(t, "frontend", projected.GetName())
+	assert.Equal(t, "1", projected.GetResourceVersion())
+	assert.Nil(t, projected.Object["spec"])
+	assert.Nil(t, projected.Object["status"])
+	assert.Equal(t, map[string]interface{}{
+		"ready":    "True",
+		"types":    []interface{}{"Issuing", "Ready"},
+		"notAfter": "2026-01-01T00:00:00Z",
+	}, projected.Object["fields"])
+
+	// the whole status is kept if the fields aren't configured
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"data":   map[string]interface{}{"key": "value"},
+		"status": map[string]interface{}{"phase": "Ready"},
+	}}
+	cm.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"})
+	cm.SetName("config")
+	s.project(cm)
+	assert.Nil(t, cm.Object["data"])
+	assert.Equal(t, map[string]interface{}{"phase": "Ready"}, cm.Object["status"])
+
+	// the watch of the kind is stopped once it's removed from the configmap
+	configmap.SetGenericResources([]configmap.GenericResource{{Version: "v1", Kind: "ConfigMap"}})
+	unwatched, removed = s.updateRules(configmap.GetGenericResources())
+	assert.Empty(t, unwatched)
+	assert.Equal(t, []schema.GroupVersionKind{selected.GroupVersionKind()}, removed)
+	assert.Nil(t, s.rule(selected))
+
+	assert.Equal(t, "cert-manager.io/v1/Certificate", gvkString(selected.GroupVersionKind()))
+	assert.Equal(t, "v1/ConfigMap", gvkString(cm.GroupVersionKind()))
+	assert.Equal(t, "genericresource-certificate.cert-manager.io-v1", controllerName(selected.GroupVersionKind()))
+}
+
+func TestAccessible(t *testing.T) {
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	var reviewed []string
+	newClient := func(denied string) client.Client {
+		reviewed = nil
+		return fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				assert.Equal(t, "certificates", review.Spec.ResourceAttributes.Resource)
+				reviewed = append(reviewed, review.Spec.ResourceAttributes.Verb)
+				review.Status.Allowed = review.Spec.ResourceAttributes.Verb != denied
+				return nil
+			},
+		}).Build()
+	}
+
+	allowed, err := accessible(t.Context(), newClient(""), certificates)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Equal(t, []string{"get", "list", "watch"}, reviewed)
+
+	// the kind isn't watched if any of the verbs is denied
+	allowed, err = accessible(t.Context(), newClient("list"), certificates)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, []string{"get", "list"}, reviewed)
+}
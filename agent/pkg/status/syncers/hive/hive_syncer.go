@@ -0,0 +1,263 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// crdCheckInterval is the interval to check whether the hive CRDs are installed
+const crdCheckInterval = 30 * time.Second
+
+var log = logger.DefaultZapLogger()
+
+var (
+	clusterDeploymentGVK = schema.GroupVersionKind{Group: "hive.openshift.io", Version: "v1", Kind: "ClusterDeployment"}
+	clusterPoolGVK       = schema.GroupVersionKind{Group: "hive.openshift.io", Version: "v1", Kind: "ClusterPool"}
+
+	hiveKinds = []schema.GroupVersionKind{clusterDeploymentGVK, clusterPoolGVK}
+)
+
+var addedHiveSyncer = false
+
+// hiveSyncer syncs the hive ClusterDeployments and ClusterPools, each kind has its own emitter and it's watched once
+// its CRD is installed
+type hiveSyncer struct {
+	mgr      ctrl.Manager
+	emitters map[schema.GroupVersionKind]*emitters.ObjectEmitter
+
+	mu      sync.RWMutex
+	watched map[schema.GroupVersionKind]bool
+}
+
+func AddHiveSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedHiveSyncer {
+		return nil
+	}
+	s := &hiveSyncer{
+		mgr:      mgr,
+		emitters: map[schema.GroupVersionKind]*emitters.ObjectEmitter{},
+		watched:  map[schema.GroupVersionKind]bool{},
+	}
+
+	// 1. define the emitters for the clusterdeployments and clusterpools
+	s.emitters[clusterDeploymentGVK] = emitters.NewObjectEmitter(
+		enum.ClusterDeploymentType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(clusterDeploymentTweakFunc), // only keep the provisioning and power state
+	)
+	s.emitters[clusterPoolGVK] = emitters.NewObjectEmitter(
+		enum.ClusterPoolType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(clusterPoolTweakFunc), // only keep the capacity of the pool
+	)
+
+	// 2. the controllers are added once the CRDs are installed
+	if err := mgr.Add(s); err != nil {
+		return err
+	}
+
+	// 3. register the emitters to periodic syncer
+	for _, gvk := range hiveKinds {
+		periodicSyncer.Register(&generic.EmitterRegistration{
+			ListFunc: func() ([]client.Object, error) {
+				if !s.isWatched(gvk) {
+					return nil, nil
+				}
+				list := &unstructured.UnstructuredList{}
+				list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+				if err := mgr.GetClient().List(ctx, list); err != nil {
+					return nil, fmt.Errorf("failed to list %s: %w", gvk.Kind, err)
+				}
+				objects := make([]client.Object, 0, len(list.Items))
+				for i := range list.Items {
+					objects = append(objects, &list.Items[i])
+				}
+				return objects, nil
+			},
+			Emitter: s.emitters[gvk],
+		})
+	}
+
+	addedHiveSyncer = true
+	return nil
+}
+
+func (s *hiveSyncer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(crdCheckInterval)
+	defer ticker.Stop()
+	for {
+		if s.watch() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watch adds the controllers of the kinds whose CRDs are installed, it returns true if all the kinds are watched
+func (s *hiveSyncer) watch() bool {
+	done := true
+	for _, gvk := range hiveKinds {
+		if s.isWatched(gvk) {
+			continue
+		}
+		if _, err := s.mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			log.Debugw("skip watching the hive resource", "kind", gvk.Kind, "error", err)
+			done = false
+			continue
+		}
+		if err := generic.AddSyncCtrl(s.mgr, "hive-"+strings.ToLower(gvk.Kind), func() client.Object {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			return obj
+		}, s.emitters[gvk]); err != nil {
+			log.Errorw("failed to watch the hive resource", "kind", gvk.Kind, "error", err)
+			done = false
+			continue
+		}
+		s.mu.Lock()
+		s.watched[gvk] = true
+		s.mu.Unlock()
+		log.Infow("watching the hive resource", "kind", gvk.Kind)
+	}
+	return done
+}
+
+func (s *hiveSyncer) isWatched(gvk schema.GroupVersionKind) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.watched[gvk]
+}
+
+// clusterDeploymentTweakFunc keeps the identity, the platform, the pool reference and the provisioning status of the
+// ClusterDeployment. The provision reference is the ClusterProvision which keeps the install log of the cluster.
+// The secret references and the probe time of the conditions are dropped.
+func clusterDeploymentTweakFunc(object client.Object) {
+	u, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an unstructured object: %v", object)
+		return
+	}
+
+	projected := project(u)
+	for _, field := range []string{"clusterName", "baseDomain", "installed", "powerState", "clusterPoolRef"} {
+		copyField(u.Object, projected.Object, "spec", field)
+	}
+	copyField(u.Object, projected.Object, "spec", "clusterMetadata", "clusterID")
+	copyField(u.Object, projected.Object, "spec", "clusterMetadata", "infraID")
+	copyField(u.Object, projected.Object, "spec", "provisioning", "imageSetRef")
+	copyPlatform(u.Object, projected.Object, "spec", "platform")
+
+	for _, field := range []string{
+		"powerState", "installRestarts", "installedTimestamp", "installVersion", "provisionRef", "apiURL",
+		"webConsoleURL",
+	} {
+		copyField(u.Object, projected.Object, "status", field)
+	}
+	if conditions, found, _ := unstructured.NestedSlice(u.Object, "status", "conditions"); found {
+		_ = unstructured.SetNestedSlice(projected.Object, tweakConditions(conditions), "status", "conditions")
+	}
+	u.Object = projected.Object
+}
+
+// clusterPoolTweakFunc keeps the identity, the platform and the capacity of the ClusterPool
+func clusterPoolTweakFunc(object client.Object) {
+	u, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not an unstructured object: %v", object)
+		return
+	}
+
+	projected := project(u)
+	for _, field := range []string{"size", "maxSize", "maxConcurrent", "runningCount", "baseDomain", "imageSetRef"} {
+		copyField(u.Object, projected.Object, "spec", field)
+	}
+	copyPlatform(u.Object, projected.Object, "spec", "platform")
+
+	for _, field := range []string{"size", "standby", "ready"} {
+		copyField(u.Object, projected.Object, "status", field)
+	}
+	if conditions, found, _ := unstructured.NestedSlice(u.Object, "status", "conditions"); found {
+		_ = unstructured.SetNestedSlice(projected.Object, tweakConditions(conditions), "status", "conditions")
+	}
+	u.Object = projected.Object
+}
+
+// project returns a new object with the identity of the given object
+func project(u *unstructured.Unstructured) *unstructured.Unstructured {
+	projected := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	projected.SetAPIVersion(u.GetAPIVersion())
+	projected.SetKind(u.GetKind())
+	projected.SetNamespace(u.GetNamespace())
+	projected.SetName(u.GetName())
+	projected.SetUID(u.GetUID())
+	projected.SetResourceVersion(u.GetResourceVersion())
+	projected.SetGeneration(u.GetGeneration())
+	projected.SetCreationTimestamp(u.GetCreationTimestamp())
+	projected.SetDeletionTimestamp(u.GetDeletionTimestamp())
+	projected.SetLabels(u.GetLabels())
+	return projected
+}
+
+// copyPlatform keeps the platform type and the region, e.g. {"aws": {"region": "us-east-1"}}, the credentials
+// aren't copied
+func copyPlatform(from, to map[string]interface{}, fields ...string) {
+	platform, found, _ := unstructured.NestedMap(from, fields...)
+	if !found {
+		return
+	}
+	projected := map[string]interface{}{}
+	for platformType, value := range platform {
+		item := map[string]interface{}{}
+		if spec, ok := value.(map[string]interface{}); ok {
+			copyField(spec, item, "region")
+		}
+		projected[platformType] = item
+	}
+	_ = unstructured.SetNestedMap(to, projected, fields...)
+}
+
+// tweakConditions removes the probe time of the conditions, it's updated on every check of hive
+func tweakConditions(conditions []interface{}) []interface{} {
+	tweaked := make([]interface{}, 0, len(conditions))
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(c, "lastProbeTime")
+		tweaked = append(tweaked, c)
+	}
+	return tweaked
+}
+
+func copyField(from, to map[string]interface{}, fields ...string) {
+	value, found, err := unstructured.NestedFieldNoCopy(from, fields...)
+	if err != nil || !found {
+		return
+	}
+	// the value is deep copied by the setter
+	_ = unstructured.SetNestedField(to, value, fields...)
+}
@@ -0,0 +1,98 @@
+package hive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestClusterDeploymentTweakFunc(t *testing.T) {
+	cd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "hive.openshift.io/v1",
+		"kind":       "ClusterDeployment",
+		"metadata": map[string]interface{}{
+			"namespace":     "cluster1",
+			"name":          "cluster1",
+			"finalizers":    []interface{}{"hive.openshift.io/deprovision"},
+			"managedFields": []interface{}{map[string]interface{}{"manager": "hive"}},
+		},
+		"spec": map[string]interface{}{
+			"clusterName": "cluster1",
+			"baseDomain":  "example.com",
+			"platform": map[string]interface{}{
+				"aws": map[string]interface{}{
+					"region":               "us-east-1",
+					"credentialsSecretRef": map[string]interface{}{"name": "aws-creds"},
+				},
+			},
+			"provisioning": map[string]interface{}{
+				"imageSetRef":            map[string]interface{}{"name": "img4.16"},
+				"installConfigSecretRef": map[string]interface{}{"name": "install-config"},
+			},
+			"pullSecretRef": map[string]interface{}{"name": "pull-secret"},
+		},
+		"status": map[string]interface{}{
+			"installRestarts": int64(2),
+			"provisionRef":    map[string]interface{}{"name": "cluster1-0-abcde"},
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":          "ProvisionFailed",
+					"status":        "True",
+					"reason":        "InvalidInstallConfig",
+					"lastProbeTime": "2025-01-01T00:00:00Z",
+				},
+			},
+		},
+	}}
+
+	clusterDeploymentTweakFunc(cd)
+
+	assert.Nil(t, cd.GetFinalizers())
+	assert.Nil(t, cd.GetManagedFields())
+	assert.Equal(t, map[string]interface{}{
+		"clusterName":  "cluster1",
+		"baseDomain":   "example.com",
+		"platform":     map[string]interface{}{"aws": map[string]interface{}{"region": "us-east-1"}},
+		"provisioning": map[string]interface{}{"imageSetRef": map[string]interface{}{"name": "img4.16"}},
+	}, cd.Object["spec"])
+	assert.Equal(t, map[string]interface{}{
+		"installRestarts": int64(2),
+		"provisionRef":    map[string]interface{}{"name": "cluster1-0-abcde"},
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "ProvisionFailed", "status": "True", "reason": "InvalidInstallConfig"},
+		},
+	}, cd.Object["status"])
+}
+
+func TestClusterPoolTweakFunc(t *testing.T) {
+	pool := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "hive.openshift.io/v1",
+		"kind":       "ClusterPool",
+		"metadata":   map[string]interface{}{"namespace": "pools", "name": "aws-pool"},
+		"spec": map[string]interface{}{
+			"size":          int64(3),
+			"maxSize":       int64(5),
+			"platform":      map[string]interface{}{"gcp": map[string]interface{}{"region": "us-east1"}},
+			"pullSecretRef": map[string]interface{}{"name": "pull-secret"},
+		},
+		"status": map[string]interface{}{
+			"size":    int64(3),
+			"ready":   int64(1),
+			"standby": int64(2),
+		},
+	}}
+
+	clusterPoolTweakFunc(pool)
+
+	assert.Equal(t, map[string]interface{}{
+		"size":     int64(3),
+		"maxSize":  int64(5),
+		"platform": map[string]interface{}{"gcp": map[string]interface{}{"region": "us-east1"}},
+	}, pool.Object["spec"])
+	assert.Equal(t, map[string]interface{}{
+		"size":    int64(3),
+		"ready":   int64(1),
+		"standby": int64(2),
+	}, pool.Object["status"])
+}
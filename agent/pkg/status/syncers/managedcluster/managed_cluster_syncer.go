@@ -11,6 +11,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
@@ -43,6 +44,9 @@ func AddManagedClusterSyncer(ctx context.Context, mgr ctrl.Manager, p transport.
 		}),
 		emitters.WithTweakFunc(clusterTweakFunc),       // clean unnecessary fields, like managedFields
 		emitters.WithMetadataFunc(clusterMetadataFunc), // extract metadata from object, use clusterClaimId as the object id
+		// send the changes of the clusters instead of the whole objects if it's enabled in the agent configmap
+		emitters.WithMergePatch(configmap.IsMergePatchEnabled),
+		emitters.WithSyncDelivery(),
 	)
 
 	// 2. add the emitter to controller
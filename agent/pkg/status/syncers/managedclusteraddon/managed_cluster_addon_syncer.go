@@ -0,0 +1,86 @@
+package managedclusteraddon
+
+import (
+	"context"
+
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var log = logger.DefaultZapLogger()
+
+var addedManagedClusterAddOnSyncer = false
+
+// AddManagedClusterAddOnSyncer syncs the health of the addons of all the managed clusters, the addon is identified by
+// the cluster namespace and the addon name
+func AddManagedClusterAddOnSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedManagedClusterAddOnSyncer {
+		return nil
+	}
+	// 1. define a emitter for the addons
+	addonEmitter := emitters.NewObjectEmitter(
+		enum.ManagedClusterAddOnType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(addonTweakFunc), // only keep the health of the addon
+	)
+
+	// 2. add the emitter to controller
+	if err := generic.AddSyncCtrl(
+		mgr,
+		"managedclusteraddon",
+		func() client.Object { return &addonv1alpha1.ManagedClusterAddOn{} },
+		addonEmitter,
+	); err != nil {
+		return err
+	}
+
+	// 3. register the emitter to periodic syncer
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var addons addonv1alpha1.ManagedClusterAddOnList
+			if err := mgr.GetClient().List(ctx, &addons); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(addons.Items))
+			for i := range addons.Items {
+				objects = append(objects, &addons.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: addonEmitter,
+	})
+
+	addedManagedClusterAddOnSyncer = true
+	return nil
+}
+
+// addonTweakFunc removes the spec and the status fields unrelated to the health, like the related objects and the
+// config references, so that the updates of them aren't sent to the global hub
+func addonTweakFunc(object client.Object) {
+	addon, ok := object.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not a ManagedClusterAddOn: %v", object)
+		return
+	}
+	addon.SetManagedFields(nil)
+	addon.SetOwnerReferences(nil)
+	addon.SetFinalizers(nil)
+	addon.Spec = addonv1alpha1.ManagedClusterAddOnSpec{InstallNamespace: addon.Spec.InstallNamespace}
+	addon.Status = addonv1alpha1.ManagedClusterAddOnStatus{
+		Conditions:  addon.Status.Conditions,
+		AddOnMeta:   addon.Status.AddOnMeta,
+		Namespace:   addon.Status.Namespace,
+		HealthCheck: addon.Status.HealthCheck,
+	}
+}
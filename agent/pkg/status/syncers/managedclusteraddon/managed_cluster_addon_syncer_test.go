@@ -0,0 +1,41 @@
+package managedclusteraddon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+)
+
+func TestAddonTweakFunc(t *testing.T) {
+	conditions := []metav1.Condition{
+		{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+		{Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: metav1.ConditionFalse},
+	}
+	addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "cluster1",
+			Name:          "application-manager",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "addon-manager"}},
+		},
+		Spec: addonv1alpha1.ManagedClusterAddOnSpec{
+			InstallNamespace: "open-cluster-management-agent-addon",
+			Configs:          []addonv1alpha1.AddOnConfig{{ConfigReferent: addonv1alpha1.ConfigReferent{Name: "config"}}},
+		},
+		Status: addonv1alpha1.ManagedClusterAddOnStatus{
+			Conditions:     conditions,
+			RelatedObjects: []addonv1alpha1.ObjectReference{{Resource: "deployments", Name: "application-manager"}},
+			HealthCheck:    addonv1alpha1.HealthCheck{Mode: addonv1alpha1.HealthCheckModeLease},
+		},
+	}
+
+	addonTweakFunc(addon)
+
+	assert.Nil(t, addon.ManagedFields)
+	assert.Equal(t, "open-cluster-management-agent-addon", addon.Spec.InstallNamespace)
+	assert.Empty(t, addon.Spec.Configs)
+	assert.Equal(t, conditions, addon.Status.Conditions)
+	assert.Empty(t, addon.Status.RelatedObjects)
+	assert.Equal(t, addonv1alpha1.HealthCheckModeLease, addon.Status.HealthCheck.Mode)
+}
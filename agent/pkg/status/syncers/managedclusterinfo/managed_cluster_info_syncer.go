@@ -0,0 +1,148 @@
+package managedclusterinfo
+
+import (
+	"context"
+	"strings"
+
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var log = logger.DefaultZapLogger()
+
+var addedManagedClusterInfoSyncer = false
+
+// nodeLabelPrefixes are the node labels kept for the inventory, the region and zone of the node and its roles
+var nodeLabelPrefixes = []string{
+	"topology.kubernetes.io/",
+	"failure-domain.beta.kubernetes.io/",
+	"node-role.kubernetes.io/",
+	"node.kubernetes.io/instance-type",
+}
+
+// AddManagedClusterInfoSyncer syncs the capacity and the version of the managed clusters, the ManagedClusterInfo is
+// identified by the cluster namespace and name
+func AddManagedClusterInfoSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedManagedClusterInfoSyncer {
+		return nil
+	}
+	// 1. define a emitter for the cluster infos
+	clusterInfoEmitter := emitters.NewObjectEmitter(
+		enum.ManagedClusterInfoType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(clusterInfoTweakFunc), // only keep the nodes and the distribution version
+	)
+
+	// 2. add the emitter to controller
+	if err := generic.AddSyncCtrl(
+		mgr,
+		"managedclusterinfo",
+		func() client.Object { return &clusterinfov1beta1.ManagedClusterInfo{} },
+		clusterInfoEmitter,
+	); err != nil {
+		return err
+	}
+
+	// 3. register the emitter to periodic syncer
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var clusterInfos clusterinfov1beta1.ManagedClusterInfoList
+			if err := mgr.GetClient().List(ctx, &clusterInfos); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(clusterInfos.Items))
+			for i := range clusterInfos.Items {
+				objects = append(objects, &clusterInfos.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: clusterInfoEmitter,
+	})
+
+	addedManagedClusterInfoSyncer = true
+	return nil
+}
+
+// clusterInfoTweakFunc keeps the vendors, the distribution version and the upgrades, and the capacity, readiness and
+// topology labels of the nodes. The client config, the logging endpoint and the version history are dropped.
+func clusterInfoTweakFunc(object client.Object) {
+	clusterInfo, ok := object.(*clusterinfov1beta1.ManagedClusterInfo)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not a ManagedClusterInfo: %v", object)
+		return
+	}
+	clusterInfo.SetManagedFields(nil)
+	clusterInfo.SetOwnerReferences(nil)
+	clusterInfo.SetFinalizers(nil)
+	clusterInfo.SetAnnotations(nil)
+	clusterInfo.Spec = clusterinfov1beta1.ClusterInfoSpec{}
+
+	ocp := clusterInfo.Status.DistributionInfo.OCP
+	nodes := make([]clusterinfov1beta1.NodeStatus, 0, len(clusterInfo.Status.NodeList))
+	for _, node := range clusterInfo.Status.NodeList {
+		nodes = append(nodes, clusterinfov1beta1.NodeStatus{
+			Name:       node.Name,
+			Labels:     nodeLabels(node.Labels),
+			Capacity:   node.Capacity,
+			Conditions: node.Conditions,
+		})
+	}
+	clusterInfo.Status = clusterinfov1beta1.ClusterInfoStatus{
+		Version:     clusterInfo.Status.Version,
+		KubeVendor:  clusterInfo.Status.KubeVendor,
+		CloudVendor: clusterInfo.Status.CloudVendor,
+		ClusterID:   clusterInfo.Status.ClusterID,
+		DistributionInfo: clusterinfov1beta1.DistributionInfo{
+			Type: clusterInfo.Status.DistributionInfo.Type,
+			OCP: clusterinfov1beta1.OCPDistributionInfo{
+				Version:          ocp.Version,
+				AvailableUpdates: availableUpdates(ocp),
+				DesiredVersion:   ocp.DesiredVersion,
+				UpgradeFailed:    ocp.UpgradeFailed,
+				Channel:          ocp.Channel,
+			},
+		},
+		NodeList: nodes,
+	}
+}
+
+// availableUpdates returns the versions of the available updates, the deprecated availableUpdates is used if the
+// versionAvailableUpdates isn't reported
+func availableUpdates(ocp clusterinfov1beta1.OCPDistributionInfo) []string {
+	if len(ocp.VersionAvailableUpdates) == 0 {
+		return ocp.AvailableUpdates
+	}
+	updates := make([]string, 0, len(ocp.VersionAvailableUpdates))
+	for _, release := range ocp.VersionAvailableUpdates {
+		updates = append(updates, release.Version)
+	}
+	return updates
+}
+
+func nodeLabels(labels map[string]string) map[string]string {
+	var kept map[string]string
+	for key, value := range labels {
+		for _, prefix := range nodeLabelPrefixes {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if kept == nil {
+				kept = map[string]string{}
+			}
+			kept[key] = value
+			break
+		}
+	}
+	return kept
+}
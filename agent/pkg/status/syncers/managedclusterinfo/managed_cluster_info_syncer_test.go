@@ -0,0 +1,84 @@
+package managedclusterinfo
+
+import (
+	"testing"
+
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterInfoTweakFunc(t *testing.T) {
+	capacity := clusterinfov1beta1.ResourceList{
+		clusterinfov1beta1.ResourceCPU:    resource.MustParse("8"),
+		clusterinfov1beta1.ResourceMemory: resource.MustParse("32Gi"),
+	}
+	conditions := []clusterinfov1beta1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	clusterInfo := &clusterinfov1beta1.ManagedClusterInfo{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "cluster1",
+			Name:          "cluster1",
+			Labels:        map[string]string{clusterinfov1beta1.LabelCloudVendor: "Amazon"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "clusterinfo-controller"}},
+		},
+		Spec: clusterinfov1beta1.ClusterInfoSpec{MasterEndpoint: "https://api.cluster1.example.com:6443"},
+		Status: clusterinfov1beta1.ClusterInfoStatus{
+			Version:     "v1.27.6+f67aeb3",
+			KubeVendor:  clusterinfov1beta1.KubeVendorOpenShift,
+			CloudVendor: clusterinfov1beta1.CloudVendorAWS,
+			ConsoleURL:  "https://console.cluster1.example.com",
+			DistributionInfo: clusterinfov1beta1.DistributionInfo{
+				Type: clusterinfov1beta1.DistributionTypeOCP,
+				OCP: clusterinfov1beta1.OCPDistributionInfo{
+					Version:          "4.14.8",
+					AvailableUpdates: []string{"4.14.9"},
+					Channel:          "stable-4.15",
+					VersionAvailableUpdates: []clusterinfov1beta1.OCPVersionRelease{
+						{Version: "4.14.9", Image: "quay.io/openshift-release-dev/ocp-release:4.14.9"},
+						{Version: "4.15.2", Image: "quay.io/openshift-release-dev/ocp-release:4.15.2"},
+					},
+					VersionHistory: []clusterinfov1beta1.OCPVersionUpdateHistory{{State: "Completed", Version: "4.14.8"}},
+				},
+			},
+			NodeList: []clusterinfov1beta1.NodeStatus{{
+				Name: "worker-0",
+				Labels: map[string]string{
+					"topology.kubernetes.io/region":    "us-east-1",
+					"node-role.kubernetes.io/worker":   "",
+					"kubernetes.io/hostname":           "worker-0",
+					"node.kubernetes.io/instance-type": "m5.2xlarge",
+				},
+				Capacity:   capacity,
+				Conditions: conditions,
+			}},
+		},
+	}
+
+	clusterInfoTweakFunc(clusterInfo)
+
+	assert.Nil(t, clusterInfo.ManagedFields)
+	assert.Equal(t, "Amazon", clusterInfo.Labels[clusterinfov1beta1.LabelCloudVendor])
+	assert.Empty(t, clusterInfo.Spec.MasterEndpoint)
+	assert.Empty(t, clusterInfo.Status.ConsoleURL)
+	assert.Equal(t, "v1.27.6+f67aeb3", clusterInfo.Status.Version)
+	assert.Equal(t, clusterinfov1beta1.CloudVendorAWS, clusterInfo.Status.CloudVendor)
+
+	ocp := clusterInfo.Status.DistributionInfo.OCP
+	assert.Equal(t, "4.14.8", ocp.Version)
+	assert.Equal(t, "stable-4.15", ocp.Channel)
+	assert.Equal(t, []string{"4.14.9", "4.15.2"}, ocp.AvailableUpdates)
+	assert.Empty(t, ocp.VersionAvailableUpdates)
+	assert.Empty(t, ocp.VersionHistory)
+
+	assert.Len(t, clusterInfo.Status.NodeList, 1)
+	node := clusterInfo.Status.NodeList[0]
+	assert.Equal(t, map[string]string{
+		"topology.kubernetes.io/region":    "us-east-1",
+		"node-role.kubernetes.io/worker":   "",
+		"node.kubernetes.io/instance-type": "m5.2xlarge",
+	}, node.Labels)
+	assert.Equal(t, capacity, node.Capacity)
+	assert.Equal(t, conditions, node.Conditions)
+}
@@ -51,6 +51,8 @@ func (s *heartbeatEmitter) ToCloudEvent(data interface{}) (*cloudevents.Event, e
 	e.SetSource(configs.GetLeafHubName())
 	e.SetType(string(s.eventType))
 	e.SetExtension(eventversion.ExtVersion, s.currentVersion.String())
+	// the manager estimates the clock offset of the hub with the emit time of the heartbeat
+	transport.SetEmitTime(&e)
 	err := e.SetData(cloudevents.ApplicationJSON, genericdata.GenericObjectBundle{})
 	return &e, err
 }
@@ -0,0 +1,85 @@
+package manifestwork
+
+import (
+	"context"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+var log = logger.DefaultZapLogger()
+
+var addedManifestWorkSyncer = false
+
+// AddManifestWorkSyncer syncs the conditions and the status feedback of the manifestworks, the manifestwork is
+// identified by the cluster namespace and the name
+func AddManifestWorkSyncer(ctx context.Context, mgr ctrl.Manager, p transport.Producer,
+	periodicSyncer *generic.PeriodicSyncer,
+) error {
+	if addedManifestWorkSyncer {
+		return nil
+	}
+	// 1. define a emitter for the manifestworks
+	workEmitter := newManifestWorkEmitter(p)
+
+	// 2. add the emitter to controller
+	if err := generic.AddSyncCtrl(
+		mgr,
+		"manifestwork",
+		func() client.Object { return &workv1.ManifestWork{} },
+		workEmitter,
+	); err != nil {
+		return err
+	}
+
+	// 3. register the emitter to periodic syncer
+	periodicSyncer.Register(&generic.EmitterRegistration{
+		ListFunc: func() ([]client.Object, error) {
+			var works workv1.ManifestWorkList
+			if err := mgr.GetClient().List(ctx, &works); err != nil {
+				return nil, err
+			}
+			objects := make([]client.Object, 0, len(works.Items))
+			for i := range works.Items {
+				objects = append(objects, &works.Items[i])
+			}
+			return objects, nil
+		},
+		Emitter: workEmitter,
+	})
+
+	addedManifestWorkSyncer = true
+	return nil
+}
+
+func newManifestWorkEmitter(p transport.Producer) *emitters.ObjectEmitter {
+	return emitters.NewObjectEmitter(
+		enum.ManifestWorkType,
+		p,
+		emitters.WithPredicateFunc(predicate.NewPredicateFuncs(func(object client.Object) bool { return true })),
+		emitters.WithTweakFunc(manifestWorkTweakFunc), // only keep the status of the manifestwork
+	)
+}
+
+// manifestWorkTweakFunc removes the workload and the configs of the manifestwork, they're the full manifests and can
+// be large. The labels are kept to find the placement and the manifestworkreplicaset which the work is created by.
+func manifestWorkTweakFunc(object client.Object) {
+	work, ok := object.(*workv1.ManifestWork)
+	if !ok {
+		log.Errorf("wrong instance passed to tweak function, not a ManifestWork: %v", object)
+		return
+	}
+	work.SetManagedFields(nil)
+	work.SetOwnerReferences(nil)
+	work.SetFinalizers(nil)
+	work.SetAnnotations(nil)
+	work.Spec = workv1.ManifestWorkSpec{}
+}
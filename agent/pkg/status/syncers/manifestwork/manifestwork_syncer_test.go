@@ -0,0 +1,99 @@
+package manifestwork
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestManifestWorkTweakFunc(t *testing.T) {
+	status := workv1.ManifestWorkStatus{
+		Conditions: []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue}},
+		ResourceStatus: workv1.ManifestResourceStatus{
+			Manifests: []workv1.ManifestCondition{{
+				ResourceMeta: workv1.ManifestResourceMeta{Kind: "Deployment", Namespace: "default", Name: "nginx"},
+				StatusFeedbacks: workv1.StatusFeedbackResult{Values: []workv1.FeedbackValue{{
+					Name: "ReadyReplicas",
+				}}},
+			}},
+		},
+	}
+	work := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:     "cluster1",
+			Name:          "nginx",
+			Labels:        map[string]string{"work.open-cluster-management.io/placementname": "placement1"},
+			Annotations:   map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "work-agent"}},
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"Deployment"}`)}}},
+			},
+		},
+		Status: status,
+	}
+
+	manifestWorkTweakFunc(work)
+
+	assert.Nil(t, work.ManagedFields)
+	assert.Nil(t, work.Annotations)
+	assert.Equal(t, "placement1", work.Labels["work.open-cluster-management.io/placementname"])
+	assert.Empty(t, work.Spec.Workload.Manifests)
+	assert.Equal(t, status, work.Status)
+}
+
+type producer struct {
+	events []cloudevents.Event
+}
+
+func (p *producer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *producer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func TestManifestWorkEmitter(t *testing.T) {
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "hub1"})
+	p := &producer{}
+	emitter := newManifestWorkEmitter(p)
+
+	newWork := func(resourceVersion string) *workv1.ManifestWork {
+		return &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "cluster1", Name: "nginx", UID: "uid1", ResourceVersion: resourceVersion,
+			},
+			Spec: workv1.ManifestWorkSpec{
+				Workload: workv1.ManifestsTemplate{
+					Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: []byte(`{"kind":"Secret"}`)}}},
+				},
+			},
+		}
+	}
+
+	// the work is updated again before the bundle is sent
+	require.NoError(t, emitter.Update(newWork("1")))
+	require.NoError(t, emitter.Update(newWork("2")))
+	require.NoError(t, emitter.Send())
+
+	require.Len(t, p.events, 1)
+	bundle := genericbundle.GenericBundle[*workv1.ManifestWork]{}
+	require.NoError(t, json.Unmarshal(p.events[0].Data(), &bundle))
+	require.Len(t, bundle.Update, 1)
+	assert.Equal(t, "2", bundle.Update[0].ResourceVersion)
+	assert.Empty(t, bundle.Update[0].Spec.Workload.Manifests)
+}
@@ -120,6 +120,9 @@ func (c *StackRoxClient) DoRequest(method, path string, body string) ([]byte, *i
 
 	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.token))
 	req.Header.Add("Accept", "application/json")
+	if body != "" {
+		req.Header.Add("Content-Type", "application/json")
+	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
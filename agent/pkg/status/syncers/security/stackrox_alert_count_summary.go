@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
 )
 
@@ -34,6 +35,7 @@ var AlertsSummeryCountsRequest = stackRoxRequest{
 	Method:      "GET",
 	Path:        stackRoxAlertsSummaryCountsPath,
 	Body:        "",
+	EventType:   enum.SecurityAlertCountsType,
 	CacheStruct: &AlertsSummeryCountsResponse{},
 	GenerateFromCache: func(values ...any) (any, error) {
 		if len(values) != 4 {
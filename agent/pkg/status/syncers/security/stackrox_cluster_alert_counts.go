@@ -0,0 +1,66 @@
+package security
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// stackRoxClusterAlertsSummaryCountsPath groups the alert counts by the secured clusters, each group is named after
+// the cluster.
+const stackRoxClusterAlertsSummaryCountsPath = stackRoxAlertsSummaryCountsPath + "?group_by=CLUSTER"
+
+var ClusterAlertCountsRequest = stackRoxRequest{
+	Method:      "GET",
+	Path:        stackRoxClusterAlertsSummaryCountsPath,
+	Body:        "",
+	EventType:   enum.SecurityClusterAlertCountsType,
+	CacheStruct: &AlertsSummeryCountsResponse{},
+	GenerateFromCache: func(values ...any) (any, error) {
+		consoleURL, source, err := centralDetails(values...)
+		if err != nil {
+			return nil, err
+		}
+
+		response, ok := values[0].(*AlertsSummeryCountsResponse)
+		if !ok {
+			return nil, fmt.Errorf("cluster alert count cache struct is not of the right type")
+		}
+
+		clusterAlertCounts := wiremodels.SecurityClusterAlertCounts{
+			Clusters:  []wiremodels.ClusterAlertCounts{},
+			DetailURL: fmt.Sprintf("%s%s", consoleURL, stackRoxAlertsDetailsPath),
+			Source:    source,
+		}
+		for _, group := range response.Groups {
+			if group.Group == "" {
+				continue
+			}
+			cluster := wiremodels.ClusterAlertCounts{Cluster: group.Group}
+			for _, count := range group.Counts {
+				countInt, err := convertSringToInt(count.Count)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert %s to integer: %v", count.Count, err)
+				}
+				switch count.Severity {
+				case StackRoxResponseLowSeverity:
+					cluster.Low = *countInt
+				case StackRoxResponseMediumSeverity:
+					cluster.Medium = *countInt
+				case StackRoxResponseHighSeverity:
+					cluster.High = *countInt
+				case StackRoxResponseCriticalSeverity:
+					cluster.Critical = *countInt
+				}
+			}
+			clusterAlertCounts.Clusters = append(clusterAlertCounts.Clusters, cluster)
+		}
+		sort.Slice(clusterAlertCounts.Clusters, func(i, j int) bool {
+			return clusterAlertCounts.Clusters[i].Cluster < clusterAlertCounts.Clusters[j].Cluster
+		})
+
+		return clusterAlertCounts, nil
+	},
+}
@@ -0,0 +1,113 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const stackRoxGraphQLPath = "/api/graphql"
+
+// stackRoxClusterImageVulnerabilitiesQuery counts the distinct CVEs of the images deployed in each secured cluster.
+const stackRoxClusterImageVulnerabilitiesQuery = `query getClusterImageVulnerabilities {
+	clusters {
+		id
+		name
+		imageVulnerabilityCounter {
+			all { total fixable }
+			low { total fixable }
+			moderate { total fixable }
+			important { total fixable }
+			critical { total fixable }
+		}
+	}
+}`
+
+var stackRoxClusterImageVulnerabilitiesBody = func() string {
+	// marshaling a map of strings never fails
+	body, _ := json.Marshal(map[string]string{"query": stackRoxClusterImageVulnerabilitiesQuery})
+	return string(body)
+}()
+
+type VulnerabilityFixableCounter struct {
+	Total   int `json:"total"`
+	Fixable int `json:"fixable"`
+}
+
+type VulnerabilityCounter struct {
+	All       VulnerabilityFixableCounter `json:"all"`
+	Low       VulnerabilityFixableCounter `json:"low"`
+	Moderate  VulnerabilityFixableCounter `json:"moderate"`
+	Important VulnerabilityFixableCounter `json:"important"`
+	Critical  VulnerabilityFixableCounter `json:"critical"`
+}
+
+type ClusterVulnerabilities struct {
+	ID                        string               `json:"id"`
+	Name                      string               `json:"name"`
+	ImageVulnerabilityCounter VulnerabilityCounter `json:"imageVulnerabilityCounter"`
+}
+
+type GraphQLError struct {
+	Message string `json:"message"`
+}
+
+type ClusterVulnerabilitiesResponse struct {
+	Data struct {
+		Clusters []ClusterVulnerabilities `json:"clusters"`
+	} `json:"data"`
+	Errors []GraphQLError `json:"errors"`
+}
+
+var ImageVulnerabilitiesRequest = stackRoxRequest{
+	Method:      "POST",
+	Path:        stackRoxGraphQLPath,
+	Body:        stackRoxClusterImageVulnerabilitiesBody,
+	EventType:   enum.SecurityImageVulnerabilitiesType,
+	CacheStruct: &ClusterVulnerabilitiesResponse{},
+	GenerateFromCache: func(values ...any) (any, error) {
+		_, source, err := centralDetails(values...)
+		if err != nil {
+			return nil, err
+		}
+
+		response, ok := values[0].(*ClusterVulnerabilitiesResponse)
+		if !ok {
+			return nil, fmt.Errorf("cluster vulnerabilities cache struct is not of the right type")
+		}
+		if len(response.Errors) > 0 {
+			messages := make([]string, 0, len(response.Errors))
+			for _, e := range response.Errors {
+				messages = append(messages, e.Message)
+			}
+			return nil, fmt.Errorf("failed to query the cluster vulnerabilities: %s", strings.Join(messages, "; "))
+		}
+
+		imageVulnerabilities := wiremodels.SecurityImageVulnerabilities{
+			Clusters: []wiremodels.ClusterImageVulnerabilities{},
+			Source:   source,
+		}
+		for _, cluster := range response.Data.Clusters {
+			counter := cluster.ImageVulnerabilityCounter
+			imageVulnerabilities.Clusters = append(imageVulnerabilities.Clusters,
+				wiremodels.ClusterImageVulnerabilities{
+					Cluster:   cluster.Name,
+					ClusterID: cluster.ID,
+					Low:       counter.Low.Total,
+					Moderate:  counter.Moderate.Total,
+					Important: counter.Important.Total,
+					Critical:  counter.Critical.Total,
+					Fixable:   counter.All.Fixable,
+				})
+		}
+		sort.Slice(imageVulnerabilities.Clusters, func(i, j int) bool {
+			return imageVulnerabilities.Clusters[i].Cluster < imageVulnerabilities.Clusters[j].Cluster
+		})
+
+		return imageVulnerabilities, nil
+	},
+}
@@ -0,0 +1,145 @@
+package security
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	// stackRoxTopPolicyViolations is the number of the most violated policies kept for each cluster
+	stackRoxTopPolicyViolations = 10
+	// stackRoxMaxListedAlerts is the maximum number of the active alerts listed from the central in a poll
+	stackRoxMaxListedAlerts = 10000
+	stackRoxAlertsPath      = "/v1/alerts"
+)
+
+var stackRoxActiveAlertsPath = fmt.Sprintf("%s?query=%s&pagination.limit=%d", stackRoxAlertsPath,
+	url.QueryEscape("Violation State:ACTIVE"), stackRoxMaxListedAlerts)
+
+// stackRoxSeverityOrder is the order of the policy severities, from the least to the most severe.
+var stackRoxSeverityOrder = []string{
+	StackRoxResponseLowSeverity,
+	StackRoxResponseMediumSeverity,
+	StackRoxResponseHighSeverity,
+	StackRoxResponseCriticalSeverity,
+}
+
+type ListAlertPolicy struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Severity   string   `json:"severity"`
+	Categories []string `json:"categories"`
+}
+
+type ListAlertCommonEntityInfo struct {
+	ClusterName string `json:"clusterName"`
+	ClusterID   string `json:"clusterId"`
+	Namespace   string `json:"namespace"`
+}
+
+type ListAlert struct {
+	ID               string                    `json:"id"`
+	Policy           ListAlertPolicy           `json:"policy"`
+	CommonEntityInfo ListAlertCommonEntityInfo `json:"commonEntityInfo"`
+}
+
+type ListAlertsResponse struct {
+	Alerts []ListAlert `json:"alerts"`
+}
+
+var PolicyViolationsRequest = stackRoxRequest{
+	Method:      "GET",
+	Path:        stackRoxActiveAlertsPath,
+	Body:        "",
+	EventType:   enum.SecurityPolicyViolationsType,
+	CacheStruct: &ListAlertsResponse{},
+	GenerateFromCache: func(values ...any) (any, error) {
+		_, source, err := centralDetails(values...)
+		if err != nil {
+			return nil, err
+		}
+
+		response, ok := values[0].(*ListAlertsResponse)
+		if !ok {
+			return nil, fmt.Errorf("list alerts cache struct is not of the right type")
+		}
+
+		// Count the alerts of each policy in each cluster:
+		violationsByCluster := map[string]map[string]*wiremodels.PolicyViolation{}
+		for _, alert := range response.Alerts {
+			cluster := alert.CommonEntityInfo.ClusterName
+			if cluster == "" || alert.Policy.ID == "" {
+				continue
+			}
+			violations, ok := violationsByCluster[cluster]
+			if !ok {
+				violations = map[string]*wiremodels.PolicyViolation{}
+				violationsByCluster[cluster] = violations
+			}
+			violation, ok := violations[alert.Policy.ID]
+			if !ok {
+				violation = &wiremodels.PolicyViolation{
+					Cluster:    cluster,
+					ClusterID:  alert.CommonEntityInfo.ClusterID,
+					PolicyID:   alert.Policy.ID,
+					PolicyName: alert.Policy.Name,
+					Severity:   shortSeverity(alert.Policy.Severity),
+					Categories: alert.Policy.Categories,
+				}
+				violations[alert.Policy.ID] = violation
+			}
+			violation.Count++
+		}
+
+		// Keep the most violated policies of each cluster:
+		policyViolations := wiremodels.SecurityPolicyViolations{
+			Violations: []wiremodels.PolicyViolation{},
+			Source:     source,
+		}
+		clusters := make([]string, 0, len(violationsByCluster))
+		for cluster := range violationsByCluster {
+			clusters = append(clusters, cluster)
+		}
+		sort.Strings(clusters)
+		for _, cluster := range clusters {
+			violations := make([]wiremodels.PolicyViolation, 0, len(violationsByCluster[cluster]))
+			for _, violation := range violationsByCluster[cluster] {
+				violations = append(violations, *violation)
+			}
+			sort.Slice(violations, func(i, j int) bool {
+				if violations[i].Count != violations[j].Count {
+					return violations[i].Count > violations[j].Count
+				}
+				if si, sj := severityIndex(violations[i].Severity), severityIndex(violations[j].Severity); si != sj {
+					return si > sj
+				}
+				return violations[i].PolicyName < violations[j].PolicyName
+			})
+			if len(violations) > stackRoxTopPolicyViolations {
+				violations = violations[:stackRoxTopPolicyViolations]
+			}
+			policyViolations.Violations = append(policyViolations.Violations, violations...)
+		}
+
+		return policyViolations, nil
+	},
+}
+
+// shortSeverity converts the severity of the policy, e.g. "HIGH_SEVERITY", to the short form, e.g. "high".
+func shortSeverity(severity string) string {
+	return strings.ToLower(strings.TrimSuffix(severity, "_SEVERITY"))
+}
+
+func severityIndex(severity string) int {
+	for i, s := range stackRoxSeverityOrder {
+		if shortSeverity(s) == severity {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,13 +1,44 @@
 package security
 
+import (
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
 type stackRoxRequest struct {
 	Method            string
 	Path              string
 	Body              string
+	EventType         enum.EventType
 	CacheStruct       any
 	GenerateFromCache func(...any) (any, error)
 }
 
 var stackRoxRequests = []stackRoxRequest{
 	AlertsSummeryCountsRequest,
+	ClusterAlertCountsRequest,
+	PolicyViolationsRequest,
+	ImageVulnerabilitiesRequest,
+}
+
+// centralDetails extracts the ACS external URL and the "<namespace>/<name>" of the Central CR instance from the values
+// passed to the GenerateFromCache function after the cache struct.
+func centralDetails(values ...any) (consoleURL string, source string, err error) {
+	if len(values) != 4 {
+		return "", "", fmt.Errorf("cache struct or ACS base URL were not provided")
+	}
+	consoleURL, ok := values[1].(string)
+	if !ok {
+		return "", "", fmt.Errorf("ACS external URL is not valid")
+	}
+	namespace, ok := values[2].(string)
+	if !ok {
+		return "", "", fmt.Errorf("ACS Central namespace was not provided")
+	}
+	name, ok := values[3].(string)
+	if !ok {
+		return "", "", fmt.Errorf("ACS Central name was not provided")
+	}
+	return consoleURL, fmt.Sprintf("%s/%s", namespace, name), nil
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -432,7 +433,7 @@ func (s *StackRoxSyncer) sync(ctx context.Context, data *stackRoxData) error {
 		// }
 
 		s.currentVersion.Incr()
-		if err := s.produce(ctx, messageStruct); err != nil {
+		if err := s.produce(ctx, request.EventType, messageStruct); err != nil {
 			return fmt.Errorf("failed to produce a message to kafka: %v", err)
 		}
 		s.lastSentData = messageStruct
@@ -441,11 +442,11 @@ func (s *StackRoxSyncer) sync(ctx context.Context, data *stackRoxData) error {
 	return nil
 }
 
-func (s *StackRoxSyncer) produce(ctx context.Context, messageStruct any) error {
+func (s *StackRoxSyncer) produce(ctx context.Context, eventType enum.EventType, messageStruct any) error {
 	s.dataLock.Lock()
 	defer s.dataLock.Unlock()
 
-	evt := ToEvent(configs.GetLeafHubName(), string(enum.SecurityAlertCountsType), s.currentVersion.String())
+	evt := ToEvent(configs.GetLeafHubName(), string(eventType), s.currentVersion.String())
 	err := evt.SetData(cloudevents.ApplicationJSON, messageStruct)
 	if err != nil {
 		return fmt.Errorf("failed to get CloudEvent instance from event %s: %v", *evt, err)
@@ -485,6 +486,8 @@ func (s *StackRoxSyncer) poll(ctx context.Context, data *stackRoxData, request s
 		}
 	}
 
+	// Reset the cache struct, otherwise the fields missing in the response keep the values of the previous poll:
+	reflect.ValueOf(request.CacheStruct).Elem().SetZero()
 	err = json.Unmarshal(response, request.CacheStruct)
 	if err != nil {
 		return fmt.Errorf(
@@ -22,6 +22,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	crfakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -214,6 +215,115 @@ var _ = Describe("StackRox syncer", func() {
 				},
 			)
 
+			// RespondClusterCountsOK sends a valid response of the alert counts grouped by cluster.
+			RespondClusterCountsOK := RespondWith(
+				http.StatusOK,
+				`{
+					"groups": [
+						{
+							"group": "cluster2",
+							"counts": [
+								{
+									"severity": "HIGH_SEVERITY",
+									"count": "5"
+								}
+							]
+						},
+						{
+							"group": "cluster1",
+							"counts": [
+								{
+									"severity": "LOW_SEVERITY",
+									"count": "1"
+								},
+								{
+									"severity": "CRITICAL_SEVERITY",
+									"count": "2"
+								}
+							]
+						}
+					]
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			)
+
+			// RespondAlertsOK sends a valid response of the active alerts.
+			RespondAlertsOK := RespondWith(
+				http.StatusOK,
+				`{
+					"alerts": [
+						{
+							"id": "alert1",
+							"policy": {
+								"id": "policy1",
+								"name": "Fixable Severity at least Important",
+								"severity": "HIGH_SEVERITY",
+								"categories": ["Vulnerability Management"]
+							},
+							"commonEntityInfo": {
+								"clusterName": "cluster1",
+								"clusterId": "cluster1-id",
+								"namespace": "default"
+							}
+						},
+						{
+							"id": "alert2",
+							"policy": {
+								"id": "policy2",
+								"name": "Privileged Container",
+								"severity": "MEDIUM_SEVERITY"
+							},
+							"commonEntityInfo": {
+								"clusterName": "cluster1",
+								"clusterId": "cluster1-id",
+								"namespace": "default"
+							}
+						},
+						{
+							"id": "alert3",
+							"policy": {
+								"id": "policy2",
+								"name": "Privileged Container",
+								"severity": "MEDIUM_SEVERITY"
+							},
+							"commonEntityInfo": {
+								"clusterName": "cluster1",
+								"clusterId": "cluster1-id",
+								"namespace": "kube-system"
+							}
+						}
+					]
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			)
+
+			// RespondVulnerabilitiesOK sends a valid response of the image vulnerabilities query.
+			RespondVulnerabilitiesOK := RespondWith(
+				http.StatusOK,
+				`{
+					"data": {
+						"clusters": [{
+							"id": "cluster1-id",
+							"name": "cluster1",
+							"imageVulnerabilityCounter": {
+								"all": { "total": 10, "fixable": 6 },
+								"low": { "total": 1, "fixable": 0 },
+								"moderate": { "total": 2, "fixable": 1 },
+								"important": { "total": 3, "fixable": 2 },
+								"critical": { "total": 4, "fixable": 3 }
+							}
+						}]
+					}
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			)
+
 			// RespondUnathorized responds with an authorization error.
 			RespondUnathorized := RespondWith(http.StatusUnauthorized, nil)
 
@@ -235,6 +345,13 @@ var _ = Describe("StackRox syncer", func() {
 				Expect(err).ToNot(HaveOccurred())
 				caBytes := caBuffer.Bytes()
 
+				// The per cluster details are requested after the alert counts, so they are always routed:
+				server.RouteToHandler(http.MethodGet, "/v1/alerts", RespondAlertsOK)
+				server.RouteToHandler(http.MethodPost, "/api/graphql", CombineHandlers(
+					VerifyContentType("application/json"),
+					RespondVulnerabilitiesOK,
+				))
+
 				// Create the objects:
 				central := &unstructured.Unstructured{}
 				central.SetGroupVersionKind(centralCRGVK)
@@ -290,22 +407,86 @@ var _ = Describe("StackRox syncer", func() {
 						VerifyRequest(http.MethodGet, "/v1/alerts/summary/counts"),
 						RespondOK,
 					),
+					CombineHandlers(
+						VerifyHeaderKV("Authorization", "Bearer my-token"),
+						VerifyRequest(http.MethodGet, "/v1/alerts/summary/counts", "group_by=CLUSTER"),
+						RespondClusterCountsOK,
+					),
 				)
 
 				// Create the producer:
+				messages := map[string]int{}
 				producer := &transport.ProducerMock{
 					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
 						defer GinkgoRecover()
+						messages[evt.Type()]++
 
 						// Verify the message:
-						Expect(evt.Data()).To(MatchJSON(`{
-							"low": 1,
-							"medium": 2,
-							"high": 3,
-							"critical": 4,
-							"detail_url": "https://my-console.com/main/violations",
-							"source": "rhacs-operator/stackrox-central-services"
-						}`))
+						switch evt.Type() {
+						case string(enum.SecurityAlertCountsType):
+							Expect(evt.Data()).To(MatchJSON(`{
+								"low": 1,
+								"medium": 2,
+								"high": 3,
+								"critical": 4,
+								"detail_url": "https://my-console.com/main/violations",
+								"source": "rhacs-operator/stackrox-central-services"
+							}`))
+						case string(enum.SecurityClusterAlertCountsType):
+							Expect(evt.Data()).To(MatchJSON(`{
+								"clusters": [
+									{
+										"cluster": "cluster1",
+										"low": 1,
+										"critical": 2
+									},
+									{
+										"cluster": "cluster2",
+										"high": 5
+									}
+								],
+								"detail_url": "https://my-console.com/main/violations",
+								"source": "rhacs-operator/stackrox-central-services"
+							}`))
+						case string(enum.SecurityPolicyViolationsType):
+							Expect(evt.Data()).To(MatchJSON(`{
+								"violations": [
+									{
+										"cluster": "cluster1",
+										"cluster_id": "cluster1-id",
+										"policy_id": "policy2",
+										"policy_name": "Privileged Container",
+										"severity": "medium",
+										"count": 2
+									},
+									{
+										"cluster": "cluster1",
+										"cluster_id": "cluster1-id",
+										"policy_id": "policy1",
+										"policy_name": "Fixable Severity at least Important",
+										"severity": "high",
+										"categories": ["Vulnerability Management"],
+										"count": 1
+									}
+								],
+								"source": "rhacs-operator/stackrox-central-services"
+							}`))
+						case string(enum.SecurityImageVulnerabilitiesType):
+							Expect(evt.Data()).To(MatchJSON(`{
+								"clusters": [{
+									"cluster": "cluster1",
+									"cluster_id": "cluster1-id",
+									"low": 1,
+									"moderate": 2,
+									"important": 3,
+									"critical": 4,
+									"fixable": 6
+								}],
+								"source": "rhacs-operator/stackrox-central-services"
+							}`))
+						default:
+							Fail("unexpected event type " + evt.Type())
+						}
 
 						return nil
 					},
@@ -332,6 +513,12 @@ var _ = Describe("StackRox syncer", func() {
 				Expect(err).ToNot(HaveOccurred())
 				err = syncer.Sync(ctx, centralKey)
 				Expect(err).ToNot(HaveOccurred())
+				Expect(messages).To(Equal(map[string]int{
+					string(enum.SecurityAlertCountsType):          1,
+					string(enum.SecurityClusterAlertCountsType):   1,
+					string(enum.SecurityPolicyViolationsType):     1,
+					string(enum.SecurityImageVulnerabilitiesType): 1,
+				}))
 			})
 
 			It("Polls in a loop", func() {
@@ -610,12 +797,19 @@ var _ = Describe("StackRox syncer", func() {
 						VerifyHeaderKV("Authorization", "Bearer new-token"),
 						RespondOK,
 					),
+					CombineHandlers(
+						VerifyHeaderKV("Authorization", "Bearer new-token"),
+						RespondClusterCountsOK,
+					),
 				)
 
 				// Create the producer:
 				producer := &transport.ProducerMock{
 					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
 						defer GinkgoRecover()
+						if evt.Type() != string(enum.SecurityAlertCountsType) {
+							return nil
+						}
 
 						// Verify the message:
 						Expect(evt.Data()).To(MatchJSON(`{
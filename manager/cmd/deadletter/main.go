@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// deadletter is the command line tool to list, inspect and replay the dead letters with the global hub API:
+//
+//	deadletter --server https://$GLOBAL_HUB_API_HOST --token $TOKEN list --hub hub1 --reason handler_failed
+//	deadletter --server https://$GLOBAL_HUB_API_HOST --token $TOKEN get <id>
+//	deadletter --server https://$GLOBAL_HUB_API_HOST --token $TOKEN replay <id>
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+type options struct {
+	server             string
+	basePath           string
+	token              string
+	insecureSkipVerify bool
+	output             string
+
+	hub      string
+	typ      string
+	reason   string
+	replayed bool
+	limit    int
+}
+
+func main() {
+	opts := &options{}
+	pflag.StringVar(&opts.server, "server", os.Getenv("GLOBAL_HUB_API_HOST"),
+		"The address of the global hub API, default is the GLOBAL_HUB_API_HOST environment variable.")
+	pflag.StringVar(&opts.basePath, "server-base-path", "/global-hub-api/v1", "The base path of the global hub API.")
+	pflag.StringVar(&opts.token, "token", os.Getenv("TOKEN"),
+		"The bearer token to access the global hub API, default is the TOKEN environment variable.")
+	pflag.BoolVar(&opts.insecureSkipVerify, "insecure-skip-tls-verify", false,
+		"Skip verifying the certificate of the global hub API.")
+	pflag.StringVarP(&opts.output, "output", "o", "table", "The output format: table or json.")
+	pflag.StringVar(&opts.hub, "hub", "", "List the dead letters of the leaf hub.")
+	pflag.StringVar(&opts.typ, "type", "", "List the dead letters of the event type.")
+	pflag.StringVar(&opts.reason, "reason", "", "List the dead letters of the reason: handler_failed or unregistered.")
+	pflag.BoolVar(&opts.replayed, "replayed", false, "List the replayed dead letters as well.")
+	pflag.IntVar(&opts.limit, "limit", 100, "The maximum number of the listed dead letters.")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] list|get <id>|replay <id>\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	if err := run(opts, pflag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(opts *options, args []string) error {
+	if len(args) == 0 {
+		pflag.Usage()
+		return fmt.Errorf("the command is required")
+	}
+	if opts.server == "" {
+		return fmt.Errorf("the server of the global hub API is required")
+	}
+	if !strings.HasPrefix(opts.server, "http") {
+		opts.server = "https://" + opts.server
+	}
+
+	switch args[0] {
+	case "list":
+		query := url.Values{}
+		query.Set("hub", opts.hub)
+		query.Set("type", opts.typ)
+		query.Set("reason", opts.reason)
+		query.Set("replayed", strconv.FormatBool(opts.replayed))
+		query.Set("limit", strconv.Itoa(opts.limit))
+		deadLetters := []models.DeadLetter{}
+		if err := request(opts, http.MethodGet, "/deadletters?"+query.Encode(), &deadLetters); err != nil {
+			return err
+		}
+		return printDeadLetters(opts, deadLetters...)
+	case "get", "replay":
+		if len(args) != 2 {
+			return fmt.Errorf("the dead letter id is required: %s <id>", args[0])
+		}
+		method, path := http.MethodGet, "/deadletter/"+args[1]
+		if args[0] == "replay" {
+			method, path = http.MethodPost, path+"/replay"
+		}
+		deadLetter := models.DeadLetter{}
+		if err := request(opts, method, path, &deadLetter); err != nil {
+			return err
+		}
+		if args[0] == "get" && opts.output == "table" {
+			// the payload is the key to debug the dead letter, so print it in json
+			opts.output = "json"
+		}
+		return printDeadLetters(opts, deadLetter)
+	default:
+		pflag.Usage()
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func request(opts *options, method, path string, out interface{}) error {
+	req, err := http.NewRequest(method, strings.TrimSuffix(opts.server, "/")+opts.basePath+path, nil)
+	if err != nil {
+		return err
+	}
+	if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+	}
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	// #nosec G402
+	httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: opts.insecureSkipVerify}
+	client := &http.Client{Transport: httpTransport, Timeout: 30 * time.Second}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, string(bytes.TrimSpace(body)))
+	}
+	return json.Unmarshal(body, out)
+}
+
+func printDeadLetters(opts *options, deadLetters ...models.DeadLetter) error {
+	if opts.output == "json" {
+		var out interface{} = deadLetters
+		if len(deadLetters) == 1 {
+			out = deadLetters[0]
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tHUB\tTYPE\tVERSION\tREASON\tCREATED\tREPLAYED\tERROR")
+	for _, d := range deadLetters {
+		replayed := ""
+		if d.ReplayedAt != nil {
+			replayed = d.ReplayedAt.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", d.ID, d.LeafHubName, d.EventType,
+			d.EventVersion, d.Reason, d.CreatedAt.Format(time.RFC3339), replayed, d.Error)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// eventarchive is the command line tool to capture the cloudevents of the transport topics into a JSONL archive, and
+// replay the archive into the conflation pipeline of a running manager with the global hub API:
+//
+//	eventarchive capture --kafka-config kafka.yaml --topic gh-status.hub1 --output events.jsonl --max-events 100
+//	eventarchive --server https://$GLOBAL_HUB_API_HOST --token $TOKEN replay events.jsonl
+//
+// The kafka.yaml is the content of the transport secret, the certificates must be embedded in it.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/archives"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/archive"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+)
+
+type options struct {
+	// capture options
+	kafkaConfig     string
+	kafkaClient     string
+	consumerGroupID string
+	topics          []string
+	output          string
+	maxEvents       int
+	duration        time.Duration
+
+	// replay options
+	server             string
+	basePath           string
+	token              string
+	insecureSkipVerify bool
+}
+
+func main() {
+	opts := &options{}
+	pflag.StringVar(&opts.kafkaConfig, "kafka-config", "",
+		"The kafka.yaml of the transport secret to capture the events from.")
+	pflag.StringVar(&opts.kafkaClient, "kafka-client", string(transport.ConfluentKafkaClient),
+		"The client library of the kafka transport: confluent or sarama.")
+	pflag.StringVar(&opts.consumerGroupID, "consumer-group-id", "",
+		"The consumer group to capture the events, a new group is used if it isn't specified, which captures the "+
+			"events from the earliest offsets.")
+	pflag.StringSliceVar(&opts.topics, "topic", nil,
+		"The topics to capture the events from, the topic starts with '^' is subscribed as a regex.")
+	pflag.StringVarP(&opts.output, "output", "o", "", "The archive file to write, default is the stdout.")
+	pflag.IntVar(&opts.maxEvents, "max-events", 0, "Stop capturing once the number of events is reached.")
+	pflag.DurationVar(&opts.duration, "duration", 0, "Stop capturing after the duration.")
+	pflag.StringVar(&opts.server, "server", os.Getenv("GLOBAL_HUB_API_HOST"),
+		"The address of the global hub API, default is the GLOBAL_HUB_API_HOST environment variable.")
+	pflag.StringVar(&opts.basePath, "server-base-path", "/global-hub-api/v1", "The base path of the global hub API.")
+	pflag.StringVar(&opts.token, "token", os.Getenv("TOKEN"),
+		"The bearer token to access the global hub API, default is the TOKEN environment variable.")
+	pflag.BoolVar(&opts.insecureSkipVerify, "insecure-skip-tls-verify", false,
+		"Skip verifying the certificate of the global hub API.")
+	pflag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] capture|replay <archive>\n", os.Args[0])
+		pflag.PrintDefaults()
+	}
+	pflag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := run(ctx, opts, pflag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts *options, args []string) error {
+	if len(args) == 0 {
+		pflag.Usage()
+		return fmt.Errorf("the command is required")
+	}
+	switch args[0] {
+	case "capture":
+		return capture(ctx, opts)
+	case "replay":
+		if len(args) != 2 {
+			return fmt.Errorf("the archive file is required: replay <archive>")
+		}
+		return replay(opts, args[1])
+	default:
+		pflag.Usage()
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func capture(ctx context.Context, opts *options) error {
+	if len(opts.topics) == 0 {
+		return fmt.Errorf("the topic to capture is required")
+	}
+	kafkaConfig, err := loadKafkaConfig(opts.kafkaConfig)
+	if err != nil {
+		return err
+	}
+	kafkaConfig.ConsumerGroupID = opts.consumerGroupID
+	if kafkaConfig.ConsumerGroupID == "" {
+		kafkaConfig.ConsumerGroupID = fmt.Sprintf("global-hub-capture-%d", time.Now().Unix())
+	}
+
+	receiver, err := consumer.NewGenericConsumer(&transport.TransportInternalConfig{
+		TransportType:   string(transport.Kafka),
+		KafkaCredential: kafkaConfig,
+		KafkaClientType: transport.KafkaClientType(opts.kafkaClient),
+	}, opts.topics)
+	if err != nil {
+		return fmt.Errorf("failed to create the consumer: %w", err)
+	}
+
+	out := os.Stdout
+	if opts.output != "" {
+		if out, err = os.Create(opts.output); err != nil {
+			return err
+		}
+		defer func() { _ = out.Close() }()
+	}
+	writer := archive.NewWriter(out)
+
+	if opts.duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.duration)
+		defer cancel()
+	}
+	captureCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errChan := make(chan error, 1)
+	go func() { errChan <- receiver.Start(captureCtx) }()
+
+	captured := 0
+	for opts.maxEvents <= 0 || captured < opts.maxEvents {
+		select {
+		case <-captureCtx.Done():
+			fmt.Fprintf(os.Stderr, "captured %d events\n", captured)
+			return nil
+		case err := <-errChan:
+			return fmt.Errorf("the consumer is stopped after capturing %d events: %w", captured, err)
+		case evt := <-receiver.EventChan():
+			if err := writer.Write(evt); err != nil {
+				return err
+			}
+			captured++
+		}
+	}
+	fmt.Fprintf(os.Stderr, "captured %d events\n", captured)
+	return nil
+}
+
+// loadKafkaConfig loads the kafka credential from the kafka.yaml of the transport secret, the certificates are
+// embedded with base64 encoding, since the secrets of the certificates aren't accessible without the cluster
+func loadKafkaConfig(path string) (*transport.KafkaConfig, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the kafka config is required to capture the events")
+	}
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+	kafkaConfig := &transport.KafkaConfig{}
+	if err := yaml.Unmarshal(data, kafkaConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the kafka config: %w", err)
+	}
+	if kafkaConfig.CASecretName != "" || kafkaConfig.ClientSecretName != "" {
+		return nil, fmt.Errorf("the certificates must be embedded in the kafka config instead of the secrets")
+	}
+	if err := config.ParseCredentialConn("", nil, kafkaConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse the certificates of the kafka config: %w", err)
+	}
+	return kafkaConfig, nil
+}
+
+func replay(opts *options, path string) error {
+	if opts.server == "" {
+		return fmt.Errorf("the server of the global hub API is required")
+	}
+	if !strings.HasPrefix(opts.server, "http") {
+		opts.server = "https://" + opts.server
+	}
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(opts.server, "/")+opts.basePath+"/events/replay",
+		bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/jsonl")
+	if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+	}
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	// #nosec G402
+	httpTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: opts.insecureSkipVerify}
+	client := &http.Client{Transport: httpTransport, Timeout: 5 * time.Minute}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("%s: %s", resp.Status, string(bytes.TrimSpace(body)))
+	}
+
+	result := &archives.ReplayResult{}
+	if err := json.Unmarshal(body, result); err != nil {
+		return err
+	}
+	fmt.Printf("replayed %d events, rejected %d events\n", result.Replayed, len(result.Rejected))
+	for _, rejected := range result.Rejected {
+		fmt.Println("  " + rejected)
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -27,19 +29,27 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/controllers"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/certexpiry"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
 	specsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/spec"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	generichandler "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/latency"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	genericconsumer "github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	resttransport "github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -50,6 +60,7 @@ const (
 	webhookCertDir             = "/webhook-certs"
 	leaderElectionLockID       = "multicluster-global-hub-manager-lock"
 	launchJobNamesEnv          = "LAUNCH_JOB_NAMES"
+	podIPEnv                   = "POD_IP"
 	namespacePath              = "metadata.namespace"
 )
 
@@ -69,7 +80,10 @@ func parseFlags() *configs.ManagerConfig {
 		},
 		StatisticsConfig:    &statistics.StatisticsConfig{},
 		RestAPIServerConfig: &restapis.RestApiServerConfig{},
+		RestTransportConfig: &resttransport.ServerConfig{},
 		ElectionConfig:      &commonobjects.LeaderElectionConfig{},
+		TracingConfig:       &tracing.TracingConfig{},
+		SchedulingConfig:    &conflator.SchedulingConfig{},
 		LaunchJobNames:      "",
 	}
 
@@ -118,6 +132,61 @@ func parseFlags() *configs.ManagerConfig {
 	pflag.BoolVar(&managerConfig.EnablePprof, "enable-pprof", false, "enable the pprof tool")
 	pflag.IntVar(&managerConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar((*string)(&managerConfig.TransportConfig.CompressionType), "transport-compression",
+		string(compressor.NoOp), "The codec to compress the transport payloads: no-op, gzip, zstd or snappy.")
+	pflag.StringVar(&managerConfig.TransportConfig.SigningCAFile, "transport-signing-ca-file", "",
+		"The CA to verify the signature of the status events, the unsigned events are rejected if it's specified.")
+	pflag.DurationVar(&managerConfig.TransportConfig.SignatureMaxAge, "transport-signature-max-age", 24*time.Hour,
+		"The signed status events created before the max age are rejected as stale, 0 means the age isn't checked. "+
+			"It should be longer than the downtime the manager is expected to catch up with.")
+	pflag.StringVar((*string)(&managerConfig.TransportConfig.KafkaClientType), "transport-kafka-client",
+		string(transport.ConfluentKafkaClient), "The client library of the kafka transport: confluent or sarama.")
+	pflag.StringSliceVar(&managerConfig.TransportConfig.AdditionalKafkaSecrets, "transport-additional-secrets", nil,
+		"The transport secrets of the additional kafka clusters to consume the status events from, they're in the "+
+			"same namespace as the transport secret.")
+	pflag.DurationVar(&managerConfig.TransportConfig.ChunkCollectionTTL, "transport-chunk-ttl",
+		genericconsumer.DefaultChunkCollectionTTL,
+		"The max duration to wait for the next chunk of an incomplete status event before it's evicted.")
+	pflag.IntVar(&managerConfig.TransportConfig.ChunkMemoryLimit, "transport-chunk-memory-limit",
+		genericconsumer.DefaultAssemblerMemoryLimit,
+		"The size limit of the chunks buffered by the consumer, the least recently updated events are evicted over it.")
+	pflag.StringVar(&managerConfig.TransportConfig.TransportType, "transport-type", string(transport.Kafka),
+		"The transport type to exchange the events with the agents: kafka or rest.")
+	pflag.StringVar(&managerConfig.RestTransportConfig.Address, "transport-rest-address", ":9444",
+		"The address of the restful transport server, it's only used when the transport-type is rest.")
+	pflag.StringVar(&managerConfig.RestTransportConfig.CertFile, "transport-rest-cert-file", "",
+		"The server certificate of the restful transport server, it's required by the rest transport.")
+	pflag.StringVar(&managerConfig.RestTransportConfig.KeyFile, "transport-rest-key-file", "",
+		"The server private key of the restful transport server.")
+	pflag.StringVar(&managerConfig.RestTransportConfig.ClientCAFile, "transport-rest-client-ca-file", "",
+		"The CA to verify the client certificates of the agents for the restful transport server, it's required "+
+			"by the rest transport. The common name of the client certificate must be the name of the hub.")
+	pflag.IntVar(&managerConfig.RestTransportConfig.Retention, "transport-rest-retention", resttransport.DefaultRetention,
+		"The count of the events retained in the spec topic of the restful transport server.")
+	pflag.BoolVar(&managerConfig.TransportConfig.StatusSharding, "status-sharding", false,
+		"Share the status processing between the manager replicas by the kafka partitions assigned from the consumer "+
+			"group, instead of processing all of them by the leader. It requires the confluent kafka client.")
+	pflag.StringToIntVar(&managerConfig.SchedulingConfig.Weights, "status-hub-weights", nil,
+		"The weights of the leaf hubs to share the status workers, e.g. hub1=2,hub2=4. The '*' key sets the weight of "+
+			"the other hubs, which is 1 by default.")
+	pflag.StringToIntVar(&managerConfig.SchedulingConfig.ConcurrencyLimits, "status-hub-concurrency-limits", nil,
+		"The maximum number of the status jobs of the leaf hubs processed at once, e.g. *=4,hub1=8. It's unlimited "+
+			"by default.")
+	pflag.StringToIntVar(&managerConfig.SchedulingConfig.Quotas, "status-hub-quotas", nil,
+		"The maximum number of the pending delta events of the leaf hubs, e.g. *=200. The exceeding events are "+
+			"dropped and resynced from the hub once its pending events are drained. It's unlimited by default.")
+	pflag.StringVar(&managerConfig.TracingConfig.OTLPEndpoint, "tracing-otlp-endpoint", "",
+		"The host:port of the OTLP gRPC receiver to export the trace spans, the tracing is disabled if neither the "+
+			"endpoint nor the tracing-file is specified.")
+	pflag.BoolVar(&managerConfig.TracingConfig.OTLPInsecure, "tracing-otlp-insecure", false,
+		"Connect the OTLP receiver without TLS.")
+	pflag.StringVar(&managerConfig.TracingConfig.FilePath, "tracing-file", "",
+		"The file to write the trace spans as JSON lines, it's used for testing.")
+	pflag.Float64Var(&managerConfig.TracingConfig.SampleRatio, "tracing-sample-ratio", 1,
+		"The ratio of the sampled traces started by the manager, e.g. the spec syncers.")
+	pflag.DurationVar(&managerConfig.CertificateExpiryWindow, "certificate-expiry-window", 30*24*time.Hour,
+		"Warn the certificates and tokens of the hubs which expire within the window, e.g. the klusterlet bootstrap "+
+			"and hub kubeconfig, via the metrics and the rest api.")
 	pflag.Parse()
 
 	pflag.Visit(func(f *pflag.Flag) {
@@ -127,6 +196,7 @@ func parseFlags() *configs.ManagerConfig {
 		}
 	})
 	managerNamespace = managerConfig.ManagerNamespace
+	managerConfig.RestAPIServerConfig.CertificateExpiryWindow = managerConfig.CertificateExpiryWindow
 	return managerConfig
 }
 
@@ -134,6 +204,13 @@ func completeConfig(managerConfig *configs.ManagerConfig) error {
 	if managerConfig.DatabaseConfig.ProcessDatabaseURL == "" {
 		return fmt.Errorf("database url for process user: %w", errFlagParameterEmpty)
 	}
+	if managerConfig.TransportConfig.StatusSharding && (managerConfig.TransportConfig.TransportType !=
+		string(transport.Kafka) || managerConfig.TransportConfig.KafkaClientType == transport.SaramaKafkaClient) {
+		return fmt.Errorf("the status sharding is only supported by the kafka transport with the confluent client")
+	}
+	if err := managerConfig.SchedulingConfig.Validate(); err != nil {
+		return err
+	}
 	// the specified jobs(concatenate multiple jobs with ',') runs when the container starts
 	val, ok := os.LookupEnv(launchJobNamesEnv)
 	if ok && val != "" {
@@ -198,12 +275,22 @@ func createManager(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("failed to create a new manager: %w", err)
 	}
+	genericconsumer.RegisterMetrics()
+	statistics.RegisterMetrics()
+	latency.RegisterMetrics()
+	certexpiry.RegisterMetrics()
+	conflator.RegisterMetrics()
 
 	// add the configmap: logLevel
 	if err = logger.AddLogConfigController(ctx, mgr); err != nil {
 		return nil, fmt.Errorf("failed to add configmap controller to manager: %w", err)
 	}
 	configs.SetEnableInventoryAPI(managerConfig.EnableInventoryAPI)
+	if managerConfig.TransportConfig.TransportType == string(transport.Rest) {
+		if err := addRestTransportServer(mgr, managerConfig); err != nil {
+			return nil, err
+		}
+	}
 	err = controller.NewTransportCtrl(managerConfig.ManagerNamespace, constants.GHTransportConfigSecret,
 		transportCallback(mgr, managerConfig),
 		managerConfig.TransportConfig, true,
@@ -225,6 +312,9 @@ func createManager(ctx context.Context,
 	if err := backupPVC.SetupWithManager(mgr); err != nil {
 		return nil, err
 	}
+	if err := certexpiry.AddCertificateExpiryMonitor(mgr, managerConfig.CertificateExpiryWindow); err != nil {
+		return nil, fmt.Errorf("failed to add the certificate expiry monitor: %w", err)
+	}
 	if managerConfig.EnableGlobalResource {
 		if err := restapis.AddRestApiServer(mgr, managerConfig.RestAPIServerConfig); err != nil {
 			return nil, fmt.Errorf("failed to add non-k8s-api-server: %w", err)
@@ -233,6 +323,38 @@ func createManager(ctx context.Context,
 	return mgr, nil
 }
 
+// addRestTransportServer shares the broker with the producer and consumer, and serves it to the agents
+func addRestTransportServer(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) error {
+	broker := resttransport.NewBroker(managerConfig.RestTransportConfig.Retention)
+	if managerConfig.TransportConfig.Extends == nil {
+		managerConfig.TransportConfig.Extends = make(map[string]interface{})
+	}
+	managerConfig.TransportConfig.Extends[resttransport.BrokerKey] = broker
+	if err := mgr.Add(resttransport.NewServer(managerConfig.RestTransportConfig, broker)); err != nil {
+		return fmt.Errorf("failed to add the restful transport server: %w", err)
+	}
+
+	// only the leader runs the server, so the service of the server is routed to the leader
+	podIP := os.Getenv(podIPEnv)
+	if podIP == "" {
+		log.Warnf("the %s isn't set, the restful transport service isn't routed to the leader", podIPEnv)
+		return nil
+	}
+	_, port, err := net.SplitHostPort(managerConfig.RestTransportConfig.Address)
+	if err != nil {
+		return fmt.Errorf("invalid restful transport address: %w", err)
+	}
+	portNum, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid restful transport port %s: %w", port, err)
+	}
+	if err := mgr.Add(resttransport.NewLeaderEndpoints(mgr.GetClient(), managerConfig.ManagerNamespace, podIP,
+		int32(portNum))); err != nil {
+		return fmt.Errorf("failed to add the restful transport endpoints: %w", err)
+	}
+	return nil
+}
+
 func transportCallback(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) controller.TransportCallback {
 	return func(transportClient transport.TransportClient) error {
 		if !managerConfig.WithACM {
@@ -259,6 +381,14 @@ func transportCallback(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) c
 		if err := hubmanagement.AddHubManagement(mgr, producer); err != nil {
 			return fmt.Errorf("failed to add hubmanagement to manager - %w", err)
 		}
+		// the objects which can't be patched by the status handlers are recovered by the resync
+		generichandler.SetResyncFunc(func(ctx context.Context, hubName string, eventTypes ...string) error {
+			return hubmanagement.RequestResync(ctx, producer, hubName, eventTypes...)
+		})
+		// the delta events exceeding the quota of the hub are recovered by the resync
+		conflator.SetResyncFunc(func(ctx context.Context, hubName string, eventTypes ...string) error {
+			return hubmanagement.RequestResync(ctx, producer, hubName, eventTypes...)
+		})
 
 		// add managedClusterMigration controller
 		if err := migration.AddMigrationToManager(mgr, producer, managerConfig); err != nil {
@@ -280,6 +410,18 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 	}
 
 	utils.PrintRuntimeInfo()
+
+	shutdownTracing, err := tracing.InitTracerProvider(ctx, "multicluster-global-hub-manager",
+		managerConfig.TracingConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize the tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warnw("failed to shutdown the tracing", "error", err)
+		}
+	}()
+
 	databaseConfig := &database.DatabaseConfig{
 		URL:        managerConfig.DatabaseConfig.ProcessDatabaseURL,
 		Dialect:    database.PostgresDialect,
@@ -287,7 +429,7 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 		PoolSize:   managerConfig.DatabaseConfig.MaxOpenConns,
 	}
 	// Init the default gorm instance, it's used to sync data to db
-	err := database.InitGormInstance(databaseConfig)
+	err = database.InitGormInstance(databaseConfig)
 	if err != nil {
 		return fmt.Errorf("failed to initialize GORM instance %w", err)
 	}
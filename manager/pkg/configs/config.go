@@ -7,9 +7,12 @@ import (
 	"time"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
 )
 
 type ManagerConfig struct {
@@ -21,12 +24,17 @@ type ManagerConfig struct {
 	TransportConfig      *transport.TransportInternalConfig
 	StatisticsConfig     *statistics.StatisticsConfig
 	RestAPIServerConfig  *restapis.RestApiServerConfig
+	RestTransportConfig  *rest.ServerConfig
 	ElectionConfig       *commonobjects.LeaderElectionConfig
+	TracingConfig        *tracing.TracingConfig
+	SchedulingConfig     *conflator.SchedulingConfig
 	EnableGlobalResource bool
 	EnableInventoryAPI   bool
 	WithACM              bool
 	LaunchJobNames       string
 	EnablePprof          bool
+	// CertificateExpiryWindow warns the certificates of the hubs which expire within it
+	CertificateExpiryWindow time.Duration
 }
 
 type SyncerConfig struct {
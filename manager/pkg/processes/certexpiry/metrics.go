@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package certexpiry
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	expiryTimestampGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_certificate_expiry_timestamp_seconds",
+			Help: "The expiry time of the certificates and tokens scanned on the hubs in unix seconds.",
+		},
+		[]string{
+			"hub",       // The leaf hub name.
+			"source",    // The source of the credential, e.g. KlusterletBootstrap, HubKubeconfig, APIServer.
+			"namespace", // The namespace of the secret, it's empty for the api server and the transport.
+			"name",      // The name of the secret, the api server address or the transport type.
+			"key",       // The key of the credential in the secret.
+		},
+	)
+
+	expiringGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_certificates_expiring",
+			Help: "The number of the certificates and tokens which expire within the window, including the expired.",
+		},
+		[]string{
+			"hub",    // The leaf hub name.
+			"source", // The source of the credential.
+		},
+	)
+)
+
+var registerOnce sync.Once
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		metrics.Registry.MustRegister(expiryTimestampGauge, expiringGauge)
+	})
+}
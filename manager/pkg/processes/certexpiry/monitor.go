@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package certexpiry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// checkInterval is the interval to refresh the metrics, the agents scan the certificates hourly by default
+const checkInterval = 10 * time.Minute
+
+var log = logger.DefaultZapLogger()
+
+// Monitor exposes the expiry of the certificates synced from the hubs as the metrics, and warns the certificates
+// which expire within the window
+type Monitor struct {
+	window time.Duration
+	// warned is the certificates which have been warned, so they're only warned once until they're renewed
+	warned map[string]time.Time
+}
+
+func AddCertificateExpiryMonitor(mgr ctrl.Manager, window time.Duration) error {
+	return mgr.Add(&Monitor{window: window, warned: map[string]time.Time{}})
+}
+
+func (m *Monitor) Start(ctx context.Context) error {
+	log.Infow("certificate expiry monitor", "interval", checkInterval, "window", m.window)
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.check(); err != nil {
+			log.Errorw("failed to check the certificate expiries", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) check() error {
+	var certs []models.CertificateExpiry
+	if err := database.GetGorm().Find(&certs).Error; err != nil {
+		return err
+	}
+
+	for _, cert := range m.newlyExpiring(m.export(certs, time.Now())) {
+		log.Warnw("the certificate expires within the window", "hub", cert.LeafHubName, "source", cert.Source,
+			"namespace", cert.Namespace, "name", cert.Name, "key", cert.Key, "kind", cert.Kind,
+			"notAfter", cert.NotAfter, "window", m.window)
+	}
+	return nil
+}
+
+// export refreshes the metrics of the certificates and returns the expiring ones. The expiring count is set for each
+// hub and source of the certificates, so the ones without expiring certificates are exposed as 0 rather than no data.
+func (m *Monitor) export(certs []models.CertificateExpiry, now time.Time) []models.CertificateExpiry {
+	expiryTimestampGauge.Reset()
+	expiringGauge.Reset()
+	for _, cert := range certs {
+		expiryTimestampGauge.WithLabelValues(cert.LeafHubName, cert.Source, cert.Namespace, cert.Name, cert.Key).
+			Set(float64(cert.NotAfter.Unix()))
+		expiringGauge.WithLabelValues(cert.LeafHubName, cert.Source).Set(0)
+	}
+
+	expiring := m.expiring(certs, now)
+	for _, cert := range expiring {
+		expiringGauge.WithLabelValues(cert.LeafHubName, cert.Source).Inc()
+	}
+	return expiring
+}
+
+// expiring returns the certificates which expire within the window, the expired ones are included
+func (m *Monitor) expiring(certs []models.CertificateExpiry, now time.Time) []models.CertificateExpiry {
+	deadline := now.Add(m.window)
+	expiring := []models.CertificateExpiry{}
+	for _, cert := range certs {
+		if cert.NotAfter.Before(deadline) {
+			expiring = append(expiring, cert)
+		}
+	}
+	return expiring
+}
+
+// newlyExpiring returns the expiring certificates which haven't been warned, the renewed or deleted certificates are
+// removed from the warned ones, so they're warned again once they're expiring
+func (m *Monitor) newlyExpiring(expiring []models.CertificateExpiry) []models.CertificateExpiry {
+	current := map[string]time.Time{}
+	newly := []models.CertificateExpiry{}
+	for _, cert := range expiring {
+		id := identity(cert)
+		current[id] = cert.NotAfter
+		if notAfter, ok := m.warned[id]; !ok || !notAfter.Equal(cert.NotAfter) {
+			newly = append(newly, cert)
+		}
+	}
+	m.warned = current
+	return newly
+}
+
+func identity(cert models.CertificateExpiry) string {
+	return strings.Join([]string{cert.LeafHubName, cert.Source, cert.Namespace, cert.Name, cert.Key}, "/")
+}
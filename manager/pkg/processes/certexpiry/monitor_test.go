@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package certexpiry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Now()
+	m := &Monitor{window: 24 * time.Hour, warned: map[string]time.Time{}}
+
+	expired := models.CertificateExpiry{LeafHubName: "hub1", Source: "HubKubeconfig", Name: "expired",
+		NotAfter: now.Add(-time.Hour)}
+	soon := models.CertificateExpiry{LeafHubName: "hub1", Source: "HubKubeconfig", Name: "soon",
+		NotAfter: now.Add(time.Hour)}
+	later := models.CertificateExpiry{LeafHubName: "hub1", Source: "HubKubeconfig", Name: "later",
+		NotAfter: now.Add(48 * time.Hour)}
+
+	expiring := m.expiring([]models.CertificateExpiry{expired, soon, later}, now)
+	assert.Equal(t, []models.CertificateExpiry{expired, soon}, expiring)
+
+	// warn the expiring certificates only once
+	assert.Len(t, m.newlyExpiring(expiring), 2)
+	assert.Empty(t, m.newlyExpiring(expiring))
+
+	// the renewed certificate is warned again once it's expiring
+	assert.Empty(t, m.newlyExpiring([]models.CertificateExpiry{expired}))
+	renewed := soon
+	renewed.NotAfter = now.Add(2 * time.Hour)
+	assert.Equal(t, []models.CertificateExpiry{renewed}, m.newlyExpiring([]models.CertificateExpiry{expired, renewed}))
+}
+
+func TestExport(t *testing.T) {
+	now := time.Now()
+	m := &Monitor{window: 24 * time.Hour, warned: map[string]time.Time{}}
+
+	certs := []models.CertificateExpiry{
+		{LeafHubName: "hub1", Source: "HubKubeconfig", Name: "soon", NotAfter: now.Add(time.Hour)},
+		{LeafHubName: "hub1", Source: "HubKubeconfig", Name: "later", NotAfter: now.Add(48 * time.Hour)},
+		{LeafHubName: "hub2", Source: "APIServer", Name: "later", NotAfter: now.Add(48 * time.Hour)},
+	}
+	assert.Len(t, m.export(certs, now), 1)
+	assert.Equal(t, float64(1), testutil.ToFloat64(expiringGauge.WithLabelValues("hub1", "HubKubeconfig")))
+
+	// the hub without expiring certificates is exposed as 0
+	assert.Equal(t, 2, testutil.CollectAndCount(expiringGauge))
+	assert.Equal(t, float64(0), testutil.ToFloat64(expiringGauge.WithLabelValues("hub2", "APIServer")))
+}
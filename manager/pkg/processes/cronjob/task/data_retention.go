@@ -19,6 +19,7 @@ var (
 	// 1. create partition tables for days in the future, the partition table for the next month is created
 	// 2. delete partition tables that are no longer needed, the partition table for the previous 18 month is deleted
 	// 3. completely delete the soft deleted records from database after retainedMonths
+	// 4. delete the appended records which are created before retainedMonths
 	RetentionTaskName = "data-retention"
 
 	// after the record is marked as deleted, retentionMonth is used to indicate how long it will be retained
@@ -30,6 +31,12 @@ var (
 		"local_spec.policies",
 	}
 
+	// the following tables are only appended without the soft deletion, the records are deleted by the created time
+	AppendedTables = []string{
+		"status.transport_audit_logs",
+		"status.dead_letters",
+	}
+
 	// partition by month
 	PartitionDateFormat = "2006_01"
 	// the following data tables will generate records over time, so it is necessary to split them into small tables to
@@ -90,6 +97,13 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 			return
 		}
 	}
+	for _, tableName := range AppendedTables {
+		err = deleteCreatedRecords(tableName, minTime)
+		if err != nil {
+			retentionLog.Error(err, "failed to delete the appended records")
+			return
+		}
+	}
 	err = db.Where("last_timestamp < ? AND status = ?", minTime, hubmanagement.HubInactive).
 		Delete(&models.LeafHubHeartbeat{}).Error
 	if err != nil {
@@ -136,6 +150,19 @@ func deleteExpiredRecords(tableName string, minDate time.Time) error {
 	return nil
 }
 
+func deleteCreatedRecords(tableName string, minDate time.Time) error {
+	sql := fmt.Sprintf("DELETE FROM %s WHERE created_at < '%s'", tableName, minDate.Format(DateFormat))
+	db := database.GetGorm()
+	result := db.Exec(sql)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete records created before %s from %s: %w",
+			minDate.Format(DateFormat), tableName, result.Error)
+	}
+	retentionLog.Info("delete records", "table", tableName, "createdBefore", minDate.Format(DateFormat),
+		"count", result.RowsAffected)
+	return nil
+}
+
 func traceDataRetentionLog(tableName string, startTime time.Time, err error, partition bool) error {
 	db := database.GetGorm()
 	dataRetentionLog := &models.DataRetentionJobLog{
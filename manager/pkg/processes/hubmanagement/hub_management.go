@@ -13,10 +13,12 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/latency"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -69,6 +71,15 @@ func AddHubManagement(mgr ctrl.Manager, producer transport.Producer) error {
 }
 
 func (h *HubManagement) Start(ctx context.Context) error {
+	// the active hubs label the status metrics before their next heartbeats
+	var activeHubs []models.LeafHubHeartbeat
+	if err := database.GetGorm().Where("status = ?", HubActive).Find(&activeHubs).Error; err != nil {
+		return fmt.Errorf("failed to list the active hubs: %w", err)
+	}
+	for _, hub := range activeHubs {
+		statistics.AddHub(hub.Name)
+	}
+
 	// when start the hub management, resync all the necessary resources
 	err := h.resync(ctx, transport.Broadcast)
 	if err != nil {
@@ -133,6 +144,8 @@ func (h *HubManagement) inactive(ctx context.Context, hubs []models.LeafHubHeart
 		if err != nil {
 			return err
 		}
+		latency.RemoveHub(hub.Name)
+		statistics.RemoveHub(hub.Name)
 	}
 	return nil
 }
@@ -170,6 +183,41 @@ func (h *HubManagement) cleanup(hubName string) error {
 			return e
 		}
 
+		// delete the addons of the clusters
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ManagedClusterAddOn{}).Error
+		if e != nil {
+			return e
+		}
+		// delete the argocd applications
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ArgoCDApplication{}).Error
+		if e != nil {
+			return e
+		}
+		// delete the manifestworks
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ManifestWork{}).Error
+		if e != nil {
+			return e
+		}
+		// delete the hive clusterdeployments and clusterpools
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ClusterDeployment{}).Error
+		if e != nil {
+			return e
+		}
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ClusterPool{}).Error
+		if e != nil {
+			return e
+		}
+		// delete the cluster inventory
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.ClusterInventory{}).Error
+		if e != nil {
+			return e
+		}
+		// delete the certificate expiries
+		e = tx.Where("leaf_hub_name = ?", hubName).Delete(&models.CertificateExpiry{}).Error
+		if e != nil {
+			return e
+		}
+
 		// inactive the hub status
 		return tx.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name = ?", hubName).Update("status", HubInactive).Error
 	})
@@ -202,18 +250,29 @@ func (h *HubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeart
 }
 
 func (h *HubManagement) resync(ctx context.Context, hubName string) error {
-	resyncResources := []string{
+	return RequestResync(ctx, h.producer, hubName,
 		string(enum.HubClusterInfoType),
 		string(enum.ManagedClusterType),
+		string(enum.ManagedClusterAddOnType),
+		string(enum.ManagedClusterInfoType),
+		string(enum.ArgoCDApplicationType),
+		string(enum.ManifestWorkType),
+		string(enum.ClusterDeploymentType),
+		string(enum.ClusterPoolType),
+		string(enum.CertificateExpiryType),
 		string(enum.LocalPolicySpecType),
 		string(enum.LocalComplianceType),
-	}
-	payloadBytes, err := json.Marshal(resyncResources)
+	)
+}
+
+// RequestResync requests the hub to resync the objects of the event types
+func RequestResync(ctx context.Context, producer transport.Producer, hubName string, eventTypes ...string) error {
+	payloadBytes, err := json.Marshal(eventTypes)
 	if err != nil {
 		return err
 	}
 
 	e := utils.ToCloudEvent(constants.ResyncMsgKey, constants.CloudEventGlobalHubClusterName, hubName, payloadBytes)
 
-	return h.producer.SendEvent(ctx, e)
+	return producer.SendEvent(ctx, e)
 }
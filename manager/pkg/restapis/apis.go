@@ -15,9 +15,20 @@ import (
 	"go.uber.org/zap"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/archives"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/argocdapplications"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/certificates"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/clusterdeployments"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/clusterinventory"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/clusterpools"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/deadletters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/latencies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/managedclusteraddons"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/managedclusters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/manifestworks"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/security"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/subscriptions"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
@@ -30,6 +41,10 @@ type RestApiServerConfig struct {
 	ClusterAPIURL          string
 	ClusterAPICABundlePath string
 	ServerBasePath         string
+	// CertificateExpiryWindow is the window to list the certificates expire within it as the expiring ones
+	CertificateExpiryWindow time.Duration
+	// HubAuthorizer authorizes the authenticated user to replay the events of the hubs
+	HubAuthorizer authentication.HubAuthorizer
 }
 
 // NeedLeaderElection implements the LeaderElectionRunnable interface, which indicates
@@ -61,6 +76,9 @@ func readCertificateAuthority(nonK8sAPIServerConfig *RestApiServerConfig) ([]byt
 
 // AddRestApiServer adds the non-k8s-api-server to the Manager.
 func AddRestApiServer(mgr ctrl.Manager, restApiConfig *RestApiServerConfig) error {
+	if restApiConfig.HubAuthorizer == nil {
+		restApiConfig.HubAuthorizer = authentication.NewHubAuthorizer(mgr.GetClient())
+	}
 	router, err := SetupRouter(restApiConfig)
 	if err != nil {
 		return err
@@ -100,9 +118,12 @@ func AddRestApiServer(mgr ctrl.Manager, restApiConfig *RestApiServerConfig) erro
 // @description					Authorization with user access token
 func SetupRouter(nonK8sAPIServerConfig *RestApiServerConfig) (*gin.Engine, error) {
 	router := gin.Default()
+	// the authorization is skipped along with the authentication
+	var hubAuthorizer authentication.HubAuthorizer
 	// add aythentication eith openshift oauth
 	// skip authentication middleware if ClusterAPIURL is empty for testing
 	if nonK8sAPIServerConfig.ClusterAPIURL != "" {
+		hubAuthorizer = nonK8sAPIServerConfig.HubAuthorizer
 		clusterAPICABundle, err := readCertificateAuthority(nonK8sAPIServerConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read certificates authority: %w", err)
@@ -114,10 +135,25 @@ func SetupRouter(nonK8sAPIServerConfig *RestApiServerConfig) (*gin.Engine, error
 	routerGroup.GET("/managedclusters", managedclusters.ListManagedClusters())
 	routerGroup.PATCH("/managedcluster/:clusterID",
 		managedclusters.PatchManagedCluster())
+	routerGroup.GET("/managedclusteraddons", managedclusteraddons.ListManagedClusterAddOns())
+	routerGroup.GET("/manifestworks", manifestworks.ListManifestWorks())
+	routerGroup.GET("/clusterdeployments", clusterdeployments.ListClusterDeployments())
+	routerGroup.GET("/clusterpools", clusterpools.ListClusterPools())
+	routerGroup.GET("/clusterinventory", clusterinventory.ListClusterInventory())
+	routerGroup.GET("/clusterinventory/summary", clusterinventory.SummarizeClusterInventory())
+	routerGroup.GET("/certificates", certificates.ListCertificates(nonK8sAPIServerConfig.CertificateExpiryWindow))
+	routerGroup.GET("/security/clusterrisks", security.ListClusterRisks())
+	routerGroup.GET("/security/policyviolations", security.ListPolicyViolations())
 	routerGroup.GET("/policies", policies.ListPolicies())
 	routerGroup.GET("/policy/:policyID/status", policies.GetPolicyStatus())
 	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions())
 	routerGroup.GET("/subscriptionreport/:subscriptionID", subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/argocdapplications", argocdapplications.ListArgoCDApplications())
+	routerGroup.GET("/deadletters", deadletters.ListDeadLetters())
+	routerGroup.GET("/deadletter/:deadLetterID", deadletters.GetDeadLetter())
+	routerGroup.POST("/deadletter/:deadLetterID/replay", deadletters.ReplayDeadLetter(hubAuthorizer))
+	routerGroup.POST("/events/replay", archives.ReplayArchive(hubAuthorizer))
+	routerGroup.GET("/latencies", latencies.ListLatencies())
 
 	return router, nil
 }
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package archives
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/archive"
+)
+
+// ReplayResult summarizes the replayed archive, the rejected events are the ones which are superseded by the newer
+// version of the hub or have no handler registered, the forbidden events are the ones of the hubs which the user
+// isn't authorized for, and the unavailable events are the ones of the hubs which aren't processed by the replica
+type ReplayResult struct {
+	Replayed    int      `json:"replayed"`
+	Rejected    []string `json:"rejected,omitempty"`
+	Forbidden   []string `json:"forbidden,omitempty"`
+	Unavailable []string `json:"unavailable,omitempty"`
+}
+
+// ReplayArchive godoc
+// @summary replay event archive
+// @description replay the captured events of the JSONL archive through the conflation pipeline in order, only the
+// @description events of the hubs which the user is authorized to update are replayed. The events of the hubs which
+// @description aren't processed by the replica are unavailable, e.g. the replica isn't the leader or the hub is owned
+// @description by another replica of the sharded manager
+// @accept plain
+// @produce json
+// @success      200  {object}     ReplayResult
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      409
+// @failure      503
+// @security     ApiKeyAuth
+// @router /events/replay [post]
+func ReplayArchive(authorizer authentication.HubAuthorizer) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		result := &ReplayResult{}
+		// cache the authorization of the hubs, the archive usually contains many events of the same hub
+		authorizedHubs := map[string]bool{}
+		err := archive.Read(ginCtx.Request.Body, func(record *archive.Record) error {
+			hub := record.Event.Source()
+			authorized, found := authorizedHubs[hub]
+			if !found {
+				var err error
+				if authorized, err = authentication.AuthorizeHub(ginCtx, authorizer, hub); err != nil {
+					return err
+				}
+				authorizedHubs[hub] = authorized
+			}
+			if !authorized {
+				result.Forbidden = append(result.Forbidden, fmt.Sprintf("%s/%s", hub, record.Event.ID()))
+				return nil
+			}
+			if err := deadletter.ReplayEvent(record.Event); err != nil {
+				switch {
+				case errors.Is(err, deadletter.ErrPipelineNotRunning):
+					result.Unavailable = append(result.Unavailable,
+						fmt.Sprintf("%s/%s: %v", record.Event.Source(), record.Event.ID(), err))
+				case errors.Is(err, deadletter.ErrReplayRejected):
+					result.Rejected = append(result.Rejected,
+						fmt.Sprintf("%s/%s: %v", record.Event.Source(), record.Event.ID(), err))
+				default:
+					return err
+				}
+				return nil
+			}
+			result.Replayed++
+			return nil
+		})
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid archive: %v", err)
+			return
+		}
+		if result.Replayed == 0 && len(result.Forbidden) > 0 {
+			ginCtx.JSON(http.StatusForbidden, result)
+			return
+		}
+		if result.Replayed == 0 && len(result.Unavailable) > 0 {
+			ginCtx.JSON(http.StatusServiceUnavailable, result)
+			return
+		}
+		if result.Replayed == 0 && len(result.Rejected) > 0 {
+			ginCtx.JSON(http.StatusConflict, result)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, result)
+	}
+}
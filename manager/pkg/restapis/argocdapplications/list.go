@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package argocdapplications
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListArgoCDApplications godoc
+// @summary list argo cd applications
+// @description list the argo cd applications and applicationsets of all the hubs with the sync and health status
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the applications by the leaf hub name"
+// @param        kind             query     string  false  "filter the applications by the kind: Application or ApplicationSet"
+// @param        namespace        query     string  false  "filter the applications by the namespace"
+// @param        project          query     string  false  "filter the applications by the argo cd project"
+// @param        cluster          query     string  false  "filter the applications by the destination cluster name or server"
+// @param        repo             query     string  false  "filter the applications by the source repository url"
+// @param        sync             query     string  false  "filter the applications by the sync status, e.g. OutOfSync"
+// @param        health           query     string  false  "filter the applications by the health status, e.g. Degraded"
+// @param        limit            query     int     false  "maximum application number to receive"
+// @success      200  {array}     models.ArgoCDApplication
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /argocdapplications [get]
+func ListArgoCDApplications() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.ArgoCDApplication{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if kind := ginCtx.Query("kind"); kind != "" {
+			query = query.Where("LOWER(kind) = ?", strings.ToLower(kind))
+		}
+		if namespace := ginCtx.Query("namespace"); namespace != "" {
+			query = query.Where("namespace = ?", namespace)
+		}
+		if project := ginCtx.Query("project"); project != "" {
+			query = query.Where("project = ?", project)
+		}
+		if cluster := ginCtx.Query("cluster"); cluster != "" {
+			// the destination is specified by either the cluster name or the server url
+			query = query.Where("destination_name = ? OR destination_server = ?", cluster, cluster)
+		}
+		if repo := ginCtx.Query("repo"); repo != "" {
+			query = query.Where("repo_url = ?", repo)
+		}
+		// the status is matched case-insensitively, e.g. "outofsync" matches "OutOfSync"
+		if sync := ginCtx.Query("sync"); sync != "" {
+			query = query.Where("LOWER(sync_status) = ?", strings.ToLower(sync))
+		}
+		if health := ginCtx.Query("health"); health != "" {
+			query = query.Where("LOWER(health_status) = ?", strings.ToLower(health))
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		applications := []models.ArgoCDApplication{}
+		err := query.Order("leaf_hub_name, kind, namespace, name").Find(&applications).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing argocd applications: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, applications)
+	}
+}
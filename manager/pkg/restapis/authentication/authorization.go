@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authentication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HubAuthorizer returns whether the user is authorized to write the status of the managed hub
+type HubAuthorizer func(ctx context.Context, user string, groups []string, hub string) (bool, error)
+
+// NewHubAuthorizer authorizes the user by the subject access review of updating the managed cluster of the hub, the
+// managed hubs are the managed clusters of the global hub cluster
+func NewHubAuthorizer(c client.Client) HubAuthorizer {
+	return func(ctx context.Context, user string, groups []string, hub string) (bool, error) {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user,
+				Groups: groups,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     "update",
+					Group:    "cluster.open-cluster-management.io",
+					Resource: "managedclusters",
+					Name:     hub,
+				},
+			},
+		}
+		if err := c.Create(ctx, review); err != nil {
+			return false, fmt.Errorf("failed to review the access of the user %s to the hub %s: %w", user, hub, err)
+		}
+		return review.Status.Allowed, nil
+	}
+}
+
+// AuthorizeHub checks the authenticated user of the request against the hub, it's always allowed if the authorizer is
+// nil, e.g. the authentication is skipped for testing
+func AuthorizeHub(ginCtx *gin.Context, authorizer HubAuthorizer, hub string) (bool, error) {
+	if authorizer == nil {
+		return true, nil
+	}
+	user := ginCtx.GetString(UserKey)
+	if user == "" {
+		return false, nil
+	}
+	return authorizer(ginCtx.Request.Context(), user, ginCtx.GetStringSlice(GroupsKey), hub)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package certificates
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListCertificates godoc
+// @summary list the certificate expiries
+// @description list the expiry of the klusterlet, api server and transport certificates and tokens of all the hubs,
+// @description the ones expire first are listed first
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the certificates by the leaf hub name"
+// @param        source           query     string  false  "filter the certificates by the source, e.g. HubKubeconfig"
+// @param        kind             query     string  false  "filter the certificates by the kind: Certificate or Token"
+// @param        namespace        query     string  false  "filter the certificates by the namespace of the secret"
+// @param        expiring         query     bool    false  "only list the certificates expire within the window"
+// @param        expiringWithin   query     string  false  "only list the certificates expire within the duration, e.g. 72h"
+// @param        expired          query     bool    false  "only list the expired certificates"
+// @param        limit            query     int     false  "maximum certificate number to receive"
+// @success      200  {array}     models.CertificateExpiry
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /certificates [get]
+func ListCertificates(window time.Duration) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.CertificateExpiry{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if source := ginCtx.Query("source"); source != "" {
+			query = query.Where("source = ?", source)
+		}
+		if kind := ginCtx.Query("kind"); kind != "" {
+			query = query.Where("kind = ?", kind)
+		}
+		if namespace := ginCtx.Query("namespace"); namespace != "" {
+			query = query.Where("namespace = ?", namespace)
+		}
+		now := time.Now()
+		if expiring := ginCtx.Query("expiring"); expiring == "true" {
+			query = query.Where("not_after < ?", now.Add(window))
+		}
+		if expiringWithin := ginCtx.Query("expiringWithin"); expiringWithin != "" {
+			duration, err := time.ParseDuration(expiringWithin)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid expiringWithin: %s", expiringWithin)
+				return
+			}
+			query = query.Where("not_after < ?", now.Add(duration))
+		}
+		if expired := ginCtx.Query("expired"); expired == "true" {
+			query = query.Where("not_after < ?", now)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		certificates := []models.CertificateExpiry{}
+		err := query.Order("not_after, leaf_hub_name, source, namespace, name").Find(&certificates).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing certificates: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, certificates)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clusterdeployments
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListClusterDeployments godoc
+// @summary list hive clusterdeployments
+// @description list the clusters provisioned by hive on all the hubs with the provisioning phase, the power state and
+// @description the failure of the install
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the clusterdeployments by the leaf hub name"
+// @param        namespace        query     string  false  "filter the clusterdeployments by the namespace"
+// @param        phase            query     string  false  "filter the clusterdeployments by the phase: Pending, Provisioning, ProvisionFailed, ProvisionStopped, Provisioned or Deprovisioning"
+// @param        powerState       query     string  false  "filter the clusterdeployments by the power state, e.g. Running or Hibernating"
+// @param        platform         query     string  false  "filter the clusterdeployments by the platform, e.g. aws"
+// @param        pool             query     string  false  "filter the clusterdeployments by the name of the clusterpool"
+// @param        minAge           query     string  false  "filter the clusterdeployments created before the duration, e.g. 2h for the stuck installs"
+// @param        limit            query     int     false  "maximum clusterdeployment number to receive"
+// @success      200  {array}     models.ClusterDeployment
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /clusterdeployments [get]
+func ListClusterDeployments() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.ClusterDeployment{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if namespace := ginCtx.Query("namespace"); namespace != "" {
+			query = query.Where("namespace = ?", namespace)
+		}
+		if phase := ginCtx.Query("phase"); phase != "" {
+			query = query.Where("phase = ?", phase)
+		}
+		if powerState := ginCtx.Query("powerState"); powerState != "" {
+			query = query.Where("power_state = ?", powerState)
+		}
+		if platform := ginCtx.Query("platform"); platform != "" {
+			query = query.Where("platform = ?", platform)
+		}
+		if pool := ginCtx.Query("pool"); pool != "" {
+			query = query.Where("pool_name = ?", pool)
+		}
+		if minAge := ginCtx.Query("minAge"); minAge != "" {
+			age, err := time.ParseDuration(minAge)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid minAge: %s", minAge)
+				return
+			}
+			query = query.Where("cluster_created_at < ?", time.Now().UTC().Add(-age))
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		clusterDeployments := []models.ClusterDeployment{}
+		err := query.Order("leaf_hub_name, namespace, name").Find(&clusterDeployments).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing clusterdeployments: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, clusterDeployments)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clusterinventory
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListClusterInventory godoc
+// @summary list cluster inventory
+// @description list the capacity and the version of the managed clusters of all the hubs, e.g. the clusters on 4.14
+// @description with an available 4.15 upgrade by "version=4.14&upgradeTo=4.15"
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the clusters by the leaf hub name"
+// @param        cloud            query     string  false  "filter the clusters by the cloud vendor, e.g. Amazon"
+// @param        region           query     string  false  "filter the clusters by the region"
+// @param        channel          query     string  false  "filter the clusters by the upgrade channel"
+// @param        version          query     string  false  "filter the clusters by the openshift version or its prefix, e.g. 4.14"
+// @param        upgradeTo        query     string  false  "filter the clusters by the available upgrade or its prefix, e.g. 4.15"
+// @param        upgradeFailed    query     bool    false  "filter the clusters by whether the upgrade is failed"
+// @param        limit            query     int     false  "maximum cluster number to receive"
+// @success      200  {array}     models.ClusterInventory
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /clusterinventory [get]
+func ListClusterInventory() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query, err := filter(ginCtx, db.WithContext(ginCtx.Request.Context()).Model(&models.ClusterInventory{}))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		clusters := []models.ClusterInventory{}
+		err = query.Order("leaf_hub_name, cluster_name").Find(&clusters).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing cluster inventory: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, clusters)
+	}
+}
+
+// filter applies the query parameters shared by the list and the summary. The version and the upgrade are matched
+// by the exact version or its prefix, so "4.14" matches "4.14.8" but not "4.140.0".
+func filter(ginCtx *gin.Context, query *gorm.DB) (*gorm.DB, error) {
+	if hub := ginCtx.Query("hub"); hub != "" {
+		query = query.Where("leaf_hub_name = ?", hub)
+	}
+	if cloud := ginCtx.Query("cloud"); cloud != "" {
+		query = query.Where("cloud = ?", cloud)
+	}
+	if region := ginCtx.Query("region"); region != "" {
+		query = query.Where("region = ?", region)
+	}
+	if channel := ginCtx.Query("channel"); channel != "" {
+		query = query.Where("channel = ?", channel)
+	}
+	if version := ginCtx.Query("version"); version != "" {
+		query = query.Where("openshift_version = ? OR openshift_version LIKE ?", version, version+".%")
+	}
+	if upgradeTo := ginCtx.Query("upgradeTo"); upgradeTo != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(available_updates) AS u(version) "+
+			"WHERE u.version = ? OR u.version LIKE ?)", upgradeTo, upgradeTo+".%")
+	}
+	if upgradeFailed := ginCtx.Query("upgradeFailed"); upgradeFailed != "" {
+		failed, err := strconv.ParseBool(upgradeFailed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid upgradeFailed: %s", upgradeFailed)
+		}
+		query = query.Where("upgrade_failed = ?", failed)
+	}
+	return query, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clusterinventory
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// groupByColumns are the supported groupBy values and the expressions of them, the minorVersion is the major and
+// minor of the openshift version, e.g. "4.14"
+var groupByColumns = map[string]string{
+	"hub":          "leaf_hub_name",
+	"cloud":        "cloud",
+	"region":       "region",
+	"channel":      "channel",
+	"kubeVendor":   "kube_vendor",
+	"version":      "openshift_version",
+	"minorVersion": `substring(openshift_version from '^[0-9]+\.[0-9]+')`,
+}
+
+// InventorySummary is the capacity of the clusters in a group, the cpu is in millicores and the memory is in bytes
+type InventorySummary struct {
+	Group             string `json:"group"`
+	Clusters          int64  `json:"clusters"`
+	Nodes             int64  `json:"nodes"`
+	ReadyNodes        int64  `json:"readyNodes"`
+	CPUCapacity       int64  `json:"cpuCapacity"`
+	MemoryCapacity    int64  `json:"memoryCapacity"`
+	CPUAllocatable    int64  `json:"cpuAllocatable"`
+	MemoryAllocatable int64  `json:"memoryAllocatable"`
+}
+
+// SummarizeClusterInventory godoc
+// @summary summarize cluster inventory
+// @description aggregate the capacity of the managed clusters of all the hubs by the group, e.g. the total cpu per
+// @description region by "groupBy=region". The filters are same as the list of the cluster inventory.
+// @accept json
+// @produce json
+// @param        groupBy          query     string  true   "hub, cloud, region, channel, kubeVendor, version or minorVersion"
+// @param        hub              query     string  false  "filter the clusters by the leaf hub name"
+// @param        cloud            query     string  false  "filter the clusters by the cloud vendor, e.g. Amazon"
+// @param        region           query     string  false  "filter the clusters by the region"
+// @param        channel          query     string  false  "filter the clusters by the upgrade channel"
+// @param        version          query     string  false  "filter the clusters by the openshift version or its prefix, e.g. 4.14"
+// @param        upgradeTo        query     string  false  "filter the clusters by the available upgrade or its prefix, e.g. 4.15"
+// @param        upgradeFailed    query     bool    false  "filter the clusters by whether the upgrade is failed"
+// @success      200  {array}     InventorySummary
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /clusterinventory/summary [get]
+func SummarizeClusterInventory() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		groupBy := ginCtx.Query("groupBy")
+		column, ok := groupByColumns[groupBy]
+		if !ok {
+			ginCtx.String(http.StatusBadRequest, "invalid groupBy: %s", groupBy)
+			return
+		}
+
+		db := database.GetGorm()
+		query, err := filter(ginCtx, db.WithContext(ginCtx.Request.Context()).Model(&models.ClusterInventory{}))
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		summaries := []InventorySummary{}
+		err = query.Select(fmt.Sprintf("COALESCE(%s, '') AS \"group\", COUNT(*) AS clusters, "+
+			"SUM(node_count) AS nodes, SUM(ready_node_count) AS ready_nodes, "+
+			"SUM(cpu_capacity) AS cpu_capacity, SUM(memory_capacity) AS memory_capacity, "+
+			"SUM(cpu_allocatable) AS cpu_allocatable, SUM(memory_allocatable) AS memory_allocatable", column)).
+			Group("1").Order("1").Scan(&summaries).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in summarizing cluster inventory: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, summaries)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package clusterpools
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListClusterPools godoc
+// @summary list hive clusterpools
+// @description list the hive clusterpools of all the hubs with the size and the number of the ready and standby
+// @description clusters
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the clusterpools by the leaf hub name"
+// @param        namespace        query     string  false  "filter the clusterpools by the namespace"
+// @param        platform         query     string  false  "filter the clusterpools by the platform, e.g. aws"
+// @param        limit            query     int     false  "maximum clusterpool number to receive"
+// @success      200  {array}     models.ClusterPool
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /clusterpools [get]
+func ListClusterPools() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.ClusterPool{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if namespace := ginCtx.Query("namespace"); namespace != "" {
+			query = query.Where("namespace = ?", namespace)
+		}
+		if platform := ginCtx.Query("platform"); platform != "" {
+			query = query.Where("platform = ?", platform)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		clusterPools := []models.ClusterPool{}
+		err := query.Order("leaf_hub_name, namespace, name").Find(&clusterPools).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing clusterpools: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, clusterPools)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package deadletters
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// ListDeadLetters godoc
+// @summary list dead letters
+// @description list the status events which are failed to be handled or have no handler registered
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the dead letters by the leaf hub name"
+// @param        type             query     string  false  "filter the dead letters by the event type"
+// @param        reason           query     string  false  "filter the dead letters by the reason: handler_failed or unregistered"
+// @param        replayed         query     bool    false  "include the replayed dead letters"
+// @param        limit            query     int     false  "maximum dead letter number to receive"
+// @success      200  {array}     models.DeadLetter
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /deadletters [get]
+func ListDeadLetters() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		opts := deadletter.ListOptions{
+			LeafHubName: ginCtx.Query("hub"),
+			EventType:   ginCtx.Query("type"),
+			Reason:      ginCtx.Query("reason"),
+		}
+		if replayed := ginCtx.Query("replayed"); replayed != "" {
+			includeReplayed, err := strconv.ParseBool(replayed)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid replayed: %s", replayed)
+				return
+			}
+			opts.IncludeReplayed = includeReplayed
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			opts.Limit = limitNum
+		}
+
+		deadLetters, err := deadletter.List(opts)
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing dead letters: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, deadLetters)
+	}
+}
+
+// GetDeadLetter godoc
+// @summary get dead letter
+// @description get the dead letter with the payload of the event
+// @accept json
+// @produce json
+// @param        deadLetterID    path    int    true    "Dead Letter ID"
+// @success      200  {object}     models.DeadLetter
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @security     ApiKeyAuth
+// @router /deadletter/{deadLetterID} [get]
+func GetDeadLetter() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		id, ok := parseID(ginCtx)
+		if !ok {
+			return
+		}
+		deadLetter, err := deadletter.Get(id)
+		if err != nil {
+			handleError(ginCtx, id, err)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, deadLetter)
+	}
+}
+
+// ReplayDeadLetter godoc
+// @summary replay dead letter
+// @description replay the dead letter through the conflation pipeline, the event is rejected if it's superseded
+// @description or the user isn't authorized to update the hub of it. The dead letter is marked as replayed once the
+// @description event is committed, and it's unavailable on the replica which doesn't run the pipeline
+// @accept json
+// @produce json
+// @param        deadLetterID    path    int    true    "Dead Letter ID"
+// @success      200  {object}     models.DeadLetter
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      409
+// @failure      503
+// @security     ApiKeyAuth
+// @router /deadletter/{deadLetterID}/replay [post]
+func ReplayDeadLetter(authorizer authentication.HubAuthorizer) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		id, ok := parseID(ginCtx)
+		if !ok {
+			return
+		}
+		deadLetter, err := deadletter.Get(id)
+		if err != nil {
+			handleError(ginCtx, id, err)
+			return
+		}
+		authorized, err := authentication.AuthorizeHub(ginCtx, authorizer, deadLetter.LeafHubName)
+		if err != nil {
+			handleError(ginCtx, id, err)
+			return
+		}
+		if !authorized {
+			ginCtx.String(http.StatusForbidden, "not authorized to replay the dead letter of the hub %s",
+				deadLetter.LeafHubName)
+			return
+		}
+		deadLetter, err = deadletter.Replay(id)
+		if errors.Is(err, deadletter.ErrPipelineNotRunning) {
+			ginCtx.String(http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if errors.Is(err, deadletter.ErrReplayRejected) {
+			ginCtx.String(http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			handleError(ginCtx, id, err)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, deadLetter)
+	}
+}
+
+func parseID(ginCtx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ginCtx.Param("deadLetterID"), 10, 64)
+	if err != nil {
+		ginCtx.String(http.StatusBadRequest, "invalid dead letter id: %s", ginCtx.Param("deadLetterID"))
+		return 0, false
+	}
+	return id, true
+}
+
+func handleError(ginCtx *gin.Context, id int64, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ginCtx.String(http.StatusNotFound, "dead letter %d not found", id)
+		return
+	}
+	_, _ = fmt.Fprintf(gin.DefaultWriter, "error in getting dead letter(%d): %v\n", id, err)
+	ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+}
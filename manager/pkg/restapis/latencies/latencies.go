@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package latencies
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/latency"
+)
+
+// ListLatencies godoc
+// @summary list status latencies
+// @description list the latency of the status events from the emission of the agent to the received, conflated and
+// @description committed stages of the manager, the stages are corrected by the clock offset of the hubs estimated
+// @description by the heartbeats, and the raw stages aren't corrected
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the latencies by the leaf hub name"
+// @success      200  {array}     latency.HubLatency
+// @failure      401
+// @failure      403
+// @security     ApiKeyAuth
+// @router /latencies [get]
+func ListLatencies() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		ginCtx.JSON(http.StatusOK, latency.List(ginCtx.Query("hub")))
+	}
+}
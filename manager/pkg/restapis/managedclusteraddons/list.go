@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusteraddons
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// conditionParams are the query parameters filtering the addons by the status of the conditions
+var conditionParams = []string{"available", "degraded", "progressing"}
+
+// ListManagedClusterAddOns godoc
+// @summary list managed cluster addons
+// @description list the addons of the managed clusters of all the hubs with the status of the available, degraded
+// @description and progressing conditions
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the addons by the leaf hub name"
+// @param        cluster          query     string  false  "filter the addons by the managed cluster name"
+// @param        addon            query     string  false  "filter the addons by the addon name, e.g. application-manager"
+// @param        available        query     string  false  "filter the addons by the available condition: true, false or unknown"
+// @param        degraded         query     string  false  "filter the addons by the degraded condition: true, false or unknown"
+// @param        progressing      query     string  false  "filter the addons by the progressing condition: true, false or unknown"
+// @param        limit            query     int     false  "maximum addon number to receive"
+// @success      200  {array}     models.ManagedClusterAddOn
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /managedclusteraddons [get]
+func ListManagedClusterAddOns() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.ManagedClusterAddOn{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if cluster := ginCtx.Query("cluster"); cluster != "" {
+			query = query.Where("cluster_name = ?", cluster)
+		}
+		if addon := ginCtx.Query("addon"); addon != "" {
+			query = query.Where("addon_name = ?", addon)
+		}
+		for _, param := range conditionParams {
+			value := ginCtx.Query(param)
+			if value == "" {
+				continue
+			}
+			status, err := util.ParseConditionStatus(value)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid %s: %s", param, value)
+				return
+			}
+			// the column is named by the condition
+			query = query.Where(param+" = ?", status)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		addons := []models.ManagedClusterAddOn{}
+		err := query.Order("leaf_hub_name, cluster_name, addon_name").Find(&addons).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing managed cluster addons: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, addons)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package manifestworks
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// conditionParams are the query parameters filtering the manifestworks by the status of the conditions
+var conditionParams = []string{"applied", "available", "degraded", "progressing"}
+
+// ListManifestWorks godoc
+// @summary list manifestworks
+// @description list the manifestworks delivered to the managed clusters of all the hubs with the status of the
+// @description applied, available, degraded and progressing conditions, and the status feedback of the manifests
+// @accept json
+// @produce json
+// @param        hub                  query     string  false  "filter the manifestworks by the leaf hub name"
+// @param        cluster              query     string  false  "filter the manifestworks by the managed cluster name"
+// @param        name                 query     string  false  "filter the manifestworks by the name"
+// @param        placementNamespace   query     string  false  "filter the manifestworks by the namespace of the placement"
+// @param        placement            query     string  false  "filter the manifestworks by the name of the placement"
+// @param        applied              query     string  false  "filter the manifestworks by the applied condition: true, false or unknown"
+// @param        available            query     string  false  "filter the manifestworks by the available condition: true, false or unknown"
+// @param        degraded             query     string  false  "filter the manifestworks by the degraded condition: true, false or unknown"
+// @param        progressing          query     string  false  "filter the manifestworks by the progressing condition: true, false or unknown"
+// @param        limit                query     int     false  "maximum manifestwork number to receive"
+// @success      200  {array}     models.ManifestWork
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /manifestworks [get]
+func ListManifestWorks() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.ManifestWork{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("leaf_hub_name = ?", hub)
+		}
+		if cluster := ginCtx.Query("cluster"); cluster != "" {
+			query = query.Where("cluster_name = ?", cluster)
+		}
+		if name := ginCtx.Query("name"); name != "" {
+			query = query.Where("work_name = ?", name)
+		}
+		if namespace := ginCtx.Query("placementNamespace"); namespace != "" {
+			query = query.Where("placement_namespace = ?", namespace)
+		}
+		if placement := ginCtx.Query("placement"); placement != "" {
+			query = query.Where("placement_name = ?", placement)
+		}
+		for _, param := range conditionParams {
+			value := ginCtx.Query(param)
+			if value == "" {
+				continue
+			}
+			status, err := util.ParseConditionStatus(value)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid %s: %s", param, value)
+				return
+			}
+			// the column is named by the condition
+			query = query.Where(param+" = ?", status)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		works := []models.ManifestWork{}
+		err := query.Order("leaf_hub_name, cluster_name, work_name").Find(&works).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing manifestworks: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, works)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// clusterRisksQuery sums the alerts and the image vulnerabilities of each cluster over the Centrals of the hub, the
+// cluster is listed if it has either of them
+const clusterRisksQuery = `
+WITH alerts AS (
+	SELECT hub_name, cluster_name, SUM(low) AS low, SUM(medium) AS medium, SUM(high) AS high,
+		SUM(critical) AS critical
+	FROM security.cluster_alert_counts GROUP BY hub_name, cluster_name
+), vulnerabilities AS (
+	SELECT hub_name, cluster_name, SUM(low) AS low, SUM(moderate) AS moderate, SUM(important) AS important,
+		SUM(critical) AS critical, SUM(fixable) AS fixable
+	FROM security.image_vulnerabilities GROUP BY hub_name, cluster_name
+)
+SELECT COALESCE(a.hub_name, v.hub_name) AS hub_name, COALESCE(a.cluster_name, v.cluster_name) AS cluster_name,
+	COALESCE(a.critical, 0) AS critical_alerts, COALESCE(a.high, 0) AS high_alerts,
+	COALESCE(a.medium, 0) AS medium_alerts, COALESCE(a.low, 0) AS low_alerts,
+	COALESCE(v.critical, 0) AS critical_vulnerabilities, COALESCE(v.important, 0) AS important_vulnerabilities,
+	COALESCE(v.moderate, 0) AS moderate_vulnerabilities, COALESCE(v.low, 0) AS low_vulnerabilities,
+	COALESCE(v.fixable, 0) AS fixable_vulnerabilities
+FROM alerts a FULL OUTER JOIN vulnerabilities v ON a.hub_name = v.hub_name AND a.cluster_name = v.cluster_name`
+
+// clusterRisksOrder ranks the clusters by the most severe findings first, a critical alert or vulnerability outweighs
+// any number of the less severe ones
+const clusterRisksOrder = "critical_alerts + critical_vulnerabilities DESC, " +
+	"high_alerts + important_vulnerabilities DESC, medium_alerts + moderate_vulnerabilities DESC, " +
+	"low_alerts + low_vulnerabilities DESC, hub_name, cluster_name"
+
+// ClusterRisk is the number of the active security alerts and the image vulnerabilities of a managed cluster
+type ClusterRisk struct {
+	HubName                  string `json:"hubName"`
+	ClusterName              string `json:"clusterName"`
+	CriticalAlerts           int64  `json:"criticalAlerts"`
+	HighAlerts               int64  `json:"highAlerts"`
+	MediumAlerts             int64  `json:"mediumAlerts"`
+	LowAlerts                int64  `json:"lowAlerts"`
+	CriticalVulnerabilities  int64  `json:"criticalVulnerabilities"`
+	ImportantVulnerabilities int64  `json:"importantVulnerabilities"`
+	ModerateVulnerabilities  int64  `json:"moderateVulnerabilities"`
+	LowVulnerabilities       int64  `json:"lowVulnerabilities"`
+	FixableVulnerabilities   int64  `json:"fixableVulnerabilities"`
+}
+
+// ListClusterRisks godoc
+// @summary rank the managed clusters by the security risk
+// @description list the active StackRox alerts and the image vulnerabilities of the managed clusters of all the hubs
+// @description by the severity, the clusters with the most severe findings are listed first
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the clusters by the leaf hub name"
+// @param        cluster          query     string  false  "filter the clusters by the managed cluster name"
+// @param        limit            query     int     false  "maximum cluster number to receive"
+// @success      200  {array}     ClusterRisk
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /security/clusterrisks [get]
+func ListClusterRisks() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Table("(?) AS risks", db.Raw(clusterRisksQuery))
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("hub_name = ?", hub)
+		}
+		if cluster := ginCtx.Query("cluster"); cluster != "" {
+			query = query.Where("cluster_name = ?", cluster)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		risks := []ClusterRisk{}
+		err := query.Order(clusterRisksOrder).Scan(&risks).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing cluster risks: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, risks)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package security
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ListPolicyViolations godoc
+// @summary list the violated StackRox policies
+// @description list the most violated StackRox policies of the managed clusters of all the hubs, ordered by the
+// @description number of the active alerts
+// @accept json
+// @produce json
+// @param        hub              query     string  false  "filter the violations by the leaf hub name"
+// @param        cluster          query     string  false  "filter the violations by the managed cluster name"
+// @param        policy           query     string  false  "filter the violations by the policy name"
+// @param        severity         query     string  false  "filter the violations by the policy severity: low, medium, high or critical"
+// @param        limit            query     int     false  "maximum violation number to receive"
+// @success      200  {array}     models.SecurityPolicyViolation
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /security/policyviolations [get]
+func ListPolicyViolations() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		db := database.GetGorm()
+		query := db.WithContext(ginCtx.Request.Context()).Model(&models.SecurityPolicyViolation{})
+		if hub := ginCtx.Query("hub"); hub != "" {
+			query = query.Where("hub_name = ?", hub)
+		}
+		if cluster := ginCtx.Query("cluster"); cluster != "" {
+			query = query.Where("cluster_name = ?", cluster)
+		}
+		if policy := ginCtx.Query("policy"); policy != "" {
+			query = query.Where("policy_name = ?", policy)
+		}
+		if severity := ginCtx.Query("severity"); severity != "" {
+			query = query.Where("severity = ?", severity)
+		}
+		if limit := ginCtx.Query("limit"); limit != "" {
+			limitNum, err := strconv.Atoi(limit)
+			if err != nil {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limit)
+				return
+			}
+			query = query.Limit(limitNum)
+		}
+
+		violations := []models.SecurityPolicyViolation{}
+		err := query.Order("count DESC, hub_name, cluster_name, policy_name").Find(&violations).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in listing policy violations: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, violations)
+	}
+}
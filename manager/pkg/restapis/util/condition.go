@@ -0,0 +1,26 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParseConditionStatus converts the query value to the status of the condition, e.g. "true" to "True"
+func ParseConditionStatus(value string) (metav1.ConditionStatus, error) {
+	if strings.EqualFold(value, string(metav1.ConditionUnknown)) {
+		return metav1.ConditionUnknown, nil
+	}
+	status, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", err
+	}
+	if status {
+		return metav1.ConditionTrue, nil
+	}
+	return metav1.ConditionFalse, nil
+}
@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"time"
 
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -84,7 +87,7 @@ func (syncer *genericDBToTransportSyncer) periodicSync(ctx context.Context) {
 func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventType string,
 	specDB specdb.SpecDB, dbTableName string, createObjFunc bundle.CreateObjectFunction,
 	createBundleFunc bundle.CreateBundleFunction, lastSyncTimestampPtr *time.Time,
-) (bool, error) {
+) (synced bool, err error) {
 	lastUpdateTimestamp, err := specDB.GetLastUpdateTimestamp(ctx, dbTableName, true) // filter local resources
 	if err != nil {
 		return false, fmt.Errorf("unable to sync bundle - %w", err)
@@ -96,6 +99,11 @@ func syncObjectsBundle(ctx context.Context, producer transport.Producer, eventTy
 
 	// if we got here, then the last update timestamp from db is after what we have in memory.
 	// this means something has changed in db, syncing all the objects to transport.
+	// the trace of the spec change starts here, and it's continued by the agents with the traceparent of the event
+	ctx, span := tracing.Tracer().Start(ctx, "sync spec "+dbTableName, trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.DBCollectionName(dbTableName)))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	bundleResult := createBundleFunc()
 	lastUpdateTimestamp, err = specDB.GetObjectsBundle(ctx, dbTableName, createObjFunc, bundleResult)
 	if err != nil {
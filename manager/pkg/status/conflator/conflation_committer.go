@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -19,14 +20,35 @@ type MetadataFunc func() []ConflationMetadata
 
 const KafkaPartitionDelimiter = "@"
 
+// transportPartitionDelimiter separates the partition from the topic in the stored position, it isn't a valid
+// character of the kafka topic name
+const transportPartitionDelimiter = ":"
+
 func positionKey(topic string, partition int32) string {
 	return fmt.Sprintf("%s@%d", topic, partition)
 }
 
+// transportName is the name of the stored position, the identity of the kafka cluster is appended to the topic if the
+// manager consumes from multiple kafka clusters, and the partition is appended if it isn't the first one of the topic,
+// e.g. the shared status topic, otherwise the positions of the same topic overwrite each other. The name of the first
+// partition keeps the topic, so the positions stored before are still valid.
+func transportName(position *transport.EventPosition) string {
+	name := position.Topic
+	if position.Partition != 0 {
+		name = fmt.Sprintf("%s%s%d", name, transportPartitionDelimiter, position.Partition)
+	}
+	if position.OwnerIdentity == "" {
+		return name
+	}
+	return name + KafkaPartitionDelimiter + position.OwnerIdentity
+}
+
 type ConflationCommitter struct {
 	log                  *zap.SugaredLogger
 	retrieveMetadataFunc MetadataFunc
 	committedPositions   map[string]int64
+	// the commit is invoked by the ticker and the rebalancing of the sharded consumer
+	mutex sync.Mutex
 }
 
 func NewKafkaConflationCommitter(metadataFunc MetadataFunc) *ConflationCommitter {
@@ -61,6 +83,9 @@ func (k *ConflationCommitter) Start(ctx context.Context) error {
 }
 
 func (k *ConflationCommitter) commit() error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
 	// get metadata (both pending and processed)
 	transportMetadatas := k.retrieveMetadataFunc()
 
@@ -85,7 +110,7 @@ func (k *ConflationCommitter) commit() error {
 			return err
 		}
 		databaseTransports = append(databaseTransports, models.Transport{
-			Name:    transPosition.Topic,
+			Name:    transportName(transPosition),
 			Payload: payload,
 		})
 		k.committedPositions[key] = int64(transPosition.Offset)
@@ -110,6 +135,15 @@ func (k *ConflationCommitter) commit() error {
 	return nil
 }
 
+// forget clears the committed offsets of the revoked partitions, since they're committed by the other replicas
+func (k *ConflationCommitter) forget(keys []string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	for _, key := range keys {
+		delete(k.committedPositions, key)
+	}
+}
+
 func metadataToCommit(metadataArray []ConflationMetadata) map[string]*transport.EventPosition {
 	// extract the lowest per partition in the pending bundles, the highest per partition in the processed bundles
 	pendingLowestMetadataMap := make(map[string]*transport.EventPosition)
@@ -122,7 +156,11 @@ func metadataToCommit(metadataArray []ConflationMetadata) map[string]*transport.
 
 		// metadata := bundleStatus.GetTransportMetadata()
 		position := metadata.TransportPosition()
-		key := positionKey(position.Topic, position.Partition)
+		// the replayed events aren't consumed from the transport, there is no position to commit
+		if position == nil || position.Topic == "" {
+			continue
+		}
+		key := positionKey(transportName(position), position.Partition)
 
 		if !metadata.Processed() {
 			// this belongs to a pending bundle, update the lowest-offsets-map
@@ -29,6 +29,54 @@ func TestCommitOffset(t *testing.T) {
 	assert.Equal(t, metadatas[positionKey("topic3", 0)].Offset, int64(6))
 }
 
+func TestCommitOffsetOfKafkaClusters(t *testing.T) {
+	transportMetadatas := []ConflationMetadata{
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{OwnerIdentity: "cluster1", Topic: "gh-status.hub1", Partition: 0, Offset: 3}),
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{OwnerIdentity: "cluster2", Topic: "gh-status.hub1", Partition: 0, Offset: 8}),
+	}
+
+	// the positions of the same topic from the different kafka clusters don't overwrite each other
+	metadatas := metadataToCommit(transportMetadatas)
+	assert.Len(t, metadatas, 2)
+	assert.Equal(t, int64(4), metadatas[positionKey("gh-status.hub1@cluster1", 0)].Offset)
+	assert.Equal(t, int64(9), metadatas[positionKey("gh-status.hub1@cluster2", 0)].Offset)
+	assert.Equal(t, "gh-status.hub1@cluster2", transportName(metadatas[positionKey("gh-status.hub1@cluster2", 0)]))
+	assert.Equal(t, "gh-status.hub1", transportName(&transport.EventPosition{Topic: "gh-status.hub1"}))
+}
+
+func TestCommitOffsetOfSharedTopic(t *testing.T) {
+	transportMetadatas := []ConflationMetadata{
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{Topic: "gh-status", Partition: 0, Offset: 3}),
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{Topic: "gh-status", Partition: 1, Offset: 8}),
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{OwnerIdentity: "cluster1", Topic: "gh-status", Partition: 1, Offset: 5}),
+	}
+
+	// the positions of the partitions are stored in their own rows, the first one keeps the topic name
+	names := map[string]int64{}
+	for _, position := range metadataToCommit(transportMetadatas) {
+		names[transportName(position)] = position.Offset
+	}
+	assert.Equal(t, map[string]int64{"gh-status": 4, "gh-status:1": 9, "gh-status:1@cluster1": 6}, names)
+}
+
+func TestSkipReplayedPosition(t *testing.T) {
+	transportMetadatas := []ConflationMetadata{
+		metadata.NewThresholdMetadataFromPosition(0,
+			&transport.EventPosition{Topic: "gh-status.hub1", Partition: 0, Offset: 3}),
+		// the replayed event has no transport position
+		metadata.NewThresholdMetadataFromPosition(3, &transport.EventPosition{}),
+	}
+
+	metadatas := metadataToCommit(transportMetadatas)
+	assert.Len(t, metadatas, 1)
+	assert.Equal(t, int64(4), metadatas[positionKey("gh-status.hub1", 0)].Offset)
+}
+
 func getTransportMetadatas(topic string, processedOffsets []int64, unprocessedOffsets []int64) []ConflationMetadata {
 	transportMetadatas := make([]ConflationMetadata, len(unprocessedOffsets)+len(processedOffsets))
 	for _, offset := range unprocessedOffsets {
@@ -1,6 +1,10 @@
 package conflator
 
-import cloudevents "github.com/cloudevents/sdk-go/v2"
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
 
 func NewConflationJob(event *cloudevents.Event, metadata ConflationMetadata, handle EventHandleFunc,
 	reporter ResultReporter, state *ElementState,
@@ -11,6 +15,7 @@ func NewConflationJob(event *cloudevents.Event, metadata ConflationMetadata, han
 		Handle:       handle,
 		Reporter:     reporter,
 		ElementState: state,
+		EnqueuedAt:   time.Now(),
 	}
 }
 
@@ -24,4 +29,18 @@ type ConflationJob struct {
 	Reporter ResultReporter
 
 	ElementState *ElementState
+
+	// EnqueuedAt is the time when the event is inserted into the conflation unit
+	EnqueuedAt time.Time
+
+	// done releases the slot of the leaf hub in the ready queue
+	done func()
+}
+
+// Done is invoked once the job is finished by the worker, so the other jobs of the leaf hub can be dispatched
+func (job *ConflationJob) Done() {
+	if job.done != nil {
+		job.done()
+		job.done = nil
+	}
 }
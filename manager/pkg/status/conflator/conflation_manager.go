@@ -8,6 +8,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/metadata"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -25,6 +26,10 @@ type ConflationManager struct {
 	lock          sync.Mutex
 	statistics    *statistics.Statistics
 	Requster      transport.Requester
+	// ownedPartitions are the partitions assigned to the manager replica when the status processing is sharded,
+	// the events of the other partitions are dropped. It's nil if the replica consumes all the partitions
+	ownedPartitions map[string]bool
+	partitionLock   sync.RWMutex
 }
 
 // NewConflationManager creates a new instance of ConflationManager.
@@ -56,25 +61,75 @@ func (cm *ConflationManager) Register(registration *ConflationRegistration) {
 // Insert function inserts the bundle to the appropriate conflation unit.
 func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
 	// validate the event
-	if _, ok := cm.registrations[evt.Type()]; !ok {
+	registration, ok := cm.registrations[evt.Type()]
+	if !ok {
 		cm.log.Infow("unregistered event type", "type", enum.ShortenEventType(evt.Type()))
 		fmt.Print(evt)
+		deadletter.Save(evt, deadletter.ReasonUnregistered, nil)
 		return
 	}
 	// metadata
-	conflationMetadata := metadata.NewThresholdMetadata(consumer.TransportID(), 3, evt)
+	conflationMetadata := metadata.NewThresholdMetadata(clusterIdentity(evt), 3, evt)
 	if conflationMetadata == nil {
 		return
 	}
+	if !cm.ownsPartition(conflationMetadata.TransportPosition()) {
+		cm.log.Debugw("drop the event of the revoked partition", "type", enum.ShortenEventType(evt.Type()),
+			"source", evt.Source())
+		return
+	}
+	if err := cm.admit(registration, evt, evt.Type()); err != nil {
+		cm.log.Warnw("drop the event exceeding the quota, it's resynced once the hub is drained",
+			"type", enum.ShortenEventType(evt.Type()), "source", evt.Source(), "error", err)
+		return
+	}
+
+	if !cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata) {
+		cm.cancel(registration, evt)
+	}
+}
 
-	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+// Replay inserts the dead lettered event into the conflation unit again. Unlike the Insert, it returns error if the
+// event type isn't registered or the event is superseded by a newer version of the hub. The event of the hub which
+// is owned by another replica of the sharded manager is refused, so the replica doesn't race the owner.
+func (cm *ConflationManager) Replay(evt *cloudevents.Event) error {
+	if !cm.ownsHub(evt.Source()) {
+		return fmt.Errorf("%w: the hub %s is owned by another replica", deadletter.ErrPipelineNotRunning,
+			evt.Source())
+	}
+	registration, ok := cm.registrations[evt.Type()]
+	if !ok {
+		return fmt.Errorf("the event type %s isn't registered", enum.ShortenEventType(evt.Type()))
+	}
+	conflationMetadata := metadata.NewThresholdMetadata(clusterIdentity(evt), 3, evt)
+	if conflationMetadata == nil {
+		return fmt.Errorf("failed to parse the version of the event")
+	}
+	// the rejected dead letter is kept, so the event type isn't resynced
+	if err := cm.admit(registration, evt, ""); err != nil {
+		return err
+	}
+	if !cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata) {
+		cm.cancel(registration, evt)
+		return fmt.Errorf("the event(%s) is superseded by a newer version", conflationMetadata.Version())
+	}
+	return nil
 }
 
 // GetTransportMetadatas provides collections of the CU's bundle transport-metadata.
+// The metadata of the partitions which aren't owned by the replica are skipped, so that the offsets committed by the
+// new owner aren't overwritten.
 func (cm *ConflationManager) GetMetadatas() []ConflationMetadata {
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+
 	metadata := make([]ConflationMetadata, 0)
 	for _, cu := range cm.conflationUnits {
-		metadata = append(metadata, cu.getMetadatas()...)
+		for _, m := range cu.getMetadatas() {
+			if cm.ownsPartition(m.TransportPosition()) {
+				metadata = append(metadata, m)
+			}
+		}
 	}
 	return metadata
 }
@@ -94,6 +149,33 @@ func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUn
 	return conflationUnit
 }
 
+// admit reserves the slot of the leaf hub before the delta event is inserted, the complete event is always admitted
+// since it's conflated into a single bundle of the conflation unit. The event type is resynced if it's rejected.
+func (cm *ConflationManager) admit(registration *ConflationRegistration, evt *cloudevents.Event,
+	resyncType string,
+) error {
+	if registration.syncMode == enum.CompleteStateMode {
+		return nil
+	}
+	return cm.readyQueue.Admit(evt.Source(), resyncType)
+}
+
+// cancel releases the slot reserved by the admit if the delta event isn't inserted
+func (cm *ConflationManager) cancel(registration *ConflationRegistration, evt *cloudevents.Event) {
+	if registration.syncMode == enum.CompleteStateMode {
+		return
+	}
+	cm.readyQueue.Cancel(evt.Source())
+}
+
 func (cm *ConflationManager) GetReadyQueue() *ConflationReadyQueue {
 	return cm.readyQueue
 }
+
+// clusterIdentity returns the identity of the kafka cluster which the event is consumed from
+func clusterIdentity(evt *cloudevents.Event) string {
+	if identity, ok := evt.Extensions()[transport.KafkaClusterKey]; ok {
+		return fmt.Sprintf("%v", identity)
+	}
+	return consumer.TransportID()
+}
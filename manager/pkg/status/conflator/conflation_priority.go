@@ -5,18 +5,28 @@ type ConflationPriority uint8
 
 // priority list of conflation unit.
 const (
-	HubClusterHeartbeatPriority        ConflationPriority = iota
-	HubClusterInfoPriority             ConflationPriority = iota
-	ManagedClustersPriority            ConflationPriority = iota
-	ManagedClusterEventPriority        ConflationPriority = iota
-	LocalPolicySpecPriority            ConflationPriority = iota
-	LocalCompliancePriority            ConflationPriority = iota
-	LocalCompleteCompliancePriority    ConflationPriority = iota
-	LocalEventRootPolicyPriority       ConflationPriority = iota
-	LocalReplicatedPolicyEventPriority ConflationPriority = iota
-	LocalPlacementRulesSpecPriority    ConflationPriority = iota
-	SecurityAlertCountsPriority        ConflationPriority = iota
-	ManagedClusterMigrationPriority    ConflationPriority = iota
+	HubClusterHeartbeatPriority          ConflationPriority = iota
+	HubClusterInfoPriority               ConflationPriority = iota
+	ManagedClustersPriority              ConflationPriority = iota
+	ManagedClusterEventPriority          ConflationPriority = iota
+	LocalPolicySpecPriority              ConflationPriority = iota
+	LocalCompliancePriority              ConflationPriority = iota
+	LocalCompleteCompliancePriority      ConflationPriority = iota
+	LocalEventRootPolicyPriority         ConflationPriority = iota
+	LocalReplicatedPolicyEventPriority   ConflationPriority = iota
+	LocalPlacementRulesSpecPriority      ConflationPriority = iota
+	SecurityAlertCountsPriority          ConflationPriority = iota
+	SecurityClusterAlertCountsPriority   ConflationPriority = iota
+	SecurityPolicyViolationsPriority     ConflationPriority = iota
+	SecurityImageVulnerabilitiesPriority ConflationPriority = iota
+	ManagedClusterMigrationPriority      ConflationPriority = iota
+	ManagedClusterAddOnPriority          ConflationPriority = iota
+	ArgoCDApplicationPriority            ConflationPriority = iota
+	ManifestWorkPriority                 ConflationPriority = iota
+	ClusterDeploymentPriority            ConflationPriority = iota
+	ClusterPoolPriority                  ConflationPriority = iota
+	ClusterInventoryPriority             ConflationPriority = iota
+	CertificateExpiryPriority            ConflationPriority = iota
 
 	// enable global resource
 	CompliancePriority         ConflationPriority = iota
@@ -30,4 +40,6 @@ const (
 
 	SubscriptionStatusPriority ConflationPriority = iota
 	SubscriptionReportPriority ConflationPriority = iota
+
+	GenericResourcePriority ConflationPriority = iota
 )
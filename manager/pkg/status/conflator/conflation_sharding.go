@@ -0,0 +1,142 @@
+package conflator
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// ConflationSharding shards the status processing between the manager replicas by the partitions assigned from the
+// consumer group. Each replica only conflates the events of its partitions and commits their offsets, the conflation
+// units of the leaf hubs are removed once their partitions are revoked.
+//
+// The leaf hub is owned by a single replica since its events are sent to the same partition, which is guaranteed by
+// the status topic of each hub, or by the key of the events, which is the hub name, in a shared status topic. The
+// position of each partition is stored in its own row, so the replicas don't overwrite the positions of each other.
+type ConflationSharding struct {
+	log       *zap.SugaredLogger
+	manager   *ConflationManager
+	committer *ConflationCommitter
+}
+
+// NewConflationSharding enables the sharding of the conflation manager, it drops all the events until the partitions
+// are assigned to the replica
+func NewConflationSharding(manager *ConflationManager, committer *ConflationCommitter) *ConflationSharding {
+	manager.partitionLock.Lock()
+	manager.ownedPartitions = map[string]bool{}
+	manager.partitionLock.Unlock()
+
+	return &ConflationSharding{
+		log:       logger.ZapLogger("conflation-sharding"),
+		manager:   manager,
+		committer: committer,
+	}
+}
+
+// OnPartitionsAssigned implements the transport.RebalanceListener
+func (s *ConflationSharding) OnPartitionsAssigned(partitions []transport.EventPosition) {
+	s.log.Infow("partitions assigned", "partitions", partitionKeys(partitions))
+	s.manager.assignPartitions(partitions)
+}
+
+// OnPartitionsRevoked implements the transport.RebalanceListener, the offsets of the processed events are committed
+// before the partitions are revoked, so the new owner continues from them
+func (s *ConflationSharding) OnPartitionsRevoked(partitions []transport.EventPosition) {
+	s.log.Infow("partitions revoked", "partitions", partitionKeys(partitions))
+	if err := s.committer.commit(); err != nil {
+		s.log.Warnw("failed to commit the offsets of the revoked partitions", "error", err)
+	}
+	s.manager.revokePartitions(partitions)
+	s.committer.forget(partitionKeys(partitions))
+}
+
+func (cm *ConflationManager) assignPartitions(partitions []transport.EventPosition) {
+	cm.partitionLock.Lock()
+	defer cm.partitionLock.Unlock()
+	if cm.ownedPartitions == nil {
+		return
+	}
+	for _, key := range partitionKeys(partitions) {
+		cm.ownedPartitions[key] = true
+	}
+}
+
+// revokePartitions removes the conflation units which have no events of the owned partitions
+func (cm *ConflationManager) revokePartitions(partitions []transport.EventPosition) {
+	cm.partitionLock.Lock()
+	if cm.ownedPartitions == nil {
+		cm.partitionLock.Unlock()
+		return
+	}
+	for _, key := range partitionKeys(partitions) {
+		delete(cm.ownedPartitions, key)
+	}
+	cm.partitionLock.Unlock()
+
+	cm.lock.Lock()
+	defer cm.lock.Unlock()
+	for leafHubName, cu := range cm.conflationUnits {
+		owned := false
+		for _, m := range cu.getMetadatas() {
+			if cm.ownsPartition(m.TransportPosition()) {
+				owned = true
+				break
+			}
+		}
+		if owned {
+			continue
+		}
+		cu.revoke()
+		delete(cm.conflationUnits, leafHubName)
+		cm.statistics.DecrementNumberOfConflations()
+		cm.log.Infow("conflation unit is removed", "leafHub", leafHubName)
+	}
+}
+
+// ownsPartition returns true if the partition of the position is assigned to the replica, or the status processing
+// isn't sharded
+func (cm *ConflationManager) ownsPartition(position *transport.EventPosition) bool {
+	cm.partitionLock.RLock()
+	defer cm.partitionLock.RUnlock()
+	if cm.ownedPartitions == nil {
+		return true
+	}
+	if position == nil {
+		return false
+	}
+	return cm.ownedPartitions[positionKey(transportName(position), position.Partition)]
+}
+
+// ownsHub returns true if the conflation unit of the leaf hub conflates the events of the partitions owned by the
+// replica, or the status processing isn't sharded. The leaf hub without the conflation unit isn't owned, since its
+// partition is unknown until any event of it is received by the replica.
+func (cm *ConflationManager) ownsHub(leafHubName string) bool {
+	cm.partitionLock.RLock()
+	sharded := cm.ownedPartitions != nil
+	cm.partitionLock.RUnlock()
+	if !sharded {
+		return true
+	}
+
+	cm.lock.Lock()
+	cu, found := cm.conflationUnits[leafHubName]
+	cm.lock.Unlock()
+	if !found {
+		return false
+	}
+	for _, m := range cu.getMetadatas() {
+		if cm.ownsPartition(m.TransportPosition()) {
+			return true
+		}
+	}
+	return false
+}
+
+func partitionKeys(partitions []transport.EventPosition) []string {
+	keys := make([]string, 0, len(partitions))
+	for i := range partitions {
+		keys = append(keys, positionKey(transportName(&partitions[i]), partitions[i].Partition))
+	}
+	return keys
+}
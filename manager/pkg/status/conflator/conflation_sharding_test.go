@@ -0,0 +1,87 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newShardingEvent(hub, offset, ver string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(hub + "-" + offset)
+	evt.SetType(string(enum.HubClusterHeartbeatType))
+	evt.SetSource(hub)
+	evt.SetExtension(transport.KafkaTopicKey, "gh-status."+hub)
+	evt.SetExtension(transport.KafkaPartitionKey, "0")
+	evt.SetExtension(transport.KafkaOffsetKey, offset)
+	evt.SetExtension(version.ExtVersion, ver)
+	return &evt
+}
+
+func TestConflationSharding(t *testing.T) {
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}), nil)
+	cm.Register(NewConflationRegistration(HubClusterHeartbeatPriority, enum.CompleteStateMode,
+		string(enum.HubClusterHeartbeatType), func(context.Context, *cloudevents.Event) error { return nil }))
+	committer := NewKafkaConflationCommitter(cm.GetMetadatas)
+	sharding := NewConflationSharding(cm, committer)
+
+	// the events are dropped until the partitions are assigned
+	cm.Insert(newShardingEvent("hub1", "1", "0.1"))
+	assert.Empty(t, cm.conflationUnits)
+
+	sharding.OnPartitionsAssigned([]transport.EventPosition{
+		{Topic: "gh-status.hub1", Partition: 0},
+		{Topic: "gh-status.hub2", Partition: 0},
+	})
+	cm.Insert(newShardingEvent("hub1", "2", "0.2"))
+	cm.Insert(newShardingEvent("hub2", "3", "0.3"))
+	cm.Insert(newShardingEvent("hub3", "4", "0.4"))
+	assert.Len(t, cm.conflationUnits, 2)
+	assert.Len(t, cm.GetMetadatas(), 2)
+
+	// the replayed event is refused if the hub isn't owned by the replica
+	assert.NoError(t, cm.Replay(newShardingEvent("hub2", "", "0.31")))
+	assert.ErrorIs(t, cm.Replay(newShardingEvent("hub3", "", "0.41")), deadletter.ErrPipelineNotRunning)
+	assert.Len(t, cm.conflationUnits, 2)
+
+	// the conflation unit of the revoked partition is removed and stops dispatching the bundles
+	cu := cm.conflationUnits["hub1"]
+	cm.revokePartitions([]transport.EventPosition{{Topic: "gh-status.hub1", Partition: 0}})
+	assert.Len(t, cm.conflationUnits, 1)
+	assert.Contains(t, cm.conflationUnits, "hub2")
+	_, err := cu.GetNext()
+	assert.Error(t, err)
+
+	metadatas := cm.GetMetadatas()
+	assert.Len(t, metadatas, 1)
+	assert.Equal(t, "gh-status.hub2", metadatas[0].TransportPosition().Topic)
+
+	// the events of the revoked partition are dropped
+	cm.Insert(newShardingEvent("hub1", "5", "0.5"))
+	assert.Len(t, cm.conflationUnits, 1)
+}
+
+func TestConflationWithoutSharding(t *testing.T) {
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}), nil)
+	cm.Register(NewConflationRegistration(HubClusterHeartbeatPriority, enum.CompleteStateMode,
+		string(enum.HubClusterHeartbeatType), func(context.Context, *cloudevents.Event) error { return nil }))
+
+	// all the partitions are owned if the sharding isn't enabled
+	cm.Insert(newShardingEvent("hub1", "1", "0.1"))
+	cm.Insert(newShardingEvent("hub2", "2", "0.2"))
+	assert.Len(t, cm.conflationUnits, 2)
+	cm.revokePartitions([]transport.EventPosition{{Topic: "gh-status.hub1", Partition: 0}})
+	assert.Len(t, cm.conflationUnits, 2)
+
+	// all the hubs are owned if the sharding isn't enabled
+	assert.NoError(t, cm.Replay(newShardingEvent("hub3", "", "0.3")))
+	assert.Len(t, cm.conflationUnits, 3)
+}
@@ -13,19 +13,24 @@ import (
 
 // ConflationUnit abstracts the conflation of prioritized multiple bundles with dependencies between them.
 type ConflationUnit struct {
+	name                 string
 	ElementPriorityQueue []ConflationElement
 	eventTypeToPriority  map[string]ConflationPriority
 	readyQueue           *ConflationReadyQueue
 	// requireInitialDependencyChecks bool
 	isInReadyQueue bool
-	lock           sync.Mutex
-	statistics     *statistics.Statistics
+	// revoked is set once the partition of the leaf hub is revoked from the manager replica, the remaining bundles
+	// are left to the new owner of the partition
+	revoked    bool
+	lock       sync.Mutex
+	statistics *statistics.Statistics
 }
 
 func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	registrations map[string]*ConflationRegistration, statistics *statistics.Statistics,
 ) *ConflationUnit {
 	conflationUnit := &ConflationUnit{
+		name:                 name,
 		ElementPriorityQueue: make([]ConflationElement, len(registrations)),
 		eventTypeToPriority:  make(map[string]ConflationPriority),
 		readyQueue:           readyQueue,
@@ -55,8 +60,9 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	return conflationUnit
 }
 
-// insert is an internal function, new bundles are inserted only via conflation manager.
-func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) {
+// insert is an internal function, new bundles are inserted only via conflation manager. It returns false if the
+// event isn't accepted by the conflation element.
+func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) bool {
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
@@ -64,23 +70,21 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	conflationElement := cu.ElementPriorityQueue[priority]
 	if conflationElement == nil {
 		log.Debugw("the conflationElement hasn't been registered to conflation unit", "eventType", event.Type())
-		return
+		return false
 	}
 
 	if !conflationElement.Predicate(eventMetadata.Version()) {
 		log.Infow("the conflationElement predication is false")
 		utils.PrettyPrint(event)
-		return
+		return false
 	}
 
 	// for the delta element, insert the ready queue directly and process one by one
 
-	// start conflation unit metric for specific bundle type - overwrite it each time new bundle arrives
-	// cu.statistics.StartConflationUnitMetrics(event)
-
 	// if we got here, we got bundle with newer version
 	// update the bundle in the priority queue.
 	conflationElement.AddToReadyQueue(event, eventMetadata, cu)
+	return true
 }
 
 // GetNext returns the next ready to be processed bundle and its transport metadata.
@@ -88,6 +92,10 @@ func (cu *ConflationUnit) GetNext() (*ConflationJob, error) {
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
 
+	if cu.revoked {
+		return nil, errors.New("the conflation unit is revoked")
+	}
+
 	element := cu.getNextReadyCompleteElement()
 	if element == nil { // CU adds itself to RQ only when it has ready to process bundle
 		return nil, errors.New("no event(element) is ready to be processed")
@@ -99,9 +107,6 @@ func (cu *ConflationUnit) GetNext() (*ConflationJob, error) {
 	if job == nil {
 		return nil, errors.New("no job is ready to be processed")
 	}
-	// stop conflation unit metric for specific bundle type - evaluated once bundle is fetched from the priority queue
-	// cu.statistics.StopConflationUnitMetrics(job.Event, nil)
-
 	return job, nil
 }
 
@@ -121,13 +126,13 @@ func (cu *ConflationUnit) ReportResult(metadata ConflationMetadata, err error) {
 }
 
 func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
-	if cu.isInReadyQueue {
+	if cu.isInReadyQueue || cu.revoked {
 		return // allow CU to appear only once in RQ/processing
 	}
 	// if we reached here, CU is not in RQ, then get next element(isn't processing)
 	element := cu.getNextReadyCompleteElement()
 	if element != nil { // there is a ready to be processed bundle
-		cu.readyQueue.ConflationUnitChan <- cu // let the dispatcher know this CU has a ready to be processed bundle
+		cu.readyQueue.PushConflationUnit(cu.name, cu) // let the dispatcher know this CU has a ready bundle
 		cu.isInReadyQueue = true
 	}
 }
@@ -169,6 +174,13 @@ func (cu *ConflationUnit) getMetadatas() []ConflationMetadata {
 	return metadatas
 }
 
+// revoke stops the conflation unit from dispatching the bundles
+func (cu *ConflationUnit) revoke() {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+	cu.revoked = true
+}
+
 // // function to determine whether the transport component requires initial-dependencies between bundles to be checked
 // // (on load). If the returned is false, then we may assume that dependency of the initial bundle of
 // // each type is met. Otherwise, there are no guarantees and the dependencies must be checked.
@@ -3,6 +3,7 @@ package conflator
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/go-logr/logr"
@@ -26,8 +27,9 @@ type completeElement struct {
 	isInProcess          bool
 	lastProcessedVersion *version.Version
 	// payload
-	event    *cloudevents.Event
-	metadata ConflationMetadata
+	event      *cloudevents.Event
+	metadata   ConflationMetadata
+	insertedAt time.Time
 }
 
 func NewCompleteElement(leafHubName string, registration *ConflationRegistration) *completeElement {
@@ -87,6 +89,7 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 func (e *completeElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
 	e.event = event
 	e.metadata = metadata
+	e.insertedAt = time.Now()
 
 	cu.addCUToReadyQueueIfNeeded()
 }
@@ -104,7 +107,9 @@ func (e *completeElement) ProcessJob(cu *ConflationUnit) *ConflationJob {
 		return nil
 	}
 	e.isInProcess = true
-	return NewConflationJob(e.event, e.metadata, e.handlerFunction, cu, nil)
+	job := NewConflationJob(e.event, e.metadata, e.handlerFunction, cu, nil)
+	job.EnqueuedAt = e.insertedAt
+	return job
 }
 
 // Success is to update the conflation element state after processing the event
@@ -57,7 +57,7 @@ func (e *hybridElement) Predicate(eventVersion *version.Version) bool {
 }
 
 func (e *hybridElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
-	cu.readyQueue.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu, e.elementState)
+	cu.readyQueue.PushDeltaJob(cu.name, NewConflationJob(event, metadata, e.handlerFunction, cu, e.elementState))
 }
 
 // Success is to update the conflation element state after processing the event
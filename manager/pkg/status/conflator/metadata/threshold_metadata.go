@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"strconv"
 
-	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/types"
 
@@ -35,18 +34,18 @@ var log = logger.DefaultZapLogger()
 
 // the retry times(max) when the bundle has been failed processed
 func NewThresholdMetadata(clusterIdentity string, max int, evt *cloudevents.Event) *ThresholdMetadata {
-	topic, err := types.ToString(evt.Extensions()[kafka_confluent.KafkaTopicKey])
+	topic, err := types.ToString(evt.Extensions()[transport.KafkaTopicKey])
 	if err != nil {
 		log.Info("failed to parse topic from event", "error", err)
 	}
-	partition, err := types.ToInteger(evt.Extensions()[kafka_confluent.KafkaPartitionKey])
+	partition, err := types.ToInteger(evt.Extensions()[transport.KafkaPartitionKey])
 	if err != nil {
 		log.Info("failed to parse partition from event", "error", err)
 	}
 
-	offsetStr, ok := evt.Extensions()[kafka_confluent.KafkaOffsetKey].(string)
+	offsetStr, ok := evt.Extensions()[transport.KafkaOffsetKey].(string)
 	if !ok {
-		log.Info("failed to get offset string from event", "offset", evt.Extensions()[kafka_confluent.KafkaOffsetKey])
+		log.Info("failed to get offset string from event", "offset", evt.Extensions()[transport.KafkaOffsetKey])
 	}
 
 	offset, err := strconv.ParseInt(offsetStr, 10, 64)
@@ -0,0 +1,50 @@
+package conflator
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	hubQueueWaitHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_status_hub_queue_wait_seconds",
+			Help:    "The time the jobs of the leaf hub spent in the ready queue before they're dispatched to the workers.",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{
+			"hub", // The leaf hub name.
+		},
+	)
+
+	hubQueueSizeGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_hub_queue_size",
+			Help: "The number of the delta events and the conflation unit of the leaf hub waiting in the ready queue.",
+		},
+		[]string{
+			"hub", // The leaf hub name.
+		},
+	)
+
+	hubQuotaExceededCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_status_hub_quota_exceeded_total",
+			Help: "The number of the delta events of the leaf hub dropped for exceeding the quota, they're resynced from the hub.",
+		},
+		[]string{
+			"hub", // The leaf hub name.
+		},
+	)
+)
+
+var registerOnce sync.Once
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		metrics.Registry.MustRegister(hubQueueWaitHistogram, hubQueueSizeGauge, hubQuotaExceededCounter)
+	})
+}
@@ -1,22 +1,421 @@
 package conflator
 
 import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
+const (
+	// DefaultHubKey sets the scheduling value of the leaf hubs which aren't specified in the SchedulingConfig
+	DefaultHubKey = "*"
+	// the total number of the pending delta events of all the hubs, the inserter is blocked until the workers catch up
+	deltaEventCapacity = 1000
+)
+
+// SchedulingConfig configures the weighted fair scheduling of the leaf hubs in the ready queue, the values are keyed
+// by the leaf hub name or the DefaultHubKey
+type SchedulingConfig struct {
+	// Weights is the share of the workers of the hub relative to the other busy hubs, it's 1 by default
+	Weights map[string]int
+	// ConcurrencyLimits is the maximum number of the jobs of the hub processed by the workers at once, the hub is
+	// skipped by the dispatcher until one of them is done. It's unlimited by default
+	ConcurrencyLimits map[string]int
+	// Quotas is the maximum number of the pending delta events of the hub, the exceeding events are dropped so that a
+	// flooding hub doesn't block the receiving of the other hubs, and their event types are resynced from the hub once
+	// its pending delta events are drained. It's unlimited by default
+	Quotas map[string]int
+}
+
+func (c *SchedulingConfig) weight(hub string) int {
+	if c == nil {
+		return 1
+	}
+	return hubValue(c.Weights, hub, 1)
+}
+
+func (c *SchedulingConfig) concurrencyLimit(hub string) int {
+	if c == nil {
+		return 0
+	}
+	return hubValue(c.ConcurrencyLimits, hub, 0)
+}
+
+func (c *SchedulingConfig) quota(hub string) int {
+	if c == nil {
+		return 0
+	}
+	return hubValue(c.Quotas, hub, 0)
+}
+
+func hubValue(values map[string]int, hub string, defaultValue int) int {
+	if value, found := values[hub]; found {
+		return value
+	}
+	if value, found := values[DefaultHubKey]; found {
+		return value
+	}
+	return defaultValue
+}
+
+// Validate returns error if any of the values is invalid
+func (c *SchedulingConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for hub, weight := range c.Weights {
+		if weight <= 0 {
+			return fmt.Errorf("the weight of the hub %s must be positive: %d", hub, weight)
+		}
+	}
+	for hub, limit := range c.ConcurrencyLimits {
+		if limit < 0 {
+			return fmt.Errorf("the concurrency limit of the hub %s must not be negative: %d", hub, limit)
+		}
+	}
+	for hub, quota := range c.Quotas {
+		if quota < 0 {
+			return fmt.Errorf("the quota of the hub %s must not be negative: %d", hub, quota)
+		}
+	}
+	return nil
+}
+
+// ResyncFunc requests the hub to resync the objects of the event types
+type ResyncFunc func(ctx context.Context, hubName string, eventTypes ...string) error
+
+var resyncFunc ResyncFunc
+
+// SetResyncFunc sets the function to request the resync from the hubs, the delta events exceeding the quota of the hub
+// are recovered by the resync
+func SetResyncFunc(fn ResyncFunc) {
+	resyncFunc = fn
+}
+
+func requestResync(hub string, eventTypes []string) {
+	if resyncFunc == nil {
+		log.Warnw("the resync isn't configured, the dropped delta events aren't recovered", "hub", hub,
+			"types", eventTypes)
+		return
+	}
+	if err := resyncFunc(context.Background(), hub, eventTypes...); err != nil {
+		log.Errorw("failed to request the resync of the dropped delta events", "hub", hub, "types", eventTypes,
+			"error", err)
+	}
+}
+
+// hubQueue is the pending delta event jobs and the conflation unit of a leaf hub in the order of arrival
+type hubQueue struct {
+	name  string
+	items []*ReadyItem
+	// deltaCount is the number of the admitted delta events, including the ones which aren't pushed yet
+	deltaCount int
+	inFlight   int
+	// pass is the virtual time of the hub, it's advanced by the stride (1/weight) on each dispatch, and the hub with
+	// the lowest pass is dispatched first, so the busy hubs share the workers by their weights
+	pass float64
+	// index is the position of the hub in the schedulable heap, it's -1 if the hub isn't in the heap
+	index int
+	// dropped are the event types whose delta events exceed the quota, they're resynced once the hub is drained
+	dropped map[string]struct{}
+}
+
+func (hub *hubQueue) idle() bool {
+	return len(hub.items) == 0 && hub.inFlight == 0 && hub.deltaCount == 0 && len(hub.dropped) == 0
+}
+
+// hubHeap orders the schedulable hubs by the pass, so the next hub is found without scanning all the hubs
+type hubHeap []*hubQueue
+
+func (h hubHeap) Len() int { return len(h) }
+
+func (h hubHeap) Less(i, j int) bool {
+	if h[i].pass == h[j].pass {
+		return h[i].name < h[j].name
+	}
+	return h[i].pass < h[j].pass
+}
+
+func (h hubHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *hubHeap) Push(x any) {
+	hub := x.(*hubQueue)
+	hub.index = len(*h)
+	*h = append(*h, hub)
+}
+
+func (h *hubHeap) Pop() any {
+	old := *h
+	hub := old[len(old)-1]
+	old[len(old)-1] = nil
+	hub.index = -1
+	*h = old[:len(old)-1]
+	return hub
+}
+
+// ReadyItem is a delta event job or a conflation unit with a ready bundle, which is dispatched from the ready queue
+type ReadyItem struct {
+	hub        string
+	job        *ConflationJob
+	unit       *ConflationUnit
+	enqueuedAt time.Time
+	queue      *ConflationReadyQueue
+}
+
+// NextJob returns the job of the item, the slot of the hub is released once the job is done by the worker, or right
+// away if the conflation unit has no ready bundle
+func (item *ReadyItem) NextJob() (*ConflationJob, error) {
+	job := item.job
+	if item.unit != nil {
+		var err error
+		job, err = item.unit.GetNext()
+		if err != nil {
+			item.queue.release(item.hub)
+			return nil, err
+		}
+	}
+	job.done = func() { item.queue.release(item.hub) }
+	return job, nil
+}
+
 // NewConflationReadyQueue creates a new instance of ConflationReadyQueue.
 func NewConflationReadyQueue(statistics *statistics.Statistics) *ConflationReadyQueue {
-	return &ConflationReadyQueue{
-		statistics:         statistics,
-		DeltaEventJobChan:  make(chan *ConflationJob, 1000),
-		ConflationUnitChan: make(chan *ConflationUnit, 100),
+	rq := &ConflationReadyQueue{
+		statistics: statistics,
+		hubs:       map[string]*hubQueue{},
+		notify:     make(chan struct{}, 1),
 	}
+	rq.capacity = sync.NewCond(&rq.lock)
+	return rq
 }
 
-// ConflationReadyQueue is a queue of conflation units that have at least one bundle to process.
+// ConflationReadyQueue is a queue of conflation units that have at least one bundle to process, and the delta event
+// jobs. The leaf hubs are served by the weighted fair scheduling instead of first-come-first-served, so that a hub
+// flooding the events doesn't delay the others.
 type ConflationReadyQueue struct {
 	statistics *statistics.Statistics
-	// create a Job chan for the detal event
-	DeltaEventJobChan  chan *ConflationJob
-	ConflationUnitChan chan *ConflationUnit
+	config     *SchedulingConfig
+
+	lock sync.Mutex
+	// hubs are the leaf hubs with the pending items, the jobs in flight or the admitted delta events, the idle hubs
+	// are removed
+	hubs map[string]*hubQueue
+	// schedulable are the hubs with the pending items which don't reach their concurrency limits
+	schedulable hubHeap
+	// virtualTime is the pass of the last dispatched hub, the hub becoming active starts from it, so it can't claim
+	// the share of the workers when it's idle
+	virtualTime float64
+	size        int
+	deltaCount  int
+	// notify wakes up the dispatcher when an item is pushed or a slot of the hub is released
+	notify chan struct{}
+	// capacity blocks the inserter when the total pending delta events reach the deltaEventCapacity
+	capacity *sync.Cond
+}
+
+// SetSchedulingConfig sets the weights, concurrency limits and quotas of the leaf hubs
+func (rq *ConflationReadyQueue) SetSchedulingConfig(config *SchedulingConfig) {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+	rq.config = config
+	// the concurrency limits might be changed
+	for _, hub := range rq.hubs {
+		rq.schedule(hub)
+	}
+}
+
+// Admit is invoked before inserting the delta event of the hub, it blocks until the pending delta events are below the
+// total capacity, and returns error if the pending delta events of the hub exceed its quota. The admitted event is
+// counted as pending until it's dispatched, so the check is atomic with the push, and it must be canceled if the event
+// isn't pushed. The event type of the rejected event is resynced once the hub is drained, it's empty if the rejected
+// event is kept by the caller, e.g. the replayed dead letter.
+func (rq *ConflationReadyQueue) Admit(hub, eventType string) error {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+
+	quota := rq.config.quota(hub)
+	for {
+		hubQueue := rq.hubQueue(hub)
+		if quota > 0 && hubQueue.deltaCount >= quota {
+			hubQuotaExceededCounter.WithLabelValues(hub).Inc()
+			if eventType != "" {
+				if hubQueue.dropped == nil {
+					hubQueue.dropped = map[string]struct{}{}
+				}
+				hubQueue.dropped[eventType] = struct{}{}
+			}
+			return fmt.Errorf("the pending delta events of the hub %s exceed the quota %d", hub, quota)
+		}
+		if rq.deltaCount < deltaEventCapacity {
+			hubQueue.deltaCount++
+			rq.deltaCount++
+			return nil
+		}
+		rq.capacity.Wait()
+	}
+}
+
+// Cancel releases the admission of the delta event which isn't pushed, e.g. it's superseded by a newer version
+func (rq *ConflationReadyQueue) Cancel(hub string) {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+
+	hubQueue, found := rq.hubs[hub]
+	if !found || hubQueue.deltaCount == 0 {
+		return
+	}
+	rq.consume(hubQueue)
+	rq.schedule(hubQueue)
+}
+
+// PushDeltaJob adds the delta event job, which is admitted by the Admit, into the queue of the hub
+func (rq *ConflationReadyQueue) PushDeltaJob(hub string, job *ConflationJob) {
+	rq.push(&ReadyItem{hub: hub, job: job})
+}
+
+// PushConflationUnit adds the conflation unit with the ready bundle into the queue of the hub
+func (rq *ConflationReadyQueue) PushConflationUnit(hub string, cu *ConflationUnit) {
+	rq.push(&ReadyItem{hub: hub, unit: cu})
+}
+
+func (rq *ConflationReadyQueue) push(item *ReadyItem) {
+	rq.lock.Lock()
+	item.queue = rq
+	item.enqueuedAt = time.Now()
+	hub := rq.hubQueue(item.hub)
+	if len(hub.items) == 0 && hub.pass < rq.virtualTime {
+		hub.pass = rq.virtualTime
+	}
+	hub.items = append(hub.items, item)
+	rq.size++
+	rq.schedule(hub)
+	hubQueueSizeGauge.WithLabelValues(hub.name).Set(float64(len(hub.items)))
+	rq.lock.Unlock()
+
+	rq.ReportSize()
+	rq.wakeup()
+}
+
+// Next blocks until an item is ready to be dispatched, it returns false if the context is done. The item of the hub
+// with the lowest pass is dispatched first, and the hubs reaching the concurrency limits are skipped.
+func (rq *ConflationReadyQueue) Next(ctx context.Context) (*ReadyItem, bool) {
+	for {
+		if item := rq.pop(); item != nil {
+			rq.ReportSize()
+			return item, true
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-rq.notify:
+		}
+	}
+}
+
+func (rq *ConflationReadyQueue) pop() *ReadyItem {
+	rq.lock.Lock()
+	defer rq.lock.Unlock()
+
+	if len(rq.schedulable) == 0 {
+		return nil
+	}
+	selected := rq.schedulable[0]
+
+	item := selected.items[0]
+	selected.items[0] = nil
+	selected.items = selected.items[1:]
+	if item.job != nil {
+		rq.consume(selected)
+	}
+	rq.size--
+	selected.inFlight++
+	rq.virtualTime = selected.pass
+	selected.pass += 1 / float64(rq.config.weight(selected.name))
+	rq.schedule(selected)
+
+	hubQueueSizeGauge.WithLabelValues(selected.name).Set(float64(len(selected.items)))
+	hubQueueWaitHistogram.WithLabelValues(selected.name).Observe(time.Since(item.enqueuedAt).Seconds())
+	return item
+}
+
+// consume decreases the pending delta events of the hub, and resyncs the dropped event types once it's drained
+func (rq *ConflationReadyQueue) consume(hub *hubQueue) {
+	hub.deltaCount--
+	rq.deltaCount--
+	rq.capacity.Broadcast()
+
+	if hub.deltaCount > 0 || len(hub.dropped) == 0 {
+		return
+	}
+	eventTypes := make([]string, 0, len(hub.dropped))
+	for eventType := range hub.dropped {
+		eventTypes = append(eventTypes, eventType)
+	}
+	hub.dropped = nil
+	go requestResync(hub.name, eventTypes)
+}
+
+// schedule puts the hub into the schedulable heap if it has the pending items and doesn't reach the concurrency
+// limit, otherwise removes it from the heap, and the idle hub is removed from the queue
+func (rq *ConflationReadyQueue) schedule(hub *hubQueue) {
+	limit := rq.config.concurrencyLimit(hub.name)
+	schedulable := len(hub.items) > 0 && (limit <= 0 || hub.inFlight < limit)
+	switch {
+	case schedulable && hub.index < 0:
+		heap.Push(&rq.schedulable, hub)
+	case schedulable:
+		heap.Fix(&rq.schedulable, hub.index)
+	case hub.index >= 0:
+		heap.Remove(&rq.schedulable, hub.index)
+	}
+	if hub.idle() {
+		delete(rq.hubs, hub.name)
+		hubQueueSizeGauge.DeleteLabelValues(hub.name)
+	}
+}
+
+// release frees the slot of the hub once its job is done
+func (rq *ConflationReadyQueue) release(hub string) {
+	rq.lock.Lock()
+	if hubQueue, found := rq.hubs[hub]; found && hubQueue.inFlight > 0 {
+		hubQueue.inFlight--
+		rq.schedule(hubQueue)
+	}
+	rq.lock.Unlock()
+	rq.wakeup()
+}
+
+func (rq *ConflationReadyQueue) wakeup() {
+	select {
+	case rq.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (rq *ConflationReadyQueue) hubQueue(name string) *hubQueue {
+	hub, found := rq.hubs[name]
+	if !found {
+		hub = &hubQueue{name: name, pass: rq.virtualTime, index: -1}
+		rq.hubs[name] = hub
+	}
+	return hub
+}
+
+// ReportSize updates the statistics with the number of the conflation units and the delta event jobs in the queue
+func (rq *ConflationReadyQueue) ReportSize() {
+	if rq.statistics == nil {
+		return
+	}
+	rq.lock.Lock()
+	size := rq.size
+	rq.lock.Unlock()
+	rq.statistics.SetConflationReadyQueueSize(size)
 }
@@ -0,0 +1,159 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pushDeltaJobs(rq *ConflationReadyQueue, hub string, count int) {
+	for i := 0; i < count; i++ {
+		evt := cloudevents.NewEvent()
+		evt.SetSource(hub)
+		if err := rq.Admit(hub, ""); err != nil {
+			panic(err)
+		}
+		rq.PushDeltaJob(hub, NewConflationJob(&evt, nil, nil, nil, nil))
+	}
+}
+
+// dispatch pops the items and finishes their jobs right away, and returns the hubs in the dispatching order
+func dispatch(t *testing.T, rq *ConflationReadyQueue, count int) []string {
+	hubs := []string{}
+	for i := 0; i < count; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		item, ok := rq.Next(ctx)
+		cancel()
+		require.True(t, ok)
+		job, err := item.NextJob()
+		require.NoError(t, err)
+		job.Done()
+		hubs = append(hubs, item.hub)
+	}
+	return hubs
+}
+
+func count(hubs []string, hub string) int {
+	n := 0
+	for _, h := range hubs {
+		if h == hub {
+			n++
+		}
+	}
+	return n
+}
+
+func TestReadyQueueFairness(t *testing.T) {
+	rq := NewConflationReadyQueue(nil)
+
+	// the noisy hub floods the events before the others, but it doesn't delay them
+	pushDeltaJobs(rq, "noisy", 100)
+	pushDeltaJobs(rq, "hub1", 2)
+	pushDeltaJobs(rq, "hub2", 2)
+	hubs := dispatch(t, rq, 6)
+	assert.Equal(t, 2, count(hubs, "hub1"))
+	assert.Equal(t, 2, count(hubs, "hub2"))
+	assert.Equal(t, 2, count(hubs, "noisy"))
+
+	// the idle hub doesn't accumulate the share of the workers
+	hubs = dispatch(t, rq, 50)
+	assert.Equal(t, 50, count(hubs, "noisy"))
+	pushDeltaJobs(rq, "hub1", 10)
+	hubs = dispatch(t, rq, 10)
+	assert.InDelta(t, 5, count(hubs, "hub1"), 1)
+}
+
+func TestReadyQueueWeights(t *testing.T) {
+	rq := NewConflationReadyQueue(nil)
+	rq.SetSchedulingConfig(&SchedulingConfig{Weights: map[string]int{"hub1": 3}})
+
+	pushDeltaJobs(rq, "hub1", 100)
+	pushDeltaJobs(rq, "hub2", 100)
+	hubs := dispatch(t, rq, 40)
+	assert.Equal(t, 30, count(hubs, "hub1"))
+	assert.Equal(t, 10, count(hubs, "hub2"))
+}
+
+func TestReadyQueueConcurrencyLimit(t *testing.T) {
+	rq := NewConflationReadyQueue(nil)
+	rq.SetSchedulingConfig(&SchedulingConfig{ConcurrencyLimits: map[string]int{DefaultHubKey: 1, "hub2": 2}})
+	pushDeltaJobs(rq, "hub1", 3)
+	pushDeltaJobs(rq, "hub2", 3)
+
+	// the hubs reaching the limits are skipped until the jobs are done
+	jobs := []*ConflationJob{}
+	for _, expected := range []string{"hub1", "hub2", "hub2"} {
+		item, ok := rq.Next(context.Background())
+		require.True(t, ok)
+		assert.Equal(t, expected, item.hub)
+		job, err := item.NextJob()
+		require.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, ok := rq.Next(ctx)
+	assert.False(t, ok)
+
+	jobs[0].Done()
+	item, ok := rq.Next(context.Background())
+	require.True(t, ok)
+	assert.Equal(t, "hub1", item.hub)
+}
+
+func TestReadyQueueQuota(t *testing.T) {
+	resynced := make(chan []string, 1)
+	SetResyncFunc(func(ctx context.Context, hubName string, eventTypes ...string) error {
+		resynced <- append([]string{hubName}, eventTypes...)
+		return nil
+	})
+	defer SetResyncFunc(nil)
+
+	rq := NewConflationReadyQueue(nil)
+	rq.SetSchedulingConfig(&SchedulingConfig{Quotas: map[string]int{DefaultHubKey: 2}})
+
+	// the admitted events are counted before they're pushed
+	assert.NoError(t, rq.Admit("hub1", "type1"))
+	assert.NoError(t, rq.Admit("hub1", "type1"))
+	assert.Error(t, rq.Admit("hub1", "type1"))
+	assert.NoError(t, rq.Admit("hub2", "type1"))
+
+	// the canceled admission is released, and the admitted events are pushed
+	rq.Cancel("hub1")
+	assert.NoError(t, rq.Admit("hub1", "type1"))
+	assert.Error(t, rq.Admit("hub1", "type2"))
+	for i := 0; i < 2; i++ {
+		evt := cloudevents.NewEvent()
+		rq.PushDeltaJob("hub1", NewConflationJob(&evt, nil, nil, nil, nil))
+	}
+
+	// the dropped event types are resynced once the hub is drained
+	dispatch(t, rq, 2)
+	select {
+	case hub := <-resynced:
+		assert.Equal(t, "hub1", hub[0])
+		assert.ElementsMatch(t, []string{"type1", "type2"}, hub[1:])
+	case <-time.After(time.Second):
+		t.Fatal("the dropped event types aren't resynced")
+	}
+	assert.NoError(t, rq.Admit("hub1", "type1"))
+
+	assert.Error(t, (&SchedulingConfig{Weights: map[string]int{"hub1": 0}}).Validate())
+	assert.NoError(t, (&SchedulingConfig{Quotas: map[string]int{DefaultHubKey: 0}}).Validate())
+}
+
+func TestReadyQueuePruneIdleHubs(t *testing.T) {
+	rq := NewConflationReadyQueue(nil)
+	pushDeltaJobs(rq, "hub1", 2)
+	pushDeltaJobs(rq, "hub2", 1)
+	dispatch(t, rq, 3)
+	assert.Empty(t, rq.hubs)
+
+	require.NoError(t, rq.Admit("hub1", ""))
+	rq.Cancel("hub1")
+	assert.Empty(t, rq.hubs)
+}
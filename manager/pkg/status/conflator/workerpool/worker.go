@@ -5,12 +5,18 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/latency"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 )
 
 // NewWorker creates a new instance of DBWorker.
@@ -62,6 +68,19 @@ func (worker *Worker) start(ctx context.Context) {
 }
 
 func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJob) {
+	defer job.Done()
+
+	// the job has been waited in the conflation unit and the ready queue until it's picked by the worker
+	if worker.statistics != nil && !job.EnqueuedAt.IsZero() {
+		worker.statistics.AddConflationUnitMetrics(job.Event, time.Since(job.EnqueuedAt))
+	}
+	latency.Conflated(job.Event)
+
+	// continue the trace of the event, the handler commits the event into the database with the span
+	ctx = startConflationSpan(ctx, job)
+	ctx, span := tracing.Tracer().Start(ctx, "handle "+enum.ShortenEventType(job.Event.Type()),
+		trace.WithAttributes(attribute.Int("worker.id", int(worker.workerID))))
+
 	conn := database.GetConn()
 
 	err := database.Lock(conn)
@@ -70,11 +89,12 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 
 	if err != nil {
 		log.Error(err)
+		tracing.EndSpan(span, err)
 		return
 	}
 	// deprecated: previous full bundle handling
 	if job.ElementState == nil {
-		worker.fullBundleHandle(ctx, job)
+		tracing.EndSpan(span, worker.fullBundleHandle(ctx, job))
 		return
 	}
 
@@ -84,14 +104,18 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 
 	// based on the handle result, update the element state
 	startTime := time.Now()
+	deadLettered := false
 	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 1*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
 			err := job.Handle(ctx, job.Event)
 			if err != nil {
+				span.RecordError(err)
 				// TODO: This is to handle the expired array bundles from 1.5 to 1.6 upgrade.
 				// It will be removed after the upgrade.
 				if !strings.Contains(err.Error(), "cannot unmarshal array into Go value of") {
 					log.Warnf("received the expired event array bundle %, skipping the event", job.Event.Type())
+					deadletter.Save(job.Event, deadletter.ReasonHandlerFailed, err)
+					deadLettered = true
 					return true, nil
 				}
 				log.Errorf("retrying to handle failed event (%s): %v", job.Event.Type(), err)
@@ -109,24 +133,33 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 	)
 
 	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
+	tracing.EndSpan(span, err)
 
 	if err != nil {
 		log.Errorw("fails to process the DB job", "LF", job.Event.Source(), "WorkerID", worker.workerID,
 			"event", job.Event, "error", err)
+		deadletter.Save(job.Event, deadletter.ReasonHandlerFailed, err)
 	} else {
+		latency.Committed(job.Event)
+		if !deadLettered {
+			deadletter.Committed(job.Event)
+		}
 		log.Debugw("handle the DB job successfully", "LF", job.Event.Source(),
 			"WorkerID", worker.workerID,
 			"version", job.Event.Extensions()[version.ExtVersion])
 	}
 }
 
-func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.ConflationJob) {
+func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.ConflationJob) error {
 	startTime := time.Now()
 	// handle the event until it's metadata is marked as processed
+	var handleErr error
 	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
 			err := job.Handle(ctx, job.Event) // db connection released to pool when done
+			handleErr = err
 			if err != nil {
+				trace.SpanFromContext(ctx).RecordError(err)
 				job.Metadata.MarkAsUnprocessed()
 				log.Warnf("failed to handle event (%s): %v", job.Event.Type(), err)
 			} else {
@@ -146,14 +179,34 @@ func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.Confl
 	job.Reporter.ReportResult(job.Metadata, err)
 
 	if err != nil {
+		// save the last error of the handler instead of the timeout error
+		if handleErr == nil {
+			handleErr = err
+		}
+		deadletter.Save(job.Event, deadletter.ReasonHandlerFailed, handleErr)
 		log.Error(err, "fails to process the DB job", "LF", job.Event.Source(),
 			"WorkerID", worker.workerID,
 			"type", job.Event.Type(),
 			"version", job.Metadata.Version())
-	} else {
-		log.Debugw("handle the DB job successfully", "LF", job.Event.Source(),
-			"WorkerID", worker.workerID,
-			"type", job.Event.Type(),
-			"version", job.Metadata.Version())
+		return handleErr
+	}
+	latency.Committed(job.Event)
+	deadletter.Committed(job.Event)
+	log.Debugw("handle the DB job successfully", "LF", job.Event.Source(),
+		"WorkerID", worker.workerID,
+		"type", job.Event.Type(),
+		"version", job.Metadata.Version())
+	return nil
+}
+
+// startConflationSpan records the span of the job waiting in the conflation unit and the ready queue, it's started
+// when the event is inserted into the conflation unit and ended when the job is picked by the worker
+func startConflationSpan(ctx context.Context, job *conflator.ConflationJob) context.Context {
+	opts := []trace.SpanStartOption{}
+	if !job.EnqueuedAt.IsZero() {
+		opts = append(opts, trace.WithTimestamp(job.EnqueuedAt))
 	}
+	ctx, span := tracing.StartEventSpan(ctx, job.Event, "conflate "+enum.ShortenEventType(job.Event.Type()), opts...)
+	span.End()
+	return ctx
 }
@@ -0,0 +1,210 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// ReasonHandlerFailed means the registered handler returns error when processing the event
+	ReasonHandlerFailed = "handler_failed"
+	// ReasonUnregistered means there is no handler registered for the event type
+	ReasonUnregistered = "unregistered"
+
+	// ExtDeadLetterID is the extension of the replayed dead letter, so the dead letter is marked as replayed once the
+	// event is committed by the handler, or its error is updated if the event is failed again
+	ExtDeadLetterID = "deadletterid"
+)
+
+var (
+	// ErrReplayRejected means the dead letter isn't accepted by the conflation pipeline
+	ErrReplayRejected = errors.New("replay is rejected")
+	// ErrPipelineNotRunning means the conflation pipeline doesn't run on the replica, e.g. it isn't the leader or the
+	// hub is owned by another replica of the sharded manager, so the replay should be retried on the other replica
+	ErrPipelineNotRunning = errors.New("the conflation pipeline isn't running on this replica")
+)
+
+// ReplayFunc inserts the event into the conflation pipeline again
+type ReplayFunc func(evt *cloudevents.Event) error
+
+var (
+	log         = logger.DefaultZapLogger()
+	replayFunc  ReplayFunc
+	replayMutex sync.RWMutex
+)
+
+// SetReplayFunc is invoked once the conflation pipeline is started on the replica, then the dead letters can be
+// replayed. It's reset with nil once the pipeline is stopped.
+func SetReplayFunc(fn ReplayFunc) {
+	replayMutex.Lock()
+	defer replayMutex.Unlock()
+	replayFunc = fn
+}
+
+// Save persists the event into the dead letter table, it's best effort, so the error is only logged
+func Save(evt *cloudevents.Event, reason string, handleErr error) {
+	if err := save(evt, reason, handleErr); err != nil {
+		log.Errorw("failed to save the dead letter", "type", evt.Type(), "source", evt.Source(), "reason", reason,
+			"error", err)
+	}
+}
+
+func save(evt *cloudevents.Event, reason string, handleErr error) error {
+	db := database.GetGorm()
+	if db == nil {
+		return errors.New("database is not initialized")
+	}
+	// the replayed dead letter is failed again, so it's updated instead of saving a new one
+	if id, ok := deadLetterID(evt); ok {
+		errMessage := ""
+		if handleErr != nil {
+			errMessage = handleErr.Error()
+		}
+		return db.Model(&models.DeadLetter{ID: id}).Updates(map[string]interface{}{
+			"reason": reason,
+			"error":  errMessage,
+		}).Error
+	}
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	deadLetter := &models.DeadLetter{
+		LeafHubName: evt.Source(),
+		EventType:   evt.Type(),
+		Reason:      reason,
+		Payload:     payload,
+	}
+	if eventVersion, found := evt.Extensions()[version.ExtVersion]; found {
+		deadLetter.EventVersion = fmt.Sprintf("%v", eventVersion)
+	}
+	if handleErr != nil {
+		deadLetter.Error = handleErr.Error()
+	}
+	return db.Create(deadLetter).Error
+}
+
+// ListOptions filters the dead letters, the empty field matches all
+type ListOptions struct {
+	LeafHubName string
+	EventType   string
+	Reason      string
+	// IncludeReplayed returns the replayed dead letters as well
+	IncludeReplayed bool
+	Limit           int
+}
+
+// List returns the dead letters ordered by the created time desc
+func List(opts ListOptions) ([]models.DeadLetter, error) {
+	db := database.GetGorm()
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	query := db.Model(&models.DeadLetter{})
+	if opts.LeafHubName != "" {
+		query = query.Where("leaf_hub_name = ?", opts.LeafHubName)
+	}
+	if opts.EventType != "" {
+		query = query.Where("event_type = ?", opts.EventType)
+	}
+	if opts.Reason != "" {
+		query = query.Where("reason = ?", opts.Reason)
+	}
+	if !opts.IncludeReplayed {
+		query = query.Where("replayed_at IS NULL")
+	}
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+	deadLetters := []models.DeadLetter{}
+	err := query.Order("created_at DESC").Find(&deadLetters).Error
+	return deadLetters, err
+}
+
+// Get returns the dead letter with the id
+func Get(id int64) (*models.DeadLetter, error) {
+	db := database.GetGorm()
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	deadLetter := &models.DeadLetter{}
+	if err := db.First(deadLetter, id).Error; err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+// Replay inserts the dead letter into the conflation pipeline. The dead letter is marked as replayed once the event
+// is committed by the handler, and its error is updated if the event is failed again.
+func Replay(id int64) (*models.DeadLetter, error) {
+	deadLetter, err := Get(id)
+	if err != nil {
+		return nil, err
+	}
+	evt := cloudevents.NewEvent()
+	if err := json.Unmarshal(deadLetter.Payload, &evt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the dead letter to cloudevent: %w", err)
+	}
+	evt.SetExtension(ExtDeadLetterID, strconv.FormatInt(id, 10))
+	if err := ReplayEvent(&evt); err != nil {
+		return nil, err
+	}
+	return deadLetter, nil
+}
+
+// ReplayEvent inserts the event into the conflation pipeline, the event isn't necessary to be a dead letter, e.g. it
+// can be the captured event of the transport archive
+func ReplayEvent(evt *cloudevents.Event) error {
+	replayMutex.RLock()
+	defer replayMutex.RUnlock()
+	if replayFunc == nil {
+		return ErrPipelineNotRunning
+	}
+	if err := replayFunc(evt); err != nil {
+		if errors.Is(err, ErrPipelineNotRunning) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", ErrReplayRejected, err)
+	}
+	return nil
+}
+
+// Committed marks the dead letter as replayed if the committed event is replayed from it
+func Committed(evt *cloudevents.Event) {
+	id, ok := deadLetterID(evt)
+	if !ok {
+		return
+	}
+	db := database.GetGorm()
+	if db == nil {
+		return
+	}
+	err := db.Model(&models.DeadLetter{ID: id}).Update("replayed_at", time.Now()).Error
+	if err != nil {
+		log.Errorw("failed to mark the dead letter as replayed", "id", id, "error", err)
+	}
+}
+
+func deadLetterID(evt *cloudevents.Event) (int64, bool) {
+	val, found := evt.Extensions()[ExtDeadLetterID]
+	if !found {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(fmt.Sprintf("%v", val), 10, 64)
+	if err != nil {
+		log.Warnw("invalid dead letter id of the replayed event", "id", val, "error", err)
+		return 0, false
+	}
+	return id, true
+}
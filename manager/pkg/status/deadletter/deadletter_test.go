@@ -0,0 +1,30 @@
+package deadletter
+
+import (
+	"errors"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayEvent(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetExtension(ExtDeadLetterID, "12")
+	id, ok := deadLetterID(&evt)
+	require.True(t, ok)
+	assert.Equal(t, int64(12), id)
+
+	// the replay is rejected by the replica which doesn't run the pipeline
+	SetReplayFunc(nil)
+	assert.ErrorIs(t, ReplayEvent(&evt), ErrPipelineNotRunning)
+
+	SetReplayFunc(func(evt *cloudevents.Event) error { return errors.New("superseded") })
+	defer SetReplayFunc(nil)
+	assert.ErrorIs(t, ReplayEvent(&evt), ErrReplayRejected)
+
+	// the event which isn't replayed from the dead letter
+	_, ok = deadLetterID(&cloudevents.Event{})
+	assert.False(t, ok)
+}
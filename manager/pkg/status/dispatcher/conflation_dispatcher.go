@@ -73,22 +73,22 @@ func (dispatcher *ConflationDispatcher) Start(ctx context.Context) error {
 
 func (dispatcher *ConflationDispatcher) dispatch(ctx context.Context) {
 	for {
-		select {
-		case <-ctx.Done(): // if dispatcher was stopped do not process more bundles
+		// the ready items of the leaf hubs are dispatched by the weighted fair scheduling
+		item, ok := dispatcher.conflationReadyQueue.Next(ctx)
+		if !ok { // if dispatcher was stopped do not process more bundles
+			return
+		}
+		eventJob, err := item.NextJob()
+		if err != nil {
+			dispatcher.log.Info(err.Error()) // don't need to throw the error when bundle is not ready
+			continue
+		}
+		worker := dispatcher.getBlockingWorker(ctx)
+		if worker == nil {
+			eventJob.Done()
 			return
-
-		case deltaEventJob := <-dispatcher.conflationReadyQueue.DeltaEventJobChan:
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(deltaEventJob)
-		case conflationUnit := <-dispatcher.conflationReadyQueue.ConflationUnitChan:
-			eventJob, err := conflationUnit.GetNext()
-			if err != nil {
-				dispatcher.log.Info(err.Error()) // don't need to throw the error when bundle is not ready
-				continue
-			}
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(eventJob)
 		}
+		worker.RunAsync(eventJob)
 	}
 }
 
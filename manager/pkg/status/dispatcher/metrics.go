@@ -0,0 +1,29 @@
+package dispatcher
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var rejectedEventCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_rejected_events_total",
+		Help: "The number of the status events which are rejected since the signature is invalid.",
+	},
+	[]string{
+		// The reason: unsigned, invalid_certificate, source_mismatch, invalid_signature or stale. The source of the
+		// rejected event isn't a label, since it's claimed by the untrusted event, see the transport audit logs.
+		"reason",
+	},
+)
+
+var registerOnce sync.Once
+
+// registerMetrics will register metrics with the global prometheus registry
+func registerMetrics() {
+	registerOnce.Do(func() {
+		metrics.Registry.MustRegister(rejectedEventCounter)
+	})
+}
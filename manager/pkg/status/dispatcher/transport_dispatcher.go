@@ -4,22 +4,41 @@ import (
 	"context"
 	"fmt"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/latency"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
 )
 
+// positionExtensions are set by the consumer to the position of the event in the transport
+var positionExtensions = []string{
+	transport.KafkaTopicKey,
+	transport.KafkaPartitionKey,
+	transport.KafkaOffsetKey,
+}
+
 // Get message from transport, convert it to bundle and forward it to conflation manager.
 type TransportDispatcher struct {
 	log               *zap.SugaredLogger
 	consumer          transport.Consumer
 	conflationManager *conflator.ConflationManager
 	statistic         *statistics.Statistics
+	// verifier rejects the events which aren't signed by the certificates of their sources, it's nil if the signing
+	// CA isn't specified
+	verifier *signature.Verifier
 }
 
 func AddTransportDispatcher(mgr ctrl.Manager, consumer transport.Consumer, managerConfig *configs.ManagerConfig,
@@ -31,6 +50,15 @@ func AddTransportDispatcher(mgr ctrl.Manager, consumer transport.Consumer, manag
 		conflationManager: conflationManager,
 		statistic:         stats,
 	}
+	if managerConfig.TransportConfig.SigningCAFile != "" {
+		verifier, err := signature.NewVerifierFromFile(managerConfig.TransportConfig.SigningCAFile,
+			managerConfig.TransportConfig.SignatureMaxAge)
+		if err != nil {
+			return fmt.Errorf("failed to create the signature verifier: %w", err)
+		}
+		transportDispatcher.verifier = verifier
+		registerMetrics()
+	}
 	if err := mgr.Add(transportDispatcher); err != nil {
 		return fmt.Errorf("failed to add transport dispatcher to runtime manager: %w", err)
 	}
@@ -43,7 +71,12 @@ func (d *TransportDispatcher) Start(ctx context.Context) error {
 
 	go d.dispatch(ctx)
 
+	// the dead letters and the archived events are replayed through the dispatcher, so that they're verified the same
+	// as the received ones. They're only accepted by the replica running the pipeline, e.g. the leader.
+	deadletter.SetReplayFunc(d.Replay)
+
 	<-ctx.Done() // blocking wait for stop event
+	deadletter.SetReplayFunc(nil)
 	d.log.Info("stopped dispatching events")
 
 	return nil
@@ -57,7 +90,65 @@ func (d *TransportDispatcher) dispatch(ctx context.Context) {
 		case evt := <-d.consumer.EventChan():
 			d.statistic.ReceivedEvent(evt)
 			d.log.Debugf("received event: %s", evt)
+			if d.verifier != nil {
+				if err := d.verifier.Verify(evt); err != nil {
+					d.reject(evt, err)
+					continue
+				}
+			}
+			latency.Received(evt)
+			// continue the trace of the agent, the traceparent is replaced with the received span, so that the
+			// conflation and the handler stages are its children
+			spanCtx, span := tracing.StartEventSpan(ctx, evt, "receive "+enum.ShortenEventType(evt.Type()),
+				trace.WithSpanKind(trace.SpanKindConsumer))
+			tracing.Inject(spanCtx, evt)
+			span.End()
 			d.conflationManager.Insert(evt)
 		}
 	}
 }
+
+// Replay verifies the signature of the replayed event the same as the received one, and removes its transport
+// position before inserting it into the conflation pipeline, so the offset of the original consumption isn't
+// committed again. The age of the event isn't checked, since the dead letters and archives are replayed later on.
+func (d *TransportDispatcher) Replay(evt *cloudevents.Event) error {
+	if d.verifier != nil {
+		if err := d.verifier.VerifySignature(evt); err != nil {
+			d.reject(evt, err)
+			return err
+		}
+	}
+	return d.conflationManager.Replay(withoutPosition(evt))
+}
+
+// withoutPosition returns the copy of the event without the transport position extensions
+func withoutPosition(evt *cloudevents.Event) *cloudevents.Event {
+	replayed := evt.Clone()
+	for _, key := range positionExtensions {
+		replayed.SetExtension(key, nil)
+	}
+	return &replayed
+}
+
+// reject drops the event before it's conflated, and records it into the audit log
+func (d *TransportDispatcher) reject(evt *cloudevents.Event, err error) {
+	reason := signature.Reason(err)
+	d.log.Warnw("reject the event with invalid signature", "source", evt.Source(), "type", evt.Type(),
+		"reason", reason, "error", err)
+	rejectedEventCounter.WithLabelValues(reason).Inc()
+
+	db := database.GetGorm()
+	if db == nil {
+		return
+	}
+	auditLog := &models.TransportAuditLog{
+		LeafHubName: evt.Source(),
+		EventType:   evt.Type(),
+		EventID:     evt.ID(),
+		Reason:      reason,
+		Message:     err.Error(),
+	}
+	if err := db.Create(auditLog).Error; err != nil {
+		d.log.Errorw("failed to save the audit log of the rejected event", "source", evt.Source(), "error", err)
+	}
+}
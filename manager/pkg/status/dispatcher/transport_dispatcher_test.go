@@ -0,0 +1,31 @@
+package dispatcher
+
+import (
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestWithoutPosition(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetSource("hub1")
+	evt.SetType("test")
+	evt.SetExtension(version.ExtVersion, "1.1")
+	evt.SetExtension(transport.KafkaTopicKey, "gh-status.hub1")
+	evt.SetExtension(transport.KafkaPartitionKey, int32(0))
+	evt.SetExtension(transport.KafkaOffsetKey, "10")
+
+	// the replayed event doesn't carry the position of the original consumption, so the offset isn't committed again
+	replayed := withoutPosition(&evt)
+	for _, key := range positionExtensions {
+		require.NotContains(t, replayed.Extensions(), key)
+	}
+	require.Equal(t, "1.1", replayed.Extensions()[version.ExtVersion])
+
+	// the original event is unchanged
+	require.Contains(t, evt.Extensions(), transport.KafkaOffsetKey)
+}
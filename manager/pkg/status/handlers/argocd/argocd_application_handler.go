@@ -0,0 +1,228 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	applicationSetKind = "ApplicationSet"
+
+	syncStatusSynced    = "Synced"
+	syncStatusOutOfSync = "OutOfSync"
+	healthStatusHealthy = "Healthy"
+)
+
+// healthOrder is the severity of the argo cd health status from the best to the worst, it's same as the gitops-engine
+var healthOrder = []string{"Healthy", "Suspended", "Progressing", "Missing", "Degraded", "Unknown"}
+
+// argoCDApplicationHandler upserts the argo cd Applications and ApplicationSets, they're identified by the hub, the
+// kind, the namespace and the name
+type argoCDApplicationHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterArgoCDApplicationHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ArgoCDApplicationType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &argoCDApplicationHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ArgoCDApplicationPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *argoCDApplicationHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[unstructured.Unstructured]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal argocd application bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, objects := range [][]unstructured.Unstructured{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, objects); err != nil {
+			return fmt.Errorf("failed to upsert argocd applications - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND kind = ? AND namespace = ? AND name = ?", leafHubName,
+			path.Base(deleted.GVK), deleted.Namespace, deleted.Name).Delete(&models.ArgoCDApplication{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete argocd applications - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.deleteStale(db, leafHubName, bundle.ResyncMetadata); err != nil {
+			return fmt.Errorf("failed to delete stale argocd applications - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *argoCDApplicationHandler) upsert(db *gorm.DB, leafHubName string, objects []unstructured.Unstructured) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	rows := make([]models.ArgoCDApplication, 0, len(objects))
+	for i := range objects {
+		row, err := toApplication(leafHubName, &objects[i])
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "kind"}, {Name: "namespace"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uid", "project", "repo_url", "target_revision", "destination_server", "destination_name",
+			"destination_namespace", "sync_status", "health_status", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// deleteStale removes the applications of the hub which aren't in the resync metadata
+func (h *argoCDApplicationHandler) deleteStale(db *gorm.DB, leafHubName string,
+	resyncMetadata []generic.ObjectMetadata,
+) error {
+	synced := map[string]bool{}
+	for _, metadata := range resyncMetadata {
+		synced[path.Base(metadata.GVK)+"/"+metadata.Namespace+"/"+metadata.Name] = true
+	}
+
+	var existing []models.ArgoCDApplication
+	err := db.Select("kind", "namespace", "name").Where("leaf_hub_name = ?", leafHubName).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, app := range existing {
+		if synced[app.Kind+"/"+app.Namespace+"/"+app.Name] {
+			continue
+		}
+		err := db.Where("leaf_hub_name = ? AND kind = ? AND namespace = ? AND name = ?", leafHubName, app.Kind,
+			app.Namespace, app.Name).Delete(&models.ArgoCDApplication{}).Error
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		h.log.Debugw("deleted stale argocd applications", "LH", leafHubName, "count", count)
+	}
+	return nil
+}
+
+// toApplication converts the Application or ApplicationSet into the row, the ApplicationSet is out of sync if any of
+// its Applications is out of sync, and its health is the worst health of them
+func toApplication(leafHubName string, obj *unstructured.Unstructured) (*models.ArgoCDApplication, error) {
+	payload, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	app := &models.ArgoCDApplication{
+		LeafHubName: leafHubName,
+		Kind:        obj.GetKind(),
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		UID:         string(obj.GetUID()),
+		Payload:     payload,
+	}
+
+	specFields := []string{"spec"}
+	if app.Kind == applicationSetKind {
+		specFields = []string{"spec", "template", "spec"}
+	}
+	app.Project = nestedString(obj.Object, append(specFields, "project")...)
+	source, found, _ := unstructured.NestedMap(obj.Object, append(specFields, "source")...)
+	if !found {
+		if sources, _, _ := unstructured.NestedSlice(obj.Object, append(specFields, "sources")...); len(sources) > 0 {
+			source, _ = sources[0].(map[string]interface{})
+		}
+	}
+	app.RepoURL = nestedString(source, "repoURL")
+	app.TargetRevision = nestedString(source, "targetRevision")
+	app.DestinationServer = nestedString(obj.Object, append(specFields, "destination", "server")...)
+	app.DestinationName = nestedString(obj.Object, append(specFields, "destination", "name")...)
+	app.DestinationNamespace = nestedString(obj.Object, append(specFields, "destination", "namespace")...)
+
+	if app.Kind != applicationSetKind {
+		app.SyncStatus = nestedString(obj.Object, "status", "sync", "status")
+		app.HealthStatus = nestedString(obj.Object, "status", "health", "status")
+		return app, nil
+	}
+
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "status", "resources")
+	if len(resources) == 0 {
+		return app, nil
+	}
+	app.SyncStatus, app.HealthStatus = syncStatusSynced, healthStatusHealthy
+	for _, resource := range resources {
+		r, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if status := nestedString(r, "status"); status != syncStatusSynced {
+			app.SyncStatus = syncStatusOutOfSync
+		}
+		if health := nestedString(r, "health", "status"); worseHealth(health, app.HealthStatus) {
+			app.HealthStatus = health
+		}
+	}
+	return app, nil
+}
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
+
+func worseHealth(health, than string) bool {
+	return healthIndex(health) > healthIndex(than)
+}
+
+// healthIndex returns the severity of the health, the empty or unknown value is treated as "Unknown"
+func healthIndex(health string) int {
+	for i, h := range healthOrder {
+		if h == health {
+			return i
+		}
+	}
+	return len(healthOrder) - 1
+}
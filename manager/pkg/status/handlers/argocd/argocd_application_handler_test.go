@@ -0,0 +1,113 @@
+package argocd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToApplication(t *testing.T) {
+	cases := []struct {
+		name   string
+		object map[string]interface{}
+		want   map[string]string
+	}{
+		{
+			name: "application with a single source",
+			object: map[string]interface{}{
+				"kind":     "Application",
+				"metadata": map[string]interface{}{"namespace": "openshift-gitops", "name": "guestbook"},
+				"spec": map[string]interface{}{
+					"project":     "default",
+					"source":      map[string]interface{}{"repoURL": "https://repo", "targetRevision": "HEAD"},
+					"destination": map[string]interface{}{"name": "cluster1", "namespace": "guestbook"},
+				},
+				"status": map[string]interface{}{
+					"sync":   map[string]interface{}{"status": "OutOfSync"},
+					"health": map[string]interface{}{"status": "Progressing"},
+				},
+			},
+			want: map[string]string{
+				"project": "default", "repo": "https://repo", "revision": "HEAD", "cluster": "cluster1",
+				"sync": "OutOfSync", "health": "Progressing",
+			},
+		},
+		{
+			name: "application with multiple sources",
+			object: map[string]interface{}{
+				"kind":     "Application",
+				"metadata": map[string]interface{}{"namespace": "openshift-gitops", "name": "guestbook"},
+				"spec": map[string]interface{}{
+					"sources": []interface{}{
+						map[string]interface{}{"repoURL": "https://repo1", "targetRevision": "v1"},
+						map[string]interface{}{"repoURL": "https://repo2"},
+					},
+					"destination": map[string]interface{}{"server": "https://kubernetes.default.svc"},
+				},
+				"status": map[string]interface{}{
+					"sync":   map[string]interface{}{"status": "Synced"},
+					"health": map[string]interface{}{"status": "Healthy"},
+				},
+			},
+			want: map[string]string{
+				"repo": "https://repo1", "revision": "v1", "server": "https://kubernetes.default.svc",
+				"sync": "Synced", "health": "Healthy",
+			},
+		},
+		{
+			name: "applicationset with the worst status of the applications",
+			object: map[string]interface{}{
+				"kind":     "ApplicationSet",
+				"metadata": map[string]interface{}{"namespace": "openshift-gitops", "name": "guestbook"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"project": "apps",
+							"source":  map[string]interface{}{"repoURL": "https://repo"},
+						},
+					},
+				},
+				"status": map[string]interface{}{
+					"resources": []interface{}{
+						map[string]interface{}{
+							"name": "cluster1-guestbook", "status": "Synced",
+							"health": map[string]interface{}{"status": "Degraded"},
+						},
+						map[string]interface{}{
+							"name": "cluster2-guestbook", "status": "OutOfSync",
+							"health": map[string]interface{}{"status": "Progressing"},
+						},
+					},
+				},
+			},
+			want: map[string]string{
+				"project": "apps", "repo": "https://repo", "sync": "OutOfSync", "health": "Degraded",
+			},
+		},
+		{
+			name: "applicationset without the applications",
+			object: map[string]interface{}{
+				"kind":     "ApplicationSet",
+				"metadata": map[string]interface{}{"namespace": "openshift-gitops", "name": "guestbook"},
+			},
+			want: map[string]string{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			app, err := toApplication("hub1", &unstructured.Unstructured{Object: c.object})
+			require.NoError(t, err)
+			assert.Equal(t, "hub1", app.LeafHubName)
+			assert.Equal(t, c.want["project"], app.Project)
+			assert.Equal(t, c.want["repo"], app.RepoURL)
+			assert.Equal(t, c.want["revision"], app.TargetRevision)
+			assert.Equal(t, c.want["cluster"], app.DestinationName)
+			assert.Equal(t, c.want["server"], app.DestinationServer)
+			assert.Equal(t, c.want["sync"], app.SyncStatus)
+			assert.Equal(t, c.want["health"], app.HealthStatus)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// certificateExpiryHandler replaces the certificates of the hub with the scanned ones, the event always contains all
+// the certificates of the hub
+type certificateExpiryHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterCertificateExpiryHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.CertificateExpiryType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &certificateExpiryHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.CertificateExpiryPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *certificateExpiryHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	data := &wiremodels.CertificateExpiries{}
+	if err := evt.DataAs(data); err != nil {
+		h.log.Warnw("failed to unmarshal certificate expiry event", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	rows := make([]models.CertificateExpiry, 0, len(data.Certificates))
+	for _, cert := range data.Certificates {
+		rows = append(rows, models.CertificateExpiry{
+			LeafHubName: leafHubName,
+			Source:      cert.Source,
+			Namespace:   cert.Namespace,
+			Name:        cert.Name,
+			Key:         cert.Key,
+			Kind:        cert.Kind,
+			Subject:     cert.Subject,
+			Issuer:      cert.Issuer,
+			NotBefore:   cert.NotBefore,
+			NotAfter:    cert.NotAfter,
+		})
+	}
+
+	err := database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if len(rows) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "leaf_hub_name"}, {Name: "source"}, {Name: "namespace"}, {Name: "name"}, {Name: "key"},
+				},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"kind", "subject", "issuer", "not_before", "not_after", "updated_at",
+				}),
+			}).Create(&rows).Error
+			if err != nil {
+				return err
+			}
+		}
+		return h.deleteStale(tx, leafHubName, rows)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync the certificate expiries - %w", err)
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+// deleteStale removes the certificates of the hub which aren't scanned any more, e.g. the import secret is deleted
+func (h *certificateExpiryHandler) deleteStale(tx *gorm.DB, leafHubName string,
+	scanned []models.CertificateExpiry,
+) error {
+	synced := map[string]bool{}
+	for _, cert := range scanned {
+		synced[identity(cert)] = true
+	}
+
+	var existing []models.CertificateExpiry
+	err := tx.Select("source", "namespace", "name", "key").Where("leaf_hub_name = ?", leafHubName).
+		Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, cert := range existing {
+		if synced[identity(cert)] {
+			continue
+		}
+		err := tx.Where("leaf_hub_name = ? AND source = ? AND namespace = ? AND name = ? AND key = ?", leafHubName,
+			cert.Source, cert.Namespace, cert.Name, cert.Key).Delete(&models.CertificateExpiry{}).Error
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		h.log.Debugw("deleted stale certificate expiries", "LH", leafHubName, "count", count)
+	}
+	return nil
+}
+
+func identity(cert models.CertificateExpiry) string {
+	return strings.Join([]string{cert.Source, cert.Namespace, cert.Name, cert.Key}, "/")
+}
@@ -0,0 +1,79 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// ResyncFunc requests the hub to resync the objects of the event types
+type ResyncFunc func(ctx context.Context, hubName string, eventTypes ...string) error
+
+var resyncFunc ResyncFunc
+
+// SetResyncFunc sets the function to request the resync from the hubs, the objects which can't be patched are
+// recovered by the resync
+func SetResyncFunc(fn ResyncFunc) {
+	resyncFunc = fn
+}
+
+// PatchQuery locates the stored object of the patch, the query is scoped to the leaf hub
+type PatchQuery func(db *gorm.DB, metadata genericbundle.ObjectMetadata) *gorm.DB
+
+// ApplyMergePatches applies the JSON merge patches of the bundle to the payloads of the objects in the table of the
+// model, and returns the patched payloads. The hub is requested to resync the event type if any object isn't found,
+// e.g. the bundle with the whole object is failed to be handled, or the stored object isn't the base of the patch,
+// e.g. the bundle with the previous patch is dropped.
+func ApplyMergePatches(ctx context.Context, db *gorm.DB, leafHubName, eventType string, model interface{},
+	patches []genericbundle.ObjectPatch, query PatchQuery,
+) ([][]byte, error) {
+	patched := make([][]byte, 0, len(patches))
+	missing, mismatched := 0, 0
+	for _, patch := range patches {
+		var payloads []datatypes.JSON
+		err := query(db.Model(model).Where("leaf_hub_name = ?", leafHubName), patch.ObjectMetadata).
+			Pluck("payload", &payloads).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the payload of %s/%s: %w", patch.Namespace, patch.Name, err)
+		}
+		if len(payloads) == 0 {
+			missing++
+			continue
+		}
+		if patch.BaseResourceVersion != "" &&
+			genericbundle.PayloadResourceVersion(payloads[0]) != patch.BaseResourceVersion {
+			mismatched++
+			continue
+		}
+
+		payload, err := jsonpatch.MergePatch(payloads[0], patch.Patch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply the patch of %s/%s: %w", patch.Namespace, patch.Name, err)
+		}
+		err = query(db.Model(model).Where("leaf_hub_name = ?", leafHubName), patch.ObjectMetadata).
+			Update("payload", datatypes.JSON(payload)).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to update the payload of %s/%s: %w", patch.Namespace, patch.Name, err)
+		}
+		patched = append(patched, payload)
+	}
+
+	if missing > 0 || mismatched > 0 {
+		logger.DefaultZapLogger().Warnw("the patched objects aren't found or mismatch the base, requesting resync",
+			"LH", leafHubName, "type", enum.ShortenEventType(eventType), "missing", missing, "mismatched", mismatched)
+		if resyncFunc == nil {
+			return patched, fmt.Errorf("%d patched objects aren't found, %d mismatch the base", missing, mismatched)
+		}
+		if err := resyncFunc(ctx, leafHubName, eventType); err != nil {
+			return patched, fmt.Errorf("failed to request resync for %d patched objects: %w", missing+mismatched, err)
+		}
+	}
+	return patched, nil
+}
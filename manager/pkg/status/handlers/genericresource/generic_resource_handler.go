@@ -0,0 +1,167 @@
+package genericresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	generichandler "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// genericResourceHandler upserts the custom resources configured in the agent configmaps, the resources of all the
+// kinds are sent by a single event type and identified by the hub, gvk and uid
+type genericResourceHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterGenericResourceHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.GenericResourceType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &genericResourceHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.GenericResourcePriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *genericResourceHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[unstructured.Unstructured]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal generic resource bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, objects := range [][]unstructured.Unstructured{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, objects); err != nil {
+			return fmt.Errorf("failed to upsert generic resources - %w", err)
+		}
+	}
+
+	if len(bundle.Patch) > 0 {
+		_, err := generichandler.ApplyMergePatches(ctx, db, leafHubName, h.eventType, &models.GenericResource{},
+			bundle.Patch, func(db *gorm.DB, metadata generic.ObjectMetadata) *gorm.DB {
+				return db.Where("gvk = ? AND uid = ?", metadata.GVK, metadata.ID)
+			})
+		if err != nil {
+			return fmt.Errorf("failed to patch generic resources - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		if err := h.delete(db, leafHubName, deleted); err != nil {
+			return fmt.Errorf("failed to delete generic resources - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.deleteStale(db, leafHubName, &bundle); err != nil {
+			return fmt.Errorf("failed to delete stale generic resources - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *genericResourceHandler) upsert(db *gorm.DB, leafHubName string, objects []unstructured.Unstructured) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	resources := make([]models.GenericResource, 0, len(objects))
+	for i := range objects {
+		obj := &objects[i]
+		payload, err := json.Marshal(obj.Object)
+		if err != nil {
+			return err
+		}
+		resources = append(resources, models.GenericResource{
+			LeafHubName: leafHubName,
+			GVK:         gvkString(obj),
+			UID:         string(obj.GetUID()),
+			Namespace:   obj.GetNamespace(),
+			Name:        obj.GetName(),
+			Payload:     payload,
+		})
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "leaf_hub_name"}, {Name: "gvk"}, {Name: "uid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"namespace", "name", "payload", "updated_at"}),
+	}).Create(&resources).Error
+}
+
+// delete removes the resource by the uid, or by the namespace and name if the agent didn't get the deleted resource
+func (h *genericResourceHandler) delete(db *gorm.DB, leafHubName string, deleted generic.ObjectMetadata) error {
+	query := db.Where("leaf_hub_name = ?", leafHubName)
+	if deleted.ID != "" {
+		query = query.Where("uid = ?", deleted.ID)
+	} else if deleted.GVK != "" && deleted.Name != "" {
+		query = query.Where("gvk = ? AND namespace = ? AND name = ?", deleted.GVK, deleted.Namespace, deleted.Name)
+	} else {
+		h.log.Warnw("generic resource delete event without ID or GVK/Name", "LH", leafHubName)
+		return nil
+	}
+	return query.Delete(&models.GenericResource{}).Error
+}
+
+// deleteStale removes the resources of the hub which aren't in the resync metadata, including the resources of the
+// kinds removed from the agent configmap
+func (h *genericResourceHandler) deleteStale(db *gorm.DB, leafHubName string,
+	bundle *generic.GenericBundle[unstructured.Unstructured],
+) error {
+	var uids []string
+	err := db.Model(&models.GenericResource{}).Where("leaf_hub_name = ?", leafHubName).Pluck("uid", &uids).Error
+	if err != nil {
+		return err
+	}
+	staleUIDs := []string{}
+	for _, uid := range uids {
+		if bundle.FoundMetadataById(uid) == nil {
+			staleUIDs = append(staleUIDs, uid)
+		}
+	}
+	if len(staleUIDs) == 0 {
+		return nil
+	}
+	err = db.Where("leaf_hub_name = ?", leafHubName).Where("uid IN ?", staleUIDs).
+		Delete(&models.GenericResource{}).Error
+	if err != nil {
+		return err
+	}
+	h.log.Debugw("deleted stale generic resources", "LH", leafHubName, "count", len(staleUIDs))
+	return nil
+}
+
+// gvkString formats the kind of the resource as the apiVersion and kind, e.g. "cert-manager.io/v1/Certificate"
+func gvkString(obj *unstructured.Unstructured) string {
+	return obj.GetAPIVersion() + "/" + obj.GetKind()
+}
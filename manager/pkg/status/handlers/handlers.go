@@ -9,10 +9,17 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/argocd"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/certificate"
 	clustermigration "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/clustermigartion"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/genericresource"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/hive"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedcluster"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedclusteraddon"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedclusterinfo"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedhub"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/manifestwork"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/policy"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/security"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -27,6 +34,8 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 	// managed cluster
 	managedcluster.RegisterManagedClusterHandler(mgr.GetClient(), cmr)
 	managedcluster.RegisterManagedClusterEventHandler(cmr)
+	managedclusteraddon.RegisterManagedClusterAddOnHandler(cmr)
+	managedclusterinfo.RegisterManagedClusterInfoHandler(cmr)
 
 	// managed cluster migration
 	clustermigration.RegisterManagedClusterMigrationHandler(mgr, cmr)
@@ -48,6 +57,25 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 
 	// security
 	security.RegisterSecurityAlertCountsHandler(cmr)
+	security.RegisterSecurityClusterAlertCountsHandler(cmr)
+	security.RegisterSecurityPolicyViolationsHandler(cmr)
+	security.RegisterSecurityImageVulnerabilitiesHandler(cmr)
+
+	// argo cd applications and applicationsets
+	argocd.RegisterArgoCDApplicationHandler(cmr)
+
+	// manifestworks
+	manifestwork.RegisterManifestWorkHandler(cmr)
+
+	// hive clusterdeployments and clusterpools
+	hive.RegisterClusterDeploymentHandler(cmr)
+	hive.RegisterClusterPoolHandler(cmr)
+
+	// certificate and token expiry of the hubs
+	certificate.RegisterCertificateExpiryHandler(cmr)
+
+	// generic resources configured in the agent configmap
+	genericresource.RegisterGenericResourceHandler(cmr)
 
 	if enableGlobalResource {
 		// global policy
@@ -0,0 +1,197 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// the provisioning phases of the clusterdeployment
+const (
+	phasePending          = "Pending"
+	phaseProvisioning     = "Provisioning"
+	phaseProvisionFailed  = "ProvisionFailed"
+	phaseProvisionStopped = "ProvisionStopped"
+	phaseProvisioned      = "Provisioned"
+	phaseDeprovisioning   = "Deprovisioning"
+)
+
+// failureConditions are the hive conditions which indicate the cluster fails to be provisioned or deprovisioned, the
+// first one with the "True" status is the failure of the clusterdeployment
+var failureConditions = []string{
+	"ProvisionStopped",
+	"ProvisionFailed",
+	"InstallImagesNotResolved",
+	"DNSNotReady",
+	"AuthenticationFailure",
+	"DeprovisionLaunchError",
+}
+
+// clusterDeploymentHandler upserts the hive clusterdeployments with the provisioning phase and the power state, the
+// clusterdeployment is identified by the hub, the namespace and the name
+type clusterDeploymentHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterClusterDeploymentHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ClusterDeploymentType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &clusterDeploymentHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ClusterDeploymentPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *clusterDeploymentHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[unstructured.Unstructured]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal clusterdeployment bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, objects := range [][]unstructured.Unstructured{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, objects); err != nil {
+			return fmt.Errorf("failed to upsert clusterdeployments - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND namespace = ? AND name = ?", leafHubName, deleted.Namespace,
+			deleted.Name).Delete(&models.ClusterDeployment{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete clusterdeployments - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := deleteStale(db, leafHubName, bundle.ResyncMetadata, &models.ClusterDeployment{}); err != nil {
+			return fmt.Errorf("failed to delete stale clusterdeployments - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *clusterDeploymentHandler) upsert(db *gorm.DB, leafHubName string, objects []unstructured.Unstructured) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	rows := make([]models.ClusterDeployment, 0, len(objects))
+	for i := range objects {
+		row, err := toClusterDeployment(leafHubName, &objects[i])
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "namespace"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uid", "cluster_name", "platform", "region", "base_domain", "pool_namespace", "pool_name", "image_set",
+			"install_version", "phase", "power_state", "install_restarts", "provision_name", "failure_reason",
+			"failure_message", "cluster_created_at", "installed_at", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+func toClusterDeployment(leafHubName string, obj *unstructured.Unstructured) (*models.ClusterDeployment, error) {
+	payload, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	cd := &models.ClusterDeployment{
+		LeafHubName:      leafHubName,
+		Namespace:        obj.GetNamespace(),
+		Name:             obj.GetName(),
+		UID:              string(obj.GetUID()),
+		ClusterName:      nestedString(obj.Object, "spec", "clusterName"),
+		BaseDomain:       nestedString(obj.Object, "spec", "baseDomain"),
+		PoolNamespace:    nestedString(obj.Object, "spec", "clusterPoolRef", "namespace"),
+		PoolName:         nestedString(obj.Object, "spec", "clusterPoolRef", "poolName"),
+		ImageSet:         nestedString(obj.Object, "spec", "provisioning", "imageSetRef", "name"),
+		InstallVersion:   nestedString(obj.Object, "status", "installVersion"),
+		ProvisionName:    nestedString(obj.Object, "status", "provisionRef", "name"),
+		ClusterCreatedAt: obj.GetCreationTimestamp().Time,
+		Payload:          payload,
+	}
+	cd.Platform, cd.Region = platform(obj.Object)
+	cd.InstallRestarts, _, _ = unstructured.NestedInt64(obj.Object, "status", "installRestarts")
+
+	// the status is the actual power state, and the spec is the desired one
+	cd.PowerState = nestedString(obj.Object, "status", "powerState")
+	if cd.PowerState == "" {
+		cd.PowerState = nestedString(obj.Object, "spec", "powerState")
+	}
+
+	if installedAt := nestedString(obj.Object, "status", "installedTimestamp"); installedAt != "" {
+		if t, err := time.Parse(time.RFC3339, installedAt); err == nil {
+			cd.InstalledAt = &t
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, conditionType := range failureConditions {
+		if condition := findCondition(conditions, conditionType); nestedString(condition, "status") == "True" {
+			cd.FailureReason = nestedString(condition, "reason")
+			cd.FailureMessage = nestedString(condition, "message")
+			break
+		}
+	}
+	cd.Phase = provisionPhase(obj, conditions)
+	return cd, nil
+}
+
+// provisionPhase derives the phase of the clusterdeployment, the installed cluster is "Provisioned" even if the
+// previous attempts are failed
+func provisionPhase(obj *unstructured.Unstructured, conditions []interface{}) string {
+	if obj.GetDeletionTimestamp() != nil {
+		return phaseDeprovisioning
+	}
+	if installed, _, _ := unstructured.NestedBool(obj.Object, "spec", "installed"); installed {
+		return phaseProvisioned
+	}
+	if nestedString(findCondition(conditions, "ProvisionStopped"), "status") == "True" {
+		return phaseProvisionStopped
+	}
+	if nestedString(findCondition(conditions, "ProvisionFailed"), "status") == "True" {
+		return phaseProvisionFailed
+	}
+	if nestedString(obj.Object, "status", "provisionRef", "name") != "" {
+		return phaseProvisioning
+	}
+	return phasePending
+}
@@ -0,0 +1,141 @@
+package hive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newClusterDeployment(spec, status map[string]interface{}) *unstructured.Unstructured {
+	cd := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec, "status": status}}
+	cd.SetAPIVersion("hive.openshift.io/v1")
+	cd.SetKind("ClusterDeployment")
+	cd.SetNamespace("cluster1")
+	cd.SetName("cluster1")
+	return cd
+}
+
+func TestToClusterDeployment(t *testing.T) {
+	failed := map[string]interface{}{
+		"type": "ProvisionFailed", "status": "True", "reason": "AWSInsufficientCapacity", "message": "no capacity",
+	}
+	cases := []struct {
+		name    string
+		cd      *unstructured.Unstructured
+		phase   string
+		failure string
+	}{
+		{
+			name:  "pending",
+			cd:    newClusterDeployment(map[string]interface{}{}, map[string]interface{}{}),
+			phase: phasePending,
+		},
+		{
+			name: "provisioning",
+			cd: newClusterDeployment(map[string]interface{}{}, map[string]interface{}{
+				"provisionRef": map[string]interface{}{"name": "cluster1-0-abcde"},
+			}),
+			phase: phaseProvisioning,
+		},
+		{
+			name: "provision failed",
+			cd: newClusterDeployment(map[string]interface{}{}, map[string]interface{}{
+				"provisionRef": map[string]interface{}{"name": "cluster1-1-abcde"},
+				"conditions":   []interface{}{failed},
+			}),
+			phase:   phaseProvisionFailed,
+			failure: "AWSInsufficientCapacity",
+		},
+		{
+			name: "provision stopped",
+			cd: newClusterDeployment(map[string]interface{}{}, map[string]interface{}{
+				"conditions": []interface{}{
+					failed,
+					map[string]interface{}{"type": "ProvisionStopped", "status": "True", "reason": "InstallAttemptsLimitReached"},
+				},
+			}),
+			phase:   phaseProvisionStopped,
+			failure: "InstallAttemptsLimitReached",
+		},
+		{
+			name: "provisioned",
+			cd: newClusterDeployment(map[string]interface{}{"installed": true}, map[string]interface{}{
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "ProvisionFailed", "status": "False", "reason": "Provisioned"},
+				},
+			}),
+			phase: phaseProvisioned,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cd, err := toClusterDeployment("hub1", c.cd)
+			require.NoError(t, err)
+			assert.Equal(t, c.phase, cd.Phase)
+			assert.Equal(t, c.failure, cd.FailureReason)
+		})
+	}
+
+	now := metav1.NewTime(time.Now())
+	deleting := newClusterDeployment(map[string]interface{}{"installed": true}, map[string]interface{}{})
+	deleting.SetDeletionTimestamp(&now)
+	cd, err := toClusterDeployment("hub1", deleting)
+	require.NoError(t, err)
+	assert.Equal(t, phaseDeprovisioning, cd.Phase)
+}
+
+func TestToClusterDeploymentFields(t *testing.T) {
+	obj := newClusterDeployment(map[string]interface{}{
+		"clusterName":    "cluster1",
+		"baseDomain":     "example.com",
+		"powerState":     "Hibernating",
+		"platform":       map[string]interface{}{"aws": map[string]interface{}{"region": "us-east-1"}},
+		"clusterPoolRef": map[string]interface{}{"namespace": "pools", "poolName": "aws-pool"},
+		"provisioning":   map[string]interface{}{"imageSetRef": map[string]interface{}{"name": "img4.16"}},
+	}, map[string]interface{}{
+		"installRestarts":    int64(2),
+		"installVersion":     "4.16.1",
+		"installedTimestamp": "2025-01-01T00:00:00Z",
+	})
+
+	cd, err := toClusterDeployment("hub1", obj)
+	require.NoError(t, err)
+	assert.Equal(t, "aws", cd.Platform)
+	assert.Equal(t, "us-east-1", cd.Region)
+	assert.Equal(t, "pools", cd.PoolNamespace)
+	assert.Equal(t, "aws-pool", cd.PoolName)
+	assert.Equal(t, "img4.16", cd.ImageSet)
+	assert.Equal(t, "4.16.1", cd.InstallVersion)
+	// the desired power state is used if the actual one isn't reported
+	assert.Equal(t, "Hibernating", cd.PowerState)
+	assert.Equal(t, int64(2), cd.InstallRestarts)
+	require.NotNil(t, cd.InstalledAt)
+	assert.Equal(t, 2025, cd.InstalledAt.Year())
+}
+
+func TestToClusterPool(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"size":        int64(3),
+			"platform":    map[string]interface{}{"gcp": map[string]interface{}{"region": "us-east1"}},
+			"imageSetRef": map[string]interface{}{"name": "img4.16"},
+		},
+		"status": map[string]interface{}{"ready": int64(1), "standby": int64(2)},
+	}}
+	obj.SetNamespace("pools")
+	obj.SetName("gcp-pool")
+
+	pool, err := toClusterPool("hub1", obj)
+	require.NoError(t, err)
+	assert.Equal(t, "gcp", pool.Platform)
+	assert.Equal(t, "us-east1", pool.Region)
+	assert.Equal(t, "img4.16", pool.ImageSet)
+	assert.Equal(t, int64(3), pool.Size)
+	assert.Nil(t, pool.MaxSize)
+	assert.Equal(t, int64(1), pool.Ready)
+	assert.Equal(t, int64(2), pool.Standby)
+}
@@ -0,0 +1,128 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// clusterPoolHandler upserts the hive clusterpools with the capacity, the clusterpool is identified by the hub, the
+// namespace and the name
+type clusterPoolHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterClusterPoolHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ClusterPoolType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &clusterPoolHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ClusterPoolPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *clusterPoolHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[unstructured.Unstructured]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal clusterpool bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, objects := range [][]unstructured.Unstructured{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, objects); err != nil {
+			return fmt.Errorf("failed to upsert clusterpools - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND namespace = ? AND name = ?", leafHubName, deleted.Namespace,
+			deleted.Name).Delete(&models.ClusterPool{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete clusterpools - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := deleteStale(db, leafHubName, bundle.ResyncMetadata, &models.ClusterPool{}); err != nil {
+			return fmt.Errorf("failed to delete stale clusterpools - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *clusterPoolHandler) upsert(db *gorm.DB, leafHubName string, objects []unstructured.Unstructured) error {
+	if len(objects) == 0 {
+		return nil
+	}
+	rows := make([]models.ClusterPool, 0, len(objects))
+	for i := range objects {
+		row, err := toClusterPool(leafHubName, &objects[i])
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "namespace"}, {Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uid", "platform", "region", "image_set", "size", "max_size", "ready", "standby", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+func toClusterPool(leafHubName string, obj *unstructured.Unstructured) (*models.ClusterPool, error) {
+	payload, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	pool := &models.ClusterPool{
+		LeafHubName: leafHubName,
+		Namespace:   obj.GetNamespace(),
+		Name:        obj.GetName(),
+		UID:         string(obj.GetUID()),
+		ImageSet:    nestedString(obj.Object, "spec", "imageSetRef", "name"),
+		Payload:     payload,
+	}
+	pool.Platform, pool.Region = platform(obj.Object)
+	pool.Size, _, _ = unstructured.NestedInt64(obj.Object, "spec", "size")
+	if maxSize, found, _ := unstructured.NestedInt64(obj.Object, "spec", "maxSize"); found {
+		pool.MaxSize = &maxSize
+	}
+	pool.Ready, _, _ = unstructured.NestedInt64(obj.Object, "status", "ready")
+	pool.Standby, _, _ = unstructured.NestedInt64(obj.Object, "status", "standby")
+	return pool, nil
+}
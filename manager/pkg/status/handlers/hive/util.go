@@ -0,0 +1,63 @@
+package hive
+
+import (
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+)
+
+// deleteStale removes the objects of the hub which aren't in the resync metadata, the model is identified by the hub,
+// the namespace and the name
+func deleteStale(db *gorm.DB, leafHubName string, resyncMetadata []generic.ObjectMetadata, model interface{}) error {
+	synced := map[string]bool{}
+	for _, metadata := range resyncMetadata {
+		synced[metadata.Namespace+"/"+metadata.Name] = true
+	}
+
+	var existing []struct {
+		Namespace string
+		Name      string
+	}
+	err := db.Model(model).Select("namespace", "name").Where("leaf_hub_name = ?", leafHubName).
+		Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	for _, obj := range existing {
+		if synced[obj.Namespace+"/"+obj.Name] {
+			continue
+		}
+		err := db.Where("leaf_hub_name = ? AND namespace = ? AND name = ?", leafHubName, obj.Namespace, obj.Name).
+			Delete(model).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// platform returns the platform type and the region, e.g. {"aws": {"region": "us-east-1"}}
+func platform(obj map[string]interface{}) (string, string) {
+	platforms, _, _ := unstructured.NestedMap(obj, "spec", "platform")
+	for platformType, value := range platforms {
+		spec, _ := value.(map[string]interface{})
+		return platformType, nestedString(spec, "region")
+	}
+	return "", ""
+}
+
+func findCondition(conditions []interface{}, conditionType string) map[string]interface{} {
+	for _, condition := range conditions {
+		c, ok := condition.(map[string]interface{})
+		if ok && c["type"] == conditionType {
+			return c
+		}
+	}
+	return nil
+}
+
+func nestedString(obj map[string]interface{}, fields ...string) string {
+	value, _, _ := unstructured.NestedString(obj, fields...)
+	return value
+}
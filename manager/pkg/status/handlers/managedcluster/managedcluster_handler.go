@@ -8,6 +8,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	kessel "github.com/project-kessel/inventory-api/api/kessel/inventory/v1beta1/resources"
 	"github.com/stolostron/multicloud-operators-foundation/pkg/klusterlet/clusterclaim"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	generichandler "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedhub"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
@@ -81,6 +83,24 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 	}
 
 	db := database.GetGorm()
+	if len(bundle.Patch) > 0 {
+		payloads, err := generichandler.ApplyMergePatches(ctx, db, leafHubName, h.eventType, &models.ManagedCluster{},
+			bundle.Patch, func(db *gorm.DB, metadata generic.ObjectMetadata) *gorm.DB {
+				return db.Where("cluster_id = ?", metadata.ID)
+			})
+		if err != nil {
+			return fmt.Errorf("failed to patch managed clusters - %w", err)
+		}
+		// the patched clusters are synced to the inventory as the updated ones
+		for _, payload := range payloads {
+			cluster := clusterv1.ManagedCluster{}
+			if err := json.Unmarshal(payload, &cluster); err != nil {
+				return fmt.Errorf("failed to unmarshal patched managed cluster - %w", err)
+			}
+			bundle.Update = append(bundle.Update, cluster)
+		}
+	}
+
 	if len(bundle.Delete) > 0 {
 		for _, deleted := range bundle.Delete {
 			if deleted.ID != "" {
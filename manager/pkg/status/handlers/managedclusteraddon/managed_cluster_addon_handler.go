@@ -0,0 +1,158 @@
+package managedclusteraddon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/api/meta"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// managedClusterAddOnHandler upserts the addons of the managed clusters with the status of the health conditions, the
+// addon is identified by the hub, the cluster and the addon name
+type managedClusterAddOnHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterManagedClusterAddOnHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ManagedClusterAddOnType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &managedClusterAddOnHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ManagedClusterAddOnPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedClusterAddOnHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal managed cluster addon bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, addons := range [][]addonv1alpha1.ManagedClusterAddOn{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, addons); err != nil {
+			return fmt.Errorf("failed to upsert managed cluster addons - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ? AND addon_name = ?", leafHubName, deleted.Namespace,
+			deleted.Name).Delete(&models.ManagedClusterAddOn{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete managed cluster addons - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.deleteStale(db, leafHubName, bundle.ResyncMetadata); err != nil {
+			return fmt.Errorf("failed to delete stale managed cluster addons - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *managedClusterAddOnHandler) upsert(db *gorm.DB, leafHubName string,
+	addons []addonv1alpha1.ManagedClusterAddOn,
+) error {
+	if len(addons) == 0 {
+		return nil
+	}
+	rows := make([]models.ManagedClusterAddOn, 0, len(addons))
+	for i := range addons {
+		addon := &addons[i]
+		payload, err := json.Marshal(addon)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, models.ManagedClusterAddOn{
+			LeafHubName: leafHubName,
+			ClusterName: addon.Namespace,
+			AddonName:   addon.Name,
+			UID:         string(addon.UID),
+			Available:   conditionStatus(addon, addonv1alpha1.ManagedClusterAddOnConditionAvailable),
+			Degraded:    conditionStatus(addon, addonv1alpha1.ManagedClusterAddOnConditionDegraded),
+			Progressing: conditionStatus(addon, addonv1alpha1.ManagedClusterAddOnConditionProgressing),
+			Payload:     payload,
+		})
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "cluster_name"}, {Name: "addon_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uid", "available", "degraded", "progressing", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// deleteStale removes the addons of the hub which aren't in the resync metadata
+func (h *managedClusterAddOnHandler) deleteStale(db *gorm.DB, leafHubName string,
+	resyncMetadata []generic.ObjectMetadata,
+) error {
+	synced := map[string]bool{}
+	for _, metadata := range resyncMetadata {
+		synced[metadata.Namespace+"/"+metadata.Name] = true
+	}
+
+	var existing []models.ManagedClusterAddOn
+	err := db.Select("cluster_name", "addon_name").Where("leaf_hub_name = ?", leafHubName).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, addon := range existing {
+		if synced[addon.ClusterName+"/"+addon.AddonName] {
+			continue
+		}
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ? AND addon_name = ?", leafHubName, addon.ClusterName,
+			addon.AddonName).Delete(&models.ManagedClusterAddOn{}).Error
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		h.log.Debugw("deleted stale managed cluster addons", "LH", leafHubName, "count", count)
+	}
+	return nil
+}
+
+func conditionStatus(addon *addonv1alpha1.ManagedClusterAddOn, conditionType string) string {
+	condition := meta.FindStatusCondition(addon.Status.Conditions, conditionType)
+	if condition == nil {
+		return ""
+	}
+	return string(condition.Status)
+}
@@ -0,0 +1,268 @@
+package managedclusterinfo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const regionClaimName = "region.open-cluster-management.io"
+
+// regionLabels are the node labels of the region, the deprecated one is used by the old clusters
+var regionLabels = []string{corev1.LabelTopologyRegion, corev1.LabelFailureDomainBetaRegion}
+
+// managedClusterInfoHandler upserts the capacity and the version inventory of the managed clusters, the cluster is
+// identified by the hub and the cluster name
+type managedClusterInfoHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterManagedClusterInfoHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ManagedClusterInfoType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &managedClusterInfoHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ClusterInventoryPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *managedClusterInfoHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[clusterinfov1beta1.ManagedClusterInfo]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal managed cluster info bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, clusterInfos := range [][]clusterinfov1beta1.ManagedClusterInfo{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, clusterInfos); err != nil {
+			return fmt.Errorf("failed to upsert cluster inventory - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ?", leafHubName, deleted.Name).
+			Delete(&models.ClusterInventory{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete cluster inventory - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.deleteStale(db, leafHubName, bundle.ResyncMetadata); err != nil {
+			return fmt.Errorf("failed to delete stale cluster inventory - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *managedClusterInfoHandler) upsert(db *gorm.DB, leafHubName string,
+	clusterInfos []clusterinfov1beta1.ManagedClusterInfo,
+) error {
+	if len(clusterInfos) == 0 {
+		return nil
+	}
+	clusters, err := managedClusters(db, leafHubName, clusterInfos)
+	if err != nil {
+		return err
+	}
+	rows := make([]models.ClusterInventory, 0, len(clusterInfos))
+	for i := range clusterInfos {
+		row, err := toClusterInventory(leafHubName, &clusterInfos[i], clusters[clusterInfos[i].Name])
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "cluster_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"cluster_id", "kube_vendor", "cloud", "region", "kube_version", "openshift_version", "channel",
+			"desired_version", "upgrade_failed", "available_updates", "node_count", "ready_node_count", "cpu_capacity",
+			"memory_capacity", "cpu_allocatable", "memory_allocatable", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// deleteStale removes the clusters of the hub which aren't in the resync metadata
+func (h *managedClusterInfoHandler) deleteStale(db *gorm.DB, leafHubName string,
+	resyncMetadata []generic.ObjectMetadata,
+) error {
+	synced := map[string]bool{}
+	for _, metadata := range resyncMetadata {
+		synced[metadata.Name] = true
+	}
+
+	var existing []models.ClusterInventory
+	err := db.Select("cluster_name").Where("leaf_hub_name = ?", leafHubName).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, cluster := range existing {
+		if synced[cluster.ClusterName] {
+			continue
+		}
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ?", leafHubName, cluster.ClusterName).
+			Delete(&models.ClusterInventory{}).Error
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		h.log.Debugw("deleted stale cluster inventory", "LH", leafHubName, "count", count)
+	}
+	return nil
+}
+
+// managedClusters returns the synced ManagedClusters of the cluster infos by the name, they provide the allocatable
+// and the region claim of the clusters
+func managedClusters(db *gorm.DB, leafHubName string, clusterInfos []clusterinfov1beta1.ManagedClusterInfo,
+) (map[string]*clusterv1.ManagedCluster, error) {
+	names := make([]string, 0, len(clusterInfos))
+	for _, clusterInfo := range clusterInfos {
+		names = append(names, clusterInfo.Name)
+	}
+
+	var rows []models.ManagedCluster
+	err := db.Select("payload").Where("leaf_hub_name = ? AND cluster_name IN ?", leafHubName, names).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	clusters := map[string]*clusterv1.ManagedCluster{}
+	for _, row := range rows {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(row.Payload, cluster); err != nil {
+			return nil, err
+		}
+		clusters[cluster.Name] = cluster
+	}
+	return clusters, nil
+}
+
+// toClusterInventory converts the ManagedClusterInfo into the row, the capacity is the sum of the nodes and the
+// allocatable is the one of the ManagedCluster, the cluster may be nil if it isn't synced yet
+func toClusterInventory(leafHubName string, clusterInfo *clusterinfov1beta1.ManagedClusterInfo,
+	cluster *clusterv1.ManagedCluster,
+) (*models.ClusterInventory, error) {
+	payload, err := json.Marshal(clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	ocp := clusterInfo.Status.DistributionInfo.OCP
+	availableUpdates := ocp.AvailableUpdates
+	if availableUpdates == nil {
+		availableUpdates = []string{}
+	}
+	updates, err := json.Marshal(availableUpdates)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &models.ClusterInventory{
+		LeafHubName:      leafHubName,
+		ClusterName:      clusterInfo.Name,
+		ClusterID:        clusterInfo.Status.ClusterID,
+		KubeVendor:       string(clusterInfo.Status.KubeVendor),
+		Cloud:            cloud(clusterInfo, cluster),
+		Region:           region(clusterInfo, cluster),
+		KubeVersion:      clusterInfo.Status.Version,
+		OpenShiftVersion: ocp.Version,
+		Channel:          ocp.Channel,
+		DesiredVersion:   ocp.DesiredVersion,
+		UpgradeFailed:    ocp.UpgradeFailed,
+		AvailableUpdates: updates,
+		NodeCount:        int64(len(clusterInfo.Status.NodeList)),
+		Payload:          payload,
+	}
+	for _, node := range clusterInfo.Status.NodeList {
+		if cpu, ok := node.Capacity[clusterinfov1beta1.ResourceCPU]; ok {
+			inventory.CPUCapacity += cpu.MilliValue()
+		}
+		if memory, ok := node.Capacity[clusterinfov1beta1.ResourceMemory]; ok {
+			inventory.MemoryCapacity += memory.Value()
+		}
+		for _, condition := range node.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				inventory.ReadyNodeCount++
+			}
+		}
+	}
+	if cluster != nil {
+		if cpu, ok := cluster.Status.Allocatable[clusterv1.ResourceCPU]; ok {
+			inventory.CPUAllocatable = cpu.MilliValue()
+		}
+		if memory, ok := cluster.Status.Allocatable[clusterv1.ResourceMemory]; ok {
+			inventory.MemoryAllocatable = memory.Value()
+		}
+	}
+	return inventory, nil
+}
+
+// cloud returns the cloud vendor from the label of the cluster, e.g. "Amazon", the status of the ManagedClusterInfo
+// is used if the label isn't set
+func cloud(clusterInfo *clusterinfov1beta1.ManagedClusterInfo, cluster *clusterv1.ManagedCluster) string {
+	if cluster != nil && cluster.Labels[clusterinfov1beta1.LabelCloudVendor] != "" {
+		return cluster.Labels[clusterinfov1beta1.LabelCloudVendor]
+	}
+	if clusterInfo.Labels[clusterinfov1beta1.LabelCloudVendor] != "" {
+		return clusterInfo.Labels[clusterinfov1beta1.LabelCloudVendor]
+	}
+	return string(clusterInfo.Status.CloudVendor)
+}
+
+// region returns the region claim of the cluster, the region label of the nodes is used if the claim isn't reported
+func region(clusterInfo *clusterinfov1beta1.ManagedClusterInfo, cluster *clusterv1.ManagedCluster) string {
+	if cluster != nil {
+		for _, claim := range cluster.Status.ClusterClaims {
+			if claim.Name == regionClaimName && claim.Value != "" {
+				return claim.Value
+			}
+		}
+	}
+	for _, node := range clusterInfo.Status.NodeList {
+		for _, label := range regionLabels {
+			if node.Labels[label] != "" {
+				return node.Labels[label]
+			}
+		}
+	}
+	return ""
+}
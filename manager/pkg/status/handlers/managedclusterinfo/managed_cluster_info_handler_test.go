@@ -0,0 +1,99 @@
+package managedclusterinfo
+
+import (
+	"testing"
+
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+)
+
+func newNode(name, region string, ready corev1.ConditionStatus) clusterinfov1beta1.NodeStatus {
+	return clusterinfov1beta1.NodeStatus{
+		Name:   name,
+		Labels: map[string]string{corev1.LabelTopologyRegion: region},
+		Capacity: clusterinfov1beta1.ResourceList{
+			clusterinfov1beta1.ResourceCPU:    resource.MustParse("4"),
+			clusterinfov1beta1.ResourceMemory: resource.MustParse("16Gi"),
+		},
+		Conditions: []clusterinfov1beta1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+	}
+}
+
+func TestToClusterInventory(t *testing.T) {
+	clusterInfo := &clusterinfov1beta1.ManagedClusterInfo{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "cluster1", Name: "cluster1"},
+		Status: clusterinfov1beta1.ClusterInfoStatus{
+			Version:     "v1.27.6+f67aeb3",
+			KubeVendor:  clusterinfov1beta1.KubeVendorOpenShift,
+			CloudVendor: clusterinfov1beta1.CloudVendorAWS,
+			ClusterID:   "3f5f7b1a-0c3e-4a5b-9c6d-1e2f3a4b5c6d",
+			DistributionInfo: clusterinfov1beta1.DistributionInfo{
+				Type: clusterinfov1beta1.DistributionTypeOCP,
+				OCP: clusterinfov1beta1.OCPDistributionInfo{
+					Version:          "4.14.8",
+					AvailableUpdates: []string{"4.14.9", "4.15.2"},
+					Channel:          "stable-4.15",
+				},
+			},
+			NodeList: []clusterinfov1beta1.NodeStatus{
+				newNode("worker-0", "us-east-1", corev1.ConditionTrue),
+				newNode("worker-1", "us-east-1", corev1.ConditionFalse),
+			},
+		},
+	}
+
+	t.Run("without the managed cluster", func(t *testing.T) {
+		inventory, err := toClusterInventory("hub1", clusterInfo, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster1", inventory.ClusterName)
+		assert.Equal(t, "OpenShift", inventory.KubeVendor)
+		assert.Equal(t, "Amazon", inventory.Cloud)
+		assert.Equal(t, "us-east-1", inventory.Region)
+		assert.Equal(t, "4.14.8", inventory.OpenShiftVersion)
+		assert.Equal(t, "stable-4.15", inventory.Channel)
+		assert.JSONEq(t, `["4.14.9", "4.15.2"]`, string(inventory.AvailableUpdates))
+		assert.Equal(t, int64(2), inventory.NodeCount)
+		assert.Equal(t, int64(1), inventory.ReadyNodeCount)
+		assert.Equal(t, int64(8000), inventory.CPUCapacity)
+		assert.Equal(t, int64(32*1024*1024*1024), inventory.MemoryCapacity)
+		assert.Zero(t, inventory.CPUAllocatable)
+		assert.Zero(t, inventory.MemoryAllocatable)
+	})
+
+	t.Run("with the managed cluster", func(t *testing.T) {
+		cluster := &clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster1",
+				Labels: map[string]string{clusterinfov1beta1.LabelCloudVendor: "Azure"},
+			},
+			Status: clusterv1.ManagedClusterStatus{
+				Allocatable: clusterv1.ResourceList{
+					clusterv1.ResourceCPU:    resource.MustParse("7500m"),
+					clusterv1.ResourceMemory: resource.MustParse("30Gi"),
+				},
+				ClusterClaims: []clusterv1.ManagedClusterClaim{{Name: regionClaimName, Value: "eastus"}},
+			},
+		}
+		inventory, err := toClusterInventory("hub1", clusterInfo, cluster)
+		require.NoError(t, err)
+		assert.Equal(t, "Azure", inventory.Cloud)
+		assert.Equal(t, "eastus", inventory.Region)
+		assert.Equal(t, int64(7500), inventory.CPUAllocatable)
+		assert.Equal(t, int64(30*1024*1024*1024), inventory.MemoryAllocatable)
+	})
+
+	t.Run("without the available updates", func(t *testing.T) {
+		inventory, err := toClusterInventory("hub1", &clusterinfov1beta1.ManagedClusterInfo{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cluster2", Name: "cluster2"},
+		}, nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[]`, string(inventory.AvailableUpdates))
+		assert.Empty(t, inventory.Region)
+		assert.Zero(t, inventory.NodeCount)
+	})
+}
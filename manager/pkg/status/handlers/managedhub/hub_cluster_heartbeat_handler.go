@@ -12,6 +12,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
 func RegisterHubClusterHeartbeatHandler(conflationManager *conflator.ConflationManager) {
@@ -33,5 +34,7 @@ func handleHeartbeatEvent(ctx context.Context, evt *cloudevents.Event) error {
 	if err != nil {
 		return fmt.Errorf("failed to update heartbeat %v", err)
 	}
+	// the status metrics are labeled by the hubs reporting the heartbeats
+	statistics.AddHub(evt.Source())
 	return nil
 }
@@ -0,0 +1,188 @@
+package manifestwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/api/meta"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// the labels added by the manifestworkreplicaset controller to the manifestworks it creates, the value of the
+	// replicaset label is "<namespace>.<name>" of the manifestworkreplicaset, which is in the namespace of the placement
+	replicaSetLabelKey    = "work.open-cluster-management.io/manifestworkreplicaset"
+	placementNameLabelKey = "work.open-cluster-management.io/placementname"
+)
+
+// manifestWorkHandler upserts the manifestworks with the status of the conditions and the manifests, the manifestwork
+// is identified by the hub, the cluster and the work name
+type manifestWorkHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterManifestWorkHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.ManifestWorkType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &manifestWorkHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.HybridStateMode,
+		eventPriority: conflator.ManifestWorkPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *manifestWorkHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+
+	var bundle generic.GenericBundle[workv1.ManifestWork]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal manifestwork bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", leafHubName, "version", version, "error", err)
+		return nil
+	}
+
+	db := database.GetGorm()
+	for _, works := range [][]workv1.ManifestWork{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsert(db, leafHubName, works); err != nil {
+			return fmt.Errorf("failed to upsert manifestworks - %w", err)
+		}
+	}
+
+	for _, deleted := range bundle.Delete {
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ? AND work_name = ?", leafHubName, deleted.Namespace,
+			deleted.Name).Delete(&models.ManifestWork{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete manifestworks - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.deleteStale(db, leafHubName, bundle.ResyncMetadata); err != nil {
+			return fmt.Errorf("failed to delete stale manifestworks - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName, "version", version)
+	return nil
+}
+
+func (h *manifestWorkHandler) upsert(db *gorm.DB, leafHubName string, works []workv1.ManifestWork) error {
+	if len(works) == 0 {
+		return nil
+	}
+	rows := make([]models.ManifestWork, 0, len(works))
+	for i := range works {
+		row, err := toManifestWork(leafHubName, &works[i])
+		if err != nil {
+			return err
+		}
+		rows = append(rows, *row)
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "cluster_name"}, {Name: "work_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"uid", "placement_namespace", "placement_name", "replica_set_name", "applied", "available", "degraded",
+			"progressing", "manifests", "payload", "updated_at",
+		}),
+	}).Create(&rows).Error
+}
+
+// deleteStale removes the manifestworks of the hub which aren't in the resync metadata
+func (h *manifestWorkHandler) deleteStale(db *gorm.DB, leafHubName string,
+	resyncMetadata []generic.ObjectMetadata,
+) error {
+	synced := map[string]bool{}
+	for _, metadata := range resyncMetadata {
+		synced[metadata.Namespace+"/"+metadata.Name] = true
+	}
+
+	var existing []models.ManifestWork
+	err := db.Select("cluster_name", "work_name").Where("leaf_hub_name = ?", leafHubName).Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, work := range existing {
+		if synced[work.ClusterName+"/"+work.WorkName] {
+			continue
+		}
+		err := db.Where("leaf_hub_name = ? AND cluster_name = ? AND work_name = ?", leafHubName, work.ClusterName,
+			work.WorkName).Delete(&models.ManifestWork{}).Error
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if count > 0 {
+		h.log.Debugw("deleted stale manifestworks", "LH", leafHubName, "count", count)
+	}
+	return nil
+}
+
+// toManifestWork converts the manifestwork into the row, the placement is only set if the work is created by the
+// manifestworkreplicaset
+func toManifestWork(leafHubName string, work *workv1.ManifestWork) (*models.ManifestWork, error) {
+	payload, err := json.Marshal(work)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := json.Marshal(work.Status.ResourceStatus.Manifests)
+	if err != nil {
+		return nil, err
+	}
+	row := &models.ManifestWork{
+		LeafHubName: leafHubName,
+		ClusterName: work.Namespace,
+		WorkName:    work.Name,
+		UID:         string(work.UID),
+		Applied:     conditionStatus(work, workv1.WorkApplied),
+		Available:   conditionStatus(work, workv1.WorkAvailable),
+		Degraded:    conditionStatus(work, workv1.WorkDegraded),
+		Progressing: conditionStatus(work, workv1.WorkProgressing),
+		Manifests:   manifests,
+		Payload:     payload,
+	}
+	if replicaSet, ok := work.Labels[replicaSetLabelKey]; ok {
+		// the namespace can't contain the ".", so the first one separates the namespace and the name
+		if namespace, name, found := strings.Cut(replicaSet, "."); found {
+			row.PlacementNamespace = namespace
+			row.ReplicaSetName = name
+		}
+		row.PlacementName = work.Labels[placementNameLabelKey]
+	}
+	return row, nil
+}
+
+func conditionStatus(work *workv1.ManifestWork, conditionType string) string {
+	condition := meta.FindStatusCondition(work.Status.Conditions, conditionType)
+	if condition == nil {
+		return ""
+	}
+	return string(condition.Status)
+}
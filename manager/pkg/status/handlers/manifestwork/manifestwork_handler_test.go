@@ -0,0 +1,54 @@
+package manifestwork
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestToManifestWork(t *testing.T) {
+	work := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "cluster1",
+			Name:      "mwrs-nginx",
+			Labels: map[string]string{
+				replicaSetLabelKey:    "default.mwrs-nginx",
+				placementNameLabelKey: "placement1",
+			},
+		},
+		Status: workv1.ManifestWorkStatus{
+			Conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: metav1.ConditionTrue},
+				{Type: workv1.WorkAvailable, Status: metav1.ConditionFalse},
+			},
+			ResourceStatus: workv1.ManifestResourceStatus{
+				Manifests: []workv1.ManifestCondition{{
+					ResourceMeta: workv1.ManifestResourceMeta{Kind: "Deployment", Namespace: "default", Name: "nginx"},
+				}},
+			},
+		},
+	}
+
+	row, err := toManifestWork("hub1", work)
+	require.NoError(t, err)
+	assert.Equal(t, "cluster1", row.ClusterName)
+	assert.Equal(t, "mwrs-nginx", row.WorkName)
+	assert.Equal(t, "default", row.PlacementNamespace)
+	assert.Equal(t, "placement1", row.PlacementName)
+	assert.Equal(t, "mwrs-nginx", row.ReplicaSetName)
+	assert.Equal(t, "True", row.Applied)
+	assert.Equal(t, "False", row.Available)
+	assert.Empty(t, row.Degraded)
+	assert.Contains(t, string(row.Manifests), `"name":"nginx"`)
+
+	// the work isn't created by the manifestworkreplicaset
+	work.Labels = nil
+	row, err = toManifestWork("hub1", work)
+	require.NoError(t, err)
+	assert.Empty(t, row.PlacementNamespace)
+	assert.Empty(t, row.PlacementName)
+	assert.Empty(t, row.ReplicaSetName)
+}
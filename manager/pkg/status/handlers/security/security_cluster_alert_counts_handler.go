@@ -0,0 +1,106 @@
+package security
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// securityClusterAlertCountsHandler replaces the alert counts of the clusters secured by a Central instance of the
+// hub, the event always contains all the clusters of the Central.
+type securityClusterAlertCountsHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterSecurityClusterAlertCountsHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SecurityClusterAlertCountsType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &securityClusterAlertCountsHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SecurityClusterAlertCountsPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityClusterAlertCountsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	// Extract the data from the event:
+	wireModel := &wiremodels.SecurityClusterAlertCounts{}
+	if err := evt.DataAs(wireModel); err != nil {
+		h.log.Warnw("failed to unmarshal security cluster alert counts event", "type", enum.ShortenEventType(evt.Type()),
+			"LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	// Convert the wire representation to the database representation:
+	dbModels := make([]dbmodels.SecurityClusterAlertCounts, 0, len(wireModel.Clusters))
+	clusterNames := make([]string, 0, len(wireModel.Clusters))
+	for _, cluster := range wireModel.Clusters {
+		dbModels = append(dbModels, dbmodels.SecurityClusterAlertCounts{
+			HubName:     leafHubName,
+			Source:      wireModel.Source,
+			ClusterName: cluster.Cluster,
+			Low:         cluster.Low,
+			Medium:      cluster.Medium,
+			High:        cluster.High,
+			Critical:    cluster.Critical,
+			DetailURL:   wireModel.DetailURL,
+		})
+		clusterNames = append(clusterNames, cluster.Cluster)
+	}
+
+	// Insert or update the clusters, and delete the clusters which are no longer secured by the Central:
+	err := database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if len(dbModels) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "hub_name"}, {Name: "source"}, {Name: "cluster_name"}},
+				UpdateAll: true,
+			}).Create(&dbModels).Error
+			if err != nil {
+				return err
+			}
+		}
+		return deleteStaleClusters(tx, &dbmodels.SecurityClusterAlertCounts{}, leafHubName, wireModel.Source,
+			clusterNames)
+	})
+	if err != nil {
+		return err
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+// deleteStaleClusters deletes the rows of the hub and the Central whose clusters aren't in the given ones.
+func deleteStaleClusters(tx *gorm.DB, model any, hubName, source string, clusterNames []string) error {
+	query := tx.Where("hub_name = ? AND source = ?", hubName, source)
+	if len(clusterNames) > 0 {
+		query = query.Where("cluster_name NOT IN ?", clusterNames)
+	}
+	return query.Delete(model).Error
+}
@@ -0,0 +1,98 @@
+package security
+
+import (
+	"context"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// securityImageVulnerabilitiesHandler replaces the image vulnerability counts of the clusters secured by a Central
+// instance of the hub, the event always contains all the clusters of the Central.
+type securityImageVulnerabilitiesHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterSecurityImageVulnerabilitiesHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SecurityImageVulnerabilitiesType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &securityImageVulnerabilitiesHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SecurityImageVulnerabilitiesPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityImageVulnerabilitiesHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	// Extract the data from the event:
+	wireModel := &wiremodels.SecurityImageVulnerabilities{}
+	if err := evt.DataAs(wireModel); err != nil {
+		h.log.Warnw("failed to unmarshal security image vulnerabilities event", "type",
+			enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	// Convert the wire representation to the database representation:
+	dbModels := make([]dbmodels.SecurityImageVulnerabilities, 0, len(wireModel.Clusters))
+	clusterNames := make([]string, 0, len(wireModel.Clusters))
+	for _, cluster := range wireModel.Clusters {
+		dbModels = append(dbModels, dbmodels.SecurityImageVulnerabilities{
+			HubName:     leafHubName,
+			Source:      wireModel.Source,
+			ClusterName: cluster.Cluster,
+			ClusterID:   cluster.ClusterID,
+			Low:         cluster.Low,
+			Moderate:    cluster.Moderate,
+			Important:   cluster.Important,
+			Critical:    cluster.Critical,
+			Fixable:     cluster.Fixable,
+		})
+		clusterNames = append(clusterNames, cluster.Cluster)
+	}
+
+	// Insert or update the clusters, and delete the clusters which are no longer secured by the Central:
+	err := database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if len(dbModels) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "hub_name"}, {Name: "source"}, {Name: "cluster_name"}},
+				UpdateAll: true,
+			}).Create(&dbModels).Error
+			if err != nil {
+				return err
+			}
+		}
+		return deleteStaleClusters(tx, &dbmodels.SecurityImageVulnerabilities{}, leafHubName, wireModel.Source,
+			clusterNames)
+	})
+	if err != nil {
+		return err
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
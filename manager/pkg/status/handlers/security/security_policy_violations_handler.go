@@ -0,0 +1,119 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// securityPolicyViolationsHandler replaces the most violated policies of the clusters secured by a Central instance of
+// the hub, the event always contains all the violated policies of the Central that are kept.
+type securityPolicyViolationsHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterSecurityPolicyViolationsHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SecurityPolicyViolationsType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &securityPolicyViolationsHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.CompleteStateMode,
+		eventPriority: conflator.SecurityPolicyViolationsPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityPolicyViolationsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", evt.Type(), "LH", evt.Source(), "version", version)
+
+	// Extract the data from the event:
+	wireModel := &wiremodels.SecurityPolicyViolations{}
+	if err := evt.DataAs(wireModel); err != nil {
+		h.log.Warnw("failed to unmarshal security policy violations event", "type", enum.ShortenEventType(evt.Type()),
+			"LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	// Convert the wire representation to the database representation:
+	dbModels, err := toPolicyViolations(leafHubName, wireModel)
+	if err != nil {
+		return err
+	}
+	violated := make([][]interface{}, 0, len(dbModels))
+	for _, violation := range dbModels {
+		violated = append(violated, []interface{}{violation.ClusterName, violation.PolicyID})
+	}
+
+	// Insert or update the violations, and delete the policies which are no longer the most violated ones:
+	err = database.GetGorm().Transaction(func(tx *gorm.DB) error {
+		if len(dbModels) > 0 {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{
+					{Name: "hub_name"}, {Name: "source"}, {Name: "cluster_name"}, {Name: "policy_id"},
+				},
+				UpdateAll: true,
+			}).Create(&dbModels).Error
+			if err != nil {
+				return err
+			}
+		}
+		query := tx.Where("hub_name = ? AND source = ?", leafHubName, wireModel.Source)
+		if len(violated) > 0 {
+			query = query.Where("(cluster_name, policy_id) NOT IN ?", violated)
+		}
+		return query.Delete(&dbmodels.SecurityPolicyViolation{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	h.log.Debugw("handler finished", "type", evt.Type(), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+func toPolicyViolations(hubName string, wireModel *wiremodels.SecurityPolicyViolations,
+) ([]dbmodels.SecurityPolicyViolation, error) {
+	dbModels := make([]dbmodels.SecurityPolicyViolation, 0, len(wireModel.Violations))
+	for _, violation := range wireModel.Violations {
+		categories, err := json.Marshal(violation.Categories)
+		if err != nil {
+			return nil, err
+		}
+		dbModels = append(dbModels, dbmodels.SecurityPolicyViolation{
+			HubName:     hubName,
+			Source:      wireModel.Source,
+			ClusterName: violation.Cluster,
+			PolicyID:    violation.PolicyID,
+			ClusterID:   violation.ClusterID,
+			PolicyName:  violation.PolicyName,
+			Severity:    violation.Severity,
+			Categories:  categories,
+			Count:       violation.Count,
+		})
+	}
+	return dbModels, nil
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package latency tracks the latency of the status events from the emission of the agent to the database commit of
+// the manager. The emit time is stamped by the agent with the transport.EmitTimeKey extension, and the clock offset
+// of each hub is estimated with its heartbeats, so that the latency isn't skewed by the clocks of the hubs. The
+// estimated offset also includes the transit time of the heartbeats, so the corrected latency is a lower bound, and
+// the raw latency, which is exact if the clocks are synchronized, is reported next to it.
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// StageReceived is the latency from the emission to receiving the event from the transport
+	StageReceived = "received"
+	// StageConflated is the latency from the emission to picking the event from the conflation queue
+	StageConflated = "conflated"
+	// StageCommitted is the latency from the emission to committing the event into the database by the handler
+	StageCommitted = "committed"
+
+	// the clock offset is estimated with the minimum delay of the recent heartbeats, the heartbeat is tiny, so the
+	// minimum delay is close to the clock offset between the hub and the manager
+	clockOffsetSamples = 10
+)
+
+// StageLatency summarizes the latency of a stage in seconds
+type StageLatency struct {
+	Count       int64     `json:"count"`
+	LastSeconds float64   `json:"lastSeconds"`
+	AvgSeconds  float64   `json:"avgSeconds"`
+	MaxSeconds  float64   `json:"maxSeconds"`
+	LastTime    time.Time `json:"lastTime"`
+	sumSeconds  float64
+}
+
+// EventLatency is the latency of the stages for an event type of the hub, the stages are corrected by the clock offset
+// of the hub, and the raw stages aren't
+type EventLatency struct {
+	EventType string                   `json:"eventType"`
+	Stages    map[string]*StageLatency `json:"stages"`
+	RawStages map[string]*StageLatency `json:"rawStages"`
+}
+
+// HubLatency is the latency of the events and the estimated clock offset of the hub, a positive offset means the
+// clock of the hub is behind the manager
+type HubLatency struct {
+	Hub                string          `json:"hub"`
+	ClockOffsetSeconds float64         `json:"clockOffsetSeconds"`
+	Events             []*EventLatency `json:"events"`
+}
+
+type hubState struct {
+	// the delays of the recent heartbeats, which are used to estimate the clock offset
+	heartbeatDelays []time.Duration
+	clockOffset     time.Duration
+	// event type -> the latency of the stages
+	events map[string]*eventState
+}
+
+// eventState is the corrected and raw latency of the stages for an event type
+type eventState struct {
+	stages    map[string]*StageLatency
+	rawStages map[string]*StageLatency
+}
+
+// Tracker records the latency of the events per hub and event type
+type Tracker struct {
+	mutex sync.RWMutex
+	hubs  map[string]*hubState
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{hubs: map[string]*hubState{}}
+}
+
+var defaultTracker = NewTracker()
+
+// Received records the received stage of the event, and updates the clock offset if it's a heartbeat
+func Received(evt *cloudevents.Event) {
+	defaultTracker.Observe(evt, StageReceived, time.Now())
+}
+
+// Conflated records the stage when the event is picked from the conflation queue by the worker
+func Conflated(evt *cloudevents.Event) {
+	defaultTracker.Observe(evt, StageConflated, time.Now())
+}
+
+// Committed records the stage when the event is committed into the database by the handler
+func Committed(evt *cloudevents.Event) {
+	defaultTracker.Observe(evt, StageCommitted, time.Now())
+}
+
+// List returns the latency of the hub, or all the hubs if the hub isn't specified
+func List(hub string) []*HubLatency {
+	return defaultTracker.List(hub)
+}
+
+// RemoveHub removes the latency and the clock offset of the hub, it's invoked once the hub is inactive
+func RemoveHub(hub string) {
+	defaultTracker.RemoveHub(hub)
+}
+
+// Observe records the latency of the stage from the emit time of the event to the given time, the event without the
+// emit time is skipped since it's emitted by the agent without the latency tracking
+func (t *Tracker) Observe(evt *cloudevents.Event, stage string, at time.Time) {
+	emitTime, ok := transport.EmitTime(evt)
+	if !ok {
+		return
+	}
+	hub := evt.Source()
+	eventType := enum.ShortenEventType(evt.Type())
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	state, found := t.hubs[hub]
+	if !found {
+		state = &hubState{events: map[string]*eventState{}}
+		t.hubs[hub] = state
+	}
+	if stage == StageReceived && evt.Type() == string(enum.HubClusterHeartbeatType) {
+		state.observeHeartbeat(at.Sub(emitTime))
+		clockOffsetGauge.WithLabelValues(hub).Set(state.clockOffset.Seconds())
+	}
+
+	raw := max(at.Sub(emitTime), 0)
+	latency := max(at.Sub(emitTime)-state.clockOffset, 0)
+	events, found := state.events[eventType]
+	if !found {
+		events = &eventState{stages: map[string]*StageLatency{}, rawStages: map[string]*StageLatency{}}
+		state.events[eventType] = events
+	}
+	observeStage(events.stages, stage, latency, at)
+	observeStage(events.rawStages, stage, raw, at)
+	latencyHistogram.WithLabelValues(hub, eventType, stage).Observe(latency.Seconds())
+	rawLatencyHistogram.WithLabelValues(hub, eventType, stage).Observe(raw.Seconds())
+}
+
+// RemoveHub removes the state and the metrics of the hub
+func (t *Tracker) RemoveHub(hub string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.hubs, hub)
+	latencyHistogram.DeletePartialMatch(prometheus.Labels{"hub": hub})
+	rawLatencyHistogram.DeletePartialMatch(prometheus.Labels{"hub": hub})
+	clockOffsetGauge.DeleteLabelValues(hub)
+}
+
+func observeStage(stages map[string]*StageLatency, stage string, latency time.Duration, at time.Time) {
+	stageLatency, found := stages[stage]
+	if !found {
+		stageLatency = &StageLatency{}
+		stages[stage] = stageLatency
+	}
+	stageLatency.observe(latency, at)
+}
+
+func (s *hubState) observeHeartbeat(delay time.Duration) {
+	s.heartbeatDelays = append(s.heartbeatDelays, delay)
+	if len(s.heartbeatDelays) > clockOffsetSamples {
+		s.heartbeatDelays = s.heartbeatDelays[len(s.heartbeatDelays)-clockOffsetSamples:]
+	}
+	s.clockOffset = s.heartbeatDelays[0]
+	for _, d := range s.heartbeatDelays {
+		if d < s.clockOffset {
+			s.clockOffset = d
+		}
+	}
+}
+
+func (l *StageLatency) observe(latency time.Duration, at time.Time) {
+	seconds := latency.Seconds()
+	l.Count++
+	l.LastSeconds = seconds
+	l.LastTime = at
+	l.sumSeconds += seconds
+	l.AvgSeconds = l.sumSeconds / float64(l.Count)
+	if seconds > l.MaxSeconds {
+		l.MaxSeconds = seconds
+	}
+}
+
+// List returns the copy of the latency of the hub, or all the hubs if the hub isn't specified
+func (t *Tracker) List(hub string) []*HubLatency {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	hubLatencies := []*HubLatency{}
+	for name, state := range t.hubs {
+		if hub != "" && hub != name {
+			continue
+		}
+		hubLatency := &HubLatency{
+			Hub:                name,
+			ClockOffsetSeconds: state.clockOffset.Seconds(),
+			Events:             []*EventLatency{},
+		}
+		for eventType, events := range state.events {
+			hubLatency.Events = append(hubLatency.Events, &EventLatency{
+				EventType: eventType,
+				Stages:    copyStages(events.stages),
+				RawStages: copyStages(events.rawStages),
+			})
+		}
+		sort.Slice(hubLatency.Events, func(i, j int) bool {
+			return hubLatency.Events[i].EventType < hubLatency.Events[j].EventType
+		})
+		hubLatencies = append(hubLatencies, hubLatency)
+	}
+	sort.Slice(hubLatencies, func(i, j int) bool { return hubLatencies[i].Hub < hubLatencies[j].Hub })
+	return hubLatencies
+}
+
+func copyStages(stages map[string]*StageLatency) map[string]*StageLatency {
+	copied := make(map[string]*StageLatency, len(stages))
+	for stage, stageLatency := range stages {
+		c := *stageLatency
+		copied[stage] = &c
+	}
+	return copied
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package latency
+
+import (
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func newEmittedEvent(eventType enum.EventType, emitTime time.Time) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(eventType))
+	evt.SetSource("hub1")
+	evt.SetExtension(transport.EmitTimeKey, emitTime)
+	return &evt
+}
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	now := time.Now()
+
+	// the clock of the hub is 10s behind the manager, the offset is the minimum delay of the heartbeats
+	tracker.Observe(newEmittedEvent(enum.HubClusterHeartbeatType, now.Add(-12*time.Second)), StageReceived, now)
+	tracker.Observe(newEmittedEvent(enum.HubClusterHeartbeatType, now.Add(-10*time.Second)), StageReceived, now)
+	hubs := tracker.List("hub1")
+	require.Len(t, hubs, 1)
+	require.Equal(t, float64(10), hubs[0].ClockOffsetSeconds)
+
+	// the latency of the stages excludes the clock offset
+	evt := newEmittedEvent(enum.ManagedClusterType, now.Add(-15*time.Second))
+	tracker.Observe(evt, StageReceived, now)
+	tracker.Observe(evt, StageConflated, now.Add(2*time.Second))
+	tracker.Observe(evt, StageCommitted, now.Add(3*time.Second))
+
+	hubs = tracker.List("")
+	require.Len(t, hubs, 1)
+	require.Len(t, hubs[0].Events, 2)
+	clusterLatency := hubs[0].Events[0]
+	require.Equal(t, enum.ShortenEventType(string(enum.ManagedClusterType)), clusterLatency.EventType)
+	require.Equal(t, float64(5), clusterLatency.Stages[StageReceived].LastSeconds)
+	require.Equal(t, float64(7), clusterLatency.Stages[StageConflated].LastSeconds)
+	require.Equal(t, float64(8), clusterLatency.Stages[StageCommitted].MaxSeconds)
+
+	// the raw latency isn't corrected by the clock offset
+	require.Equal(t, float64(15), clusterLatency.RawStages[StageReceived].LastSeconds)
+	require.Equal(t, float64(18), clusterLatency.RawStages[StageCommitted].MaxSeconds)
+
+	// the event without the emit time isn't tracked
+	untracked := cloudevents.NewEvent()
+	untracked.SetType(string(enum.ManagedClusterType))
+	untracked.SetSource("hub2")
+	tracker.Observe(&untracked, StageCommitted, now)
+	require.Empty(t, tracker.List("hub2"))
+
+	// the state of the removed hub is pruned
+	tracker.RemoveHub("hub1")
+	require.Empty(t, tracker.List(""))
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package latency
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	latencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "multicluster_global_hub_status_latency_seconds",
+			Help: "The latency of the status events from the emission of the agent to the stages of the manager, " +
+				"corrected by the clock offset of the hub, it's a lower bound since the offset includes the transit time.",
+			// the SLO is that the status is visible in the global hub within 60 seconds
+			Buckets: []float64{0.5, 1, 2, 5, 10, 15, 30, 45, 60, 90, 120, 300, 600},
+		},
+		[]string{
+			"hub",   // The leaf hub name of the event.
+			"type",  // The shortened event type.
+			"stage", // The stage of the manager: received, conflated or committed.
+		},
+	)
+
+	rawLatencyHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "multicluster_global_hub_status_raw_latency_seconds",
+			Help: "The latency of the status events from the emission of the agent to the stages of the manager, " +
+				"without the correction of the clock offset of the hub.",
+			Buckets: []float64{0.5, 1, 2, 5, 10, 15, 30, 45, 60, 90, 120, 300, 600},
+		},
+		[]string{
+			"hub",   // The leaf hub name of the event.
+			"type",  // The shortened event type.
+			"stage", // The stage of the manager: received, conflated or committed.
+		},
+	)
+
+	clockOffsetGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_hub_clock_offset_seconds",
+			Help: "The clock offset of the hub estimated with the minimum delay of the heartbeats, it's positive " +
+				"if the hub is behind.",
+		},
+		[]string{
+			"hub", // The leaf hub name.
+		},
+	)
+)
+
+var registerOnce sync.Once
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		metrics.Registry.MustRegister(latencyHistogram, rawLatencyHistogram, clockOffsetGauge)
+	})
+}
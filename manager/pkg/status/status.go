@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
@@ -15,6 +16,25 @@ import (
 
 var statusCtrlStarted = false
 
+// shardedManager adds the status runnables to run on every manager replica rather than the leader only, each replica
+// processes the status of the partitions assigned to it by the consumer group
+type shardedManager struct {
+	ctrl.Manager
+}
+
+func (m *shardedManager) Add(runnable manager.Runnable) error {
+	return m.Manager.Add(&shardedRunnable{Runnable: runnable})
+}
+
+type shardedRunnable struct {
+	manager.Runnable
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface
+func (*shardedRunnable) NeedLeaderElection() bool {
+	return false
+}
+
 // AddStatusSyncers performs the initial setup required before starting the runtime manager.
 // adds controllers and/or runnables to the manager, registers handler to conflation manager
 func AddStatusSyncers(
@@ -26,6 +46,9 @@ func AddStatusSyncers(
 	if statusCtrlStarted {
 		return nil
 	}
+	if managerConfig.TransportConfig.StatusSharding {
+		mgr = &shardedManager{Manager: mgr}
+	}
 	// create statistics
 	stats := statistics.NewStatistics(managerConfig.StatisticsConfig)
 	if err := mgr.Add(stats); err != nil {
@@ -34,8 +57,8 @@ func AddStatusSyncers(
 
 	// manage all Conflation Units and handlers
 	conflationManager := conflator.NewConflationManager(stats, requester)
+	conflationManager.GetReadyQueue().SetSchedulingConfig(managerConfig.SchedulingConfig)
 	handlers.RegisterHandlers(mgr, conflationManager, managerConfig.EnableGlobalResource)
-
 	// start consume message from transport to conflation manager
 	if err := dispatcher.AddTransportDispatcher(mgr, consumer, managerConfig, conflationManager, stats); err != nil {
 		return err
@@ -51,6 +74,15 @@ func AddStatusSyncers(
 	if err := mgr.Add(committer); err != nil {
 		return fmt.Errorf("failed to start the offset committer: %w", err)
 	}
+
+	// each replica conflates the events and commits the offsets of the partitions assigned to it
+	if managerConfig.TransportConfig.StatusSharding {
+		partitionOwner, ok := consumer.(transport.PartitionOwner)
+		if !ok {
+			return fmt.Errorf("the status sharding isn't supported by the consumer %T", consumer)
+		}
+		partitionOwner.SetRebalanceListener(conflator.NewConflationSharding(conflationManager, committer))
+	}
 	statusCtrlStarted = true
 	return nil
 }
@@ -354,7 +354,10 @@ func getCA(c client.Client, isServer bool, namespace string) (*x509.Certificate,
 	if !isServer {
 		caCertName = InventoryClientCASecretName
 	}
+	return getCAByName(c, namespace, caCertName)
+}
 
+func getCAByName(c client.Client, namespace, caCertName string) (*x509.Certificate, *rsa.PrivateKey, []byte, error) {
 	key, cert, err := GetKeyAndCert(c, namespace, caCertName)
 	if err != nil {
 		log.Error(err, "Failed to get ca secret", "name", caCertName)
@@ -0,0 +1,124 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
+)
+
+const (
+	// RestTransportCASecretName is the CA to issue the certificates of the rest transport server in the manager and
+	// the clients of the hubs, the server verifies the client certificates with the ca.crt of it
+	RestTransportCASecretName    = "multicluster-global-hub-transport-rest-ca"
+	restTransportCACertificateCN = "transport-rest-ca-certificate"
+	// RestTransportServerSecretName keeps the certificate of the rest transport server and the ca.crt of the clients
+	RestTransportServerSecretName = "multicluster-global-hub-transport-rest-server"
+	// RestTransportRouteName exposes the rest transport service to the managed hubs
+	RestTransportRouteName = "multicluster-global-hub-manager-transport"
+	// RestTransportPort is the port of the rest transport server in the manager service
+	RestTransportPort = 9444
+)
+
+// CreateRestTransportCA creates the CA secret to issue the rest transport certificates if it doesn't exist
+func CreateRestTransportCA(c client.Client, scheme *runtime.Scheme, mgh *v1alpha4.MulticlusterGlobalHub) error {
+	err, _ := createCASecret(c, scheme, mgh, false, RestTransportCASecretName, mgh.Namespace,
+		restTransportCACertificateCN)
+	return err
+}
+
+// RestTransportServiceHost returns the host of the rest transport service in the cluster of the global hub, the
+// service is routed to the leader of the manager which runs the server
+func RestTransportServiceHost(namespace string) string {
+	return fmt.Sprintf("%s.%s.svc", rest.ServiceName, namespace)
+}
+
+// EnsureRestTransportServerCert issues the certificate of the rest transport server for the service and the route
+// host, the certificate is reissued once the route host is admitted
+func EnsureRestTransportServerCert(c client.Client, namespace string) error {
+	serviceHost := RestTransportServiceHost(namespace)
+	var hosts []string
+	routeHost, err := getRestTransportRouteHost(c, namespace)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if routeHost != "" {
+		hosts = append(hosts, routeHost)
+	}
+	_, _, err = ensureIssuedCert(c, namespace, RestTransportServerSecretName, RestTransportCASecretName,
+		serviceHost, true, hosts)
+	return err
+}
+
+// HubRestClientSecretName returns the secret which keeps the rest transport client certificate of the hub in the
+// global hub namespace
+func HubRestClientSecretName(hubName string) string {
+	return hubName + "-transport-rest-client"
+}
+
+// GetHubRestTransportCredential returns the rest transport credential of the hub. The common name of the client
+// certificate is the hub name, which is verified against the hub in the path of the request by the server. The host
+// is the route of the server if it's empty.
+func GetHubRestTransportCredential(c client.Client, namespace, hubName, host string) (*transport.RestfulConfig, error) {
+	if host == "" {
+		routeHost, err := getRestTransportRouteHost(c, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the rest transport route: %w", err)
+		}
+		if routeHost == "" {
+			return nil, fmt.Errorf("the host of the rest transport route isn't admitted")
+		}
+		host = fmt.Sprintf("https://%s:443", routeHost)
+	}
+
+	key, cert, err := ensureIssuedCert(c, namespace, HubRestClientSecretName(hubName), RestTransportCASecretName,
+		hubName, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure the rest transport client certificate: %w", err)
+	}
+	caCert, err := GetCACert(c, namespace, RestTransportCASecretName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the rest transport ca: %w", err)
+	}
+	return &transport.RestfulConfig{
+		Host:        host,
+		Hub:         hubName,
+		CACert:      base64.StdEncoding.EncodeToString(caCert),
+		ClientCert:  base64.StdEncoding.EncodeToString(cert),
+		ClientKey:   base64.StdEncoding.EncodeToString(key),
+		SpecTopic:   config.GetSpecTopic(),
+		StatusTopic: config.GetStatusTopic(hubName),
+	}, nil
+}
+
+// GetManagerRestTransportCredential returns the rest transport credential of the manager, which only specifies the
+// topics, since the manager serves the rest transport with the local broker
+func GetManagerRestTransportCredential() *transport.RestfulConfig {
+	return &transport.RestfulConfig{
+		SpecTopic:   config.GetSpecTopic(),
+		StatusTopic: config.ManagerStatusTopic(),
+	}
+}
+
+func getRestTransportRouteHost(c client.Client, namespace string) (string, error) {
+	route := &routev1.Route{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: RestTransportRouteName}, route)
+	if err != nil {
+		return "", err
+	}
+	return route.Spec.Host, nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"slices"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+)
+
+func TestRestTransportCerts(t *testing.T) {
+	mgh := getMGH()
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = routev1.AddToScheme(s)
+	_ = v1alpha4.SchemeBuilder.AddToScheme(s)
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+
+	if err := CreateRestTransportCA(c, s, mgh); err != nil {
+		t.Fatalf("failed to create the rest transport CA: %v", err)
+	}
+	if err := EnsureRestTransportServerCert(c, namespace); err != nil {
+		t.Fatalf("failed to issue the rest transport server certificate: %v", err)
+	}
+	if _, err := GetHubRestTransportCredential(c, namespace, "hub1", ""); err == nil {
+		t.Fatal("the credential shouldn't be generated before the route is created")
+	}
+
+	// the server certificate is reissued for the route host once it's admitted
+	routeHost := "transport.apps.example.com"
+	if err := c.Create(t.Context(), &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: RestTransportRouteName, Namespace: namespace},
+		Spec:       routev1.RouteSpec{Host: routeHost},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureRestTransportServerCert(c, namespace); err != nil {
+		t.Fatalf("failed to reissue the rest transport server certificate: %v", err)
+	}
+	_, serverCert, err := GetKeyAndCert(c, namespace, RestTransportServerSecretName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dnsNames := parseCert(t, serverCert).DNSNames
+	if !slices.Contains(dnsNames, routeHost) || !slices.Contains(dnsNames, RestTransportServiceHost(namespace)) {
+		t.Fatalf("the server certificate should be issued for the route and service: %v", dnsNames)
+	}
+
+	// the client certificate of the hub is verified by the ca.crt of the server secret
+	conn, err := GetHubRestTransportCredential(c, namespace, "hub1", "")
+	if err != nil {
+		t.Fatalf("failed to get the rest transport credential: %v", err)
+	}
+	if conn.Host != "https://"+routeHost+":443" || conn.Hub != "hub1" {
+		t.Fatalf("unexpected host %s or hub %s", conn.Host, conn.Hub)
+	}
+	clientCert, err := base64.StdEncoding.DecodeString(conn.ClientCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := GetCACert(c, namespace, RestTransportServerSecretName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(caCert)
+	cert := parseCert(t, clientCert)
+	if cert.Subject.CommonName != "hub1" {
+		t.Fatalf("the common name of the client certificate should be the hub: %s", cert.Subject.CommonName)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatalf("failed to verify the client certificate: %v", err)
+	}
+}
+
+func parseCert(t *testing.T, data []byte) *x509.Certificate {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatal("failed to decode the certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
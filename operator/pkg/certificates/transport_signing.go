@@ -0,0 +1,129 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+const (
+	// TransportSigningCASecretName is the CA to issue the certificates for the hubs to sign the status events, the
+	// manager verifies the events with the ca.crt of the secret
+	TransportSigningCASecretName    = "multicluster-global-hub-transport-signing-ca"
+	transportSigningCACertificateCN = "transport-signing-ca-certificate"
+
+	// renew the certificates issued for the hubs before they're expired, so the agent can reload them in time
+	issuedCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// CreateTransportSigningCA creates the CA secret to issue the hub signing certificates if it doesn't exist
+func CreateTransportSigningCA(c client.Client, scheme *runtime.Scheme, mgh *v1alpha4.MulticlusterGlobalHub) error {
+	err, _ := createCASecret(c, scheme, mgh, false, TransportSigningCASecretName, mgh.Namespace,
+		transportSigningCACertificateCN)
+	return err
+}
+
+// HubSigningSecretName returns the secret which keeps the signing certificate of the hub in the global hub namespace
+func HubSigningSecretName(hubName string) string {
+	return hubName + "-transport-signing"
+}
+
+// EnsureHubSigningCert returns the PEM encoded key and certificate for the hub to sign the status events. The common
+// name of the certificate is the hub name, which is verified against the source of the event by the manager. The
+// certificate is kept in the secret, and it's renewed with the same key once it's about to expire.
+func EnsureHubSigningCert(c client.Client, namespace, hubName string) ([]byte, []byte, error) {
+	return ensureIssuedCert(c, namespace, HubSigningSecretName(hubName), TransportSigningCASecretName, hubName,
+		false, nil)
+}
+
+// ensureIssuedCert returns the PEM encoded key and certificate issued by the CA secret, the certificate is kept in the
+// secret with the ca.crt, and it's renewed with the same key once it's about to expire or the dns names are changed
+func ensureIssuedCert(c client.Client, namespace, name, caSecretName, cn string, isServer bool, dns []string,
+) ([]byte, []byte, error) {
+	crtSecret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, crtSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, nil, err
+	}
+	found := err == nil
+	// the common name is also in the dns names, so the certificate is renewed once the common name is changed
+	if found && !needsRenewIssuedCert(crtSecret, append([]string{cn}, dns...)) {
+		return crtSecret.Data[tlsKeyName], crtSecret.Data[tlsCertName], nil
+	}
+
+	caCert, caKey, caCertBytes, err := getCAByName(c, namespace, caSecretName)
+	if err != nil {
+		return nil, nil, err
+	}
+	var key []byte
+	if found {
+		if block, _ := pem.Decode(crtSecret.Data[tlsKeyName]); block != nil {
+			key = block.Bytes
+		}
+	}
+	crtKey, _ := x509.ParsePKCS1PrivateKey(key)
+	keyBytes, certBytes, err := createCertificate(isServer, cn, nil, dns, nil, caCert, caKey, crtKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM, keyPEM := pemEncode(certBytes, keyBytes)
+
+	crtSecret.Data = map[string][]byte{
+		caCertName:  caCertBytes,
+		tlsCertName: certPEM.Bytes(),
+		tlsKeyName:  keyPEM.Bytes(),
+	}
+	if found {
+		if err := c.Update(context.TODO(), crtSecret); err != nil {
+			log.Error(err, "Failed to update secret", "name", name)
+			return nil, nil, err
+		}
+		log.Info("Issued certificate renewed", "name", name)
+	} else {
+		crtSecret.ObjectMeta = metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				constants.BackupKey: constants.BackupGlobalHubValue,
+			},
+		}
+		if err := c.Create(context.TODO(), crtSecret); err != nil {
+			log.Error(err, "Failed to create secret", "name", name)
+			return nil, nil, err
+		}
+	}
+	return keyPEM.Bytes(), certPEM.Bytes(), nil
+}
+
+func needsRenewIssuedCert(s *corev1.Secret, dns []string) bool {
+	block, _ := pem.Decode(s.Data[tlsCertName])
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	for _, name := range dns {
+		if !slices.Contains(cert.DNSNames, name) {
+			return true
+		}
+	}
+	return time.Until(cert.NotAfter) < issuedCertRenewBefore
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
+)
+
+func TestHubSigningCert(t *testing.T) {
+	mgh := getMGH()
+	s := scheme.Scheme
+	_ = v1alpha4.SchemeBuilder.AddToScheme(s)
+	c := fake.NewClientBuilder().Build()
+
+	if err := CreateTransportSigningCA(c, s, mgh); err != nil {
+		t.Fatalf("failed to create the transport signing CA: %v", err)
+	}
+	key, cert, err := EnsureHubSigningCert(c, namespace, "hub1")
+	if err != nil {
+		t.Fatalf("failed to issue the hub signing certificate: %v", err)
+	}
+	renewedKey, renewedCert, err := EnsureHubSigningCert(c, namespace, "hub1")
+	if err != nil {
+		t.Fatalf("failed to get the hub signing certificate: %v", err)
+	}
+	if string(key) != string(renewedKey) || string(cert) != string(renewedCert) {
+		t.Fatal("the hub signing certificate shouldn't be reissued before it's about to expire")
+	}
+	_, caCert, err := GetKeyAndCert(c, namespace, TransportSigningCASecretName)
+	if err != nil {
+		t.Fatalf("failed to get the transport signing CA: %v", err)
+	}
+
+	dir := t.TempDir()
+	keyFile, certFile, caFile := filepath.Join(dir, "tls.key"), filepath.Join(dir, "tls.crt"), filepath.Join(dir, "ca.crt")
+	for file, data := range map[string][]byte{keyFile: key, certFile: cert, caFile: caCert} {
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	signer, err := signature.NewSignerFromFiles(keyFile, certFile)
+	if err != nil {
+		t.Fatalf("failed to create the signer: %v", err)
+	}
+	verifier, err := signature.NewVerifierFromFile(caFile, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create the verifier: %v", err)
+	}
+
+	newEvent := func(source string) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster")
+		evt.SetSource(source)
+		evt.SetID("event-1")
+		evt.SetTime(time.Now())
+		evt.SetExtension(version.ExtVersion, "1.1")
+		_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"name": "cluster1"})
+		return &evt
+	}
+
+	evt := newEvent("hub1")
+	if err := signer.Sign(evt); err != nil {
+		t.Fatalf("failed to sign the event: %v", err)
+	}
+	if err := verifier.Verify(evt); err != nil {
+		t.Fatalf("failed to verify the event: %v", err)
+	}
+
+	tampered := evt.Clone()
+	tampered.SetExtension(version.ExtVersion, "1.2")
+	if err := verifier.Verify(&tampered); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Fatalf("expect the invalid signature error, but got: %v", err)
+	}
+
+	// the id and time are signed, so the captured event can't be resent as a new one
+	resent := evt.Clone()
+	resent.SetID("event-2")
+	if err := verifier.Verify(&resent); !errors.Is(err, signature.ErrInvalidSignature) {
+		t.Fatalf("expect the invalid signature error, but got: %v", err)
+	}
+
+	stale := newEvent("hub1")
+	stale.SetTime(time.Now().Add(-2 * time.Hour))
+	if err := signer.Sign(stale); err != nil {
+		t.Fatalf("failed to sign the event: %v", err)
+	}
+	if err := verifier.Verify(stale); !errors.Is(err, signature.ErrStaleEvent) {
+		t.Fatalf("expect the stale event error, but got: %v", err)
+	}
+	// the replayed events are verified without the age
+	if err := verifier.VerifySignature(stale); err != nil {
+		t.Fatalf("failed to verify the signature of the stale event: %v", err)
+	}
+
+	forged := newEvent("hub2")
+	if err := signer.Sign(forged); err != nil {
+		t.Fatalf("failed to sign the event: %v", err)
+	}
+	if err := verifier.Verify(forged); !errors.Is(err, signature.ErrSourceMismatch) {
+		t.Fatalf("expect the source mismatch error, but got: %v", err)
+	}
+
+	if err := verifier.Verify(newEvent("hub1")); !errors.Is(err, signature.ErrUnsigned) {
+		t.Fatalf("expect the unsigned error, but got: %v", err)
+	}
+}
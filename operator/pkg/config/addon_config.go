@@ -114,8 +114,14 @@ type ManifestsConfig struct {
 	KafkaClusterCASecret    string
 	KafkaClusterCACert      string
 	InventoryConfigYaml     string
+	TransportType           string
+	RestConfigYaml          string
 	InventoryServerCASecret string
 	InventoryServerCACert   string
+	TransportSigningKey     string
+	TransportSigningCert    string
+	EnableTransportOutbox   bool
+	KafkaClient             string
 	InstallACMHub           bool
 	Channel                 string
 	CurrentCSV              string
@@ -206,6 +206,24 @@ func WithInventory(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	return ok
 }
 
+// WithSignedEvents returns true if the status events are signed by the agents and verified by the manager.
+func WithSignedEvents(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	_, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHWithSignedEvents]
+	return ok
+}
+
+// WithRestTransport returns true if the events are exchanged over the rest transport server of the manager.
+func WithRestTransport(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	_, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHWithRestTransport]
+	return ok
+}
+
+// WithAgentOutbox returns true if the agents persist the status events into the outbox before delivering them.
+func WithAgentOutbox(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	_, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHWithAgentOutbox]
+	return ok
+}
+
 // WithStackroxIntegration returns true if the integration with Stackrox is enabled.
 func WithStackroxIntegration(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	_, ok := mgh.GetAnnotations()[operatorconstants.AnnotationMGHWithStackroxIntegration]
@@ -240,6 +258,11 @@ func GetEventSendMode(obj client.Object) string {
 	return eventSendMode
 }
 
+// GetKafkaClient returns the client library of the kafka transport from the annotations
+func GetKafkaClient(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	return getAnnotation(mgh, operatorconstants.AnnotationMGHKafkaClient)
+}
+
 // GetSchedulerInterval returns the scheduler interval for moving policy compliance history
 func GetSchedulerInterval(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationMGHSchedulerInterval)
@@ -68,6 +68,18 @@ const (
 	// development environments, where is is convenient to reduce the poll interval. The value should be a string
 	// that can be parsed with the time.ParseDuration function.
 	AnnotationMGHWithStackroxPollInterval = "global-hub.open-cluster-management.io/with-stackrox-poll-interval"
+	// AnnotationMGHWithSignedEvents indicates the agents sign the status events with the certificates issued per hub,
+	// and the manager rejects the events which aren't signed by the certificate of the source hub.
+	AnnotationMGHWithSignedEvents = "global-hub.open-cluster-management.io/with-signed-events"
+	// AnnotationMGHWithRestTransport indicates the manager and agents exchange the events over the rest transport
+	// server of the manager with the mTLS certificates issued per hub, instead of the kafka cluster.
+	AnnotationMGHWithRestTransport = "global-hub.open-cluster-management.io/with-rest-transport"
+	// AnnotationMGHWithAgentOutbox indicates the agents persist the status events into the local outbox until they're
+	// delivered, so the events aren't lost when the transport is unavailable for a long time.
+	AnnotationMGHWithAgentOutbox = "global-hub.open-cluster-management.io/with-agent-outbox"
+	// AnnotationMGHKafkaClient specifies the client library of the kafka transport for the manager and agents
+	// (confluent or sarama), the confluent client is used if it isn't specified.
+	AnnotationMGHKafkaClient = "global-hub.open-cluster-management.io/kafka-client"
 	// AnnotationMGHEventSendMode specifies the event send mode for policy events (batch or single)
 	AnnotationMGHEventSendMode = "global-hub.open-cluster-management.io/event-send-mode"
 	// AnnotationMGHTransportUpdate is used to trigger MetaController reconciliation when transport connection changes.
@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
@@ -147,6 +148,23 @@ func (a *GlobalHubAddonAgent) GetValues(cluster *clusterv1.ManagedCluster,
 		return nil, err
 	}
 
+	if config.WithRestTransport(mgh) {
+		if err := setRestTransportConfigs(&manifestsConfig, mgh.Namespace, cluster, a.client); err != nil {
+			log.Errorw("failed to set rest transport config", "error", err)
+			return nil, err
+		}
+	}
+
+	if config.WithSignedEvents(mgh) {
+		key, cert, err := certificates.EnsureHubSigningCert(a.client, mgh.Namespace, cluster.Name)
+		if err != nil {
+			log.Errorw("failed to ensure the hub signing certificate", "error", err)
+			return nil, err
+		}
+		manifestsConfig.TransportSigningKey = base64.StdEncoding.EncodeToString(key)
+		manifestsConfig.TransportSigningCert = base64.StdEncoding.EncodeToString(cert)
+	}
+
 	if err := a.setImagePullSecret(mgh, cluster, &manifestsConfig); err != nil {
 		log.Errorw("failed to set image pull secret", "error", err)
 		return nil, err
@@ -157,6 +175,8 @@ func (a *GlobalHubAddonAgent) GetValues(cluster *clusterv1.ManagedCluster,
 	manifestsConfig.AggregationLevel = config.AggregationLevel
 	manifestsConfig.EnableLocalPolicies = config.EnableLocalPolicies
 	manifestsConfig.EventSendMode = config.GetEventSendMode(mgh)
+	manifestsConfig.EnableTransportOutbox = config.WithAgentOutbox(mgh)
+	manifestsConfig.KafkaClient = config.GetKafkaClient(mgh)
 	manifestsConfig.Tolerations = mgh.Spec.Tolerations
 	manifestsConfig.NodeSelector = mgh.Spec.NodeSelector
 
@@ -50,6 +50,24 @@ func setTransportConfigs(manifestsConfig *config.ManifestsConfig,
 	return nil
 }
 
+// setRestTransportConfigs renders the rest transport credential of the cluster, the agent posts the status events to
+// and long polls the spec events from the rest transport server of the manager with the client certificate
+func setRestTransportConfigs(manifestsConfig *config.ManifestsConfig, namespace string,
+	cluster *clusterv1.ManagedCluster, c client.Client,
+) error {
+	restConn, err := certificates.GetHubRestTransportCredential(c, namespace, cluster.Name, "")
+	if err != nil {
+		return err
+	}
+	restConfigYaml, err := restConn.YamlMarshal(true)
+	if err != nil {
+		return fmt.Errorf("failed to marshalling the rest transport config yaml: %w", err)
+	}
+	manifestsConfig.TransportType = string(transport.Rest)
+	manifestsConfig.RestConfigYaml = base64.StdEncoding.EncodeToString(restConfigYaml)
+	return nil
+}
+
 func getInventoryCredential(c client.Client) (*transport.RestfulConfig, error) {
 	inventoryCredential := &transport.RestfulConfig{}
 
@@ -21,6 +21,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/agent/addon"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
@@ -127,6 +128,9 @@ func (s *LocalAgentController) IsResourceRemoved() bool {
 // +kubebuilder:rbac:groups=agent.open-cluster-management.io,resources=klusterletaddonconfigs,verbs=get;create;watch;list;delete;patch;update
 // +kubebuilder:rbac:groups=register.open-cluster-management.io,resources=managedclusters/accept,verbs=update
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;delete;deletecollection
+// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=managedclusteraddons,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=applications;applicationsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments;clusterpools,verbs=get;list;watch
 
 func (s *LocalAgentController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log.Debugf("reconcile local agent controller: %v", req)
@@ -174,7 +178,7 @@ func (s *LocalAgentController) Reconcile(ctx context.Context, req ctrl.Request)
 		clusterName = localClusterName
 	}
 	log.Debugf("generate local agent credential")
-	err = GenerateLocalAgentCredential(ctx, s.GetClient(), mgh.Namespace)
+	err = GenerateLocalAgentCredential(ctx, s.GetClient(), mgh)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -264,7 +268,8 @@ func (s *LocalAgentController) pruneAgentResources(ctx context.Context, namespac
 	return nil
 }
 
-func GenerateLocalAgentCredential(ctx context.Context, c client.Client, namespace string) error {
+func GenerateLocalAgentCredential(ctx context.Context, c client.Client, mgh *v1alpha4.MulticlusterGlobalHub) error {
+	namespace := mgh.Namespace
 	log.Debugf("generate local agent credential in namespace: %v", namespace)
 	err := addon.EnsureTransportResource(clusterName)
 	if err != nil {
@@ -295,6 +300,21 @@ func GenerateLocalAgentCredential(ctx context.Context, c client.Client, namespac
 		},
 	}
 
+	// the local agent accesses the rest transport server by the service of the manager
+	if config.WithRestTransport(mgh) {
+		host := fmt.Sprintf("https://%s:%d", certificates.RestTransportServiceHost(namespace),
+			certificates.RestTransportPort)
+		restConn, err := certificates.GetHubRestTransportCredential(c, namespace, clusterName, host)
+		if err != nil {
+			return err
+		}
+		restConfigYaml, err := restConn.YamlMarshal(true)
+		if err != nil {
+			return fmt.Errorf("failed to marshalling the rest transport config yaml: %w", err)
+		}
+		expectedSecret.Data["rest.yaml"] = restConfigYaml
+	}
+
 	existingSecret := &corev1.Secret{}
 	err = c.Get(ctx, client.ObjectKeyFromObject(expectedSecret), existingSecret)
 	if err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/shared"
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
@@ -36,6 +37,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	commonutils "github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -179,6 +181,8 @@ func renderAgentManifests(
 	var enableStackroxIntegration bool
 	var stackroxPollInterval time.Duration
 	var eventSendMode string
+	var transportSigningSecretName string
+	var transportType string
 
 	if mgh != nil {
 		namespace = mgh.Namespace
@@ -195,6 +199,16 @@ func renderAgentManifests(
 		enableStackroxIntegration = config.WithStackroxIntegration(mgh)
 		stackroxPollInterval = config.GetStackroxPollInterval(mgh)
 		eventSendMode = config.GetEventSendMode(mgh)
+		// the local agent runs in the global hub namespace, so it mounts the signing secret of the hub directly
+		if config.WithSignedEvents(mgh) {
+			if _, _, err := certificates.EnsureHubSigningCert(mgr.GetClient(), namespace, clusterName); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to ensure the hub signing certificate: %v", err)
+			}
+			transportSigningSecretName = certificates.HubSigningSecretName(clusterName)
+		}
+		if config.WithRestTransport(mgh) {
+			transportType = string(transport.Rest)
+		}
 	}
 	if mgha != nil {
 		namespace = mgha.Namespace
@@ -247,45 +261,49 @@ func renderAgentManifests(
 	// create the agent objects
 	agentObjects, err := hohRenderer.Render("manifests", "", func(profile string) (interface{}, error) {
 		return struct {
-			Image                     string
-			ImagePullSecret           string
-			ImagePullPolicy           string
-			Namespace                 string
-			NodeSelector              map[string]string
-			Tolerations               []corev1.Toleration
-			LeaseDuration             string
-			RenewDeadline             string
-			RetryPeriod               string
-			AgentQPS                  float32
-			AgentBurst                int
-			LogLevel                  string
-			ClusterId                 string
-			Resources                 *corev1.ResourceRequirements
-			TransportConfigSecretName string
-			EnableStackroxIntegration bool
-			StackroxPollInterval      time.Duration
-			DeployMode                string
-			EventSendMode             string
+			Image                      string
+			ImagePullSecret            string
+			ImagePullPolicy            string
+			Namespace                  string
+			NodeSelector               map[string]string
+			Tolerations                []corev1.Toleration
+			LeaseDuration              string
+			RenewDeadline              string
+			RetryPeriod                string
+			AgentQPS                   float32
+			AgentBurst                 int
+			LogLevel                   string
+			ClusterId                  string
+			Resources                  *corev1.ResourceRequirements
+			TransportConfigSecretName  string
+			EnableStackroxIntegration  bool
+			StackroxPollInterval       time.Duration
+			DeployMode                 string
+			EventSendMode              string
+			TransportSigningSecretName string
+			TransportType              string
 		}{
-			Image:                     config.GetImage(config.GlobalHubAgentImageKey),
-			ImagePullSecret:           imagePullSecret,
-			ImagePullPolicy:           string(imagePullPolicy),
-			Namespace:                 namespace,
-			NodeSelector:              nodeSelector,
-			Tolerations:               tolerations,
-			LeaseDuration:             strconv.Itoa(electionConfig.LeaseDuration),
-			RenewDeadline:             strconv.Itoa(electionConfig.RenewDeadline),
-			RetryPeriod:               strconv.Itoa(electionConfig.RetryPeriod),
-			AgentQPS:                  agentQPS,
-			AgentBurst:                agentBurst,
-			LogLevel:                  logLevel,
-			ClusterId:                 clusterName,
-			Resources:                 &resourceReq,
-			TransportConfigSecretName: transportConfigSecretName,
-			EnableStackroxIntegration: enableStackroxIntegration,
-			StackroxPollInterval:      stackroxPollInterval,
-			DeployMode:                deployMode,
-			EventSendMode:             eventSendMode,
+			Image:                      config.GetImage(config.GlobalHubAgentImageKey),
+			ImagePullSecret:            imagePullSecret,
+			ImagePullPolicy:            string(imagePullPolicy),
+			Namespace:                  namespace,
+			NodeSelector:               nodeSelector,
+			Tolerations:                tolerations,
+			LeaseDuration:              strconv.Itoa(electionConfig.LeaseDuration),
+			RenewDeadline:              strconv.Itoa(electionConfig.RenewDeadline),
+			RetryPeriod:                strconv.Itoa(electionConfig.RetryPeriod),
+			AgentQPS:                   agentQPS,
+			AgentBurst:                 agentBurst,
+			LogLevel:                   logLevel,
+			ClusterId:                  clusterName,
+			Resources:                  &resourceReq,
+			TransportConfigSecretName:  transportConfigSecretName,
+			EnableStackroxIntegration:  enableStackroxIntegration,
+			StackroxPollInterval:       stackroxPollInterval,
+			DeployMode:                 deployMode,
+			EventSendMode:              eventSendMode,
+			TransportSigningSecretName: transportSigningSecretName,
+			TransportType:              transportType,
 		}, nil
 	})
 	if err != nil {
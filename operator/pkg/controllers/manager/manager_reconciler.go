@@ -301,6 +301,28 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 		}
 	}
 
+	// the manager serves the rest transport with the server certificate, and verifies the hubs by the client CA
+	var restConfigYaml []byte
+	if config.WithRestTransport(mgh) {
+		if reconcileErr = certificates.CreateRestTransportCA(r.GetClient(), r.GetScheme(), mgh); reconcileErr != nil {
+			return ctrl.Result{}, reconcileErr
+		}
+		if reconcileErr = certificates.EnsureRestTransportServerCert(r.GetClient(), mgh.Namespace); reconcileErr != nil {
+			return ctrl.Result{}, reconcileErr
+		}
+		restConfigYaml, err = certificates.GetManagerRestTransportCredential().YamlMarshal(true)
+		if err != nil {
+			reconcileErr = fmt.Errorf("failed to marshalling the rest transport config yaml: %w", err)
+			return ctrl.Result{}, reconcileErr
+		}
+	}
+
+	if config.WithSignedEvents(mgh) {
+		if reconcileErr = certificates.CreateTransportSigningCA(r.GetClient(), r.GetScheme(), mgh); reconcileErr != nil {
+			return ctrl.Result{}, reconcileErr
+		}
+	}
+
 	log.Infof("transport-config updating: manager controller reconcile the transportConfig secret")
 	managerObjects, err := hohRenderer.Render("manifests", "", func(profile string) (interface{}, error) {
 		return ManagerVariables{
@@ -313,11 +335,14 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			DatabaseURL: base64.StdEncoding.EncodeToString(
 				[]byte(storageConn.SuperuserDatabaseURI)),
 			PostgresCACert:            base64.StdEncoding.EncodeToString(storageConn.CACert),
-			TransportType:             string(transport.Kafka),
+			TransportType:             transportType(mgh),
 			TransportConfigSecret:     constants.GHTransportConfigSecret,
 			StorageConfigSecret:       constants.GHStorageConfigSecret,
 			KafkaConfigYaml:           base64.StdEncoding.EncodeToString(kafkaConfigYaml),
 			InventoryConfigYaml:       base64.StdEncoding.EncodeToString(inventoryConfigYaml),
+			RestConfigYaml:            base64.StdEncoding.EncodeToString(restConfigYaml),
+			RestTransportServerSecret: restTransportServerSecret(mgh),
+			RestTransportPort:         certificates.RestTransportPort,
 			Namespace:                 mgh.Namespace,
 			LeaseDuration:             strconv.Itoa(electionConfig.LeaseDuration),
 			RenewDeadline:             strconv.Itoa(electionConfig.RenewDeadline),
@@ -335,6 +360,8 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			Resources:                 utils.GetResources(operatorconstants.Manager, mgh.Spec.AdvancedSpec),
 			WithACM:                   config.IsACMResourceReady(),
 			TransportFailureThreshold: r.operatorConfig.TransportFailureThreshold,
+			TransportSigningCASecret:  transportSigningCASecret(mgh),
+			KafkaClient:               config.GetKafkaClient(mgh),
 		}, nil
 	})
 	if err != nil {
@@ -473,6 +500,9 @@ type ManagerVariables struct {
 	StorageConfigSecret       string
 	KafkaConfigYaml           string
 	InventoryConfigYaml       string
+	RestConfigYaml            string
+	RestTransportServerSecret string
+	RestTransportPort         int
 	TransportType             string
 	Namespace                 string
 	LeaseDuration             string
@@ -491,4 +521,31 @@ type ManagerVariables struct {
 	Resources                 *corev1.ResourceRequirements
 	WithACM                   bool
 	TransportFailureThreshold int
+	TransportSigningCASecret  string
+	KafkaClient               string
+}
+
+// transportType returns the transport to exchange the events with the agents
+func transportType(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	if config.WithRestTransport(mgh) {
+		return string(transport.Rest)
+	}
+	return string(transport.Kafka)
+}
+
+// restTransportServerSecret returns the certificate secret of the rest transport server, it's empty if the events
+// aren't exchanged over the rest transport
+func restTransportServerSecret(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	if !config.WithRestTransport(mgh) {
+		return ""
+	}
+	return certificates.RestTransportServerSecretName
+}
+
+// transportSigningCASecret returns the CA secret to verify the status events, it's empty if the events aren't signed
+func transportSigningCASecret(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	if !config.WithSignedEvents(mgh) {
+		return ""
+	}
+	return certificates.TransportSigningCASecretName
 }
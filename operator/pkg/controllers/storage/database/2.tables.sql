@@ -156,3 +156,256 @@ CREATE TABLE IF NOT EXISTS security.alert_counts (
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     PRIMARY KEY (hub_name, source)
 );
+
+CREATE TABLE IF NOT EXISTS security.cluster_alert_counts (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    -- the secured cluster name of the central, it's usually the managed cluster name
+    cluster_name text NOT NULL,
+    low integer NOT NULL,
+    medium integer NOT NULL,
+    high integer NOT NULL,
+    critical integer NOT NULL,
+    detail_url text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name)
+);
+CREATE INDEX IF NOT EXISTS cluster_alert_counts_cluster_name_idx ON security.cluster_alert_counts (cluster_name);
+
+CREATE TABLE IF NOT EXISTS security.policy_violations (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    cluster_name text NOT NULL,
+    cluster_id text NOT NULL,
+    policy_id text NOT NULL,
+    policy_name text NOT NULL,
+    -- low, medium, high or critical
+    severity text NOT NULL,
+    categories jsonb,
+    count integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name, policy_id)
+);
+CREATE INDEX IF NOT EXISTS policy_violations_policy_name_idx ON security.policy_violations (policy_name);
+
+CREATE TABLE IF NOT EXISTS security.image_vulnerabilities (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    cluster_name text NOT NULL,
+    cluster_id text NOT NULL,
+    low integer NOT NULL,
+    moderate integer NOT NULL,
+    important integer NOT NULL,
+    critical integer NOT NULL,
+    fixable integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (hub_name, source, cluster_name)
+);
+
+-- the dead letters are deleted by the data retention job once they're created before the retention period
+CREATE TABLE IF NOT EXISTS status.dead_letters (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    event_version character varying(64),
+    -- the reason why the event is dead lettered: handler_failed or unregistered
+    reason character varying(64) NOT NULL,
+    error text,
+    -- the cloudevent in json format, which is used to replay the event
+    payload jsonb NOT NULL,
+    replayed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS dead_letters_leaf_hub_idx ON status.dead_letters (leaf_hub_name, event_type);
+CREATE INDEX IF NOT EXISTS dead_letters_created_at_idx ON status.dead_letters (created_at);
+
+CREATE TABLE IF NOT EXISTS status.transport_audit_logs (
+    id bigserial PRIMARY KEY,
+    -- the leaf hub name claimed by the source of the event, it might be forged
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    event_id character varying(254),
+    -- the reason why the event is rejected: unsigned, invalid_certificate, source_mismatch or invalid_signature
+    reason character varying(64) NOT NULL,
+    message text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+CREATE INDEX IF NOT EXISTS transport_audit_logs_leaf_hub_idx ON status.transport_audit_logs (leaf_hub_name);
+CREATE INDEX IF NOT EXISTS transport_audit_logs_created_at_idx ON status.transport_audit_logs (created_at);
+
+CREATE TABLE IF NOT EXISTS status.generic_resources (
+    leaf_hub_name character varying(254) NOT NULL,
+    -- the apiVersion and kind of the resource, e.g. cert-manager.io/v1/Certificate
+    gvk character varying(254) NOT NULL,
+    uid character varying(254) NOT NULL,
+    namespace character varying(254),
+    name character varying(254) NOT NULL,
+    -- the resource with the fields projected by the agent configmap, or the whole status
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, gvk, uid)
+);
+CREATE INDEX IF NOT EXISTS generic_resources_name_idx ON status.generic_resources (leaf_hub_name, gvk, namespace, name);
+
+CREATE TABLE IF NOT EXISTS status.managed_cluster_addons (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    addon_name character varying(254) NOT NULL,
+    uid character varying(254),
+    -- the status of the conditions: True, False or Unknown
+    available character varying(63),
+    degraded character varying(63),
+    progressing character varying(63),
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, addon_name)
+);
+CREATE INDEX IF NOT EXISTS managed_cluster_addons_addon_idx ON status.managed_cluster_addons (addon_name);
+
+CREATE TABLE IF NOT EXISTS status.argocd_applications (
+    leaf_hub_name character varying(254) NOT NULL,
+    -- Application or ApplicationSet
+    kind character varying(63) NOT NULL,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    uid character varying(254),
+    project character varying(254),
+    -- the first source if the application has multiple sources
+    repo_url text,
+    target_revision character varying(254),
+    destination_server text,
+    destination_name character varying(254),
+    destination_namespace character varying(254),
+    -- Synced, OutOfSync or Unknown
+    sync_status character varying(63),
+    -- Healthy, Progressing, Degraded, Suspended, Missing or Unknown
+    health_status character varying(63),
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, kind, namespace, name)
+);
+CREATE INDEX IF NOT EXISTS argocd_applications_status_idx ON status.argocd_applications (sync_status, health_status);
+
+CREATE TABLE IF NOT EXISTS status.manifest_works (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    work_name character varying(254) NOT NULL,
+    uid character varying(254),
+    -- the placement and manifestworkreplicaset which the work is created by, empty if the work is created directly
+    placement_namespace character varying(254),
+    placement_name character varying(254),
+    replica_set_name character varying(254),
+    -- the status of the conditions: True, False or Unknown, empty if the condition isn't reported
+    applied character varying(63),
+    available character varying(63),
+    degraded character varying(63),
+    progressing character varying(63),
+    -- the conditions and the status feedback of each manifest
+    manifests jsonb,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name, work_name)
+);
+CREATE INDEX IF NOT EXISTS manifest_works_placement_idx ON status.manifest_works (placement_namespace, placement_name);
+
+CREATE TABLE IF NOT EXISTS status.cluster_deployments (
+    leaf_hub_name character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    uid character varying(254),
+    cluster_name character varying(254),
+    platform character varying(63),
+    region character varying(63),
+    base_domain character varying(254),
+    pool_namespace character varying(254),
+    pool_name character varying(254),
+    image_set character varying(254),
+    install_version character varying(63),
+    -- Pending, Provisioning, ProvisionFailed, ProvisionStopped, Provisioned or Deprovisioning
+    phase character varying(63),
+    power_state character varying(63),
+    install_restarts integer DEFAULT 0,
+    -- the clusterprovision which keeps the install log
+    provision_name character varying(254),
+    failure_reason character varying(254),
+    failure_message text,
+    cluster_created_at timestamp without time zone,
+    installed_at timestamp without time zone,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, namespace, name)
+);
+CREATE INDEX IF NOT EXISTS cluster_deployments_phase_idx ON status.cluster_deployments (phase);
+
+CREATE TABLE IF NOT EXISTS status.cluster_pools (
+    leaf_hub_name character varying(254) NOT NULL,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    uid character varying(254),
+    platform character varying(63),
+    region character varying(63),
+    image_set character varying(254),
+    size integer DEFAULT 0,
+    max_size integer,
+    ready integer DEFAULT 0,
+    standby integer DEFAULT 0,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, namespace, name)
+);
+
+CREATE TABLE IF NOT EXISTS status.cluster_inventory (
+    leaf_hub_name character varying(254) NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    cluster_id character varying(254),
+    kube_vendor character varying(63),
+    cloud character varying(63),
+    region character varying(63),
+    kube_version character varying(63),
+    openshift_version character varying(63),
+    channel character varying(63),
+    desired_version character varying(63),
+    upgrade_failed boolean DEFAULT false,
+    -- the array of the versions which the cluster can be upgraded to
+    available_updates jsonb,
+    node_count integer DEFAULT 0,
+    ready_node_count integer DEFAULT 0,
+    -- the cpu is in millicores and the memory is in bytes
+    cpu_capacity bigint DEFAULT 0,
+    memory_capacity bigint DEFAULT 0,
+    cpu_allocatable bigint DEFAULT 0,
+    memory_allocatable bigint DEFAULT 0,
+    payload jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, cluster_name)
+);
+CREATE INDEX IF NOT EXISTS cluster_inventory_version_idx ON status.cluster_inventory (openshift_version);
+
+CREATE TABLE IF NOT EXISTS status.certificate_expiries (
+    leaf_hub_name character varying(254) NOT NULL,
+    -- KlusterletBootstrap, HubKubeconfig, APIServer or Transport
+    source character varying(63) NOT NULL,
+    namespace character varying(254) NOT NULL,
+    name character varying(254) NOT NULL,
+    key character varying(254) NOT NULL,
+    -- Certificate or Token
+    kind character varying(63),
+    subject text,
+    issuer text,
+    not_before timestamp without time zone,
+    not_after timestamp without time zone NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (leaf_hub_name, source, namespace, name, key)
+);
+CREATE INDEX IF NOT EXISTS certificate_expiries_not_after_idx ON status.certificate_expiries (not_after);
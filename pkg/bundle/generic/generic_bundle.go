@@ -16,6 +16,31 @@ type ObjectMetadata struct {
 	ID        string `json:"id,omitempty"`
 	Namespace string `json:"ns,omitempty"`
 	Name      string `json:"name,omitempty"`
+	// GVK identifies the kind of the object in the bundle of multiple kinds, e.g. "cert-manager.io/v1/Certificate"
+	GVK string `json:"gvk,omitempty"`
+}
+
+// ObjectPatch is the JSON merge patch (RFC 7386) of the object against the one sent in the previous bundles
+type ObjectPatch struct {
+	ObjectMetadata
+	// BaseResourceVersion is the resource version of the object which the patch is against, the patch is only applied
+	// to the stored object of the same version, otherwise the object is recovered by the resync
+	BaseResourceVersion string          `json:"baseResourceVersion,omitempty"`
+	Patch               json.RawMessage `json:"patch"`
+}
+
+// PayloadResourceVersion returns the resource version in the metadata of the JSON encoded object, it's empty if the
+// object has no resource version or can't be decoded
+func PayloadResourceVersion(payload []byte) string {
+	object := struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}{}
+	if err := json.Unmarshal(payload, &object); err != nil {
+		return ""
+	}
+	return object.Metadata.ResourceVersion
 }
 
 type GenericBundle[T any] struct {
@@ -24,6 +49,8 @@ type GenericBundle[T any] struct {
 	Delete         []ObjectMetadata `json:"delete,omitempty"`
 	Resync         []T              `json:"resync,omitempty"`
 	ResyncMetadata []ObjectMetadata `json:"resync_metadata,omitempty"`
+	// Patch is the changes of the objects sent in the merge patch mode of the emitter
+	Patch []ObjectPatch `json:"patch,omitempty"`
 }
 
 func NewGenericBundle[T any]() *GenericBundle[T] {
@@ -35,7 +62,8 @@ func (b *GenericBundle[T]) IsEmpty() bool {
 		len(b.Update) == 0 &&
 		len(b.Delete) == 0 &&
 		len(b.Resync) == 0 &&
-		len(b.ResyncMetadata) == 0
+		len(b.ResyncMetadata) == 0 &&
+		len(b.Patch) == 0
 }
 
 // Size returns the in-memory size in bytes of the JSON-encoded GenericBundle[T],
@@ -54,6 +82,7 @@ func (b *GenericBundle[T]) Clean() {
 	b.Delete = nil
 	b.Resync = nil
 	b.ResyncMetadata = nil
+	b.Patch = nil
 }
 
 func (b *GenericBundle[T]) AddCreate(obj T) (bool, error) {
@@ -68,6 +97,53 @@ func (b *GenericBundle[T]) AddResync(obj T) (bool, error) {
 	return b.tryAdd(&b.Resync, obj)
 }
 
+// AddPatch adds the patch of the object, it replaces the previous patch of the object in the bundle, since the patch
+// is against the object sent in the previous bundles.
+func (b *GenericBundle[T]) AddPatch(patch ObjectPatch) (bool, error) {
+	for i := range b.Patch {
+		if b.Patch[i].ObjectMetadata == patch.ObjectMetadata {
+			previous := b.Patch[i]
+			b.Patch[i] = patch
+			size, err := b.Size()
+			if err != nil || size > MaxBundleBytes {
+				b.Patch[i] = previous
+				return false, err
+			}
+			return true, nil
+		}
+	}
+
+	wasEmptyBeforeAdd := b.IsEmpty()
+
+	b.Patch = append(b.Patch, patch)
+
+	size, err := b.Size()
+	if err != nil {
+		b.Patch = b.Patch[:len(b.Patch)-1]
+		return false, err
+	}
+
+	if size > MaxBundleBytes {
+		if wasEmptyBeforeAdd {
+			return false, fmt.Errorf("patch too large: %s/%s (%d bytes)", patch.Namespace, patch.Name, size)
+		}
+		b.Patch = b.Patch[:len(b.Patch)-1]
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// RemovePatch removes the patch of the object from the bundle
+func (b *GenericBundle[T]) RemovePatch(meta ObjectMetadata) {
+	for i := range b.Patch {
+		if b.Patch[i].ObjectMetadata == meta {
+			b.Patch = append(b.Patch[:i], b.Patch[i+1:]...)
+			return
+		}
+	}
+}
+
 func (b *GenericBundle[T]) AddDelete(meta ObjectMetadata) (bool, error) {
 	wasEmptyBeforeAdd := b.IsEmpty()
 
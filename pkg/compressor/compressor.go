@@ -24,6 +24,10 @@ const (
 	NoOp CompressionType = "no-op"
 	// GZip is used to create a gzip-based Compressor.
 	GZip CompressionType = "gzip"
+	// Zstd is used to create a zstd-based Compressor.
+	Zstd CompressionType = "zstd"
+	// Snappy is used to create a snappy-based Compressor.
+	Snappy CompressionType = "snappy"
 )
 
 // NewCompressor returns a compressor instance that corresponds to the given CompressionType.
@@ -33,6 +37,10 @@ func NewCompressor(compressionType CompressionType) (Compressor, error) {
 		return newNoOpCompressor(), nil
 	case GZip:
 		return newGZipCompressor(), nil
+	case Zstd:
+		return newZstdCompressor(), nil
+	case Snappy:
+		return newSnappyCompressor(), nil
 	default:
 		return nil, errCompressionTypeNotFound
 	}
@@ -42,6 +42,29 @@ func TestTransportCompressor(t *testing.T) {
 	t.Log(prettyMessage(out))
 }
 
+func TestCompressorTypes(t *testing.T) {
+	payload := []byte(`{"update":[{"metadata":{"name":"cluster1","namespace":"cluster1"}}]}`)
+	for _, compressionType := range []compressor.CompressionType{
+		compressor.NoOp, compressor.GZip, compressor.Zstd, compressor.Snappy,
+	} {
+		t.Run(string(compressionType), func(t *testing.T) {
+			c, err := compressor.NewCompressor(compressionType)
+			assert.Nil(t, err)
+			assert.Equal(t, string(compressionType), c.GetType())
+
+			compressed, err := c.Compress(payload)
+			assert.Nil(t, err)
+
+			decompressed, err := c.Decompress(compressed)
+			assert.Nil(t, err)
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+
+	_, err := compressor.NewCompressor("lz4")
+	assert.NotNil(t, err)
+}
+
 func prettyMessage(i interface{}) string {
 	s, _ := json.MarshalIndent(i, "", "\t")
 	return string(s)
@@ -0,0 +1,55 @@
+package compressor
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	zstdCompressorErrorString = "zstd compressor error"
+	zstdCompressorErrorFormat = "%s - %w"
+	zstdType                  = "zstd"
+)
+
+// the encoder and decoder are safe for concurrent use with EncodeAll/DecodeAll, so they are shared by all the
+// zstd compressors instead of allocating the internal buffers and goroutines for each instance.
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// newZstdCompressor returns a new instance of zstd-based compressor.
+func newZstdCompressor() Compressor {
+	return &CompressorZstd{}
+}
+
+// CompressorZstd implements Compressor with zstd-based logic.
+type CompressorZstd struct{}
+
+// GetType returns the string identifier for zstd compressor.
+func (compressor *CompressorZstd) GetType() string {
+	return zstdType
+}
+
+// Compress compresses a slice of bytes using zstd lib.
+func (compressor *CompressorZstd) Compress(data []byte) ([]byte, error) {
+	if zstdEncoder == nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString,
+			fmt.Errorf("encoder is not initialized"))
+	}
+	return zstdEncoder.EncodeAll(data, make([]byte, 0, len(data))), nil
+}
+
+// Decompress decompresses a slice of zstd-compressed bytes using zstd lib.
+func (compressor *CompressorZstd) Decompress(compressedData []byte) ([]byte, error) {
+	if zstdDecoder == nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString,
+			fmt.Errorf("decoder is not initialized"))
+	}
+	data, err := zstdDecoder.DecodeAll(compressedData, nil)
+	if err != nil {
+		return nil, fmt.Errorf(zstdCompressorErrorFormat, zstdCompressorErrorString, err)
+	}
+	return data, nil
+}
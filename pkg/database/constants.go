@@ -50,6 +50,39 @@ const (
 
 	// SecurityAlertCountsTable is the name of the table for security alert counts.
 	SecurityAlertCountsTable = "alert_counts"
+
+	// SecurityClusterAlertCountsTable is the name of the table for security alert counts of the secured clusters.
+	SecurityClusterAlertCountsTable = "cluster_alert_counts"
+
+	// SecurityPolicyViolationsTable is the name of the table for the violated policies of the secured clusters.
+	SecurityPolicyViolationsTable = "policy_violations"
+
+	// SecurityImageVulnerabilitiesTable is the name of the table for image vulnerability counts of the secured clusters.
+	SecurityImageVulnerabilitiesTable = "image_vulnerabilities"
+
+	// GenericResourcesTableName table name of the custom resources configured in the agent configmap.
+	GenericResourcesTableName = "generic_resources"
+
+	// ManagedClusterAddOnsTableName table name of the addons of the managed clusters.
+	ManagedClusterAddOnsTableName = "managed_cluster_addons"
+
+	// ArgoCDApplicationsTableName table name of the argo cd applications and applicationsets.
+	ArgoCDApplicationsTableName = "argocd_applications"
+
+	// ManifestWorksTableName table name of the manifestworks delivered to the managed clusters.
+	ManifestWorksTableName = "manifest_works"
+
+	// ClusterDeploymentsTableName table name of the hive clusterdeployments.
+	ClusterDeploymentsTableName = "cluster_deployments"
+
+	// ClusterPoolsTableName table name of the hive clusterpools.
+	ClusterPoolsTableName = "cluster_pools"
+
+	// ClusterInventoryTableName table name of the capacity and the version of the managed clusters.
+	ClusterInventoryTableName = "cluster_inventory"
+
+	// CertificateExpiriesTableName table name of the certificates and tokens scanned on the hubs.
+	CertificateExpiriesTableName = "certificate_expiries"
 )
 
 // default values.
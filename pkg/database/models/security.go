@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // SecurityAlertCounts contains a summary of the security alerts from a hub.
 type SecurityAlertCounts struct {
@@ -39,3 +43,121 @@ type SecurityAlertCounts struct {
 func (SecurityAlertCounts) TableName() string {
 	return "security.alert_counts"
 }
+
+// SecurityClusterAlertCounts contains a summary of the security alerts of a cluster secured by a Central instance.
+type SecurityClusterAlertCounts struct {
+	// HubName is the name of the hub.
+	HubName string `gorm:"column:hub_name;primaryKey"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	Source string `gorm:"column:source;primaryKey"`
+
+	// ClusterName is the name of the secured cluster, which is usually the name of the managed cluster.
+	ClusterName string `gorm:"column:cluster_name;primaryKey"`
+
+	// Low is the total number of low severity alerts.
+	Low int `gorm:"column:low;not null"`
+
+	// Medium is the total number of medium severity alerts.
+	Medium int `gorm:"column:medium;not null"`
+
+	// High is the total number of high severity alerts.
+	High int `gorm:"column:high;not null"`
+
+	// Critical is the total number of critical severity alerts.
+	Critical int `gorm:"column:critical;not null"`
+
+	// DetailURL is the URL of the violations tab of the Stackrox Central UI.
+	DetailURL string `gorm:"column:detail_url;not null"`
+
+	// CreatedAt is the date and time when the row was created.
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:true"`
+
+	// UpdatedAt is the date and time when the row was last updated.
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityClusterAlertCounts) TableName() string {
+	return "security.cluster_alert_counts"
+}
+
+// SecurityPolicyViolation contains the number of the active alerts of a policy in a cluster secured by a Central
+// instance. Only the most violated policies of each cluster are kept.
+type SecurityPolicyViolation struct {
+	// HubName is the name of the hub.
+	HubName string `gorm:"column:hub_name;primaryKey"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	Source string `gorm:"column:source;primaryKey"`
+
+	// ClusterName is the name of the secured cluster.
+	ClusterName string `gorm:"column:cluster_name;primaryKey"`
+
+	// PolicyID is the identifier of the policy in the Central.
+	PolicyID string `gorm:"column:policy_id;primaryKey"`
+
+	// ClusterID is the identifier of the secured cluster in the Central.
+	ClusterID string `gorm:"column:cluster_id;not null"`
+
+	// PolicyName is the name of the policy.
+	PolicyName string `gorm:"column:policy_name;not null"`
+
+	// Severity is the severity of the policy: low, medium, high or critical.
+	Severity string `gorm:"column:severity;not null"`
+
+	// Categories are the categories of the policy.
+	Categories datatypes.JSON `gorm:"column:categories;type:jsonb"`
+
+	// Count is the number of the active alerts of the policy in the cluster.
+	Count int `gorm:"column:count;not null"`
+
+	// CreatedAt is the date and time when the row was created.
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:true"`
+
+	// UpdatedAt is the date and time when the row was last updated.
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityPolicyViolation) TableName() string {
+	return "security.policy_violations"
+}
+
+// SecurityImageVulnerabilities contains the number of the image CVEs of a cluster secured by a Central instance.
+type SecurityImageVulnerabilities struct {
+	// HubName is the name of the hub.
+	HubName string `gorm:"column:hub_name;primaryKey"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	Source string `gorm:"column:source;primaryKey"`
+
+	// ClusterName is the name of the secured cluster.
+	ClusterName string `gorm:"column:cluster_name;primaryKey"`
+
+	// ClusterID is the identifier of the secured cluster in the Central.
+	ClusterID string `gorm:"column:cluster_id;not null"`
+
+	// Low is the number of low severity CVEs.
+	Low int `gorm:"column:low;not null"`
+
+	// Moderate is the number of moderate severity CVEs.
+	Moderate int `gorm:"column:moderate;not null"`
+
+	// Important is the number of important severity CVEs.
+	Important int `gorm:"column:important;not null"`
+
+	// Critical is the number of critical severity CVEs.
+	Critical int `gorm:"column:critical;not null"`
+
+	// Fixable is the number of CVEs which can be fixed by upgrading the images.
+	Fixable int `gorm:"column:fixable;not null"`
+
+	// CreatedAt is the date and time when the row was created.
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:true"`
+
+	// UpdatedAt is the date and time when the row was last updated.
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (SecurityImageVulnerabilities) TableName() string {
+	return "security.image_vulnerabilities"
+}
@@ -98,3 +98,229 @@ type SubscriptionReport struct {
 func (SubscriptionReport) TableName() string {
 	return "status.subscription_reports"
 }
+
+// GenericResource is the custom resource synced by the kinds configured in the agent configmap, the payload is the
+// object with the projected fields or the whole status
+type GenericResource struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	GVK         string         `gorm:"column:gvk;primaryKey"`
+	UID         string         `gorm:"column:uid;primaryKey"`
+	Namespace   string         `gorm:"column:namespace"`
+	Name        string         `gorm:"column:name;not null"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (GenericResource) TableName() string {
+	return "status.generic_resources"
+}
+
+// ManagedClusterAddOn is the addon of the managed cluster, the conditions are the status of the addon health, e.g.
+// "True", "False" and "Unknown", and they're empty if the addon doesn't report the condition
+type ManagedClusterAddOn struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName string         `gorm:"column:cluster_name;primaryKey"`
+	AddonName   string         `gorm:"column:addon_name;primaryKey"`
+	UID         string         `gorm:"column:uid"`
+	Available   string         `gorm:"column:available"`
+	Degraded    string         `gorm:"column:degraded"`
+	Progressing string         `gorm:"column:progressing"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManagedClusterAddOn) TableName() string {
+	return "status.managed_cluster_addons"
+}
+
+// ArgoCDApplication is the argo cd Application or ApplicationSet, the source and destination of the ApplicationSet are
+// from its template, and the sync and health status are aggregated from the generated Applications
+type ArgoCDApplication struct {
+	LeafHubName          string         `gorm:"column:leaf_hub_name;primaryKey"`
+	Kind                 string         `gorm:"column:kind;primaryKey"`
+	Namespace            string         `gorm:"column:namespace;primaryKey"`
+	Name                 string         `gorm:"column:name;primaryKey"`
+	UID                  string         `gorm:"column:uid"`
+	Project              string         `gorm:"column:project"`
+	RepoURL              string         `gorm:"column:repo_url"`
+	TargetRevision       string         `gorm:"column:target_revision"`
+	DestinationServer    string         `gorm:"column:destination_server"`
+	DestinationName      string         `gorm:"column:destination_name"`
+	DestinationNamespace string         `gorm:"column:destination_namespace"`
+	SyncStatus           string         `gorm:"column:sync_status"`
+	HealthStatus         string         `gorm:"column:health_status"`
+	Payload              datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt            time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt            time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ArgoCDApplication) TableName() string {
+	return "status.argocd_applications"
+}
+
+// ManifestWork is the manifestwork delivered to the managed cluster, the conditions are the status of the workload,
+// e.g. "True", "False" and "Unknown". The placement is from the labels of the work created by the
+// manifestworkreplicaset, and the manifests are the conditions and the status feedback of each resource.
+type ManifestWork struct {
+	LeafHubName        string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName        string         `gorm:"column:cluster_name;primaryKey"`
+	WorkName           string         `gorm:"column:work_name;primaryKey"`
+	UID                string         `gorm:"column:uid"`
+	PlacementNamespace string         `gorm:"column:placement_namespace"`
+	PlacementName      string         `gorm:"column:placement_name"`
+	ReplicaSetName     string         `gorm:"column:replica_set_name"`
+	Applied            string         `gorm:"column:applied"`
+	Available          string         `gorm:"column:available"`
+	Degraded           string         `gorm:"column:degraded"`
+	Progressing        string         `gorm:"column:progressing"`
+	Manifests          datatypes.JSON `gorm:"column:manifests;type:jsonb"`
+	Payload            datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt          time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt          time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ManifestWork) TableName() string {
+	return "status.manifest_works"
+}
+
+// ClusterDeployment is the cluster provisioned by hive, the phase is derived from the installed flag and the
+// conditions, e.g. "Provisioning", "ProvisionFailed" and "Provisioned". The provision name is the ClusterProvision
+// which keeps the install log, and the failure is the reason and message of the failed condition.
+type ClusterDeployment struct {
+	LeafHubName      string         `gorm:"column:leaf_hub_name;primaryKey"`
+	Namespace        string         `gorm:"column:namespace;primaryKey"`
+	Name             string         `gorm:"column:name;primaryKey"`
+	UID              string         `gorm:"column:uid"`
+	ClusterName      string         `gorm:"column:cluster_name"`
+	Platform         string         `gorm:"column:platform"`
+	Region           string         `gorm:"column:region"`
+	BaseDomain       string         `gorm:"column:base_domain"`
+	PoolNamespace    string         `gorm:"column:pool_namespace"`
+	PoolName         string         `gorm:"column:pool_name"`
+	ImageSet         string         `gorm:"column:image_set"`
+	InstallVersion   string         `gorm:"column:install_version"`
+	Phase            string         `gorm:"column:phase"`
+	PowerState       string         `gorm:"column:power_state"`
+	InstallRestarts  int64          `gorm:"column:install_restarts"`
+	ProvisionName    string         `gorm:"column:provision_name"`
+	FailureReason    string         `gorm:"column:failure_reason"`
+	FailureMessage   string         `gorm:"column:failure_message"`
+	ClusterCreatedAt time.Time      `gorm:"column:cluster_created_at"`
+	InstalledAt      *time.Time     `gorm:"column:installed_at"`
+	Payload          datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt        time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt        time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ClusterDeployment) TableName() string {
+	return "status.cluster_deployments"
+}
+
+// ClusterPool is the hive pool of the clusters, the size is the desired number of the unclaimed clusters, and the
+// ready and standby are the number of the running and hibernating unclaimed clusters
+type ClusterPool struct {
+	LeafHubName string         `gorm:"column:leaf_hub_name;primaryKey"`
+	Namespace   string         `gorm:"column:namespace;primaryKey"`
+	Name        string         `gorm:"column:name;primaryKey"`
+	UID         string         `gorm:"column:uid"`
+	Platform    string         `gorm:"column:platform"`
+	Region      string         `gorm:"column:region"`
+	ImageSet    string         `gorm:"column:image_set"`
+	Size        int64          `gorm:"column:size"`
+	MaxSize     *int64         `gorm:"column:max_size"`
+	Ready       int64          `gorm:"column:ready"`
+	Standby     int64          `gorm:"column:standby"`
+	Payload     datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ClusterPool) TableName() string {
+	return "status.cluster_pools"
+}
+
+// ClusterInventory is the capacity and the version of the managed cluster reported by the ManagedClusterInfo. The
+// capacity is the sum of the nodes, the cpu is in millicores and the memory is in bytes. The allocatable is the one of
+// the ManagedCluster, it's zero if the cluster isn't synced yet. The available updates is the array of the versions.
+type ClusterInventory struct {
+	LeafHubName       string         `gorm:"column:leaf_hub_name;primaryKey"`
+	ClusterName       string         `gorm:"column:cluster_name;primaryKey"`
+	ClusterID         string         `gorm:"column:cluster_id"`
+	KubeVendor        string         `gorm:"column:kube_vendor"`
+	Cloud             string         `gorm:"column:cloud"`
+	Region            string         `gorm:"column:region"`
+	KubeVersion       string         `gorm:"column:kube_version"`
+	OpenShiftVersion  string         `gorm:"column:openshift_version"`
+	Channel           string         `gorm:"column:channel"`
+	DesiredVersion    string         `gorm:"column:desired_version"`
+	UpgradeFailed     bool           `gorm:"column:upgrade_failed"`
+	AvailableUpdates  datatypes.JSON `gorm:"column:available_updates;type:jsonb"`
+	NodeCount         int64          `gorm:"column:node_count"`
+	ReadyNodeCount    int64          `gorm:"column:ready_node_count"`
+	CPUCapacity       int64          `gorm:"column:cpu_capacity"`
+	MemoryCapacity    int64          `gorm:"column:memory_capacity"`
+	CPUAllocatable    int64          `gorm:"column:cpu_allocatable"`
+	MemoryAllocatable int64          `gorm:"column:memory_allocatable"`
+	Payload           datatypes.JSON `gorm:"column:payload;type:jsonb"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (ClusterInventory) TableName() string {
+	return "status.cluster_inventory"
+}
+
+// CertificateExpiry is the validity of a certificate or a token scanned on the hub, e.g. the bootstrap kubeconfig in
+// the import secret of the managed cluster, the kind is either "Certificate" or "Token"
+type CertificateExpiry struct {
+	LeafHubName string    `gorm:"column:leaf_hub_name;primaryKey"`
+	Source      string    `gorm:"column:source;primaryKey"`
+	Namespace   string    `gorm:"column:namespace;primaryKey"`
+	Name        string    `gorm:"column:name;primaryKey"`
+	Key         string    `gorm:"column:key;primaryKey"`
+	Kind        string    `gorm:"column:kind"`
+	Subject     string    `gorm:"column:subject"`
+	Issuer      string    `gorm:"column:issuer"`
+	NotBefore   time.Time `gorm:"column:not_before"`
+	NotAfter    time.Time `gorm:"column:not_after"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:true"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime:true"`
+}
+
+func (CertificateExpiry) TableName() string {
+	return "status.certificate_expiries"
+}
+
+// DeadLetter is the status event which is failed to be handled or has no handler registered
+type DeadLetter struct {
+	ID           int64          `gorm:"column:id;primaryKey;autoIncrement"`
+	LeafHubName  string         `gorm:"column:leaf_hub_name;not null"`
+	EventType    string         `gorm:"column:event_type;not null"`
+	EventVersion string         `gorm:"column:event_version"`
+	Reason       string         `gorm:"column:reason;not null"`
+	Error        string         `gorm:"column:error"`
+	Payload      datatypes.JSON `gorm:"column:payload;type:jsonb"` // cloudevent in json format
+	ReplayedAt   *time.Time     `gorm:"column:replayed_at"`
+	CreatedAt    time.Time      `gorm:"column:created_at;autoCreateTime:true"`
+}
+
+func (DeadLetter) TableName() string {
+	return "status.dead_letters"
+}
+
+// TransportAuditLog records the status event which is rejected by the manager before it's conflated
+type TransportAuditLog struct {
+	ID          int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	LeafHubName string    `gorm:"column:leaf_hub_name;not null"`
+	EventType   string    `gorm:"column:event_type;not null"`
+	EventID     string    `gorm:"column:event_id"`
+	Reason      string    `gorm:"column:reason;not null"`
+	Message     string    `gorm:"column:message"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime:true"`
+}
+
+func (TransportAuditLog) TableName() string {
+	return "status.transport_audit_logs"
+}
@@ -12,6 +12,7 @@ const (
 	ManagedClusterMigrationType EventType = EventTypePrefix + "managedclustermigration"
 	ManagedClusterType          EventType = EventTypePrefix + "managedcluster"
 	ManagedClusterInfoType      EventType = EventTypePrefix + "managedclusterinfo"
+	ManagedClusterAddOnType     EventType = EventTypePrefix + "managedclusteraddon"
 	SubscriptionReportType      EventType = EventTypePrefix + "subscription.report"
 	SubscriptionStatusType      EventType = EventTypePrefix + "subscription.status"
 	MigrationResourcesType      EventType = EventTypePrefix + "migration.resources"
@@ -41,6 +42,27 @@ const (
 
 	// Used to send security alerts:
 	SecurityAlertCountsType EventType = EventTypePrefix + "security.alertcounts"
+
+	// used to send the security details of the clusters secured by the stackrox centrals
+	SecurityClusterAlertCountsType   EventType = EventTypePrefix + "security.clusteralertcounts"
+	SecurityPolicyViolationsType     EventType = EventTypePrefix + "security.policyviolations"
+	SecurityImageVulnerabilitiesType EventType = EventTypePrefix + "security.imagevulnerabilities"
+
+	// used to send the custom resources configured by the agent configmap
+	GenericResourceType EventType = EventTypePrefix + "genericresource"
+
+	// used to send both the argo cd applications and applicationsets
+	ArgoCDApplicationType EventType = EventTypePrefix + "argocd.application"
+
+	// used to send the conditions and the status feedback of the manifestworks
+	ManifestWorkType EventType = EventTypePrefix + "manifestwork"
+
+	// used to send the provisioning status of the hive clusters and the capacity of the cluster pools
+	ClusterDeploymentType EventType = EventTypePrefix + "hive.clusterdeployment"
+	ClusterPoolType       EventType = EventTypePrefix + "hive.clusterpool"
+
+	// used to send the expiry of the klusterlet, api server and transport certificates of the hub
+	CertificateExpiryType EventType = EventTypePrefix + "certificate.expiry"
 )
 
 func ShortenEventType(eventType string) string {
@@ -0,0 +1,44 @@
+package statistics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unknownHub is the hub label of the events whose source isn't a known hub
+const unknownHub = "unknown"
+
+var (
+	knownHubs      = map[string]bool{}
+	knownHubsMutex sync.RWMutex
+)
+
+// AddHub registers the hub once its heartbeat is committed. The metrics of the events from the other sources are
+// labeled as the unknown hub, so that the label values aren't unbounded by the sources of the received events.
+func AddHub(hub string) {
+	knownHubsMutex.Lock()
+	defer knownHubsMutex.Unlock()
+	knownHubs[hub] = true
+}
+
+// RemoveHub unregisters the inactive hub and deletes its metrics
+func RemoveHub(hub string) {
+	knownHubsMutex.Lock()
+	defer knownHubsMutex.Unlock()
+	delete(knownHubs, hub)
+	labels := prometheus.Labels{"hub": hub}
+	receivedEventsCounter.DeletePartialMatch(labels)
+	conflationDurationHistogram.DeletePartialMatch(labels)
+	databaseDurationHistogram.DeletePartialMatch(labels)
+}
+
+// hubLabel returns the hub label of the event source
+func hubLabel(source string) string {
+	knownHubsMutex.RLock()
+	defer knownHubsMutex.RUnlock()
+	if knownHubs[source] {
+		return source
+	}
+	return unknownHub
+}
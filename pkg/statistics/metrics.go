@@ -0,0 +1,80 @@
+package statistics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// the events are expected to be handled in seconds, the buckets cover from 10ms to around 5 mins
+	durationBuckets = prometheus.ExponentialBuckets(0.01, 2, 15)
+
+	receivedEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_status_received_events_total",
+			Help: "The number of the status events received from the transport.",
+		},
+		[]string{
+			"hub",  // The leaf hub name of the event.
+			"type", // The shortened event type.
+		},
+	)
+
+	conflationReadyQueueSizeGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_conflation_ready_queue_size",
+			Help: "The number of the conflation units and delta events waiting in the ready queue for the workers.",
+		},
+	)
+
+	conflationUnitsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_conflation_units",
+			Help: "The number of the conflation units, which is one per leaf hub.",
+		},
+	)
+
+	availableDBWorkersGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_status_available_db_workers",
+			Help: "The number of the idle database workers to handle the status events.",
+		},
+	)
+
+	conflationDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_status_conflation_duration_seconds",
+			Help:    "The time the status events spent in the conflation queue before they're picked by the workers.",
+			Buckets: durationBuckets,
+		},
+		[]string{
+			"hub",  // The leaf hub name of the event.
+			"type", // The shortened event type.
+		},
+	)
+
+	databaseDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_status_handler_database_duration_seconds",
+			Help:    "The time the handlers spent in writing the status events into the database, including retries.",
+			Buckets: durationBuckets,
+		},
+		[]string{
+			"hub",    // The leaf hub name of the event.
+			"type",   // The shortened event type.
+			"result", // The result of the handler: success or failure.
+		},
+	)
+)
+
+var registerOnce sync.Once
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		metrics.Registry.MustRegister(receivedEventsCounter, conflationReadyQueueSizeGauge, conflationUnitsGauge,
+			availableDBWorkersGauge, conflationDurationHistogram, databaseDurationHistogram)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
 
@@ -47,34 +48,30 @@ func (s *Statistics) ReceivedEvent(evt *cloudevents.Event) {
 		return
 	}
 	metrics.totalReceived++
+	receivedEventsCounter.WithLabelValues(hubLabel(evt.Source()), enum.ShortenEventType(evt.Type())).Inc()
 }
 
 // SetNumberOfAvailableDBWorkers sets number of available db workers.
 func (s *Statistics) SetNumberOfAvailableDBWorkers(numOf int) {
 	s.numOfAvailableDBWorkers = numOf
+	availableDBWorkersGauge.Set(float64(numOf))
 }
 
 // SetConflationReadyQueueSize sets conflation ready queue size.
 func (s *Statistics) SetConflationReadyQueueSize(size int) {
 	s.conflationReadyQueueSize = size
+	conflationReadyQueueSizeGauge.Set(float64(size))
 }
 
-// StartConflationUnitMetrics starts conflation unit metrics of the specific event type.
-func (s *Statistics) StartConflationUnitMetrics(evt *cloudevents.Event) {
+// AddConflationUnitMetrics adds the time the event of the specific type spent in the conflation unit.
+func (s *Statistics) AddConflationUnitMetrics(evt *cloudevents.Event, duration time.Duration) {
 	eventMetrics, ok := s.eventMetrics[evt.Type()]
 	if !ok {
 		return
 	}
-	eventMetrics.conflationUnit.start(evt.Source())
-}
-
-// StopConflationUnitMetrics stops conflation unit metrics of the specific event type.
-func (s *Statistics) StopConflationUnitMetrics(evt *cloudevents.Event, err error) {
-	eventMetrics, ok := s.eventMetrics[evt.Type()]
-	if !ok {
-		return
-	}
-	eventMetrics.conflationUnit.stop(evt.Source(), err)
+	eventMetrics.conflationUnit.add(duration, nil)
+	conflationDurationHistogram.WithLabelValues(hubLabel(evt.Source()), enum.ShortenEventType(evt.Type())).
+		Observe(duration.Seconds())
 }
 
 // IncrementNumberOfConflations increments number of conflations
@@ -82,6 +79,16 @@ func (s *Statistics) IncrementNumberOfConflations() {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	s.numOfConflationUnits++
+	conflationUnitsGauge.Set(float64(s.numOfConflationUnits))
+}
+
+// DecrementNumberOfConflations decrements number of conflations, the conflation unit is removed when the partition
+// of the leaf hub is revoked from the manager replica
+func (s *Statistics) DecrementNumberOfConflations() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.numOfConflationUnits--
+	conflationUnitsGauge.Set(float64(s.numOfConflationUnits))
 }
 
 // AddDatabaseMetrics adds database metrics of the specific event type.
@@ -91,6 +98,12 @@ func (s *Statistics) AddDatabaseMetrics(evt *cloudevents.Event, duration time.Du
 		return
 	}
 	eventMetrics.database.add(duration, err)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	databaseDurationHistogram.WithLabelValues(hubLabel(evt.Source()), enum.ShortenEventType(evt.Type()), result).
+		Observe(duration.Seconds())
 }
 
 // Start starts the statistics.
@@ -0,0 +1,63 @@
+package statistics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestStatisticsMetrics(t *testing.T) {
+	stats := NewStatistics(&StatisticsConfig{LogInterval: "0s"})
+	stats.Register(string(enum.ManagedClusterType))
+	AddHub("hub1")
+	defer RemoveHub("hub1")
+
+	evt := cloudevents.NewEvent()
+	evt.SetType(string(enum.ManagedClusterType))
+	evt.SetSource("hub1")
+	shortType := enum.ShortenEventType(evt.Type())
+
+	// the unregistered event type isn't counted
+	unregistered := cloudevents.NewEvent()
+	unregistered.SetType("unregistered")
+	unregistered.SetSource("hub1")
+	stats.ReceivedEvent(&unregistered)
+
+	stats.ReceivedEvent(&evt)
+	stats.ReceivedEvent(&evt)
+	require.Equal(t, float64(2), testutil.ToFloat64(receivedEventsCounter.WithLabelValues("hub1", shortType)))
+	require.Equal(t, float64(0), testutil.ToFloat64(receivedEventsCounter.WithLabelValues("hub1", "unregistered")))
+
+	// the event of the unknown source is counted as the unknown hub
+	spoofed := evt.Clone()
+	spoofed.SetSource("spoofed")
+	stats.ReceivedEvent(&spoofed)
+	require.Equal(t, float64(1), testutil.ToFloat64(receivedEventsCounter.WithLabelValues(unknownHub, shortType)))
+	require.Equal(t, float64(0), testutil.ToFloat64(receivedEventsCounter.WithLabelValues("spoofed", shortType)))
+
+	stats.SetConflationReadyQueueSize(3)
+	stats.SetNumberOfAvailableDBWorkers(5)
+	stats.IncrementNumberOfConflations()
+	require.Equal(t, float64(3), testutil.ToFloat64(conflationReadyQueueSizeGauge))
+	require.Equal(t, float64(5), testutil.ToFloat64(availableDBWorkersGauge))
+	require.Equal(t, float64(1), testutil.ToFloat64(conflationUnitsGauge))
+
+	stats.AddConflationUnitMetrics(&evt, 2*time.Second)
+	stats.AddDatabaseMetrics(&evt, 100*time.Millisecond, nil)
+	stats.AddDatabaseMetrics(&evt, time.Second, errors.New("failed to update the database"))
+	require.Equal(t, 1, testutil.CollectAndCount(conflationDurationHistogram))
+	require.Equal(t, 2, testutil.CollectAndCount(databaseDurationHistogram))
+	require.Equal(t, int64(1), stats.eventMetrics[evt.Type()].database.failures)
+	require.Equal(t, int64(2000), stats.eventMetrics[evt.Type()].conflationUnit.maxDuration)
+
+	// the metrics of the removed hub are deleted
+	RemoveHub("hub1")
+	require.Equal(t, 0, testutil.CollectAndCount(conflationDurationHistogram))
+	require.Equal(t, 0, testutil.CollectAndCount(databaseDurationHistogram))
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	// TraceParentKey is the W3C trace context of the event, defined by the cloudevents distributed tracing extension
+	TraceParentKey = extensions.TraceParentExtension
+	TraceStateKey  = extensions.TraceStateExtension
+)
+
+var propagator = propagation.TraceContext{}
+
+// eventCarrier adapts the extensions of the cloudevent to the carrier of the W3C trace context propagator
+type eventCarrier struct {
+	evt *cloudevents.Event
+}
+
+func (c eventCarrier) Get(key string) string {
+	value, found := c.evt.Extensions()[key]
+	if !found {
+		return ""
+	}
+	str, err := types.ToString(value)
+	if err != nil {
+		return ""
+	}
+	return str
+}
+
+func (c eventCarrier) Set(key, value string) {
+	c.evt.SetExtension(key, value)
+}
+
+func (c eventCarrier) Keys() []string {
+	return []string{TraceParentKey, TraceStateKey}
+}
+
+// Inject sets the traceparent extension of the event with the span of the context, it keeps the existing one if
+// there is no valid span in the context, e.g. the event is resent by the transport or replayed from the archive
+func Inject(ctx context.Context, evt *cloudevents.Event) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	propagator.Inject(ctx, eventCarrier{evt: evt})
+}
+
+// Extract returns the context with the remote span of the traceparent extension of the event
+func Extract(ctx context.Context, evt *cloudevents.Event) context.Context {
+	return propagator.Extract(ctx, eventCarrier{evt: evt})
+}
+
+// StartEventSpan starts a span of the event which continues the trace of the traceparent extension, the span is
+// the child of the span in the context if the event isn't traced
+func StartEventSpan(ctx context.Context, evt *cloudevents.Event, name string, opts ...trace.SpanStartOption) (
+	context.Context, trace.Span,
+) {
+	if _, found := evt.Extensions()[TraceParentKey]; found {
+		ctx = Extract(ctx, evt)
+	}
+	opts = append(opts, trace.WithAttributes(EventAttributes(evt)...))
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// EventAttributes returns the span attributes to identify the event
+func EventAttributes(evt *cloudevents.Event) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.CloudeventsEventID(evt.ID()),
+		semconv.CloudeventsEventSource(evt.Source()),
+		semconv.CloudeventsEventType(enum.ShortenEventType(evt.Type())),
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// the status of the applied object is usually emitted within a few sync intervals, the span of the apply is
+	// forgotten after the ttl, so that the unrelated status changes aren't attached to the trace
+	objectSpanTTL = 10 * time.Minute
+	// the expired spans are pruned once the number of the recorded objects exceeds the limit
+	objectSpanPruneSize = 1000
+)
+
+type objectSpan struct {
+	spanContext trace.SpanContext
+	recordedAt  time.Time
+}
+
+var (
+	objectSpansMutex sync.Mutex
+	objectSpans      = map[string]objectSpan{}
+)
+
+func objectKey(obj metav1.Object) string {
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// RecordObject records the span of the context which applies the object, the status emitters of the object continue
+// the trace with it. The object is identified by the namespace and name, since the kind of the typed objects is
+// usually empty in the controllers.
+func RecordObject(ctx context.Context, obj metav1.Object) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+	objectSpansMutex.Lock()
+	defer objectSpansMutex.Unlock()
+
+	now := time.Now()
+	if len(objectSpans) >= objectSpanPruneSize {
+		for key, span := range objectSpans {
+			if now.Sub(span.recordedAt) > objectSpanTTL {
+				delete(objectSpans, key)
+			}
+		}
+	}
+	objectSpans[objectKey(obj)] = objectSpan{spanContext: spanContext, recordedAt: now}
+}
+
+// ObjectSpanContext returns the span which applies the object recently
+func ObjectSpanContext(obj metav1.Object) (trace.SpanContext, bool) {
+	objectSpansMutex.Lock()
+	defer objectSpansMutex.Unlock()
+
+	span, found := objectSpans[objectKey(obj)]
+	if !found || time.Since(span.recordedAt) > objectSpanTTL {
+		return trace.SpanContext{}, false
+	}
+	return span.spanContext, true
+}
+
+// BundleSpans collects the spans of the objects updated into a bundle. The span of sending the bundle is the child
+// of the latest applied object, and links to the others, so the status of a spec change continues its trace.
+type BundleSpans struct {
+	mutex        sync.Mutex
+	spanContexts []trace.SpanContext
+}
+
+// Add collects the span of the object if it's applied recently
+func (b *BundleSpans) Add(obj metav1.Object) {
+	spanContext, found := ObjectSpanContext(obj)
+	if !found {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, existing := range b.spanContexts {
+		if existing.Equal(spanContext) {
+			return
+		}
+	}
+	b.spanContexts = append(b.spanContexts, spanContext)
+}
+
+// Start starts the span of sending the bundle, it's a new trace if none of the objects is traced
+func (b *BundleSpans) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (
+	context.Context, trace.Span,
+) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if len(b.spanContexts) > 0 {
+		latest := len(b.spanContexts) - 1
+		ctx = trace.ContextWithRemoteSpanContext(ctx, b.spanContexts[latest])
+		for _, spanContext := range b.spanContexts[:latest] {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: spanContext}))
+		}
+	}
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// Reset forgets the collected spans once the bundle is sent
+func (b *BundleSpans) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.spanContexts = nil
+}
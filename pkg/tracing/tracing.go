@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package tracing propagates the OpenTelemetry trace through the cloudevents pipeline with the W3C `traceparent`
+// extension, so that a spec change can be followed from the spec syncer of the manager, through the agent apply,
+// back to the status handler of the manager. The spans are exported with OTLP, or into a local file for testing.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// TracerName is the instrumentation scope of the spans of the global hub
+const TracerName = "github.com/stolostron/multicluster-global-hub"
+
+type TracingConfig struct {
+	// OTLPEndpoint is the host:port of the OTLP gRPC receiver, e.g. the opentelemetry collector
+	OTLPEndpoint string
+	OTLPInsecure bool
+	// FilePath writes the spans into the file with a JSON object per line, it's used for testing
+	FilePath string
+	// SampleRatio is the ratio of the sampled traces started by the component, the traces continued from the
+	// cloudevents follow the sampling decision of the parent
+	SampleRatio float64
+}
+
+// Enabled returns true if any of the exporters is configured
+func (c *TracingConfig) Enabled() bool {
+	return c != nil && (c.OTLPEndpoint != "" || c.FilePath != "")
+}
+
+// InitTracerProvider sets the global tracer provider with the configured exporters, the returned function flushes
+// the pending spans and shuts down the exporters. The tracer is a no-op if the tracing isn't enabled.
+func InitTracerProvider(ctx context.Context, serviceName string, config *TracingConfig) (
+	func(context.Context) error, error,
+) {
+	if !config.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+	closers := []func() error{}
+
+	if config.OTLPEndpoint != "" {
+		clientOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.OTLPEndpoint)}
+		if config.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	if config.FilePath != "" {
+		file, err := os.OpenFile(config.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600) // #nosec G304
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("failed to create the file trace exporter: %w", err)
+		}
+		// the spans are written in order of ending, so they're synced to the file without batching
+		opts = append(opts, sdktrace.WithSyncer(exporter))
+		closers = append(closers, file.Close)
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	logger.DefaultZapLogger().Infow("tracing is enabled", "service", serviceName,
+		"otlpEndpoint", config.OTLPEndpoint, "file", config.FilePath, "sampleRatio", config.SampleRatio)
+
+	return func(ctx context.Context) error {
+		errs := []error{provider.Shutdown(ctx)}
+		for _, closer := range closers {
+			errs = append(errs, closer())
+		}
+		return errors.Join(errs...)
+	}, nil
+}
+
+// Tracer returns the tracer of the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// EndSpan ends the span, and marks it as failed with the error
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newEvent(id string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetSource("hub1")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.policy.compliance")
+	return &evt
+}
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "spans.jsonl")
+	shutdown, err := InitTracerProvider(ctx, "test", &TracingConfig{FilePath: file, SampleRatio: 1})
+	require.NoError(t, err)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	// the manager syncs the spec with the traceparent
+	specCtx, specSpan := Tracer().Start(ctx, "sync spec policies")
+	specEvt := newEvent("spec")
+	Inject(specCtx, specEvt)
+	specSpan.End()
+	require.Contains(t, specEvt.Extensions(), TraceParentKey)
+
+	// the event without the span in the context keeps the existing traceparent
+	traceParent := specEvt.Extensions()[TraceParentKey]
+	Inject(ctx, specEvt)
+	require.Equal(t, traceParent, specEvt.Extensions()[TraceParentKey])
+
+	// the agent applies the policy with the trace of the spec event
+	applyCtx, applySpan := StartEventSpan(ctx, specEvt, "apply Policy")
+	policy := &metav1.ObjectMeta{Namespace: "default", Name: "policy1"}
+	RecordObject(applyCtx, policy)
+	applySpan.End()
+	require.Equal(t, specSpan.SpanContext().TraceID(), applySpan.SpanContext().TraceID())
+
+	// the compliance bundle continues the trace of the latest applied object and links the others
+	otherCtx, otherSpan := Tracer().Start(ctx, "apply Policy")
+	RecordObject(otherCtx, &metav1.ObjectMeta{Namespace: "default", Name: "policy2"})
+	otherSpan.End()
+
+	spans := &BundleSpans{}
+	spans.Add(&metav1.ObjectMeta{Namespace: "default", Name: "policy2"})
+	spans.Add(policy)
+	spans.Add(policy)
+	spans.Add(&metav1.ObjectMeta{Namespace: "default", Name: "untraced"})
+	emitCtx, emitSpan := spans.Start(ctx, "emit policy.compliance")
+	statusEvt := newEvent("status")
+	Inject(emitCtx, statusEvt)
+	emitSpan.End()
+	spans.Reset()
+	require.Equal(t, specSpan.SpanContext().TraceID(), emitSpan.SpanContext().TraceID())
+
+	// the manager handles the status event in the same trace
+	handleCtx, handleSpan := StartEventSpan(ctx, statusEvt, "handle policy.compliance")
+	handleSpan.End()
+	require.Equal(t, specSpan.SpanContext().TraceID(), trace.SpanContextFromContext(handleCtx).TraceID())
+
+	// the spans are written into the file
+	require.NoError(t, shutdown(ctx))
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	type exportedSpan struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+		Links       []struct{ SpanContext struct{ TraceID string } }
+	}
+	exported := map[string]exportedSpan{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		span := exportedSpan{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		exported[span.Name] = span
+	}
+	require.Len(t, exported, 4)
+	require.Equal(t, specSpan.SpanContext().TraceID().String(), exported["handle policy.compliance"].SpanContext.TraceID)
+	require.Len(t, exported["emit policy.compliance"].Links, 1)
+	require.Equal(t, otherSpan.SpanContext().TraceID().String(),
+		exported["emit policy.compliance"].Links[0].SpanContext.TraceID)
+}
+
+func TestTracingDisabled(t *testing.T) {
+	shutdown, err := InitTracerProvider(context.Background(), "test", &TracingConfig{})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	// the noop span isn't injected into the event
+	ctx, span := Tracer().Start(context.Background(), "noop")
+	evt := newEvent("noop")
+	Inject(ctx, evt)
+	span.End()
+	require.NotContains(t, evt.Extensions(), TraceParentKey)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package archive captures the cloudevents received from the transport into a JSONL archive, one record per line,
+// and replays the archive into the transport or the conflation pipeline of the manager. It's used to reproduce the
+// handler issues with the real traffic of the hubs, and to build the fixtures of the regression tests.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// Record is a line of the archive, the topic, partition and offset are the position of the event in the transport,
+// they're empty if the event isn't consumed from kafka
+type Record struct {
+	Topic      string             `json:"topic,omitempty"`
+	Partition  int32              `json:"partition"`
+	Offset     int64              `json:"offset"`
+	CapturedAt time.Time          `json:"capturedAt"`
+	Event      *cloudevents.Event `json:"event"`
+}
+
+// NewRecord creates the record of the received event, the position is read from the kafka extensions
+func NewRecord(evt *cloudevents.Event) *Record {
+	record := &Record{CapturedAt: time.Now(), Event: evt}
+	extensions := evt.Extensions()
+	if topic, err := types.ToString(extensions[transport.KafkaTopicKey]); err == nil {
+		record.Topic = topic
+	}
+	if partition, err := types.ToInteger(extensions[transport.KafkaPartitionKey]); err == nil {
+		record.Partition = partition
+	}
+	if offset, ok := extensions[transport.KafkaOffsetKey]; ok {
+		if offset, err := strconv.ParseInt(fmt.Sprintf("%v", offset), 10, 64); err == nil {
+			record.Offset = offset
+		}
+	}
+	return record
+}
+
+// Writer appends the events into the archive, it's safe to be used by multiple goroutines
+type Writer struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{encoder: json.NewEncoder(w)}
+}
+
+// Write writes the event into a line of the archive
+func (w *Writer) Write(evt *cloudevents.Event) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.encoder.Encode(NewRecord(evt))
+}
+
+// Read reads the records of the archive in order, the empty lines are skipped
+func Read(r io.Reader, fn func(*Record) error) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			record := &Record{}
+			if unmarshalErr := json.Unmarshal(data, record); unmarshalErr != nil {
+				return fmt.Errorf("failed to unmarshal the record at line %d: %w", line, unmarshalErr)
+			}
+			if record.Event == nil {
+				return fmt.Errorf("the record at line %d has no event", line)
+			}
+			if fnErr := fn(record); fnErr != nil {
+				return fnErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReplayFunc handles the replayed record, e.g. inserts the event into the conflation pipeline of the manager
+type ReplayFunc func(record *Record) error
+
+// Replay replays the records of the archive in order with the replay function, return the number of the replayed
+// records. It stops at the first error, so that the following events don't depend on a missing one.
+func Replay(ctx context.Context, r io.Reader, replay ReplayFunc) (int, error) {
+	replayed := 0
+	err := Read(r, func(record *Record) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := replay(record); err != nil {
+			return fmt.Errorf("failed to replay the event(%s/%s): %w", record.Event.Source(), record.Event.ID(), err)
+		}
+		replayed++
+		return nil
+	})
+	return replayed, err
+}
+
+// ProducerReplayFunc sends the replayed events with the producer, e.g. the generic producer with the gochan transport
+// of the integration tests. The event is sent to the topic of the record if the topic isn't specified.
+func ProducerReplayFunc(ctx context.Context, producer transport.Producer, topic string) ReplayFunc {
+	return func(record *Record) error {
+		sendTopic := topic
+		if sendTopic == "" {
+			sendTopic = record.Topic
+		}
+		return producer.SendEvent(cectx.WithTopic(ctx, sendTopic), *record.Event)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package archive
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+)
+
+func newCapturedEvent(id string, offset int64) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(id)
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster")
+	evt.SetSource("hub1")
+	evt.SetExtension(transport.KafkaTopicKey, "gh-status.hub1")
+	evt.SetExtension(transport.KafkaPartitionKey, "0")
+	evt.SetExtension(transport.KafkaOffsetKey, offset)
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"name": "cluster" + id})
+	return &evt
+}
+
+func TestArchive(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewWriter(buf)
+	require.NoError(t, writer.Write(newCapturedEvent("1", 10)))
+	require.NoError(t, writer.Write(newCapturedEvent("2", 11)))
+	buf.WriteString("\n")
+
+	// the records are read in order with the position and the extensions of the events
+	records := []*Record{}
+	require.NoError(t, Read(bytes.NewReader(buf.Bytes()), func(record *Record) error {
+		records = append(records, record)
+		return nil
+	}))
+	require.Len(t, records, 2)
+	require.Equal(t, "gh-status.hub1", records[0].Topic)
+	require.Equal(t, int64(10), records[0].Offset)
+	require.Equal(t, int64(11), records[1].Offset)
+	require.Equal(t, "2", records[1].Event.ID())
+	require.Equal(t, "gh-status.hub1", records[1].Event.Extensions()[transport.KafkaTopicKey])
+	require.JSONEq(t, `{"name":"cluster2"}`, string(records[1].Event.Data()))
+
+	// the malformed line is reported with the line number
+	err := Read(bytes.NewReader(append(buf.Bytes(), []byte("{invalid\n")...)), func(*Record) error { return nil })
+	require.ErrorContains(t, err, "line 4")
+
+	// the archive is replayed into the gochan transport
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tranConfig := &transport.TransportInternalConfig{
+		TransportType: string(transport.Chan),
+		Extends:       map[string]interface{}{},
+	}
+	sender, err := producer.NewGenericProducer(tranConfig, "gh-status", nil)
+	require.NoError(t, err)
+	receiver, err := consumer.NewGenericConsumer(tranConfig, []string{"gh-status"})
+	require.NoError(t, err)
+	go func() { _ = receiver.Start(ctx) }()
+
+	received := make(chan *cloudevents.Event, 2)
+	go func() {
+		for evt := range receiver.EventChan() {
+			received <- evt
+		}
+	}()
+
+	replayed, err := Replay(ctx, bytes.NewReader(buf.Bytes()), ProducerReplayFunc(ctx, sender, ""))
+	require.NoError(t, err)
+	require.Equal(t, 2, replayed)
+	ids := []string{}
+	for range 2 {
+		select {
+		case evt := <-received:
+			ids = append(ids, evt.ID())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the replayed events aren't received: %v", ids)
+		}
+	}
+	require.ElementsMatch(t, []string{"1", "2"}, ids)
+}
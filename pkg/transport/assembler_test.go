@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
@@ -51,3 +52,54 @@ func TestAssembler(t *testing.T) {
 	evt := <-genericConsumer.EventChan()
 	fmt.Println("whole", evt)
 }
+
+func TestCompressedAssembler(t *testing.T) {
+	transportConfig := &transport.TransportInternalConfig{
+		TransportType:   string(transport.Chan),
+		CompressionType: compressor.Zstd,
+		KafkaCredential: &transport.KafkaConfig{
+			SpecTopic:   "spec",
+			StatusTopic: "status",
+		},
+	}
+
+	genericProducer, err := producer.NewGenericProducer(transportConfig,
+		transportConfig.KafkaCredential.SpecTopic, nil)
+	assert.Nil(t, err)
+	genericProducer.SetDataLimit(5)
+
+	genericConsumer, err := consumer.NewGenericConsumer(transportConfig,
+		[]string{transportConfig.KafkaCredential.SpecTopic})
+	assert.Nil(t, err)
+	go func() {
+		err = genericConsumer.Start(context.TODO())
+		assert.Nil(t, err)
+	}()
+
+	payload := map[string]interface{}{
+		"id":      float64(1),
+		"message": "Hello, World! Hello, World! Hello, World!",
+	}
+	e := cloudevents.NewEvent()
+	e.SetID(uuid.New().String())
+	e.SetType("com.cloudevents.sample.sent")
+	e.SetSource("https://github.com/cloudevents/sdk-go/samples/kafka/sender")
+	_ = e.SetData(cloudevents.ApplicationJSON, payload)
+
+	err = genericProducer.SendEvent(context.TODO(), e)
+	assert.Nil(t, err)
+
+	evt := <-genericConsumer.EventChan()
+	_, err = evt.Context.GetExtension(transport.CompressionKey)
+	assert.NotNil(t, err, "the compression extension should be removed after decompressing")
+
+	received := map[string]interface{}{}
+	assert.Nil(t, evt.DataAs(&received))
+	assert.Equal(t, payload, received)
+
+	_, err = producer.NewGenericProducer(&transport.TransportInternalConfig{
+		TransportType:   string(transport.Chan),
+		CompressionType: "lz4",
+	}, "spec", nil)
+	assert.NotNil(t, err)
+}
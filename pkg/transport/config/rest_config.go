@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -26,3 +28,29 @@ func GetRestfulConnBySecret(transportSecret *corev1.Secret, c client.Client) (*t
 	}
 	return conn, nil
 }
+
+// GetRestfulTLSConfig builds the mTLS config from the restful credential, return nil if the CA isn't provided.
+// The client certificate is optional, it's only required when the server verifies the client.
+func GetRestfulTLSConfig(conn *transport.RestfulConfig) (*tls.Config, error) {
+	if conn.CACert == "" {
+		return nil, nil
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM([]byte(conn.CACert)) {
+		return nil, fmt.Errorf("failed to append CA certificate to pool")
+	}
+
+	// #nosec G402
+	tlsConfig := &tls.Config{
+		RootCAs:    caCertPool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if conn.ClientCert != "" && conn.ClientKey != "" {
+		clientCert, err := tls.X509KeyPair([]byte(conn.ClientCert), []byte(conn.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed the load client cert from raw data: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+	return tlsConfig, nil
+}
@@ -3,8 +3,11 @@ package config
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/IBM/sarama"
 
@@ -59,3 +62,86 @@ func NewTLSConfig(clientCertFile, clientKeyFile, caCertFile string) (*tls.Config
 	tlsConfig.BuildNameToCertificate()
 	return &tlsConfig, err
 }
+
+// ValidateKafkaClientType returns error if the kafka client library isn't supported, the empty value means confluent
+func ValidateKafkaClientType(clientType transport.KafkaClientType) error {
+	switch clientType {
+	case "", transport.ConfluentKafkaClient, transport.SaramaKafkaClient:
+		return nil
+	default:
+		return fmt.Errorf("kafka-client - %s is not a valid option", clientType)
+	}
+}
+
+// GetSaramaConfigByKafkaCredential returns the sarama config which is consistent with the confluent config map from
+// GetConfluentConfigMapByKafkaCredential, the consumer config is applied if the consumer group id isn't empty
+func GetSaramaConfigByKafkaCredential(conn *transport.KafkaConfig, consumerGroupID string,
+	topicMetadataRefreshInterval int,
+) (*sarama.Config, error) {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V2_0_0_0
+	saramaConfig.Net.KeepAlive = 30 * time.Second
+	if consumerGroupID != "" {
+		// the offsets are committed by the receiver once the messages are acked, or not committed if the offsets are
+		// stored in the database, so the group offsets are never ahead of the processed events
+		saramaConfig.Consumer.Offsets.AutoCommit.Enable = false
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+		saramaConfig.Consumer.Fetch.Max = MaxSizeToFetch
+		if topicMetadataRefreshInterval > 0 {
+			saramaConfig.Metadata.RefreshFrequency = time.Duration(topicMetadataRefreshInterval) * time.Millisecond
+		}
+	} else {
+		// the sync producer requires the successes to be returned
+		saramaConfig.Producer.Return.Successes = true
+		saramaConfig.Producer.MaxMessageBytes = MaxSizeToSend
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForLocal
+		saramaConfig.Producer.Retry.Max = 1
+	}
+
+	// if the certs is invalid
+	if conn.CACert == "" || conn.ClientCert == "" || conn.ClientKey == "" {
+		log.Warn("Connect to Kafka without SSL")
+		return saramaConfig, nil
+	}
+	cert, err := tls.X509KeyPair([]byte(conn.ClientCert), []byte(conn.ClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM([]byte(conn.CACert)) {
+		return nil, errors.New("failed to append ca certificate")
+	}
+	saramaConfig.Net.TLS.Enable = true
+	saramaConfig.Net.TLS.Config = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// the hostname isn't verified, which is same as the ssl.endpoint.identification.algorithm=none of the
+		// confluent client, the certificate chain is still verified with the ca
+		// #nosec G402
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyCertificateChain(caCertPool),
+	}
+	return saramaConfig, nil
+}
+
+func verifyCertificateChain(roots *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no certificate is presented by the kafka broker")
+		}
+		certs := make([]*x509.Certificate, 0, len(rawCerts))
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			certs = append(certs, cert)
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+		return err
+	}
+}
@@ -0,0 +1,128 @@
+//go:build cgo
+
+package consumer
+
+import (
+	"context"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
+)
+
+// KafkaConsumer is the consumer of the confluent kafka client
+type KafkaConsumer = kafka.Consumer
+
+// getConfluentReceiverProtocol creates the receiver with the confluent kafka client, the partitions are assigned by
+// the rebalance callback if the partitions are sharded
+func (c *GenericConsumer) getConfluentReceiverProtocol(transportConfig *transport.TransportInternalConfig,
+	topics []string,
+) (*kafka.Consumer, interface{}, error) {
+	configMap, err := config.GetConfluentConfigMapByKafkaCredential(transportConfig.KafkaCredential,
+		transportConfig.KafkaCredential.ConsumerGroupID, c.topicMetadataRefreshInterval)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.Debugw("the configurations applied to the Kafka consumer", "configMap",
+		utils.FilterSensitiveKafkaConfig(configMap))
+
+	consumer, err := kafka.NewConsumer(configMap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts := []kafka_confluent.Option{
+		kafka_confluent.WithReceiver(consumer),
+		kafka_confluent.WithReceiverTopics(topics),
+	}
+	if c.partitionSharding {
+		opts = append(opts, kafka_confluent.WithRebalanceCallBack(c.rebalance))
+	}
+	protocol, err := kafka_confluent.New(opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return consumer, protocol, nil
+}
+
+// withConfluentOffsets applies the stored offsets to the confluent receiver
+func withConfluentOffsets(ctx context.Context, offsets []transport.EventPosition) context.Context {
+	topicPartitions := make([]kafka.TopicPartition, 0, len(offsets))
+	for i := range offsets {
+		topicPartitions = append(topicPartitions, kafka.TopicPartition{
+			Topic:     &offsets[i].Topic,
+			Partition: offsets[i].Partition,
+			Offset:    kafka.Offset(offsets[i].Offset),
+		})
+	}
+	return kafka_confluent.WithTopicPartitionOffsets(ctx, topicPartitions)
+}
+
+// rebalance is the rebalance callback of the sharded consumer. The assigned partitions start from the offsets stored
+// in the database, which are committed by the previous owner on revoking, rather than the auto committed offsets of
+// the consumer group, which might be ahead of the events persisted into the database
+func (c *GenericConsumer) rebalance(kafkaConsumer *kafka.Consumer, evt kafka.Event) error {
+	cooperative := kafkaConsumer.GetRebalanceProtocol() == "COOPERATIVE"
+	switch e := evt.(type) {
+	case kafka.AssignedPartitions:
+		partitions := e.Partitions
+		if c.enableDatabaseOffset {
+			offsets, err := getInitOffset(c.clusterID)
+			if err != nil {
+				log.Warnw("failed to get the stored offsets, start from the committed offsets of the group",
+					"error", err)
+			} else {
+				partitions = withStoredOffsets(partitions, offsets)
+			}
+		}
+		log.Infow("partitions assigned", "partitions", partitions, "protocol", kafkaConsumer.GetRebalanceProtocol())
+		c.notifyRebalance(c.toPositions(partitions), true)
+		if cooperative {
+			return kafkaConsumer.IncrementalAssign(partitions)
+		}
+		return kafkaConsumer.Assign(partitions)
+	case kafka.RevokedPartitions:
+		log.Infow("partitions revoked", "partitions", e.Partitions, "protocol", kafkaConsumer.GetRebalanceProtocol())
+		c.notifyRebalance(c.toPositions(e.Partitions), false)
+		if cooperative {
+			return kafkaConsumer.IncrementalUnassign(e.Partitions)
+		}
+		return kafkaConsumer.Unassign()
+	}
+	return nil
+}
+
+// withStoredOffsets sets the offsets of the assigned partitions with the stored ones, the others keep the offsets of
+// the consumer group
+func withStoredOffsets(partitions []kafka.TopicPartition, offsets []transport.EventPosition) []kafka.TopicPartition {
+	result := make([]kafka.TopicPartition, 0, len(partitions))
+	for _, partition := range partitions {
+		for _, offset := range offsets {
+			if partition.Topic != nil && *partition.Topic == offset.Topic && partition.Partition == offset.Partition {
+				partition.Offset = kafka.Offset(offset.Offset)
+				break
+			}
+		}
+		result = append(result, partition)
+	}
+	return result
+}
+
+// toPositions converts the topic partitions of the confluent client to the positions owned by the consumer
+func (c *GenericConsumer) toPositions(topicPartitions []kafka.TopicPartition) []transport.EventPosition {
+	positions := make([]transport.EventPosition, 0, len(topicPartitions))
+	for _, topicPartition := range topicPartitions {
+		if topicPartition.Topic == nil {
+			continue
+		}
+		positions = append(positions, transport.EventPosition{
+			OwnerIdentity: c.sourceCluster,
+			Topic:         *topicPartition.Topic,
+			Partition:     topicPartition.Partition,
+		})
+	}
+	return positions
+}
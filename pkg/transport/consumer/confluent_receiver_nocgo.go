@@ -0,0 +1,23 @@
+//go:build !cgo
+
+package consumer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// KafkaConsumer is the placeholder of the confluent kafka consumer, it's always nil without cgo
+type KafkaConsumer struct{}
+
+func (c *GenericConsumer) getConfluentReceiverProtocol(transportConfig *transport.TransportInternalConfig,
+	topics []string,
+) (*KafkaConsumer, interface{}, error) {
+	return nil, nil, errors.New("the confluent kafka client requires cgo, use the sarama kafka client instead")
+}
+
+func withConfluentOffsets(ctx context.Context, offsets []transport.EventPosition) context.Context {
+	return ctx
+}
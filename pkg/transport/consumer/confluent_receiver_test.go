@@ -0,0 +1,53 @@
+//go:build cgo
+
+package consumer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestGenerateConsumer(t *testing.T) {
+	mockKafkaCluster, err := kafka.NewMockCluster(1)
+	if err != nil {
+		t.Errorf("failed to init mock kafka cluster - %v", err)
+	}
+	transportConfig := &transport.TransportInternalConfig{
+		TransportType: "kafka",
+		KafkaCredential: &transport.KafkaConfig{
+			BootstrapServer: mockKafkaCluster.BootstrapServers(),
+			SpecTopic:       "test-topic",
+			ConsumerGroupID: "test-consumer",
+		},
+	}
+	options := []GenericConsumeOption{}
+	// set consumerTopics to status or spec topic based on running in manager or not
+	options = append(options, SetTopicMetadataRefreshInterval(constants.TopicMetadataRefreshInterval))
+
+	_, err = NewGenericConsumer(transportConfig, []string{transportConfig.KafkaCredential.SpecTopic}, options...)
+	if err != nil && !strings.Contains(err.Error(), "client has run out of available brokers") {
+		t.Errorf("failed to generate consumer - %v", err)
+	}
+	// cannot get the kafka.ConfigMap from a Kafka consumer after it's created
+	// The confluent-kafka-go library doesn't expose the configuration used to create the consumer.
+}
+
+func TestWithStoredOffsets(t *testing.T) {
+	hub1, hub2 := "gh-status.hub1", "gh-status.hub2"
+
+	// the assigned partitions start from the stored offsets
+	partitions := withStoredOffsets(
+		[]kafka.TopicPartition{{Topic: &hub1, Partition: 0, Offset: kafka.OffsetStored}, {Topic: &hub2, Partition: 1}},
+		[]transport.EventPosition{{Topic: hub1, Partition: 0, Offset: 12}, {Topic: hub2, Partition: 0, Offset: 8}})
+	assert.Equal(t, kafka.Offset(12), partitions[0].Offset)
+	assert.Equal(t, kafka.Offset(0), partitions[1].Offset)
+
+	c := &GenericConsumer{sourceCluster: "cluster1"}
+	assert.Equal(t, []transport.EventPosition{{OwnerIdentity: "cluster1", Topic: hub1}}, c.toPositions(partitions[:1]))
+}
@@ -7,23 +7,24 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
-	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/client"
 	cectx "github.com/cloudevents/sdk-go/v2/context"
 	ceprotocol "github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
-	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
-	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
 )
 
 var transportID string
@@ -33,11 +34,14 @@ type GenericConsumer struct {
 	eventChan            chan *cloudevents.Event
 	enableDatabaseOffset bool
 	clusterID            string
+	// sourceCluster is the identity of the kafka cluster set to the received events, and it's also used to read the
+	// stored offsets of the cluster instead of the cluster id, it's set when consuming from multiple kafka clusters
+	sourceCluster string
 
 	consumerCtx    context.Context
 	consumerCancel context.CancelFunc
 	client         cloudevents.Client
-	kafkaConsumer  *kafka.Consumer
+	kafkaConsumer  *KafkaConsumer
 
 	mutex sync.Mutex
 
@@ -47,6 +51,13 @@ type GenericConsumer struct {
 	// respond to topic changes for the global hub manager only.
 	// the global hub manager consumes from topics created dynamically when importing the managed cluster.
 	topicMetadataRefreshInterval int
+
+	// partitionSharding shares the partitions with the other members of the consumer group, the stored offsets are
+	// applied when the partitions are assigned instead of the start, and the listener is notified of the ownership
+	partitionSharding  bool
+	rebalanceMutex     sync.Mutex
+	rebalanceListener  transport.RebalanceListener
+	assignedPartitions map[string]transport.EventPosition
 }
 
 type GenericConsumeOption func(*GenericConsumer) error
@@ -65,6 +76,32 @@ func SetTopicMetadataRefreshInterval(interval int) GenericConsumeOption {
 	}
 }
 
+// SetChunkAssemblerLimits overrides the ttl of the incomplete chunk collection and the memory limit of the buffered
+// chunks in the assembler, the zero value keeps the default
+func SetChunkAssemblerLimits(ttl time.Duration, memoryLimit int) GenericConsumeOption {
+	return func(c *GenericConsumer) error {
+		if ttl < 0 || memoryLimit < 0 {
+			return fmt.Errorf("invalid chunk assembler limits: ttl(%s), memory limit(%d)", ttl, memoryLimit)
+		}
+		if ttl > 0 {
+			c.assembler.ttl = ttl
+		}
+		if memoryLimit > 0 {
+			c.assembler.memoryLimit = memoryLimit
+		}
+		return nil
+	}
+}
+
+// withSourceCluster delivers the received events into the eventChan with the identity of the kafka cluster
+func withSourceCluster(identity string, eventChan chan *cloudevents.Event) GenericConsumeOption {
+	return func(c *GenericConsumer) error {
+		c.sourceCluster = identity
+		c.eventChan = eventChan
+		return nil
+	}
+}
+
 func NewGenericConsumer(tranConfig *transport.TransportInternalConfig, topics []string,
 	opts ...GenericConsumeOption,
 ) (*GenericConsumer, error) {
@@ -72,6 +109,8 @@ func NewGenericConsumer(tranConfig *transport.TransportInternalConfig, topics []
 		eventChan:            make(chan *cloudevents.Event),
 		assembler:            newMessageAssembler(),
 		enableDatabaseOffset: tranConfig.EnableDatabaseOffset,
+		partitionSharding:    tranConfig.StatusSharding,
+		assignedPartitions:   map[string]transport.EventPosition{},
 	}
 	// Apply options BEFORE initializing client
 	if err := c.applyOptions(opts...); err != nil {
@@ -83,7 +122,7 @@ func NewGenericConsumer(tranConfig *transport.TransportInternalConfig, topics []
 	return c, nil
 }
 
-func (c *GenericConsumer) KafkaConsumer() *kafka.Consumer {
+func (c *GenericConsumer) KafkaConsumer() *KafkaConsumer {
 	return c.kafkaConsumer
 }
 
@@ -92,13 +131,30 @@ func (c *GenericConsumer) initClient(tranConfig *transport.TransportInternalConf
 	var err error
 	var clientProtocol interface{}
 
-	c.clusterID = tranConfig.KafkaCredential.ClusterID
+	if tranConfig.KafkaCredential != nil {
+		c.clusterID = tranConfig.KafkaCredential.ClusterID
+	}
+	if c.sourceCluster != "" {
+		c.clusterID = c.sourceCluster
+	}
 
 	switch tranConfig.TransportType {
 	case string(transport.Kafka):
+		if err := config.ValidateKafkaClientType(tranConfig.KafkaClientType); err != nil {
+			return err
+		}
+		if tranConfig.KafkaClientType == transport.SaramaKafkaClient {
+			log.Info("transport consumer with sarama receiver")
+			c.kafkaConsumer = nil
+			clientProtocol, err = getSaramaReceiverProtocol(tranConfig, topics, c.topicMetadataRefreshInterval,
+				c.enableDatabaseOffset)
+			if err != nil {
+				return err
+			}
+			break
+		}
 		log.Info("transport consumer with cloudevents-kafka receiver")
-		c.kafkaConsumer, clientProtocol, err = getConfluentReceiverProtocol(tranConfig,
-			topics, c.topicMetadataRefreshInterval)
+		c.kafkaConsumer, clientProtocol, err = c.getConfluentReceiverProtocol(tranConfig, topics)
 		if err != nil {
 			return err
 		}
@@ -112,6 +168,23 @@ func (c *GenericConsumer) initClient(tranConfig *transport.TransportInternalConf
 			tranConfig.Extends[topic] = gochan.New()
 		}
 		clientProtocol = tranConfig.Extends[topic]
+	case string(transport.Rest):
+		// the manager receives the events posted to the local broker, and the agent long polls the manager
+		if broker, ok := tranConfig.Extends[rest.BrokerKey].(*rest.Broker); ok {
+			log.Info("transport consumer with rest broker receiver")
+			clientProtocol = rest.NewBrokerReceiver(broker, topics)
+			break
+		}
+		if tranConfig.RestfulCredential == nil {
+			return fmt.Errorf("the restful credential must not be nil for the rest transport")
+		}
+		log.Info("transport consumer with rest polling receiver")
+		httpClient, err := rest.NewHTTPClient(tranConfig.RestfulCredential)
+		if err != nil {
+			return err
+		}
+		clientProtocol = rest.NewPollingReceiver(httpClient, tranConfig.RestfulCredential.Host,
+			tranConfig.RestfulCredential.Hub, topics[0])
 	default:
 		return fmt.Errorf("transport-type - %s is not a valid option", tranConfig.TransportType)
 	}
@@ -149,7 +222,10 @@ func (c *GenericConsumer) Reconnect(ctx context.Context,
 		c.consumerCancel()
 	}
 	c.consumerCtx, c.consumerCancel = context.WithCancel(ctx)
-	consumerGroupId := tranConfig.KafkaCredential.ConsumerGroupID
+	consumerGroupId := ""
+	if tranConfig.KafkaCredential != nil {
+		consumerGroupId = tranConfig.KafkaCredential.ConsumerGroupID
+	}
 	go func() {
 		log.Infof("reconnect consumer: %s", consumerGroupId)
 		if err := c.Start(c.consumerCtx); err != nil {
@@ -162,32 +238,43 @@ func (c *GenericConsumer) Reconnect(ctx context.Context,
 
 func (c *GenericConsumer) Start(ctx context.Context) error {
 	receiveContext := cectx.WithLogger(ctx, logger.ZapLogger("cloudevents"))
-	if c.enableDatabaseOffset {
+	// the stored offsets of the sharded consumer are applied to the assigned partitions on rebalancing
+	if c.enableDatabaseOffset && !c.partitionSharding {
 		offsets, err := getInitOffset(c.clusterID)
 		if err != nil {
 			return err
 		}
 		log.Infow("init consumer", "offsets", offsets)
 		if len(offsets) > 0 {
-			receiveContext = kafka_confluent.WithTopicPartitionOffsets(ctx, offsets)
+			receiveContext = withInitOffsets(receiveContext, offsets)
 		}
 	}
 
 	c.consumerCtx, c.consumerCancel = context.WithCancel(receiveContext)
+	// the expired collections are also evicted when no chunk is received
+	go c.assembler.evictPeriodically(c.consumerCtx)
 	err := c.client.StartReceiver(c.consumerCtx, func(ctx context.Context, event cloudevents.Event) ceprotocol.Result {
 		log.Debugw("received message", "event.Source", event.Source(), "event.Type",
 			enum.ShortenEventType(event.Type()))
 
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
-			c.eventChan <- &event
+			if err := decompressEvent(&event); err != nil {
+				log.Errorw("failed to decompress the event", "type", event.Type(), "error", err)
+				return ceprotocol.ResultACK
+			}
+			c.deliver(&event)
 			return ceprotocol.ResultACK
 		}
 		if payload := c.assembler.assemble(chunk); payload != nil {
+			// the checksum of the assembled payload has been verified by the assembler
+			event.SetExtension(transport.ChecksumKey, nil)
 			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
 				log.Errorw("failed the set the assembled data to event", "error", err)
+			} else if err := decompressEvent(&event); err != nil {
+				log.Errorw("failed to decompress the assembled event", "type", event.Type(), "error", err)
 			} else {
-				c.eventChan <- &event
+				c.deliver(&event)
 			}
 		}
 		return ceprotocol.ResultACK
@@ -198,11 +285,83 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	return nil
 }
 
+func (c *GenericConsumer) deliver(evt *cloudevents.Event) {
+	if c.sourceCluster != "" {
+		evt.SetExtension(transport.KafkaClusterKey, c.sourceCluster)
+	}
+	c.eventChan <- evt
+}
+
+// decompressEvent restores the original payload of the event if it's compressed by the producer, and then removes
+// the compression extension so that the handlers receive the same event as the one before sending.
+func decompressEvent(evt *cloudevents.Event) error {
+	compressionType, err := evt.Context.GetExtension(transport.CompressionKey)
+	if err != nil {
+		// the event isn't compressed
+		return nil
+	}
+	payloadCompressor, err := compressor.NewCompressor(compressor.CompressionType(fmt.Sprintf("%v", compressionType)))
+	if err != nil {
+		return fmt.Errorf("compression-type - %v: %w", compressionType, err)
+	}
+	payload, err := payloadCompressor.Decompress(evt.Data())
+	if err != nil {
+		return err
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return err
+	}
+	evt.SetExtension(transport.CompressionKey, nil)
+	return nil
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
 
-func getInitOffset(kafkaClusterIdentity string) ([]kafka.TopicPartition, error) {
+// SetRebalanceListener implements the transport.PartitionOwner, the listener is notified of the partitions which are
+// already assigned, since the consumer might be started before the listener is set
+func (c *GenericConsumer) SetRebalanceListener(listener transport.RebalanceListener) {
+	c.rebalanceMutex.Lock()
+	defer c.rebalanceMutex.Unlock()
+	c.rebalanceListener = listener
+	if listener == nil || len(c.assignedPartitions) == 0 {
+		return
+	}
+	partitions := make([]transport.EventPosition, 0, len(c.assignedPartitions))
+	for _, partition := range c.assignedPartitions {
+		partitions = append(partitions, partition)
+	}
+	listener.OnPartitionsAssigned(partitions)
+}
+
+// notifyRebalance records the assigned partitions, and notifies the listener of the ownership
+func (c *GenericConsumer) notifyRebalance(partitions []transport.EventPosition, assigned bool) {
+	c.rebalanceMutex.Lock()
+	defer c.rebalanceMutex.Unlock()
+
+	for _, partition := range partitions {
+		key := fmt.Sprintf("%s@%d", partition.Topic, partition.Partition)
+		if assigned {
+			c.assignedPartitions[key] = partition
+		} else {
+			delete(c.assignedPartitions, key)
+		}
+	}
+	if c.rebalanceListener == nil {
+		return
+	}
+	if assigned {
+		c.rebalanceListener.OnPartitionsAssigned(partitions)
+	} else {
+		c.rebalanceListener.OnPartitionsRevoked(partitions)
+	}
+}
+
+// getInitOffset returns the stored offsets of the kafka cluster, the position of the additional cluster is stored
+// with the name "<topic>[:<partition>]@<cluster identity>" so that the same topic of the clusters doesn't overwrite
+// each other, the topic is read from the payload and falls back to the name
+func getInitOffset(kafkaClusterIdentity string) ([]transport.EventPosition, error) {
 	db := database.GetGorm()
 	var positions []models.Transport
 	err := db.Where("name ~ ?", "^status*").
@@ -211,59 +370,34 @@ func getInitOffset(kafkaClusterIdentity string) ([]kafka.TopicPartition, error)
 	if err != nil {
 		return nil, err
 	}
-	offsetToStart := []kafka.TopicPartition{}
-	for i, pos := range positions {
+	offsetToStart := []transport.EventPosition{}
+	for _, pos := range positions {
 		var kafkaPosition transport.EventPosition
 		err := json.Unmarshal(pos.Payload, &kafkaPosition)
 		if err != nil {
 			return nil, err
 		}
-		offsetToStart = append(offsetToStart, kafka.TopicPartition{
-			Topic:     &positions[i].Name,
-			Partition: kafkaPosition.Partition,
-			Offset:    kafka.Offset(kafkaPosition.Offset),
-		})
+		if kafkaPosition.Topic == "" {
+			kafkaPosition.Topic = strings.TrimSuffix(pos.Name, "@"+kafkaClusterIdentity)
+		}
+		offsetToStart = append(offsetToStart, kafkaPosition)
 	}
 	return offsetToStart, nil
 }
 
-// func getSaramaReceiverProtocol(transportConfig *transport.TransportConfig) (interface{}, error) {
-// 	saramaConfig, err := config.GetSaramaConfig(transportConfig.KafkaConfig)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	// if set this to false, it will consume message from beginning when restart the client
-// 	saramaConfig.Consumer.Offsets.AutoCommit.Enable = true
-// 	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
-// 	// set the consumer groupId = clientId
-// 	return kafka_sarama.NewConsumer([]string{transportConfig.KafkaConfig.BootstrapServer}, saramaConfig,
-// 		transportConfig.KafkaConfig.ConsumerConfig.ConsumerID,
-// 		transportConfig.KafkaConfig.ConsumerConfig.ConsumerTopic)
-// }
-
-func getConfluentReceiverProtocol(transportConfig *transport.TransportInternalConfig,
-	topics []string, topicMetadataRefreshInterval int) (
-	*kafka.Consumer, interface{}, error,
-) {
-	configMap, err := config.GetConfluentConfigMapByKafkaCredential(transportConfig.KafkaCredential,
-		transportConfig.KafkaCredential.ConsumerGroupID, topicMetadataRefreshInterval)
-	if err != nil {
-		return nil, nil, err
-	}
-	log.Debugw("the configurations applied to the Kafka consumer", "configMap",
-		utils.FilterSensitiveKafkaConfig(configMap))
+type initOffsetsKey struct{}
 
-	consumer, err := kafka.NewConsumer(configMap)
-	if err != nil {
-		return nil, nil, err
-	}
+// withInitOffsets returns the context with the stored offsets to start the receiver, the sarama receiver reads them
+// with the initOffsetsFrom, and the confluent receiver reads them from the kafka_confluent.WithTopicPartitionOffsets
+func withInitOffsets(ctx context.Context, offsets []transport.EventPosition) context.Context {
+	return withConfluentOffsets(context.WithValue(ctx, initOffsetsKey{}, offsets), offsets)
+}
 
-	protocol, err := kafka_confluent.New(kafka_confluent.WithReceiver(consumer),
-		kafka_confluent.WithReceiverTopics(topics))
-	if err != nil {
-		return nil, nil, err
+func initOffsetsFrom(ctx context.Context) []transport.EventPosition {
+	if offsets, ok := ctx.Value(initOffsetsKey{}).([]transport.EventPosition); ok {
+		return offsets
 	}
-	return consumer, protocol, nil
+	return nil
 }
 
 func TransportID() string {
@@ -3,45 +3,17 @@ package consumer
 import (
 	"encoding/json"
 	"fmt"
-	"strings"
 	"testing"
 
-	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm/clause"
 
-	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/test/integration/utils/testpostgres"
 )
 
-func TestGenerateConsumer(t *testing.T) {
-	mockKafkaCluster, err := kafka.NewMockCluster(1)
-	if err != nil {
-		t.Errorf("failed to init mock kafka cluster - %v", err)
-	}
-	transportConfig := &transport.TransportInternalConfig{
-		TransportType: "kafka",
-		KafkaCredential: &transport.KafkaConfig{
-			BootstrapServer: mockKafkaCluster.BootstrapServers(),
-			SpecTopic:       "test-topic",
-			ConsumerGroupID: "test-consumer",
-		},
-	}
-	options := []GenericConsumeOption{}
-	// set consumerTopics to status or spec topic based on running in manager or not
-	options = append(options, SetTopicMetadataRefreshInterval(constants.TopicMetadataRefreshInterval))
-
-	_, err = NewGenericConsumer(transportConfig, []string{transportConfig.KafkaCredential.SpecTopic}, options...)
-	if err != nil && !strings.Contains(err.Error(), "client has run out of available brokers") {
-		t.Errorf("failed to generate consumer - %v", err)
-	}
-	// cannot get the kafka.ConfigMap from a Kafka consumer after it's created
-	// The confluent-kafka-go library doesn't expose the configuration used to create the consumer.
-}
-
 func TestGetInitOffset(t *testing.T) {
 	testPostgres, err := testpostgres.NewTestPostgres()
 	assert.Nil(t, err)
@@ -68,8 +40,8 @@ func TestGetInitOffset(t *testing.T) {
 
 	count := 0
 	for _, offset := range offsets {
-		fmt.Println(*offset.Topic, offset.Partition, offset.Offset)
-		if *offset.Topic == "spec" {
+		fmt.Println(offset.Topic, offset.Partition, offset.Offset)
+		if offset.Topic == "spec" {
 			t.Fatalf("the topic %s shouldn't be selected", "spec")
 		}
 		count++
@@ -77,6 +49,41 @@ func TestGetInitOffset(t *testing.T) {
 	assert.Equal(t, 3, count)
 }
 
+type partitionRecorder struct {
+	assigned []transport.EventPosition
+	revoked  []transport.EventPosition
+}
+
+func (r *partitionRecorder) OnPartitionsAssigned(partitions []transport.EventPosition) {
+	r.assigned = append(r.assigned, partitions...)
+}
+
+func (r *partitionRecorder) OnPartitionsRevoked(partitions []transport.EventPosition) {
+	r.revoked = append(r.revoked, partitions...)
+}
+
+func TestRebalanceListener(t *testing.T) {
+	hub1, hub2 := "gh-status.hub1", "gh-status.hub2"
+	c := &GenericConsumer{sourceCluster: "cluster1", assignedPartitions: map[string]transport.EventPosition{}}
+
+	// the partitions assigned before the listener is set are notified once it's set
+	c.notifyRebalance([]transport.EventPosition{
+		{OwnerIdentity: "cluster1", Topic: hub1},
+		{OwnerIdentity: "cluster1", Topic: hub2},
+	}, true)
+	recorder := &partitionRecorder{}
+	c.SetRebalanceListener(recorder)
+	assert.ElementsMatch(t, []transport.EventPosition{
+		{OwnerIdentity: "cluster1", Topic: hub1},
+		{OwnerIdentity: "cluster1", Topic: hub2},
+	}, recorder.assigned)
+
+	c.notifyRebalance([]transport.EventPosition{{OwnerIdentity: "cluster1", Topic: hub1}}, false)
+	assert.Equal(t, []transport.EventPosition{{OwnerIdentity: "cluster1", Topic: hub1}}, recorder.revoked)
+	assert.Len(t, c.assignedPartitions, 1)
+
+}
+
 func generateTransport(ownerIdentity string, topic string, offset int64) models.Transport {
 	payload, _ := json.Marshal(transport.EventPosition{
 		OwnerIdentity: ownerIdentity,
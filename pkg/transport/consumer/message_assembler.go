@@ -5,8 +5,11 @@ package consumer
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/types"
@@ -14,49 +17,71 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
+)
+
+const (
+	// DefaultChunkCollectionTTL is the max duration to wait for the next chunk of an incomplete collection
+	DefaultChunkCollectionTTL = 10 * time.Minute
+	// DefaultAssemblerMemoryLimit is the max size of the chunks buffered by the assembler
+	DefaultAssemblerMemoryLimit = 256 * 1024 * 1024
 )
 
 // messageChunk represents a chunk of a transport message.
 type messageChunk struct {
-	id     string
-	offset int
-	size   int
-	bytes  []byte
+	id       string
+	offset   int
+	size     int
+	checksum string
+	bytes    []byte
 }
 
 // messageChunksCollection holds a collection of chunks and maintains it until completion.
 type messageChunksCollection struct {
 	id              string
 	totalSize       int
+	checksum        string
 	accumulatedSize int
 	chunks          map[int]*messageChunk
 	orderedOffsets  []int
+	lastUpdateTime  time.Time
 	lock            sync.Mutex
 }
 
-func newMessageChunksCollection(id string, size int) *messageChunksCollection {
+func newMessageChunksCollection(chunk *messageChunk) *messageChunksCollection {
 	return &messageChunksCollection{
-		id:              id,
-		totalSize:       size,
+		id:              chunk.id,
+		totalSize:       chunk.size,
+		checksum:        chunk.checksum,
 		accumulatedSize: 0,
 		chunks:          make(map[int]*messageChunk),
 		orderedOffsets:  make([]int, 0),
+		lastUpdateTime:  time.Now(),
 		lock:            sync.Mutex{},
 	}
 }
 
-func (collection *messageChunksCollection) add(chunk *messageChunk) {
+// add appends the chunk into the collection and returns the size of the added bytes
+func (collection *messageChunksCollection) add(chunk *messageChunk) int {
 	collection.lock.Lock()
 	defer collection.lock.Unlock()
 
 	// don't add chunk to collection, if already exists.
 	if _, found := collection.chunks[chunk.offset]; found {
-		return
+		return 0
 	}
 
 	collection.chunks[chunk.offset] = chunk
 	collection.orderedOffsets = append(collection.orderedOffsets, chunk.offset)
 	collection.accumulatedSize += len(chunk.bytes)
+	collection.lastUpdateTime = time.Now()
+	return len(chunk.bytes)
+}
+
+// belongsTo returns false if the chunk is from another payload with the same id, e.g. the producer restarts and
+// resends a different bundle
+func (collection *messageChunksCollection) belongsTo(chunk *messageChunk) bool {
+	return collection.totalSize == chunk.size && collection.checksum == chunk.checksum
 }
 
 func (collection *messageChunksCollection) collect() ([]byte, error) {
@@ -72,13 +97,25 @@ func (collection *messageChunksCollection) collect() ([]byte, error) {
 		}
 		collection.chunks[offset].bytes = nil // faster GC
 	}
+	// the checksum is missing if the chunks are sent by the producer of the previous release
+	if collection.checksum != "" {
+		if checksum := utils.PayloadChecksum(buffer.Bytes()); checksum != collection.checksum {
+			return nil, fmt.Errorf("checksum mismatch: expected %s, but got %s", collection.checksum, checksum)
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
+// messageAssembler buffers the chunks until the collection is completed. To avoid pinning the memory by the lost or
+// duplicated chunk streams, the collection is evicted once it isn't updated within the ttl, and the least recently
+// updated collections are evicted once the buffered size exceeds the memory limit.
 type messageAssembler struct {
 	log                *zap.SugaredLogger
 	lock               sync.Mutex
 	chunkCollectionMap map[string]*messageChunksCollection
+	ttl                time.Duration
+	memoryLimit        int
+	bufferedSize       int
 }
 
 func newMessageAssembler() *messageAssembler {
@@ -86,6 +123,8 @@ func newMessageAssembler() *messageAssembler {
 		log:                logger.DefaultZapLogger(),
 		lock:               sync.Mutex{},
 		chunkCollectionMap: make(map[string]*messageChunksCollection),
+		ttl:                DefaultChunkCollectionTTL,
+		memoryLimit:        DefaultAssemblerMemoryLimit,
 	}
 }
 
@@ -93,22 +132,38 @@ func newMessageAssembler() *messageAssembler {
 func (assembler *messageAssembler) assemble(chunk *messageChunk) []byte {
 	assembler.lock.Lock()
 	defer assembler.lock.Unlock()
+	defer func() { chunkBufferedBytesGauge.Set(float64(assembler.bufferedSize)) }()
+
+	assembler.evictExpired()
+
+	if chunk.size > assembler.memoryLimit {
+		assembler.log.Warnw("drop the chunk since the payload exceeds the memory limit", "id", chunk.id,
+			"size", chunk.size, "limit", assembler.memoryLimit)
+		chunkCollectionEvictedCounter.WithLabelValues(evictReasonOversized).Inc()
+		return nil
+	}
 
 	chunkCollection, found := assembler.chunkCollectionMap[chunk.id] // chunk.id: PlacementRule
+	if found && !chunkCollection.belongsTo(chunk) {
+		assembler.evict(chunkCollection, evictReasonSuperseded)
+		found = false
+	}
 	if !found {
-		chunkCollection = newMessageChunksCollection(chunk.id, chunk.size)
+		chunkCollection = newMessageChunksCollection(chunk)
 		assembler.chunkCollectionMap[chunk.id] = chunkCollection
 	}
 
-	chunkCollection.add(chunk)
+	assembler.bufferedSize += chunkCollection.add(chunk)
 
 	if chunkCollection.totalSize <= chunkCollection.accumulatedSize {
 		// delete collection from map
-		defer delete(assembler.chunkCollectionMap, chunkCollection.id)
+		delete(assembler.chunkCollectionMap, chunkCollection.id)
+		assembler.bufferedSize -= chunkCollection.accumulatedSize
 
 		transportPayloadBytes, err := chunkCollection.collect()
 		if err != nil {
-			assembler.log.Error(err, "assemble event data failed")
+			assembler.log.Errorw("assemble event data failed", "id", chunkCollection.id, "error", err)
+			chunkCollectionEvictedCounter.WithLabelValues(evictReasonChecksumMismatch).Inc()
 			return nil
 		}
 		assembler.log.Debugw("assemble event data success!", "id", chunkCollection.id,
@@ -116,9 +171,61 @@ func (assembler *messageAssembler) assemble(chunk *messageChunk) []byte {
 		return transportPayloadBytes
 	}
 
+	assembler.evictOverLimit()
 	return nil
 }
 
+// evictPeriodically evicts the expired collections every half of the ttl until the context is done, so that the
+// chunks of the stopped streams aren't pinned until the next chunk is received
+func (assembler *messageAssembler) evictPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(max(assembler.ttl/2, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			assembler.lock.Lock()
+			assembler.evictExpired()
+			chunkBufferedBytesGauge.Set(float64(assembler.bufferedSize))
+			assembler.lock.Unlock()
+		}
+	}
+}
+
+// evictExpired removes the collections which aren't updated within the ttl
+func (assembler *messageAssembler) evictExpired() {
+	for _, collection := range assembler.chunkCollectionMap {
+		if time.Since(collection.lastUpdateTime) > assembler.ttl {
+			assembler.evict(collection, evictReasonExpired)
+		}
+	}
+}
+
+// evictOverLimit removes the least recently updated collections until the buffered size is under the memory limit
+func (assembler *messageAssembler) evictOverLimit() {
+	for assembler.bufferedSize > assembler.memoryLimit {
+		var oldest *messageChunksCollection
+		for _, collection := range assembler.chunkCollectionMap {
+			if oldest == nil || collection.lastUpdateTime.Before(oldest.lastUpdateTime) {
+				oldest = collection
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		assembler.evict(oldest, evictReasonMemoryLimit)
+	}
+}
+
+func (assembler *messageAssembler) evict(collection *messageChunksCollection, reason string) {
+	delete(assembler.chunkCollectionMap, collection.id)
+	assembler.bufferedSize -= collection.accumulatedSize
+	chunkCollectionEvictedCounter.WithLabelValues(reason).Inc()
+	assembler.log.Warnw("evict the incomplete chunk collection", "id", collection.id, "reason", reason,
+		"size", collection.totalSize, "accumulatedSize", collection.accumulatedSize)
+}
+
 func (assembler *messageAssembler) messageChunk(e cloudevents.Event) (*messageChunk, bool) {
 	offset, err := types.ToInteger(e.Extensions()[transport.ChunkOffsetKey])
 	if err != nil {
@@ -130,10 +237,16 @@ func (assembler *messageAssembler) messageChunk(e cloudevents.Event) (*messageCh
 		return nil, false
 	}
 
+	checksum := ""
+	if val, found := e.Extensions()[transport.ChecksumKey]; found {
+		checksum = fmt.Sprintf("%v", val)
+	}
+
 	return &messageChunk{
-		id:     e.ID(),
-		offset: int(offset),
-		size:   int(size),
-		bytes:  e.Data(),
+		id:       e.ID(),
+		offset:   int(offset),
+		size:     int(size),
+		checksum: checksum,
+		bytes:    e.Data(),
 	}, true
 }
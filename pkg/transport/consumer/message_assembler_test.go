@@ -0,0 +1,121 @@
+package consumer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
+)
+
+func splitChunks(id string, payload []byte, limit int) []*messageChunk {
+	chunks := []*messageChunk{}
+	checksum := utils.PayloadChecksum(payload)
+	for offset := 0; offset < len(payload); offset += limit {
+		end := min(offset+limit, len(payload))
+		chunks = append(chunks, &messageChunk{
+			id:       id,
+			offset:   end,
+			size:     len(payload),
+			checksum: checksum,
+			bytes:    payload[offset:end],
+		})
+	}
+	return chunks
+}
+
+func TestMessageAssembler(t *testing.T) {
+	payload := []byte("hello, multicluster global hub!")
+
+	t.Run("assemble the out of order and duplicated chunks", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		chunks := splitChunks("1", payload, 5)
+		for i := len(chunks) - 1; i > 0; i-- {
+			assert.Nil(t, assembler.assemble(chunks[i]))
+			assert.Nil(t, assembler.assemble(chunks[i]))
+		}
+		assert.Equal(t, payload, assembler.assemble(chunks[0]))
+		assert.Empty(t, assembler.chunkCollectionMap)
+		assert.Equal(t, 0, assembler.bufferedSize)
+	})
+
+	t.Run("reject the corrupted payload", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		chunks := splitChunks("1", payload, 5)
+		chunks[1].bytes = []byte("HELLO")
+		var assembled []byte
+		for _, chunk := range chunks {
+			assembled = assembler.assemble(chunk)
+		}
+		assert.Nil(t, assembled)
+		assert.Empty(t, assembler.chunkCollectionMap)
+		assert.Equal(t, 0, assembler.bufferedSize)
+	})
+
+	t.Run("evict the expired collection", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		assembler.ttl = 10 * time.Millisecond
+		expired := splitChunks("1", payload, 5)
+		assert.Nil(t, assembler.assemble(expired[0]))
+		time.Sleep(20 * time.Millisecond)
+
+		chunks := splitChunks("2", payload, 5)
+		assert.Nil(t, assembler.assemble(chunks[0]))
+		assert.NotContains(t, assembler.chunkCollectionMap, "1")
+		assert.Equal(t, len(chunks[0].bytes), assembler.bufferedSize)
+	})
+
+	t.Run("evict the expired collection without receiving the next chunk", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		assembler.ttl = 10 * time.Millisecond
+		assert.Nil(t, assembler.assemble(splitChunks("1", payload, 5)[0]))
+		go assembler.evictPeriodically(t.Context())
+
+		assert.Eventually(t, func() bool {
+			assembler.lock.Lock()
+			defer assembler.lock.Unlock()
+			return len(assembler.chunkCollectionMap) == 0 && assembler.bufferedSize == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("override the limits by the consumer option", func(t *testing.T) {
+		c := &GenericConsumer{assembler: newMessageAssembler()}
+		assert.NoError(t, c.applyOptions(SetChunkAssemblerLimits(time.Minute, 0)))
+		assert.Equal(t, time.Minute, c.assembler.ttl)
+		assert.Equal(t, DefaultAssemblerMemoryLimit, c.assembler.memoryLimit)
+		assert.Error(t, c.applyOptions(SetChunkAssemblerLimits(-time.Minute, 1024)))
+	})
+
+	t.Run("evict the least recently updated collection over the memory limit", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		assembler.memoryLimit = len(payload)
+		first := splitChunks("1", payload, 20)
+		second := splitChunks("2", payload, 20)
+		assert.Nil(t, assembler.assemble(first[0]))
+		time.Sleep(time.Millisecond)
+		assert.Nil(t, assembler.assemble(second[0]))
+		assert.NotContains(t, assembler.chunkCollectionMap, "1")
+		assert.Equal(t, payload, assembler.assemble(second[1]))
+	})
+
+	t.Run("drop the chunks of the superseded payload", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		stale := splitChunks("1", []byte("the payload before the producer restarted"), 5)
+		assert.Nil(t, assembler.assemble(stale[0]))
+
+		var assembled []byte
+		for _, chunk := range splitChunks("1", payload, 5) {
+			assembled = assembler.assemble(chunk)
+		}
+		assert.Equal(t, payload, assembled)
+		assert.Equal(t, 0, assembler.bufferedSize)
+	})
+
+	t.Run("drop the oversized payload", func(t *testing.T) {
+		assembler := newMessageAssembler()
+		assembler.memoryLimit = len(payload) - 1
+		assert.Nil(t, assembler.assemble(splitChunks("1", payload, 5)[0]))
+		assert.Empty(t, assembler.chunkCollectionMap)
+	})
+}
@@ -0,0 +1,41 @@
+package consumer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// evictReasonExpired means the chunks of the collection don't arrive completely within the ttl
+	evictReasonExpired = "expired"
+	// evictReasonMemoryLimit means the collection is evicted to keep the buffered chunks under the memory limit
+	evictReasonMemoryLimit = "memory_limit"
+	// evictReasonSuperseded means the chunk stream is restarted by the producer with another payload
+	evictReasonSuperseded = "superseded"
+	// evictReasonOversized means the payload is larger than the memory limit, so it's never buffered
+	evictReasonOversized = "oversized"
+	// evictReasonChecksumMismatch means the assembled payload doesn't match the checksum from the producer
+	evictReasonChecksumMismatch = "checksum_mismatch"
+)
+
+var chunkCollectionEvictedCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_chunk_collections_evicted_total",
+		Help: "The number of the chunk collections which are dropped before assembling into the event.",
+	},
+	[]string{
+		"reason", // The reason of the eviction: expired, memory_limit, superseded, oversized or checksum_mismatch.
+	},
+)
+
+var chunkBufferedBytesGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_chunk_buffered_bytes",
+		Help: "The size of the chunks which are buffered in memory and waiting for assembling.",
+	},
+)
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(chunkCollectionEvictedCounter, chunkBufferedBytesGauge)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const clusterConsumerRetryInterval = 10 * time.Second
+
+// MultiClusterConsumer consumes the topics from the primary and the additional kafka clusters with a generic consumer
+// per cluster. The events of the clusters are merged into one event channel, and each of them is set with the
+// transport.KafkaClusterKey extension, so that the offsets of the clusters are committed and restored separately.
+type MultiClusterConsumer struct {
+	eventChan chan *cloudevents.Event
+	opts      []GenericConsumeOption
+	listener  transport.RebalanceListener
+
+	mutex     sync.Mutex
+	ctx       context.Context
+	consumers map[string]*GenericConsumer
+	cancels   map[string]context.CancelFunc
+}
+
+func NewMultiClusterConsumer(tranConfig *transport.TransportInternalConfig, topics []string,
+	opts ...GenericConsumeOption,
+) (*MultiClusterConsumer, error) {
+	c := &MultiClusterConsumer{
+		eventChan: make(chan *cloudevents.Event),
+		opts:      opts,
+		consumers: map[string]*GenericConsumer{},
+		cancels:   map[string]context.CancelFunc{},
+	}
+	for identity, clusterConfig := range clusterConfigs(tranConfig) {
+		consumer, err := c.newClusterConsumer(identity, clusterConfig, topics)
+		if err != nil {
+			return nil, err
+		}
+		c.consumers[identity] = consumer
+	}
+	return c, nil
+}
+
+// clusterConfigs splits the transport config into the configs of each kafka cluster, which is keyed by the identity
+func clusterConfigs(tranConfig *transport.TransportInternalConfig) map[string]*transport.TransportInternalConfig {
+	configs := map[string]*transport.TransportInternalConfig{}
+	credentials := append([]*transport.KafkaConfig{tranConfig.KafkaCredential}, tranConfig.AdditionalKafkaCredentials...)
+	for _, credential := range credentials {
+		if credential == nil {
+			continue
+		}
+		clusterConfig := *tranConfig
+		clusterConfig.KafkaCredential = credential
+		clusterConfig.AdditionalKafkaCredentials = nil
+		configs[credential.Identity()] = &clusterConfig
+	}
+	return configs
+}
+
+func (c *MultiClusterConsumer) newClusterConsumer(identity string, tranConfig *transport.TransportInternalConfig,
+	topics []string,
+) (*GenericConsumer, error) {
+	opts := append([]GenericConsumeOption{}, c.opts...)
+	opts = append(opts, withSourceCluster(identity, c.eventChan))
+	consumer, err := NewGenericConsumer(tranConfig, topics, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the consumer for the kafka cluster %s: %w", identity, err)
+	}
+	if c.listener != nil {
+		consumer.SetRebalanceListener(c.listener)
+	}
+	return consumer, nil
+}
+
+// Start starts the consumers of the clusters and blocks until the context is done
+func (c *MultiClusterConsumer) Start(ctx context.Context) error {
+	c.mutex.Lock()
+	c.ctx = ctx
+	for identity, consumer := range c.consumers {
+		c.startClusterConsumer(identity, consumer)
+	}
+	c.mutex.Unlock()
+
+	<-ctx.Done()
+	return nil
+}
+
+// startClusterConsumer runs the consumer of the cluster until it's removed, the consumer is restarted if it's
+// stopped with error, so that a failed cluster doesn't block the others
+func (c *MultiClusterConsumer) startClusterConsumer(identity string, consumer *GenericConsumer) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.cancels[identity] = cancel
+	go func() {
+		for {
+			log.Infow("start the consumer of the kafka cluster", "cluster", identity)
+			err := consumer.Start(ctx)
+			if ctx.Err() != nil {
+				log.Infow("the consumer of the kafka cluster is stopped", "cluster", identity)
+				return
+			}
+			log.Warnw("the consumer of the kafka cluster is stopped with error, retrying", "cluster", identity,
+				"error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(clusterConsumerRetryInterval):
+			}
+		}
+	}()
+}
+
+// Reconnect reconnects the consumers of the existing clusters, starts the consumers of the added clusters and stops
+// the consumers of the removed clusters
+func (c *MultiClusterConsumer) Reconnect(ctx context.Context,
+	tranConfig *transport.TransportInternalConfig, topics []string,
+) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.ctx == nil {
+		c.ctx = ctx
+	}
+	configs := clusterConfigs(tranConfig)
+	for identity, cancel := range c.cancels {
+		if _, found := configs[identity]; !found {
+			log.Infow("stop the consumer of the removed kafka cluster", "cluster", identity)
+			cancel()
+			delete(c.cancels, identity)
+			delete(c.consumers, identity)
+		}
+	}
+
+	for identity, clusterConfig := range configs {
+		if consumer, found := c.consumers[identity]; found {
+			// stop the running consumer, and restart it with the new client in the retry loop
+			if cancel, found := c.cancels[identity]; found {
+				cancel()
+				delete(c.cancels, identity)
+			}
+			consumer.mutex.Lock()
+			err := consumer.initClient(clusterConfig, topics)
+			consumer.mutex.Unlock()
+			if err != nil {
+				return fmt.Errorf("failed to reconnect the consumer of the kafka cluster %s: %w", identity, err)
+			}
+			c.startClusterConsumer(identity, consumer)
+			continue
+		}
+		consumer, err := c.newClusterConsumer(identity, clusterConfig, topics)
+		if err != nil {
+			return err
+		}
+		c.consumers[identity] = consumer
+		c.startClusterConsumer(identity, consumer)
+	}
+	return nil
+}
+
+func (c *MultiClusterConsumer) EventChan() chan *cloudevents.Event {
+	return c.eventChan
+}
+
+// SetRebalanceListener sets the listener to the consumers of all the clusters, the partitions are distinguished by the
+// identity of the kafka cluster
+func (c *MultiClusterConsumer) SetRebalanceListener(listener transport.RebalanceListener) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.listener = listener
+	for _, consumer := range c.consumers {
+		consumer.SetRebalanceListener(listener)
+	}
+}
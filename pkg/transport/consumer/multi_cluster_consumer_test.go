@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestMultiClusterConsumer(t *testing.T) {
+	tranConfig := &transport.TransportInternalConfig{
+		TransportType:   string(transport.Chan),
+		KafkaCredential: &transport.KafkaConfig{ClusterID: "cluster1", BootstrapServer: "kafka1:9092"},
+		AdditionalKafkaCredentials: []*transport.KafkaConfig{
+			{BootstrapServer: "kafka2:9092"},
+		},
+		Extends: map[string]interface{}{},
+	}
+	topic := "gh-status"
+
+	c, err := NewMultiClusterConsumer(tranConfig, []string{topic})
+	require.NoError(t, err)
+	require.Len(t, c.consumers, 2)
+	require.Contains(t, c.consumers, "cluster1")
+	require.Contains(t, c.consumers, "kafka2:9092")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = c.Start(ctx) }()
+
+	// the events received by the consumers are merged with the identity of the kafka cluster
+	sender, err := cloudevents.NewClient(tranConfig.Extends[topic].(*gochan.SendReceiver))
+	require.NoError(t, err)
+	evt := cloudevents.NewEvent()
+	evt.SetID("1")
+	evt.SetType("test")
+	evt.SetSource("hub1")
+	require.True(t, cloudevents.IsACK(sender.Send(ctx, evt)))
+
+	select {
+	case received := <-c.EventChan():
+		require.Contains(t, []interface{}{"cluster1", "kafka2:9092"}, received.Extensions()[transport.KafkaClusterKey])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event isn't received")
+	}
+
+	// the consumer of the removed cluster is stopped
+	tranConfig.AdditionalKafkaCredentials = nil
+	require.NoError(t, c.Reconnect(ctx, tranConfig, []string{topic}))
+	c.mutex.Lock()
+	require.Len(t, c.consumers, 1)
+	require.Contains(t, c.cancels, "cluster1")
+	c.mutex.Unlock()
+
+	// the reconnected consumer is restarted in the retry loop
+	evt.SetID("2")
+	require.True(t, cloudevents.IsACK(sender.Send(ctx, evt)))
+	select {
+	case received := <-c.EventChan():
+		require.Equal(t, "2", received.ID())
+		require.Equal(t, "cluster1", received.Extensions()[transport.KafkaClusterKey])
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event isn't received after reconnecting")
+	}
+}
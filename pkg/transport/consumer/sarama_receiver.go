@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package consumer
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+const (
+	// the topics are resolved with the interval if the regex topic is subscribed, it's same as the default value of
+	// the topic.metadata.refresh.interval.ms in the confluent client
+	defaultSaramaTopicRefreshInterval = 5 * time.Minute
+	saramaConsumeRetryInterval        = 5 * time.Second
+)
+
+// saramaReceiver receives the messages with the sarama consumer group, it keeps the parity with the confluent protocol:
+//   - the topic starts with "^" is subscribed as a regex, which is resolved with the cluster metadata periodically
+//   - the initial offsets are read from the withInitOffsets of the opening context
+//   - the transport.KafkaTopicKey, KafkaPartitionKey and KafkaOffsetKey extensions are set to the message
+//
+// The group offsets aren't auto committed in the background. They're committed once the messages are acked, or not
+// committed at all if the offsets are stored in the database, since the acked events might not be persisted yet.
+type saramaReceiver struct {
+	client          sarama.Client
+	group           sarama.ConsumerGroup
+	topics          []string
+	refreshInterval time.Duration
+	// commitOffsets commits the offsets of the acked messages to the consumer group
+	commitOffsets bool
+
+	incoming chan binding.Message
+	once     sync.Once
+
+	mutex sync.Mutex
+	// offsets are applied to the partitions when they're claimed. If the group offsets are committed, they're only the
+	// initial offsets and the committed offsets are used in the subsequent rebalances, otherwise they're updated to
+	// the acked messages, so the partitions are resumed from them in the subsequent rebalances.
+	offsets map[string]map[int32]int64
+}
+
+var (
+	_ protocol.Opener   = (*saramaReceiver)(nil)
+	_ protocol.Receiver = (*saramaReceiver)(nil)
+	_ protocol.Closer   = (*saramaReceiver)(nil)
+)
+
+func getSaramaReceiverProtocol(transportConfig *transport.TransportInternalConfig, topics []string,
+	topicMetadataRefreshInterval int, databaseOffset bool,
+) (*saramaReceiver, error) {
+	saramaConfig, err := config.GetSaramaConfigByKafkaCredential(transportConfig.KafkaCredential,
+		transportConfig.KafkaCredential.ConsumerGroupID, topicMetadataRefreshInterval)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(strings.Split(transportConfig.KafkaCredential.BootstrapServer, ","), saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	group, err := sarama.NewConsumerGroupFromClient(transportConfig.KafkaCredential.ConsumerGroupID, client)
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	refreshInterval := defaultSaramaTopicRefreshInterval
+	if topicMetadataRefreshInterval > 0 {
+		refreshInterval = time.Duration(topicMetadataRefreshInterval) * time.Millisecond
+	}
+	return &saramaReceiver{
+		client:          client,
+		group:           group,
+		topics:          topics,
+		refreshInterval: refreshInterval,
+		commitOffsets:   !databaseOffset,
+		incoming:        make(chan binding.Message),
+		offsets:         map[string]map[int32]int64{},
+	}, nil
+}
+
+// OpenInbound consumes the topics until the context is done, the consumer group session is restarted once the
+// resolved topics are changed, the consumer group and the client are closed after that
+func (r *saramaReceiver) OpenInbound(ctx context.Context) error {
+	defer func() { _ = r.Close(ctx) }()
+
+	r.mutex.Lock()
+	for _, position := range initOffsetsFrom(ctx) {
+		r.setOffset(position.Topic, position.Partition, position.Offset)
+	}
+	r.mutex.Unlock()
+
+	for {
+		topics, err := r.resolveTopics()
+		if err != nil {
+			log.Warnw("failed to resolve the topics", "topics", r.topics, "error", err)
+		}
+		if len(topics) > 0 {
+			consumeCtx, cancel := context.WithCancel(ctx)
+			go r.watchTopics(consumeCtx, cancel, topics)
+			err = r.group.Consume(consumeCtx, topics, r)
+			cancel()
+			if err != nil {
+				log.Warnw("sarama consumer group session stopped", "topics", topics, "error", err)
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil || len(topics) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(saramaConsumeRetryInterval):
+			}
+		}
+	}
+}
+
+// resolveTopics returns the topics matched by the regex topics with the latest cluster metadata
+func (r *saramaReceiver) resolveTopics() ([]string, error) {
+	resolved := []string{}
+	var existingTopics []string
+	for _, topic := range r.topics {
+		if !strings.HasPrefix(topic, "^") {
+			resolved = append(resolved, topic)
+			continue
+		}
+		pattern, err := regexp.Compile(topic)
+		if err != nil {
+			return nil, err
+		}
+		if existingTopics == nil {
+			if err := r.client.RefreshMetadata(); err != nil {
+				return nil, err
+			}
+			if existingTopics, err = r.client.Topics(); err != nil {
+				return nil, err
+			}
+		}
+		for _, existing := range existingTopics {
+			if pattern.MatchString(existing) {
+				resolved = append(resolved, existing)
+			}
+		}
+	}
+	slices.Sort(resolved)
+	return slices.Compact(resolved), nil
+}
+
+// watchTopics cancels the current session if the regex topics are resolved into other topics
+func (r *saramaReceiver) watchTopics(ctx context.Context, cancel context.CancelFunc, topics []string) {
+	if !slices.ContainsFunc(r.topics, func(topic string) bool { return strings.HasPrefix(topic, "^") }) {
+		return
+	}
+	ticker := time.NewTicker(r.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			resolved, err := r.resolveTopics()
+			if err != nil {
+				log.Warnw("failed to resolve the topics", "topics", r.topics, "error", err)
+				continue
+			}
+			if !slices.Equal(resolved, topics) {
+				log.Infow("the subscribed topics are changed", "previous", topics, "current", resolved)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// Setup resets the claimed partitions to the offsets
+func (r *saramaReceiver) Setup(session sarama.ConsumerGroupSession) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for topic, partitions := range session.Claims() {
+		for _, partition := range partitions {
+			offset, found := r.offsets[topic][partition]
+			if !found {
+				continue
+			}
+			log.Infow("reset the partition offset", "topic", topic, "partition", partition, "offset", offset)
+			session.ResetOffset(topic, partition, offset, "")
+			if r.commitOffsets {
+				delete(r.offsets[topic], partition)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *saramaReceiver) setOffset(topic string, partition int32, offset int64) {
+	if _, found := r.offsets[topic]; !found {
+		r.offsets[topic] = map[int32]int64{}
+	}
+	r.offsets[topic][partition] = offset
+}
+
+// ack marks the message as consumed, and commits it to the consumer group or records it for the next claim
+func (r *saramaReceiver) ack(session sarama.ConsumerGroupSession, msg *sarama.ConsumerMessage) {
+	session.MarkMessage(msg, "")
+	if r.commitOffsets {
+		session.Commit()
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.setOffset(msg.Topic, msg.Partition, msg.Offset+1)
+}
+
+func (r *saramaReceiver) Cleanup(sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+// ConsumeClaim delivers the messages of the claim to the receiver, and acks the message once it's finished
+func (r *saramaReceiver) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			msg.Headers = append(msg.Headers,
+				&sarama.RecordHeader{Key: []byte("ce_" + transport.KafkaTopicKey), Value: []byte(msg.Topic)},
+				&sarama.RecordHeader{
+					Key:   []byte("ce_" + transport.KafkaPartitionKey),
+					Value: []byte(strconv.FormatInt(int64(msg.Partition), 10)),
+				},
+				&sarama.RecordHeader{
+					Key:   []byte("ce_" + transport.KafkaOffsetKey),
+					Value: []byte(strconv.FormatInt(msg.Offset, 10)),
+				},
+			)
+			message := binding.WithFinish(kafka_sarama.NewMessageFromConsumerMessage(msg), func(err error) {
+				if protocol.IsACK(err) {
+					r.ack(session, msg)
+				}
+			})
+			select {
+			case r.incoming <- message:
+			case <-session.Context().Done():
+				return nil
+			}
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (r *saramaReceiver) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, io.EOF
+	case msg, ok := <-r.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	}
+}
+
+func (r *saramaReceiver) Close(ctx context.Context) error {
+	var err error
+	r.once.Do(func() {
+		if err = r.group.Close(); err != nil {
+			log.Warnw("failed to close the sarama consumer group", "error", err)
+		}
+		err = r.client.Close()
+	})
+	return err
+}
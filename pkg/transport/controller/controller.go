@@ -118,27 +118,55 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	// currently, transport type is always kafka
-	c.transportConfig.TransportType = string(transport.Kafka)
+	// the transport type is kafka unless the rest transport is specified
+	if c.transportConfig.TransportType != string(transport.Rest) {
+		c.transportConfig.TransportType = string(transport.Kafka)
+	}
 	var updated bool
 	var err error
 
-	_, enableKafka := secret.Data["kafka.yaml"]
-	if enableKafka {
-		updated, err = c.ReconcileKafkaCredential(ctx, secret)
+	// the rest transport carries the events over the restful endpoint, the rest.yaml is the transport credential
+	if c.transportConfig.TransportType == string(transport.Rest) {
+		updated, err = c.ReconcileRestfulCredential(ctx, secret)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-
 		if updated {
-			// the consumer should reconcile when the credential is updated
 			if !c.disableConsumer {
 				if err := c.ReconcileConsumer(ctx); err != nil {
 					log.Warnf("consumer error: %v", err)
 					return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
 				}
 			}
-			if err := c.ReconcileProducer(); err != nil {
+			if err := c.ReconcileProducer(ctx); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	_, enableKafka := secret.Data["kafka.yaml"]
+	if enableKafka && c.transportConfig.TransportType == string(transport.Kafka) {
+		updated, err = c.ReconcileKafkaCredential(ctx, secret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		additionalUpdated := false
+		if c.inManager && len(c.transportConfig.AdditionalKafkaSecrets) > 0 {
+			additionalUpdated, err = c.ReconcileAdditionalKafkaCredentials(ctx)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		// the consumer should reconcile when the credential is updated
+		if (updated || additionalUpdated) && !c.disableConsumer {
+			if err := c.ReconcileConsumer(ctx); err != nil {
+				log.Warnf("consumer error: %v", err)
+				return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, nil
+			}
+		}
+		if updated {
+			if err := c.ReconcileProducer(ctx); err != nil {
 				return ctrl.Result{}, err
 			}
 		}
@@ -146,7 +174,7 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 
 	// if the rest.yaml exist, then build the rest client
 	_, enableRestful := secret.Data["rest.yaml"]
-	if enableRestful {
+	if enableRestful && c.transportConfig.TransportType == string(transport.Kafka) {
 		updated, err = c.ReconcileRestfulCredential(ctx, secret)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -172,20 +200,38 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 }
 
 // ReconcileProducer, transport config is changed, then create/update the producer
-func (c *TransportCtrl) ReconcileProducer() error {
+func (c *TransportCtrl) ReconcileProducer(ctx context.Context) error {
 	// set producerTopic to spec or status topic based on running in manager or not
+	specTopic, statusTopic := c.topics()
 	if c.inManager {
-		c.producerTopic = c.transportConfig.KafkaCredential.SpecTopic
+		c.producerTopic = specTopic
 	} else {
-		c.producerTopic = c.transportConfig.KafkaCredential.StatusTopic
+		c.producerTopic = statusTopic
 	}
 
 	if c.transportClient.producer == nil {
-		sender, err := producer.NewGenericProducer(c.transportConfig, c.producerTopic, nil)
+		// the agent keys the status events by the hub, so the hub is owned by a single replica of the sharded manager
+		sender, err := producer.NewGenericProducer(c.transportConfig, c.producerTopic, nil,
+			producer.EnableMessageKeyBySource(!c.inManager))
 		if err != nil {
 			return fmt.Errorf("failed to create/update the producer: %w", err)
 		}
 		c.transportClient.producer = sender
+
+		// the agent persists the status events into the outbox, then the outbox delivers them with the producer
+		if !c.inManager && c.transportConfig.OutboxDir != "" {
+			outbox, err := producer.NewOutboxProducer(sender, c.transportConfig.OutboxDir,
+				c.transportConfig.OutboxMaxBytes)
+			if err != nil {
+				return fmt.Errorf("failed to create the outbox producer: %w", err)
+			}
+			go func() {
+				if err := outbox.Start(ctx); err != nil {
+					log.Errorf("failed to start the outbox producer: %v", err)
+				}
+			}()
+			c.transportClient.producer = outbox
+		}
 	} else {
 		if err := c.transportClient.producer.Reconnect(c.transportConfig, c.producerTopic); err != nil {
 			return fmt.Errorf("failed to reconnect the producer: %w", err)
@@ -197,24 +243,34 @@ func (c *TransportCtrl) ReconcileProducer() error {
 // ReconcileConsumer, transport config is changed, then create/update the consumer
 func (c *TransportCtrl) ReconcileConsumer(ctx context.Context) error {
 	// if the consumer groupId is empty, then it's means the agent is in the standalone mode, don't create the consumer
-	if c.transportConfig.KafkaCredential.ConsumerGroupID == "" {
+	consumerGroupID := c.consumerGroupID()
+	if consumerGroupID == "" {
 		log.Infof("skip initializing consumer, consumer group id is not set")
 		return nil
 	}
 
-	options := []consumer.GenericConsumeOption{}
+	options := []consumer.GenericConsumeOption{
+		consumer.SetChunkAssemblerLimits(c.transportConfig.ChunkCollectionTTL, c.transportConfig.ChunkMemoryLimit),
+	}
 	// set consumerTopics to status or spec topic based on running in manager or not
+	specTopic, statusTopic := c.topics()
 	if c.inManager {
-		c.consumerTopics = []string{c.transportConfig.KafkaCredential.StatusTopic}
+		c.consumerTopics = []string{statusTopic}
 		options = append(options, consumer.SetTopicMetadataRefreshInterval(constants.TopicMetadataRefreshInterval))
 	} else {
-		c.consumerTopics = []string{c.transportConfig.KafkaCredential.SpecTopic}
+		c.consumerTopics = []string{specTopic}
 	}
 
-	consumerGroupID := c.transportConfig.KafkaCredential.ConsumerGroupID
 	// create/update the consumer with the kafka transport
 	if c.transportClient.consumer == nil {
-		receiver, err := consumer.NewGenericConsumer(c.transportConfig, c.consumerTopics, options...)
+		var receiver transport.Consumer
+		var err error
+		// the manager consumes from the primary and the additional kafka clusters at once
+		if c.inManager && len(c.transportConfig.AdditionalKafkaSecrets) > 0 {
+			receiver, err = consumer.NewMultiClusterConsumer(c.transportConfig, c.consumerTopics, options...)
+		} else {
+			receiver, err = consumer.NewGenericConsumer(c.transportConfig, c.consumerTopics, options...)
+		}
 		if err != nil {
 			return fmt.Errorf("failed to create the consumer: %w", err)
 		}
@@ -237,6 +293,27 @@ func (c *TransportCtrl) ReconcileConsumer(ctx context.Context) error {
 	return nil
 }
 
+// topics returns the spec and status topic from the credential of the current transport type
+func (c *TransportCtrl) topics() (specTopic, statusTopic string) {
+	if c.transportConfig.TransportType == string(transport.Rest) {
+		return c.transportConfig.RestfulCredential.SpecTopic, c.transportConfig.RestfulCredential.StatusTopic
+	}
+	return c.transportConfig.KafkaCredential.SpecTopic, c.transportConfig.KafkaCredential.StatusTopic
+}
+
+// consumerGroupID identifies the consumer, the rest transport has no consumer group, so the consumed topic is used.
+// the agent with rest transport is in the standalone mode if the spec topic isn't specified
+func (c *TransportCtrl) consumerGroupID() string {
+	if c.transportConfig.TransportType != string(transport.Rest) {
+		return c.transportConfig.KafkaCredential.ConsumerGroupID
+	}
+	specTopic, statusTopic := c.topics()
+	if c.inManager {
+		return statusTopic
+	}
+	return specTopic
+}
+
 // ReconcileInventory, transport config is changed, then create/update the inventory client
 func (c *TransportCtrl) ReconcileRequester(ctx context.Context) error {
 	if c.transportClient.requester == nil {
@@ -285,6 +362,42 @@ func (c *TransportCtrl) ReconcileKafkaCredential(ctx context.Context, secret *co
 	return true, nil
 }
 
+// ReconcileAdditionalKafkaCredentials loads the credentials of the additional kafka clusters which the manager consumes
+// the status from, return true if any of the credentials is updated
+func (c *TransportCtrl) ReconcileAdditionalKafkaCredentials(ctx context.Context) (bool, error) {
+	credentials := []*transport.KafkaConfig{}
+	for _, name := range c.transportConfig.AdditionalKafkaSecrets {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: c.secretNamespace,
+				Name:      name,
+			},
+		}
+		if err := c.runtimeClient.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+			return false, fmt.Errorf("failed to get the additional transport secret %s: %w", name, err)
+		}
+		kafkaConn, err := config.GetKafkaCredentialBySecret(secret, c.runtimeClient)
+		if err != nil {
+			return false, fmt.Errorf("failed to load the credential of the additional transport secret %s: %w",
+				name, err)
+		}
+		// update the watching secret list
+		if kafkaConn.CASecretName != "" && !utils.ContainsString(c.extraSecretNames, kafkaConn.CASecretName) {
+			c.extraSecretNames = append(c.extraSecretNames, kafkaConn.CASecretName)
+		}
+		if kafkaConn.ClientSecretName != "" && !utils.ContainsString(c.extraSecretNames, kafkaConn.ClientSecretName) {
+			c.extraSecretNames = append(c.extraSecretNames, kafkaConn.ClientSecretName)
+		}
+		credentials = append(credentials, kafkaConn)
+	}
+
+	if reflect.DeepEqual(c.transportConfig.AdditionalKafkaCredentials, credentials) {
+		return false, nil
+	}
+	c.transportConfig.AdditionalKafkaCredentials = credentials
+	return true, nil
+}
+
 // Resync the kafka client secret because we recreate the kafka cluster in globalhub 1.4 and restore case.
 func (c *TransportCtrl) ResyncKafkaClientSecret(ctx context.Context, kafkaConn *transport.KafkaConfig, secret *corev1.Secret) error {
 	if kafkaConn.ClusterID == "" {
@@ -376,10 +489,13 @@ func (c *TransportCtrl) SetupWithManager(mgr ctrl.Manager) error {
 			return false
 		},
 	}
+	// the consumer of the sharded status processing runs on every manager replica
+	needLeaderElection := !(c.inManager && c.transportConfig.StatusSharding)
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Secret{}, builder.WithPredicates(secretPred)).
 		WithOptions(
 			controller.TypedOptions[ctrl.Request]{
+				NeedLeaderElection: &needLeaderElection,
 				NewQueue: func(controllerName string, rateLimiter workqueue.TypedRateLimiter[ctrl.Request]) workqueue.TypedRateLimitingInterface[ctrl.Request] {
 					c.workqueue = workqueue.NewTypedRateLimitingQueueWithConfig(rateLimiter, workqueue.TypedRateLimitingQueueConfig[ctrl.Request]{
 						Name: controllerName,
@@ -394,6 +510,9 @@ func (c *TransportCtrl) credentialSecret(name string) bool {
 	if c.secretName == name {
 		return true
 	}
+	if utils.ContainsString(c.transportConfig.AdditionalKafkaSecrets, name) {
+		return true
+	}
 	if len(c.extraSecretNames) == 0 {
 		return false
 	}
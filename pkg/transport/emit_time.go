@@ -0,0 +1,30 @@
+package transport
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// SetEmitTime stamps the current time as the emit time of the event, it keeps the existing one, so the time is the
+// emission of the emitter rather than the redelivery of the transport
+func SetEmitTime(evt *cloudevents.Event) {
+	if _, found := evt.Extensions()[EmitTimeKey]; found {
+		return
+	}
+	evt.SetExtension(EmitTimeKey, time.Now())
+}
+
+// EmitTime returns the emit time of the event, it returns false if the event isn't stamped by the emitter
+func EmitTime(evt *cloudevents.Event) (time.Time, bool) {
+	value, found := evt.Extensions()[EmitTimeKey]
+	if !found {
+		return time.Time{}, false
+	}
+	emitTime, err := types.ToTime(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return emitTime, true
+}
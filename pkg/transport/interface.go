@@ -28,6 +28,20 @@ type Producer interface {
 	Reconnect(config *TransportInternalConfig, topic string) error
 }
 
+// SyncProducer is implemented by the producer which can wait for the delivery reports of the event, so that the
+// caller only updates the bookkeeping, like the bundle version, once the event is landed in the transport
+type SyncProducer interface {
+	SendEventSync(ctx context.Context, evt cloudevents.Event) error
+}
+
+// SendEventSync waits for the delivery of the event if the producer supports it, otherwise it's same as SendEvent
+func SendEventSync(ctx context.Context, producer Producer, evt cloudevents.Event) error {
+	if syncProducer, ok := producer.(SyncProducer); ok {
+		return syncProducer.SendEventSync(ctx, evt)
+	}
+	return producer.SendEvent(ctx, evt)
+}
+
 type Consumer interface {
 	// start the transport to consume message
 	Start(ctx context.Context) error
@@ -35,6 +49,22 @@ type Consumer interface {
 	Reconnect(ctx context.Context, config *TransportInternalConfig, topics []string) error
 }
 
+// RebalanceListener is notified when the partitions of the consumer group are assigned to or revoked from the
+// consumer, the positions only carry the topic, partition and the identity of the kafka cluster
+type RebalanceListener interface {
+	// OnPartitionsAssigned is invoked before the events of the assigned partitions are received
+	OnPartitionsAssigned(partitions []EventPosition)
+	// OnPartitionsRevoked is invoked before the revoked partitions are consumed by the other members of the group,
+	// so the listener can commit the processed offsets of them
+	OnPartitionsRevoked(partitions []EventPosition)
+}
+
+// PartitionOwner is implemented by the consumer which shares the partitions with the other members of the consumer
+// group, the listener is notified of the currently assigned partitions once it's set
+type PartitionOwner interface {
+	SetRebalanceListener(listener RebalanceListener)
+}
+
 // Transporter used to innitialize the infras, it has different implementation/protocol:
 // byo_secret, strimzi operator or plain deployment
 type Transporter interface {
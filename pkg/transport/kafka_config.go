@@ -79,3 +79,12 @@ func (k *KafkaConfig) GetCASecretName() string {
 func (k *KafkaConfig) GetClientSecretName() string {
 	return k.ClientSecretName
 }
+
+// Identity returns the cluster id to identify the kafka cluster, the bootstrap server is used if the cluster id isn't
+// specified in the credential
+func (k *KafkaConfig) Identity() string {
+	if k.ClusterID != "" {
+		return k.ClusterID
+	}
+	return k.BootstrapServer
+}
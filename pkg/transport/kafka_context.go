@@ -0,0 +1,27 @@
+package transport
+
+import "context"
+
+// The extensions of the position of the received event in the kafka topic, they're the same as the ones set by the
+// kafka_confluent protocol, so that they can be set and read without the confluent client, which requires cgo
+const (
+	KafkaTopicKey     = "kafkatopic"
+	KafkaPartitionKey = "kafkapartition"
+	KafkaOffsetKey    = "kafkaoffset"
+)
+
+type messageKeyKey struct{}
+
+// WithMessageKey returns the context with the key of the kafka message, it's applied by both the confluent and the
+// sarama producers
+func WithMessageKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, messageKeyKey{}, key)
+}
+
+// MessageKeyFrom returns the key of the kafka message in the context, it's empty if the key isn't set
+func MessageKeyFrom(ctx context.Context) string {
+	if key, ok := ctx.Value(messageKeyKey{}).(string); ok {
+		return key
+	}
+	return ""
+}
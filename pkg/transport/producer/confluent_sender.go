@@ -0,0 +1,173 @@
+//go:build cgo
+
+package producer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
+)
+
+// KafkaProducer is the producer of the confluent kafka client
+type KafkaProducer = kafka.Producer
+
+// KafkaMessage is the message of the confluent kafka client, the message failed to deliver is reported to the
+// eventErrorHandler of the producer
+type KafkaMessage = kafka.Message
+
+func (p *GenericProducer) Protocol() *kafka_confluent.Protocol {
+	return p.ceProtocol.(*kafka_confluent.Protocol)
+}
+
+// initConfluentClient initializes the protocol with the confluent kafka client, the delivery reports are handled
+// asynchronously
+func (p *GenericProducer) initConfluentClient(transportConfig *transport.TransportInternalConfig, topic string) error {
+	producer, kafkaProtocol, err := getConfluentSenderProtocol(p.log, transportConfig.KafkaCredential, topic)
+	if err != nil {
+		return err
+	}
+
+	eventChan, err := kafkaProtocol.Events()
+	if err != nil {
+		return err
+	}
+	handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, p.eventErrorHandler)
+	p.ceProtocol = kafkaProtocol
+	p.kafkaProducer = producer
+	return nil
+}
+
+// withConfluentMessageKey applies the message key of the context to the confluent protocol
+func withConfluentMessageKey(ctx context.Context) context.Context {
+	if key := transport.MessageKeyFrom(ctx); key != "" {
+		return kafka_confluent.WithMessageKey(ctx, key)
+	}
+	return ctx
+}
+
+// produceSync produces the message with the kafka producer directly instead of the cloudevents client, so the delivery
+// report is returned with the channel of the message rather than the events channel of the producer
+func (p *GenericProducer) produceSync(ctx context.Context, evt cloudevents.Event) error {
+	if err := evt.Validate(); err != nil {
+		return fmt.Errorf("invalid event: %w", err)
+	}
+
+	topic := cectx.TopicFrom(ctx)
+	if topic == "" {
+		topic = p.topic
+	}
+	kafkaMsg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+	}
+	if messageKey := kafka_confluent.MessageKeyFrom(ctx); messageKey != "" {
+		kafkaMsg.Key = []byte(messageKey)
+	}
+	if err := kafka_confluent.WriteProducerMessage(ctx, binding.ToMessage(&evt), kafkaMsg); err != nil {
+		return fmt.Errorf("failed to create the producer message: %w", err)
+	}
+
+	deliveryChan := make(chan kafka.Event, 1)
+	if err := p.kafkaProducer.Produce(kafkaMsg, deliveryChan); err != nil {
+		return fmt.Errorf("failed to produce the message: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("stop waiting for the delivery report: %w", ctx.Err())
+	case e := <-deliveryChan:
+		m, ok := e.(*kafka.Message)
+		if !ok {
+			return fmt.Errorf("unexpected delivery report: %v", e)
+		}
+		if m.TopicPartition.Error != nil {
+			return fmt.Errorf("failed to deliver the message to %s: %w", m.TopicPartition, m.TopicPartition.Error)
+		}
+	}
+	return nil
+}
+
+func getConfluentSenderProtocol(logger *zap.SugaredLogger, kafkaCredentail *transport.KafkaConfig,
+	defaultTopic string,
+) (*kafka.Producer, *kafka_confluent.Protocol, error) {
+	configMap, err := config.GetConfluentConfigMapByKafkaCredential(kafkaCredentail, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Debugw("the configurations applied to the Kafka producer", "configMap",
+		utils.FilterSensitiveKafkaConfig(configMap))
+
+	producer, err := kafka.NewProducer(configMap)
+	if err != nil {
+		return nil, nil, err
+	}
+	protocol, err := kafka_confluent.New(kafka_confluent.WithSenderTopic(defaultTopic),
+		kafka_confluent.WithSender(producer))
+	if err != nil {
+		return nil, nil, err
+	}
+	return producer, protocol, nil
+}
+
+func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int,
+	eventErrorHandler func(event *kafka.Message),
+) {
+	// Listen to all the events on the default events channel
+	// It's important to read these events otherwise the events channel will eventually fill up
+	go func() {
+		errorCount := 0
+		var lastErrorTime time.Time
+		for e := range eventChan {
+			switch ev := e.(type) {
+			case *kafka.Message:
+				// The message delivery report, indicating success or
+				// permanent failure after retries have been exhausted.
+				// Application level retries won't help since the client
+				// is already configured to do that.
+				m := ev
+				if m.TopicPartition.Error != nil {
+					if eventErrorHandler != nil {
+						eventErrorHandler(m)
+					}
+					log.Warnw("delivery failed", "error", m.TopicPartition.Error)
+				}
+			case kafka.Error:
+				// Generic client instance-level errors, such as
+				// broker connection failures, authentication issues, etc.
+				//
+				// These errors should generally be considered informational
+				// as the underlying client will automatically try to
+				// recover from any errors encountered, the application
+				// does not need to take action on them.
+				if ev.Code() == kafka.ErrAllBrokersDown {
+					// ALL_BROKERS_DOWN doesn't really mean anything to librdkafka, it is just a friendly indication
+					// to the application that currently there are no brokers to communicate with.
+					// But librdkafka will continue to try to reconnect indefinately,
+					// and it will attempt to re-send messages until message.timeout.ms or message.max.retries are exceeded.
+					log.Debugw("transport producer client error(ALL_BROKERS_DOWN), ignore it for most cases", "error", ev)
+				} else {
+					log.Warnw("transport producer client error", "error", ev)
+
+					errorCount++
+					if errorCount >= transportFailureThreshold {
+						log.Panicf("transport producer error > 10 in 5 minutes, error: %v", ev)
+					}
+					// return panic when error more than 10 times in 5 minites
+					if lastErrorTime.Add(5 * time.Minute).Before(time.Now()) {
+						errorCount = 0
+					}
+					lastErrorTime = time.Now()
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,33 @@
+//go:build !cgo
+
+package producer
+
+import (
+	"context"
+	"errors"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// errConfluentUnsupported means the confluent kafka client isn't built, since it requires cgo
+var errConfluentUnsupported = errors.New("the confluent kafka client requires cgo, use the sarama kafka client instead")
+
+// KafkaProducer is the placeholder of the confluent kafka producer, it's always nil without cgo
+type KafkaProducer struct{}
+
+// KafkaMessage is the placeholder of the confluent kafka message, the eventErrorHandler is never invoked without cgo
+type KafkaMessage struct{}
+
+func (p *GenericProducer) initConfluentClient(transportConfig *transport.TransportInternalConfig, topic string) error {
+	return errConfluentUnsupported
+}
+
+func withConfluentMessageKey(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (p *GenericProducer) produceSync(ctx context.Context, evt cloudevents.Event) error {
+	return errConfluentUnsupported
+}
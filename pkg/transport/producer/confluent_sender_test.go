@@ -0,0 +1,75 @@
+//go:build cgo
+
+// Copyright (c) 2023 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func Test_handleProducerEvents(t *testing.T) {
+	tests := []struct {
+		name                      string
+		event                     kafka.Event
+		transportFailureThreshold int
+	}{
+		{
+			name:                      "kafka error",
+			transportFailureThreshold: 10,
+			event:                     kafka.NewError(kafka.ErrFail, "errStr", false),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := logger.DefaultZapLogger()
+			eventChan := make(chan kafka.Event)
+			go handleProducerEvents(log, eventChan, tt.transportFailureThreshold, nil)
+			eventChan <- tt.event
+		})
+	}
+}
+
+func TestGenericProducerSendEventSync(t *testing.T) {
+	mockKafkaCluster, err := kafka.NewMockCluster(1)
+	require.NoError(t, err)
+	defer mockKafkaCluster.Close()
+
+	tranConfig := &transport.TransportInternalConfig{
+		TransportType:    string(transport.Kafka),
+		FailureThreshold: 10,
+		KafkaCredential: &transport.KafkaConfig{
+			BootstrapServer: mockKafkaCluster.BootstrapServers(),
+			StatusTopic:     "gh-status",
+		},
+	}
+	p, err := NewGenericProducer(tranConfig, tranConfig.KafkaCredential.StatusTopic, nil)
+	require.NoError(t, err)
+	defer p.KafkaProducer().Close()
+
+	evt := cloudevents.NewEvent()
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedhub.heartbeat")
+	evt.SetSource("hub1")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`{"name":"hub1"}`)))
+
+	// the delivery reports of all the chunks are received
+	p.SetDataLimit(5)
+	require.NoError(t, transport.SendEventSync(context.Background(), p, evt))
+
+	// the event isn't landed when the broker is down
+	require.NoError(t, mockKafkaCluster.SetBrokerDown(1))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = p.SendEventSync(ctx, evt)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -8,17 +8,21 @@ import (
 	"fmt"
 	"time"
 
-	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cectx "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
-	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/signature"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
 )
 
@@ -26,19 +30,40 @@ type GenericProducer struct {
 	log               *zap.SugaredLogger
 	ceProtocol        interface{}
 	ceClient          cloudevents.Client
-	kafkaProducer     *kafka.Producer
+	kafkaProducer     *KafkaProducer
+	compressor        compressor.Compressor
+	signer            *signature.Signer
+	topic             string
 	messageSizeLimit  int
-	eventErrorHandler func(event *kafka.Message)
+	eventErrorHandler func(event *KafkaMessage)
+	// messageKeyBySource keys the messages by the source rather than the type of the events if the key isn't set
+	messageKeyBySource bool
+	// requireACK treats the NACK result as a failure, e.g. the rest transport server rejects the event with the
+	// error status, while the other protocols only fail the undelivered event
+	requireACK bool
+}
+
+type GenericProducerOption func(*GenericProducer)
+
+// EnableMessageKeyBySource keys the status events by the hub, so all the events of a hub are sent to the same
+// partition of the shared status topic, which is owned by a single manager replica with the status sharding
+func EnableMessageKeyBySource(enabled bool) GenericProducerOption {
+	return func(p *GenericProducer) {
+		p.messageKeyBySource = enabled
+	}
 }
 
 func NewGenericProducer(transportConfig *transport.TransportInternalConfig, topic string,
-	eventErrorHandler func(event *kafka.Message),
+	eventErrorHandler func(event *KafkaMessage), opts ...GenericProducerOption,
 ) (*GenericProducer, error) {
 	genericProducer := &GenericProducer{
 		log:               logger.ZapLogger(fmt.Sprintf("%s-producer", transportConfig.TransportType)),
 		messageSizeLimit:  config.MaxSizeToChunk,
 		eventErrorHandler: eventErrorHandler,
 	}
+	for _, opt := range opts {
+		opt(genericProducer)
+	}
 	err := genericProducer.initClient(transportConfig, topic)
 	if err != nil {
 		return nil, err
@@ -47,30 +72,80 @@ func NewGenericProducer(transportConfig *transport.TransportInternalConfig, topi
 	return genericProducer, nil
 }
 
-func (p *GenericProducer) KafkaProducer() *kafka.Producer {
+func (p *GenericProducer) KafkaProducer() *KafkaProducer {
 	return p.kafkaProducer
 }
 
-func (p *GenericProducer) Protocol() *kafka_confluent.Protocol {
-	return p.ceProtocol.(*kafka_confluent.Protocol)
+// SendEvent sends the event to the transport, the kafka producer returns once the messages are enqueued, and the
+// delivery failures are reported by the eventErrorHandler asynchronously
+func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	return p.sendEvent(ctx, evt, p.send)
 }
 
-func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+// SendEventSync sends the event like SendEvent, but it waits for the delivery reports of all the chunks from the kafka
+// brokers, and returns the error if any of them isn't delivered. The other protocols are synchronous already.
+func (p *GenericProducer) SendEventSync(ctx context.Context, evt cloudevents.Event) error {
+	if p.kafkaProducer == nil {
+		return p.sendEvent(ctx, evt, p.send)
+	}
+	return p.sendEvent(ctx, evt, p.produceSync)
+}
+
+func (p *GenericProducer) messageKey(evt cloudevents.Event) string {
+	if p.messageKeyBySource && evt.Source() != "" {
+		return evt.Source()
+	}
+	return evt.Type()
+}
+
+func (p *GenericProducer) sendEvent(ctx context.Context, evt cloudevents.Event,
+	send func(context.Context, cloudevents.Event) error,
+) error {
 	// cloudevent kafka/gochan client
 	// message key
 	evtCtx := cectx.WithLogger(ctx, logger.ZapLogger("cloudevents"))
-	if kafka_confluent.MessageKeyFrom(ctx) == "" {
-		evtCtx = kafka_confluent.WithMessageKey(ctx, evt.Type())
+	if transport.MessageKeyFrom(ctx) == "" {
+		evtCtx = transport.WithMessageKey(evtCtx, p.messageKey(evt))
+	}
+	evtCtx = withConfluentMessageKey(evtCtx)
+
+	// keep consistent with the cloudevents client, which sets the id and time if they're empty. They're set before
+	// signing since they're covered by the signature, and the consumer assembles the chunks with the id
+	if evt.ID() == "" {
+		evt.SetID(uuid.New().String())
+	}
+	if evt.Time().IsZero() {
+		evt.SetTime(time.Now())
+	}
+
+	// stamp the emit time if the event isn't emitted by the emitters, e.g. the events of the migration
+	transport.SetEmitTime(&evt)
+	// continue the trace of the sender, e.g. the spec syncer of the manager or the status emitter of the agent
+	tracing.Inject(ctx, &evt)
+
+	// the signature covers the original payload, so it's signed before the payload is compressed or chunked
+	if p.signer != nil {
+		if err := p.signer.Sign(&evt); err != nil {
+			return err
+		}
 	}
 
 	// data
 	payloadBytes := evt.Data()
-	chunks := p.splitPayloadIntoChunks(payloadBytes)
-	if len(chunks) <= 1 {
-		if ret := p.ceClient.Send(evtCtx, evt); cloudevents.IsUndelivered(ret) {
-			return fmt.Errorf("failed to send event to transport: %v", ret)
+	if p.compressor != nil && len(payloadBytes) > 0 {
+		compressed, err := p.compressor.Compress(payloadBytes)
+		if err != nil {
+			return fmt.Errorf("failed to compress the event payload: %w", err)
+		}
+		evt.SetExtension(transport.CompressionKey, p.compressor.GetType())
+		if err := evt.SetData(evt.DataContentType(), compressed); err != nil {
+			return fmt.Errorf("failed to set the compressed data: %w", err)
 		}
-		return nil
+		payloadBytes = compressed
+	}
+	chunks := p.splitPayloadIntoChunks(&evt, payloadBytes)
+	if len(chunks) <= 1 {
+		return send(evtCtx, evt)
 	}
 
 	chunkOffset := 0
@@ -81,13 +156,21 @@ func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event)
 		if err := evt.SetData(cloudevents.ApplicationJSON, chunk); err != nil {
 			return fmt.Errorf("failed to set cloudevents data: %v", evt)
 		}
-		if result := p.ceClient.Send(evtCtx, evt); cloudevents.IsUndelivered(result) {
-			return fmt.Errorf("failed to send events to transport: %v", result)
+		if err := send(evtCtx, evt); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+func (p *GenericProducer) send(ctx context.Context, evt cloudevents.Event) error {
+	ret := p.ceClient.Send(ctx, evt)
+	if cloudevents.IsUndelivered(ret) || (p.requireACK && !cloudevents.IsACK(ret)) {
+		return fmt.Errorf("failed to send event to transport: %v", ret)
+	}
+	return nil
+}
+
 // Reconnect close the previous producer state and init a new producer
 func (p *GenericProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
 	// cloudevent kafka/gochan client
@@ -102,20 +185,44 @@ func (p *GenericProducer) Reconnect(config *transport.TransportInternalConfig, t
 
 // initClient will init/update the client, clientProtocol and messageLimitSize based on the transportConfig
 func (p *GenericProducer) initClient(transportConfig *transport.TransportInternalConfig, topic string) error {
-	switch transportConfig.TransportType {
-	case string(transport.Kafka):
-		producer, kafkaProtocol, err := getConfluentSenderProtocol(p.log, transportConfig.KafkaCredential, topic)
+	// the payload is sent as it is if the compression type isn't specified or it's no-op
+	p.compressor = nil
+	if transportConfig.CompressionType != "" && transportConfig.CompressionType != compressor.NoOp {
+		payloadCompressor, err := compressor.NewCompressor(transportConfig.CompressionType)
 		if err != nil {
-			return err
+			return fmt.Errorf("compression-type - %s: %w", transportConfig.CompressionType, err)
 		}
+		p.compressor = payloadCompressor
+	}
 
-		eventChan, err := kafkaProtocol.Events()
+	p.signer = nil
+	if transportConfig.SigningKeyFile != "" && transportConfig.SigningCertFile != "" {
+		signer, err := signature.NewSignerFromFiles(transportConfig.SigningKeyFile, transportConfig.SigningCertFile)
 		if err != nil {
 			return err
 		}
-		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, p.eventErrorHandler)
-		p.ceProtocol = kafkaProtocol
-		p.kafkaProducer = producer
+		p.signer = signer
+	}
+
+	p.topic = topic
+	p.kafkaProducer = nil
+	p.requireACK = transportConfig.TransportType == string(transport.Rest)
+	switch transportConfig.TransportType {
+	case string(transport.Kafka):
+		if err := config.ValidateKafkaClientType(transportConfig.KafkaClientType); err != nil {
+			return err
+		}
+		if transportConfig.KafkaClientType == transport.SaramaKafkaClient {
+			saramaProtocol, err := getSaramaSenderProtocol(transportConfig.KafkaCredential, topic)
+			if err != nil {
+				return err
+			}
+			p.ceProtocol = saramaProtocol
+			break
+		}
+		if err := p.initConfluentClient(transportConfig, topic); err != nil {
+			return err
+		}
 	case string(transport.Chan):
 		if transportConfig.Extends == nil {
 			transportConfig.Extends = make(map[string]interface{})
@@ -124,6 +231,26 @@ func (p *GenericProducer) initClient(transportConfig *transport.TransportInterna
 			transportConfig.Extends[topic] = gochan.New()
 		}
 		p.ceProtocol = transportConfig.Extends[topic]
+	case string(transport.Rest):
+		// the manager publishes the events into the local broker, which are long polled by the agents
+		if broker, ok := transportConfig.Extends[rest.BrokerKey].(*rest.Broker); ok {
+			p.ceProtocol = rest.NewBrokerSender(broker, topic)
+			break
+		}
+		if transportConfig.RestfulCredential == nil {
+			return fmt.Errorf("the restful credential must not be nil for the rest transport")
+		}
+		httpClient, err := rest.NewHTTPClient(transportConfig.RestfulCredential)
+		if err != nil {
+			return err
+		}
+		httpProtocol, err := cehttp.New(cehttp.WithTarget(rest.TopicURL(transportConfig.RestfulCredential.Host,
+			transportConfig.RestfulCredential.Hub, topic)),
+			cehttp.WithClient(*httpClient))
+		if err != nil {
+			return err
+		}
+		p.ceProtocol = httpProtocol
 	default:
 		return fmt.Errorf("transport-type - %s is not a valid option", transportConfig.TransportType)
 	}
@@ -139,7 +266,13 @@ func (p *GenericProducer) initClient(transportConfig *transport.TransportInterna
 	return nil
 }
 
-func (p *GenericProducer) splitPayloadIntoChunks(payload []byte) [][]byte {
+// splitPayloadIntoChunks splits the payload by the message size limit, and sets the checksum of the whole payload to
+// the event if it's chunked
+func (p *GenericProducer) splitPayloadIntoChunks(evt *cloudevents.Event, payload []byte) [][]byte {
+	if len(payload) > p.messageSizeLimit {
+		evt.SetExtension(transport.ChecksumKey, utils.PayloadChecksum(payload))
+	}
+
 	var chunk []byte
 	chunks := make([][]byte, 0, len(payload)/(p.messageSizeLimit)+1)
 	for len(payload) >= p.messageSizeLimit {
@@ -155,79 +288,3 @@ func (p *GenericProducer) splitPayloadIntoChunks(payload []byte) [][]byte {
 func (p *GenericProducer) SetDataLimit(size int) {
 	p.messageSizeLimit = size
 }
-
-func getConfluentSenderProtocol(logger *zap.SugaredLogger, kafkaCredentail *transport.KafkaConfig,
-	defaultTopic string,
-) (*kafka.Producer, *kafka_confluent.Protocol, error) {
-	configMap, err := config.GetConfluentConfigMapByKafkaCredential(kafkaCredentail, "", 0)
-	if err != nil {
-		return nil, nil, err
-	}
-	logger.Debugw("the configurations applied to the Kafka producer", "configMap",
-		utils.FilterSensitiveKafkaConfig(configMap))
-
-	producer, err := kafka.NewProducer(configMap)
-	if err != nil {
-		return nil, nil, err
-	}
-	protocol, err := kafka_confluent.New(kafka_confluent.WithSenderTopic(defaultTopic),
-		kafka_confluent.WithSender(producer))
-	if err != nil {
-		return nil, nil, err
-	}
-	return producer, protocol, nil
-}
-
-func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int,
-	eventErrorHandler func(event *kafka.Message),
-) {
-	// Listen to all the events on the default events channel
-	// It's important to read these events otherwise the events channel will eventually fill up
-	go func() {
-		errorCount := 0
-		var lastErrorTime time.Time
-		for e := range eventChan {
-			switch ev := e.(type) {
-			case *kafka.Message:
-				// The message delivery report, indicating success or
-				// permanent failure after retries have been exhausted.
-				// Application level retries won't help since the client
-				// is already configured to do that.
-				m := ev
-				if m.TopicPartition.Error != nil {
-					if eventErrorHandler != nil {
-						eventErrorHandler(m)
-					}
-					log.Warnw("delivery failed", "error", m.TopicPartition.Error)
-				}
-			case kafka.Error:
-				// Generic client instance-level errors, such as
-				// broker connection failures, authentication issues, etc.
-				//
-				// These errors should generally be considered informational
-				// as the underlying client will automatically try to
-				// recover from any errors encountered, the application
-				// does not need to take action on them.
-				if ev.Code() == kafka.ErrAllBrokersDown {
-					// ALL_BROKERS_DOWN doesn't really mean anything to librdkafka, it is just a friendly indication
-					// to the application that currently there are no brokers to communicate with.
-					// But librdkafka will continue to try to reconnect indefinately,
-					// and it will attempt to re-send messages until message.timeout.ms or message.max.retries are exceeded.
-					log.Debugw("transport producer client error(ALL_BROKERS_DOWN), ignore it for most cases", "error", ev)
-				} else {
-					log.Warnw("transport producer client error", "error", ev)
-
-					errorCount++
-					if errorCount >= transportFailureThreshold {
-						log.Panicf("transport producer error > 10 in 5 minutes, error: %v", ev)
-					}
-					// return panic when error more than 10 times in 5 minites
-					if lastErrorTime.Add(5 * time.Minute).Before(time.Now()) {
-						errorCount = 0
-					}
-					lastErrorTime = time.Now()
-				}
-			}
-		}
-	}()
-}
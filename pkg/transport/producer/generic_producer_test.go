@@ -6,44 +6,43 @@ package producer
 import (
 	"testing"
 
-	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/require"
 
-	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 func TestGenericProducer(t *testing.T) {
 	p := &GenericProducer{}
 	tranConfig := &transport.TransportInternalConfig{
-		TransportType: string(transport.Rest),
+		TransportType: "mqtt",
 		KafkaCredential: &transport.KafkaConfig{
 			SpecTopic:   "gh-spec",
 			StatusTopic: "gh-status",
 		},
 	}
 	err := p.initClient(tranConfig, tranConfig.KafkaCredential.StatusTopic)
-	require.Equal(t, "transport-type - rest is not a valid option", err.Error())
+	require.Equal(t, "transport-type - mqtt is not a valid option", err.Error())
+
+	// the rest transport requires the restful credential if it isn't in the manager
+	tranConfig.TransportType = string(transport.Rest)
+	err = p.initClient(tranConfig, tranConfig.KafkaCredential.StatusTopic)
+	require.Equal(t, "the restful credential must not be nil for the rest transport", err.Error())
+
+	tranConfig.RestfulCredential = &transport.RestfulConfig{Host: "https://global-hub-manager:9443"}
+	err = p.initClient(tranConfig, tranConfig.KafkaCredential.StatusTopic)
+	require.NoError(t, err)
 }
 
-func Test_handleProducerEvents(t *testing.T) {
-	tests := []struct {
-		name                      string
-		event                     kafka.Event
-		transportFailureThreshold int
-	}{
-		{
-			name:                      "kafka error",
-			transportFailureThreshold: 10,
-			event:                     kafka.NewError(kafka.ErrFail, "errStr", false),
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			log := logger.DefaultZapLogger()
-			eventChan := make(chan kafka.Event)
-			go handleProducerEvents(log, eventChan, tt.transportFailureThreshold, nil)
-			eventChan <- tt.event
-		})
-	}
+func TestMessageKey(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster")
+	evt.SetSource("hub1")
+
+	p := &GenericProducer{}
+	require.Equal(t, evt.Type(), p.messageKey(evt))
+
+	// the events of the hub are sent to the same partition
+	EnableMessageKeyBySource(true)(p)
+	require.Equal(t, "hub1", p.messageKey(evt))
 }
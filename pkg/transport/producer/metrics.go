@@ -0,0 +1,32 @@
+package producer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var outboxPendingEventsGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_pending_events",
+		Help: "The number of the events which are persisted in the outbox and waiting for delivering.",
+	},
+)
+
+var outboxPendingBytesGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "multicluster_global_hub_transport_outbox_pending_bytes",
+		Help: "The size of the events which are persisted in the outbox and waiting for delivering.",
+	},
+)
+
+var outboxCollapsedEventsCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "multicluster_global_hub_transport_outbox_collapsed_events_total",
+		Help: "The number of the pending complete state bundles which are superseded by the newer bundles.",
+	},
+)
+
+// RegisterMetrics will register metrics with the global prometheus registry
+func RegisterMetrics() {
+	metrics.Registry.MustRegister(outboxPendingEventsGauge, outboxPendingBytesGauge, outboxCollapsedEventsCounter)
+}
@@ -0,0 +1,454 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"go.uber.org/zap"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/tracing"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// DefaultOutboxMaxBytes is the default size limit of the pending events in the outbox
+	DefaultOutboxMaxBytes = 512 * 1024 * 1024
+
+	outboxFileName = "outbox.log"
+
+	outboxOpPut = "put"
+	outboxOpAck = "ack"
+
+	// the log is rewritten with only the pending events once the acked records exceed the threshold
+	outboxCompactThreshold = 1000
+
+	outboxMinRetryInterval = 1 * time.Second
+	outboxMaxRetryInterval = 30 * time.Second
+)
+
+// ErrOutboxFull means the pending events reach the size limit of the outbox, the event isn't persisted
+var ErrOutboxFull = errors.New("the transport outbox is full")
+
+// collapsibleEventTypes are the complete state bundles, the newer bundle carries the whole state of the older one, so
+// only the latest pending bundle needs to be delivered. The delta and hybrid bundles must never be collapsed.
+var collapsibleEventTypes = map[string]bool{
+	string(enum.HubClusterHeartbeatType):          true,
+	string(enum.HubClusterInfoType):               true,
+	string(enum.LocalComplianceType):              true,
+	string(enum.LocalCompleteComplianceType):      true,
+	string(enum.ComplianceType):                   true,
+	string(enum.CompleteComplianceType):           true,
+	string(enum.MiniComplianceType):               true,
+	string(enum.SecurityAlertCountsType):          true,
+	string(enum.SecurityClusterAlertCountsType):   true,
+	string(enum.SecurityPolicyViolationsType):     true,
+	string(enum.SecurityImageVulnerabilitiesType): true,
+	string(enum.LocalPlacementRuleSpecType):       true,
+	string(enum.PlacementRuleSpecType):            true,
+	string(enum.PlacementSpecType):                true,
+	string(enum.PlacementDecisionType):            true,
+	string(enum.SubscriptionReportType):           true,
+	string(enum.SubscriptionStatusType):           true,
+}
+
+// outboxRecord is a line of the outbox log, the put record carries the event and the ack record removes it
+type outboxRecord struct {
+	Seq   uint64             `json:"seq"`
+	Op    string             `json:"op"`
+	Topic string             `json:"topic,omitempty"`
+	Key   string             `json:"key,omitempty"`
+	Event *cloudevents.Event `json:"event,omitempty"`
+
+	// size is the length of the put line in the log
+	size int
+	// restored means the record is loaded from the log which is written by the previous process, the bundle version
+	// is reset once the agent is restarted, so it can't be compared with the versions of the current process
+	restored bool
+}
+
+// collapseKey identifies the bundle which is superseded by the newer one, the dependency version is included so the
+// bundle is never collapsed with the one based on another dependency
+func (r *outboxRecord) collapseKey() (string, bool) {
+	if !collapsibleEventTypes[r.Event.Type()] {
+		return "", false
+	}
+	dependency, _ := r.Event.Extensions()[version.ExtDependencyVersion].(string)
+	return fmt.Sprintf("%s/%s/%s/%s", r.Topic, r.Event.Source(), r.Event.Type(), dependency), true
+}
+
+// OutboxProducer persists the events into an append-only log before delivering them with the transport producer, so
+// the events survive the transport outages and the agent restarts. The events are delivered in order by a background
+// sender, and the pending complete state bundles are collapsed into the latest one while waiting.
+type OutboxProducer struct {
+	log      *zap.SugaredLogger
+	producer transport.Producer
+	dir      string
+	maxBytes int
+
+	mutex        sync.Mutex
+	file         *os.File
+	seq          uint64
+	pending      *list.List
+	elements     map[uint64]*list.Element
+	collapsible  map[string]*list.Element
+	pendingBytes int
+	acked        int
+	// inflight is the record is being delivered by the sender, it won't be collapsed
+	inflight *list.Element
+
+	notify chan struct{}
+}
+
+// NewOutboxProducer restores the pending events from the log in the dir, the events are delivered after it's started
+func NewOutboxProducer(producer transport.Producer, dir string, maxBytes int) (*OutboxProducer, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultOutboxMaxBytes
+	}
+	o := &OutboxProducer{
+		log:         logger.ZapLogger("transport-outbox"),
+		producer:    producer,
+		dir:         dir,
+		maxBytes:    maxBytes,
+		pending:     list.New(),
+		elements:    map[uint64]*list.Element{},
+		collapsible: map[string]*list.Element{},
+		notify:      make(chan struct{}, 1),
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox directory: %w", err)
+	}
+	if err := o.restore(); err != nil {
+		return nil, err
+	}
+	// rewrite the log to drop the acked and the truncated records of the previous process
+	if err := o.compact(); err != nil {
+		return nil, err
+	}
+	o.log.Infow("transport outbox is restored", "dir", dir, "pendingEvents", o.pending.Len(),
+		"pendingBytes", o.pendingBytes)
+	return o, nil
+}
+
+// SendEvent persists the event into the outbox, the event will be delivered by the sender in order. The topic and
+// the message key of the context are kept with the event.
+func (o *OutboxProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	// the event may be pending in the outbox for a while, so the emit time is stamped before persisting it
+	transport.SetEmitTime(&evt)
+	tracing.Inject(ctx, &evt)
+	record := &outboxRecord{
+		Op:    outboxOpPut,
+		Topic: cectx.TopicFrom(ctx),
+		Key:   transport.MessageKeyFrom(ctx),
+		Event: &evt,
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.seq++
+	record.Seq = o.seq
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the outbox record: %w", err)
+	}
+	record.size = len(line) + 1
+
+	// the superseded bundle is removed before checking the limit, since it's replaced by the current one
+	collapseKey, collapsible := record.collapseKey()
+	var superseded *list.Element
+	if collapsible {
+		if elem, found := o.collapsible[collapseKey]; found && elem != o.inflight &&
+			supersedes(record, elem.Value.(*outboxRecord)) {
+			superseded = elem
+		}
+	}
+	pendingBytes := o.pendingBytes + record.size
+	if superseded != nil {
+		pendingBytes -= superseded.Value.(*outboxRecord).size
+	}
+	if pendingBytes > o.maxBytes {
+		return fmt.Errorf("%w: %d bytes are pending", ErrOutboxFull, o.pendingBytes)
+	}
+
+	if err := o.append(line); err != nil {
+		return err
+	}
+	o.push(record)
+	if collapsible {
+		o.collapsible[collapseKey] = o.elements[record.Seq]
+	}
+	if superseded != nil {
+		// the event is persisted, delivering the superseded bundle again is harmless if it isn't acked
+		if err := o.ack(superseded.Value.(*outboxRecord).Seq); err != nil {
+			o.log.Warnw("failed to collapse the superseded event", "type", evt.Type(), "error", err)
+		} else {
+			outboxCollapsedEventsCounter.Inc()
+		}
+	}
+	o.updateMetrics()
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// supersedes returns true if the current bundle is newer than the pending one
+func supersedes(current, pending *outboxRecord) bool {
+	if pending.restored {
+		return true
+	}
+	currentVersion, err := version.VersionFrom(fmt.Sprintf("%v", current.Event.Extensions()[version.ExtVersion]))
+	if err != nil {
+		return false
+	}
+	pendingVersion, err := version.VersionFrom(fmt.Sprintf("%v", pending.Event.Extensions()[version.ExtVersion]))
+	if err != nil {
+		return false
+	}
+	return currentVersion.NewerThan(pendingVersion)
+}
+
+// Reconnect reconnects the underlying producer, the pending events are kept in the outbox
+func (o *OutboxProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return o.producer.Reconnect(config, topic)
+}
+
+// Start delivers the pending events in order until the context is done, the event is retried with backoff until it's
+// delivered by the underlying producer
+func (o *OutboxProducer) Start(ctx context.Context) error {
+	o.log.Info("transport outbox sender is started")
+	defer o.log.Info("transport outbox sender is stopped")
+
+	retryInterval := outboxMinRetryInterval
+	for {
+		record := o.next()
+		if record == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-o.notify:
+				continue
+			}
+		}
+
+		sendCtx := ctx
+		if record.Topic != "" {
+			sendCtx = cectx.WithTopic(sendCtx, record.Topic)
+		}
+		if record.Key != "" {
+			sendCtx = transport.WithMessageKey(sendCtx, record.Key)
+		}
+		if err := transport.SendEventSync(sendCtx, o.producer, *record.Event); err != nil {
+			o.log.Warnw("failed to deliver the event, retrying", "type", record.Event.Type(), "retryInterval",
+				retryInterval, "error", err)
+			o.release()
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryInterval):
+			}
+			retryInterval = min(retryInterval*2, outboxMaxRetryInterval)
+			continue
+		}
+		retryInterval = outboxMinRetryInterval
+
+		if err := o.complete(record); err != nil {
+			// the record will be delivered again after restarting, the manager drops the duplicated bundle
+			o.log.Errorw("failed to ack the delivered event", "type", record.Event.Type(), "error", err)
+		}
+	}
+}
+
+// next marks the earliest pending record as inflight and returns it, it returns nil if nothing is pending
+func (o *OutboxProducer) next() *outboxRecord {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.inflight = o.pending.Front()
+	if o.inflight == nil {
+		return nil
+	}
+	return o.inflight.Value.(*outboxRecord)
+}
+
+func (o *OutboxProducer) release() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.inflight = nil
+}
+
+// complete removes the delivered record from the outbox
+func (o *OutboxProducer) complete(record *outboxRecord) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.inflight = nil
+	if err := o.ack(record.Seq); err != nil {
+		return err
+	}
+	o.updateMetrics()
+	if o.acked > outboxCompactThreshold && o.acked > o.pending.Len() {
+		return o.compact()
+	}
+	return nil
+}
+
+// ack appends the ack record to the log, and removes the put record from the pending list
+func (o *OutboxProducer) ack(seq uint64) error {
+	line, err := json.Marshal(&outboxRecord{Seq: seq, Op: outboxOpAck})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the outbox record: %w", err)
+	}
+	if err := o.append(line); err != nil {
+		return err
+	}
+	o.remove(seq)
+	return nil
+}
+
+func (o *OutboxProducer) push(record *outboxRecord) {
+	o.elements[record.Seq] = o.pending.PushBack(record)
+	o.pendingBytes += record.size
+}
+
+func (o *OutboxProducer) remove(seq uint64) {
+	elem, found := o.elements[seq]
+	if !found {
+		return
+	}
+	record := o.pending.Remove(elem).(*outboxRecord)
+	delete(o.elements, seq)
+	if key, ok := record.collapseKey(); ok && o.collapsible[key] == elem {
+		delete(o.collapsible, key)
+	}
+	o.pendingBytes -= record.size
+	o.acked++
+}
+
+func (o *OutboxProducer) append(line []byte) error {
+	if _, err := o.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write the outbox log: %w", err)
+	}
+	if err := o.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync the outbox log: %w", err)
+	}
+	return nil
+}
+
+// restore loads the pending records from the log, the records after the broken line are dropped, since the line is
+// only partially written when the process is killed
+func (o *OutboxProducer) restore() error {
+	file, err := os.Open(filepath.Join(o.dir, outboxFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open the outbox log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			if len(line) > 0 {
+				o.log.Warnw("drop the partially written outbox record", "size", len(line))
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the outbox log: %w", err)
+		}
+
+		record := &outboxRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			o.log.Warnw("drop the broken outbox records", "seq", o.seq+1, "error", err)
+			return nil
+		}
+		o.seq = max(o.seq, record.Seq)
+		switch record.Op {
+		case outboxOpPut:
+			if record.Event == nil {
+				continue
+			}
+			record.size = len(line)
+			record.restored = true
+			o.push(record)
+			if key, ok := record.collapseKey(); ok {
+				o.collapsible[key] = o.elements[record.Seq]
+			}
+		case outboxOpAck:
+			o.remove(record.Seq)
+		}
+	}
+}
+
+// compact rewrites the log with the pending records, and switches the appending to the new log
+func (o *OutboxProducer) compact() error {
+	logFile := filepath.Join(o.dir, outboxFileName)
+	tmpFile := logFile + ".tmp"
+	file, err := os.OpenFile(tmpFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create the outbox log: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	for elem := o.pending.Front(); elem != nil; elem = elem.Next() {
+		line, err := json.Marshal(elem.Value.(*outboxRecord))
+		if err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to marshal the outbox record: %w", err)
+		}
+		if _, err := writer.Write(append(line, '\n')); err != nil {
+			_ = file.Close()
+			return fmt.Errorf("failed to write the outbox log: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write the outbox log: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to sync the outbox log: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close the outbox log: %w", err)
+	}
+	if err := os.Rename(tmpFile, logFile); err != nil {
+		return fmt.Errorf("failed to replace the outbox log: %w", err)
+	}
+
+	if o.file != nil {
+		_ = o.file.Close()
+	}
+	o.file, err = os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open the outbox log: %w", err)
+	}
+	o.acked = 0
+	return nil
+}
+
+func (o *OutboxProducer) updateMetrics() {
+	outboxPendingEventsGauge.Set(float64(o.pending.Len()))
+	outboxPendingBytesGauge.Set(float64(o.pendingBytes))
+}
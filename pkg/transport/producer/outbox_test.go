@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeProducer struct {
+	mutex  sync.Mutex
+	failed bool
+	topics []string
+	events []cloudevents.Event
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.failed {
+		return errors.New("all brokers are down")
+	}
+	p.topics = append(p.topics, cectx.TopicFrom(ctx))
+	p.events = append(p.events, evt)
+	return nil
+}
+
+func (p *fakeProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func (p *fakeProducer) setFailed(failed bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.failed = failed
+}
+
+func (p *fakeProducer) sent() []cloudevents.Event {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]cloudevents.Event{}, p.events...)
+}
+
+func newOutboxEvent(eventType enum.EventType, ver string) cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID(ver)
+	evt.SetType(string(eventType))
+	evt.SetSource("hub1")
+	evt.SetExtension(version.ExtVersion, ver)
+	_ = evt.SetData(cloudevents.ApplicationJSON, map[string]string{"version": ver})
+	return evt
+}
+
+func TestOutboxProducer(t *testing.T) {
+	dir := t.TempDir()
+	ctx := cectx.WithTopic(context.Background(), "gh-status.hub1")
+
+	sender := &fakeProducer{failed: true}
+	outbox, err := NewOutboxProducer(sender, dir, 0)
+	require.NoError(t, err)
+
+	// the superseded heartbeat is collapsed, but the delta events are kept in order
+	require.NoError(t, outbox.SendEvent(ctx, newOutboxEvent(enum.HubClusterHeartbeatType, "0.1")))
+	require.NoError(t, outbox.SendEvent(ctx, newOutboxEvent(enum.ManagedClusterEventType, "0.1")))
+	require.NoError(t, outbox.SendEvent(ctx, newOutboxEvent(enum.ManagedClusterEventType, "0.2")))
+	require.NoError(t, outbox.SendEvent(ctx, newOutboxEvent(enum.HubClusterHeartbeatType, "0.2")))
+	require.Equal(t, 3, outbox.pending.Len())
+
+	// the pending events are restored after restarting
+	restored, err := NewOutboxProducer(sender, dir, 0)
+	require.NoError(t, err)
+	require.Equal(t, 3, restored.pending.Len())
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = restored.Start(runCtx) }()
+
+	sender.setFailed(false)
+	require.Eventually(t, func() bool { return len(sender.sent()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	sent := sender.sent()
+	require.Equal(t, string(enum.ManagedClusterEventType), sent[0].Type())
+	require.Equal(t, "0.1", sent[0].ID())
+	require.Equal(t, "0.2", sent[1].ID())
+	require.Equal(t, string(enum.HubClusterHeartbeatType), sent[2].Type())
+	require.Equal(t, "0.2", sent[2].ID())
+	require.Equal(t, []string{"gh-status.hub1", "gh-status.hub1", "gh-status.hub1"}, sender.topics)
+
+	// the delivered events aren't restored again
+	require.Eventually(t, func() bool {
+		restored.mutex.Lock()
+		defer restored.mutex.Unlock()
+		return restored.pending.Len() == 0 && restored.inflight == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	reopened, err := NewOutboxProducer(sender, dir, 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, reopened.pending.Len())
+
+	// the event is rejected once the outbox is full
+	full, err := NewOutboxProducer(sender, t.TempDir(), 1)
+	require.NoError(t, err)
+	err = full.SendEvent(ctx, newOutboxEvent(enum.ManagedClusterEventType, "0.1"))
+	require.True(t, errors.Is(err, ErrOutboxFull))
+}
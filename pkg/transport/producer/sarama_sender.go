@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+// saramaSender sends the messages with the sarama sync producer. Unlike the sender of the kafka_sarama protocol, it
+// respects the topic and the message key from the context, which is the same as the confluent protocol.
+type saramaSender struct {
+	producer     sarama.SyncProducer
+	defaultTopic string
+}
+
+var (
+	_ protocol.Sender = (*saramaSender)(nil)
+	_ protocol.Closer = (*saramaSender)(nil)
+)
+
+func (s *saramaSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() { _ = m.Finish(err) }()
+
+	topic := cectx.TopicFrom(ctx)
+	if topic == "" {
+		topic = s.defaultTopic
+	}
+	msg := &sarama.ProducerMessage{Topic: topic}
+	if key := transport.MessageKeyFrom(ctx); key != "" {
+		msg.Key = sarama.StringEncoder(key)
+	}
+	if err = kafka_sarama.WriteProducerMessage(ctx, m, msg, transformers...); err != nil {
+		return err
+	}
+	_, _, err = s.producer.SendMessage(msg)
+	return err
+}
+
+func (s *saramaSender) Close(ctx context.Context) error {
+	return s.producer.Close()
+}
+
+func getSaramaSenderProtocol(kafkaCredential *transport.KafkaConfig, defaultTopic string) (*saramaSender, error) {
+	saramaConfig, err := config.GetSaramaConfigByKafkaCredential(kafkaCredential, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	producer, err := sarama.NewSyncProducer(strings.Split(kafkaCredential.BootstrapServer, ","), saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &saramaSender{producer: producer, defaultTopic: defaultTopic}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package producer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cectx "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestSaramaSender(t *testing.T) {
+	mockProducer := mocks.NewSyncProducer(t, sarama.NewConfig())
+	sender := &saramaSender{producer: mockProducer, defaultTopic: "gh-status"}
+	client, err := cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
+	require.NoError(t, err)
+	p := &GenericProducer{
+		log:              logger.ZapLogger("sarama-producer"),
+		ceProtocol:       sender,
+		ceClient:         client,
+		messageSizeLimit: 10,
+	}
+
+	headers := func(msg *sarama.ProducerMessage) map[string]string {
+		values := map[string]string{}
+		for _, header := range msg.Headers {
+			values[string(header.Key)] = string(header.Value)
+		}
+		return values
+	}
+
+	evt := cloudevents.NewEvent()
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster")
+	evt.SetSource("hub1")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`{"name":"cluster1"}`)))
+
+	// the message is sent to the default topic with the event type as the key
+	mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		key, _ := msg.Key.Encode()
+		if msg.Topic != "gh-status" || string(key) != evt.Type() {
+			return fmt.Errorf("unexpected topic %s or key %s", msg.Topic, key)
+		}
+		return nil
+	})
+	p.SetDataLimit(1024)
+	require.NoError(t, p.SendEvent(context.Background(), evt))
+
+	// the chunks are sent to the topic with the key from the context
+	ctx := transport.WithMessageKey(cectx.WithTopic(context.Background(), "gh-status.hub1"), "hub1")
+	for range 2 {
+		mockProducer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			key, _ := msg.Key.Encode()
+			if msg.Topic != "gh-status.hub1" || string(key) != "hub1" {
+				return fmt.Errorf("unexpected topic %s or key %s", msg.Topic, key)
+			}
+			if headers(msg)["ce_"+transport.ChunkSizeKey] != "19" {
+				return fmt.Errorf("unexpected chunk size: %v", headers(msg))
+			}
+			return nil
+		})
+	}
+	p.SetDataLimit(10)
+	require.NoError(t, p.SendEvent(ctx, evt))
+
+	// the error of the sync producer is returned to the caller
+	mockProducer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	p.SetDataLimit(1024)
+	require.ErrorContains(t, p.SendEvent(context.Background(), evt), sarama.ErrOutOfBrokers.Error())
+
+	require.NoError(t, sender.Close(context.Background()))
+}
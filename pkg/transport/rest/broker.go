@@ -0,0 +1,266 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// BrokerKey is the key of the TransportInternalConfig.Extends to share the broker between the rest server,
+	// producer and consumer in the global hub manager
+	BrokerKey = "rest-broker"
+
+	// DefaultRetention is the count of the events retained in the spec topic for the long polling clients
+	DefaultRetention = 1000
+
+	// OldestOffset indicates the client to fetch from the oldest retained event of the topic
+	OldestOffset int64 = -1
+)
+
+// Broker relays the cloudevents between the global hub manager and agents over the restful endpoint. The retained
+// topics(the spec topic of the manager producer) are the append only logs with bounded retention, the remote clients
+// long poll the log with their own offset. The other topics(the status topics of the hubs) aren't retained, they're
+// delivered to the matched local subscribers(the manager consumer) directly.
+// The logs are kept in memory, so the offsets restart from 0 once the manager is restarted. The epoch identifies the
+// logs of the broker, the clients polling with the offset of another epoch start from the oldest retained event.
+type Broker struct {
+	mutex       sync.Mutex
+	epoch       string
+	retention   int
+	topics      map[string]*topicLog
+	subscribers []*subscriber
+	// offsets are the next offsets of the topics which aren't retained
+	offsets map[string]int64
+}
+
+type topicLog struct {
+	// firstOffset is the offset of the events[0]
+	firstOffset int64
+	events      []*cloudevents.Event
+	// notify is closed and replaced once a new event is appended
+	notify chan struct{}
+}
+
+// ErrNoSubscriber is returned by publishing the event of the topic which is neither retained nor subscribed
+var ErrNoSubscriber = errors.New("no subscriber of the topic")
+
+type subscriber struct {
+	topics  []string
+	pattern []*regexp.Regexp
+	events  chan *cloudevents.Event
+	done    <-chan struct{}
+}
+
+func NewBroker(retention int) *Broker {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &Broker{
+		epoch:     uuid.New().String(),
+		retention: retention,
+		topics:    map[string]*topicLog{},
+		offsets:   map[string]int64{},
+	}
+}
+
+// Epoch returns the identity of the topic logs, it's changed once the broker is recreated
+func (b *Broker) Epoch() string {
+	return b.epoch
+}
+
+// Retain keeps the events of the topic for the polling clients, e.g. the spec topic published by the local producer
+func (b *Broker) Retain(topic string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if _, ok := b.topics[topic]; !ok {
+		b.topics[topic] = &topicLog{notify: make(chan struct{})}
+	}
+}
+
+// Retained returns whether the events of the topic are kept for the polling clients
+func (b *Broker) Retained(topic string) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	_, ok := b.topics[topic]
+	return ok
+}
+
+// Publish appends the event into the log of the retained topic, and delivers it to the local subscribers of the
+// topic. It blocks until the subscribers accept the event, so the sender is throttled by the slowest local consumer.
+// The event of the topic which isn't retained is only delivered to the subscribers, so the ErrNoSubscriber is
+// returned if there isn't any.
+func (b *Broker) Publish(ctx context.Context, topic string, evt cloudevents.Event) error {
+	b.mutex.Lock()
+	matched := []*subscriber{}
+	for _, s := range b.subscribers {
+		if s.match(topic) {
+			matched = append(matched, s)
+		}
+	}
+
+	tl, retained := b.topics[topic]
+	if !retained && len(matched) == 0 {
+		b.mutex.Unlock()
+		return fmt.Errorf("%w %s", ErrNoSubscriber, topic)
+	}
+	var offset int64
+	if retained {
+		offset = tl.firstOffset + int64(len(tl.events))
+	} else {
+		offset = b.offsets[topic]
+		b.offsets[topic] = offset + 1
+	}
+
+	// the kafka extensions are used by the manager to track the consumed position of the event
+	evt.SetExtension(transport.KafkaTopicKey, topic)
+	evt.SetExtension(transport.KafkaPartitionKey, "0")
+	evt.SetExtension(transport.KafkaOffsetKey, strconv.FormatInt(offset, 10))
+
+	if retained {
+		tl.events = append(tl.events, &evt)
+		if len(tl.events) > b.retention {
+			evicted := len(tl.events) - b.retention
+			tl.events = tl.events[evicted:]
+			tl.firstOffset += int64(evicted)
+		}
+		close(tl.notify)
+		tl.notify = make(chan struct{})
+	}
+	b.mutex.Unlock()
+
+	for _, s := range matched {
+		delivered := evt.Clone()
+		select {
+		case s.events <- &delivered:
+		case <-s.done:
+			// the subscriber has been stopped
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Fetch returns the retained events of the topic from the offset, and the offset for the next fetching. It waits
+// until any event is available or the context is done if there is no event after the offset. Nothing is returned
+// if the topic isn't retained.
+func (b *Broker) Fetch(ctx context.Context, topic string, offset int64, max int) ([]cloudevents.Event, int64) {
+	for {
+		b.mutex.Lock()
+		tl, ok := b.topics[topic]
+		if !ok {
+			b.mutex.Unlock()
+			return nil, offset
+		}
+		if offset < tl.firstOffset {
+			// the events before the first offset have been evicted, start from the oldest one
+			offset = tl.firstOffset
+		}
+		start := int(offset - tl.firstOffset)
+		if start < len(tl.events) {
+			end := len(tl.events)
+			if max > 0 && start+max < end {
+				end = start + max
+			}
+			events := make([]cloudevents.Event, 0, end-start)
+			for _, evt := range tl.events[start:end] {
+				events = append(events, evt.Clone())
+			}
+			b.mutex.Unlock()
+			return events, offset + int64(len(events))
+		}
+		notify := tl.notify
+		b.mutex.Unlock()
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return nil, offset
+		}
+	}
+}
+
+// Subscribe registers a local subscriber for the topics, the topic starting with '^' is matched as a regex just
+// like the kafka consumer does. The subscriber is removed once the context is done.
+func (b *Broker) Subscribe(ctx context.Context, topics []string) <-chan *cloudevents.Event {
+	s := &subscriber{
+		events: make(chan *cloudevents.Event),
+		done:   ctx.Done(),
+	}
+	for _, topic := range topics {
+		if strings.HasPrefix(topic, "^") {
+			if re, err := regexp.Compile(topic); err == nil {
+				s.pattern = append(s.pattern, re)
+				continue
+			}
+		}
+		s.topics = append(s.topics, topic)
+	}
+
+	b.mutex.Lock()
+	b.subscribers = append(b.subscribers, s)
+	b.mutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		for i, sub := range b.subscribers {
+			if sub == s {
+				b.subscribers = append(b.subscribers[:i], b.subscribers[i+1:]...)
+				break
+			}
+		}
+	}()
+	return s.events
+}
+
+// StatusTopics returns the status topics of the hub consumed by the local subscribers. The status topic of the hub
+// is the subscribed topic with the '*' replaced by the hub, e.g. '^gh-status.*' -> 'gh-status.hub1', and the
+// subscribed topic without '*' is shared by all the hubs.
+func (b *Broker) StatusTopics(hub string) []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	topics := []string{}
+	for _, s := range b.subscribers {
+		subscribed := append([]string{}, s.topics...)
+		for _, re := range s.pattern {
+			subscribed = append(subscribed, re.String())
+		}
+		for _, topic := range subscribed {
+			topic = strings.ReplaceAll(strings.TrimPrefix(topic, "^"), "*", hub)
+			if s.match(topic) && !slices.Contains(topics, topic) {
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
+
+func (s *subscriber) match(topic string) bool {
+	for _, t := range s.topics {
+		if t == topic {
+			return true
+		}
+	}
+	for _, re := range s.pattern {
+		if re.MatchString(topic) {
+			return true
+		}
+	}
+	return false
+}
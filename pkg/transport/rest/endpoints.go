@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package rest
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ServiceName is the service of the rest transport server, it has no selector, so the endpoints are only
+	// pointed to the leader of the manager replicas
+	ServiceName = "multicluster-global-hub-manager-transport"
+	// ServicePortName is the port name of the rest transport service
+	ServicePortName = "rest-transport"
+
+	endpointsFieldOwner = "multicluster-global-hub-manager"
+)
+
+// LeaderEndpoints routes the rest transport service to the pod of the leader, since the server and the local
+// consumer only run in the leader. The endpoints are applied once the replica is elected, and the previous leader
+// has stopped by then.
+type LeaderEndpoints struct {
+	client    client.Client
+	namespace string
+	podIP     string
+	port      int32
+}
+
+func NewLeaderEndpoints(c client.Client, namespace, podIP string, port int32) *LeaderEndpoints {
+	return &LeaderEndpoints{client: c, namespace: namespace, podIP: podIP, port: port}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the endpoints are only applied by the leader
+func (e *LeaderEndpoints) NeedLeaderElection() bool {
+	return true
+}
+
+// Start applies the endpoints of the service with the pod IP of the leader
+func (e *LeaderEndpoints) Start(ctx context.Context) error {
+	endpoints := &corev1.Endpoints{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Endpoints"},
+		ObjectMeta: metav1.ObjectMeta{Name: ServiceName, Namespace: e.namespace},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: e.podIP}},
+			Ports:     []corev1.EndpointPort{{Name: ServicePortName, Port: e.port, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	err := retry.OnError(retry.DefaultBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+		return e.client.Patch(ctx, endpoints, client.Apply, client.FieldOwner(endpointsFieldOwner),
+			client.ForceOwnership)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to route the rest transport service to the leader: %w", err)
+	}
+	log.Infow("routed the rest transport service to the leader", "podIP", e.podIP, "port", e.port)
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package rest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+)
+
+const pollingRetryInterval = 5 * time.Second
+
+// TopicURL returns the endpoint of the topic for the hub on the restful transport server
+func TopicURL(host, hub, topic string) string {
+	return strings.TrimSuffix(host, "/") + HubsPath + url.PathEscape(hub) + EventsPath + url.PathEscape(topic)
+}
+
+// BrokerSender publishes the events into the local broker, it's used by the producer of the global hub manager
+type BrokerSender struct {
+	broker *Broker
+	topic  string
+}
+
+// NewBrokerSender retains the topic in the broker, so the published events are polled by the remote clients
+func NewBrokerSender(broker *Broker, topic string) *BrokerSender {
+	broker.Retain(topic)
+	return &BrokerSender{broker: broker, topic: topic}
+}
+
+func (s *BrokerSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() { _ = m.Finish(err) }()
+	evt, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	return s.broker.Publish(ctx, s.topic, *evt)
+}
+
+// BrokerReceiver receives the events from the local broker, it's used by the consumer of the global hub manager
+type BrokerReceiver struct {
+	broker *Broker
+	topics []string
+	once   sync.Once
+	events <-chan *cloudevents.Event
+}
+
+func NewBrokerReceiver(broker *Broker, topics []string) *BrokerReceiver {
+	return &BrokerReceiver{broker: broker, topics: topics}
+}
+
+func (r *BrokerReceiver) Receive(ctx context.Context) (binding.Message, error) {
+	r.once.Do(func() {
+		r.events = r.broker.Subscribe(ctx, r.topics)
+	})
+	select {
+	case evt := <-r.events:
+		return binding.ToMessage(evt), nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// PollingReceiver long polls the events of the topic from the restful transport server, it's used by the consumer
+// of the global hub agent. The offset is kept in memory, so it starts from the oldest retained event after restart,
+// and the offset is reset by the server if the epoch of the broker is changed, e.g. the manager is restarted.
+type PollingReceiver struct {
+	client  *http.Client
+	url     string
+	timeout time.Duration
+
+	mutex    sync.Mutex
+	offset   int64
+	epoch    string
+	buffered []cloudevents.Event
+}
+
+func NewPollingReceiver(client *http.Client, host, hub, topic string) *PollingReceiver {
+	return &PollingReceiver{
+		client:  client,
+		url:     TopicURL(host, hub, topic),
+		timeout: DefaultPollTimeout,
+		offset:  OldestOffset,
+	}
+}
+
+func (r *PollingReceiver) Receive(ctx context.Context) (binding.Message, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for len(r.buffered) == 0 {
+		if ctx.Err() != nil {
+			return nil, io.EOF
+		}
+		events, next, epoch, err := r.poll(ctx)
+		if err != nil {
+			log.Warnw("failed to poll the events, retrying", "url", r.url, "error", err)
+			select {
+			case <-time.After(pollingRetryInterval):
+			case <-ctx.Done():
+				return nil, io.EOF
+			}
+			continue
+		}
+		if r.epoch != "" && r.epoch != epoch {
+			log.Infow("the epoch of the server is changed, polling from the oldest event", "url", r.url,
+				"epoch", epoch)
+		}
+		r.buffered = events
+		r.offset = next
+		r.epoch = epoch
+	}
+
+	evt := r.buffered[0]
+	r.buffered = r.buffered[1:]
+	return binding.ToMessage(&evt), nil
+}
+
+func (r *PollingReceiver) poll(ctx context.Context) ([]cloudevents.Event, int64, string, error) {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(r.offset, 10))
+	query.Set("timeout", r.timeout.String())
+	if r.epoch != "" {
+		query.Set("epoch", r.epoch)
+	}
+
+	// leave the server enough time to respond the empty batch once the polling timeout is reached
+	pollCtx, cancel := context.WithTimeout(ctx, r.timeout+10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(pollCtx, http.MethodGet, r.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, r.offset, r.epoch, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, r.offset, r.epoch, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, r.offset, r.epoch, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	next, err := strconv.ParseInt(resp.Header.Get(NextOffsetHeader), 10, 64)
+	if err != nil {
+		return nil, r.offset, r.epoch, fmt.Errorf("invalid header %s: %w", NextOffsetHeader, err)
+	}
+	events, err := cehttp.NewEventsFromHTTPResponse(resp)
+	if err != nil {
+		return nil, r.offset, r.epoch, err
+	}
+	return events, next, resp.Header.Get(EpochHeader), nil
+}
+
+// NewHTTPClient returns the client to access the restful transport server with the mTLS of the credential
+func NewHTTPClient(conn *transport.RestfulConfig) (*http.Client, error) {
+	tlsConfig, err := config.GetRestfulTLSConfig(conn)
+	if err != nil {
+		return nil, err
+	}
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+	httpTransport.TLSClientConfig = tlsConfig
+	return &http.Client{Transport: httpTransport}, nil
+}
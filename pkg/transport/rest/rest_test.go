@@ -0,0 +1,302 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/consumer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/producer"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/rest"
+)
+
+func TestRestTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broker := rest.NewBroker(10)
+	server := httptest.NewServer(rest.NewHandler(broker))
+	defer server.Close()
+
+	managerConfig := &transport.TransportInternalConfig{
+		TransportType: string(transport.Rest),
+		Extends:       map[string]interface{}{rest.BrokerKey: broker},
+	}
+	agentConfig := &transport.TransportInternalConfig{
+		TransportType: string(transport.Rest),
+		RestfulCredential: &transport.RestfulConfig{
+			Host:        server.URL,
+			Hub:         "hub1",
+			SpecTopic:   "gh-spec",
+			StatusTopic: "gh-status.hub1",
+		},
+	}
+
+	// status: the agent posts the chunked event to the manager
+	managerConsumer, err := consumer.NewGenericConsumer(managerConfig, []string{"^gh-status.*"})
+	require.NoError(t, err)
+	go func() {
+		_ = managerConsumer.Start(ctx)
+	}()
+
+	agentProducer, err := producer.NewGenericProducer(agentConfig, "gh-status.hub1", nil)
+	require.NoError(t, err)
+	agentProducer.SetDataLimit(8)
+
+	statusEvent := newEvent(t, "status")
+	// resend the event until the manager consumer subscribes the broker
+	var received *cloudevents.Event
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, agentProducer.SendEvent(ctx, statusEvent))
+		select {
+		case received = <-managerConsumer.EventChan():
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, statusEvent.ID(), received.ID())
+	assert.Equal(t, statusEvent.Data(), received.Data())
+
+	// spec: the manager publishes the event into the broker, and the agent long polls it
+	managerProducer, err := producer.NewGenericProducer(managerConfig, "gh-spec", nil)
+	require.NoError(t, err)
+	specEvent := newEvent(t, "spec")
+	require.NoError(t, managerProducer.SendEvent(ctx, specEvent))
+
+	agentConsumer, err := consumer.NewGenericConsumer(agentConfig, []string{"gh-spec"})
+	require.NoError(t, err)
+	go func() {
+		_ = agentConsumer.Start(ctx)
+	}()
+
+	select {
+	case received = <-agentConsumer.EventChan():
+		assert.Equal(t, specEvent.ID(), received.ID())
+		assert.Equal(t, specEvent.Data(), received.Data())
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout to poll the spec event")
+	}
+}
+
+func TestBrokerRetention(t *testing.T) {
+	broker := rest.NewBroker(2)
+	broker.Retain("gh-spec")
+	for i := 0; i < 3; i++ {
+		require.NoError(t, broker.Publish(context.Background(), "gh-spec", newEvent(t, "spec")))
+	}
+
+	// the first event is evicted, so the fetching starts from the oldest retained one
+	events, next := broker.Fetch(context.Background(), "gh-spec", rest.OldestOffset, 10)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(3), next)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	events, next = broker.Fetch(ctx, "gh-spec", next, 10)
+	assert.Empty(t, events)
+	assert.Equal(t, int64(3), next)
+}
+
+func newEvent(t *testing.T, message string) cloudevents.Event {
+	e := cloudevents.NewEvent()
+	e.SetID(uuid.New().String())
+	e.SetType("com.cloudevents.sample.sent")
+	e.SetSource("hub1")
+	require.NoError(t, e.SetData(cloudevents.ApplicationJSON, map[string]string{"message": message}))
+	return e
+}
+
+func TestBrokerEpoch(t *testing.T) {
+	broker := rest.NewBroker(10)
+	broker.Retain("gh-spec")
+	require.NoError(t, broker.Publish(context.Background(), "gh-spec", newEvent(t, "spec")))
+	server := httptest.NewServer(rest.NewHandler(broker))
+	defer server.Close()
+
+	// the offset of the same epoch is kept
+	resp := poll(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-spec"), 1, broker.Epoch())
+	assert.Equal(t, "1", resp.Header.Get(rest.NextOffsetHeader))
+	assert.Equal(t, broker.Epoch(), resp.Header.Get(rest.EpochHeader))
+
+	// the manager is restarted, the offset of the previous epoch is reset to the oldest retained event
+	resp = poll(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-spec"), 5, "previous-epoch")
+	assert.Equal(t, "1", resp.Header.Get(rest.NextOffsetHeader))
+}
+
+func TestServerRequiresMTLS(t *testing.T) {
+	server := rest.NewServer(&rest.ServerConfig{Address: "127.0.0.1:0"}, rest.NewBroker(10))
+	assert.Error(t, server.Start(context.Background()))
+}
+
+func TestAuthorizeHub(t *testing.T) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caBytes)
+	require.NoError(t, err)
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+
+	broker := rest.NewBroker(10)
+	broker.Retain("gh-spec")
+	server := httptest.NewUnstartedServer(rest.NewHandler(broker))
+	server.TLS = &tls.Config{ClientCAs: caPool, ClientAuth: tls.RequireAndVerifyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	clientKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	clientBytes, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "hub1"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}, caCert, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	client := server.Client()
+	client.Transport.(*http.Transport).TLSClientConfig.Certificates = []tls.Certificate{
+		{Certificate: [][]byte{clientBytes}, PrivateKey: clientKey},
+	}
+
+	// the hub can only access its own path
+	resp := poll(t, client, rest.TopicURL(server.URL, "hub1", "gh-spec"), rest.OldestOffset, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = poll(t, client, rest.TopicURL(server.URL, "hub2", "gh-spec"), rest.OldestOffset, "")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// the hub can't post the events of the other hubs
+	agentProducer, err := producer.NewGenericProducer(&transport.TransportInternalConfig{
+		TransportType: string(transport.Rest),
+		RestfulCredential: &transport.RestfulConfig{
+			Host: server.URL,
+			Hub:  "hub2",
+		},
+	}, "gh-status.hub2", nil)
+	require.NoError(t, err)
+	evt := newEvent(t, "status")
+	evt.SetSource("hub2")
+	assert.Error(t, agentProducer.SendEvent(context.Background(), evt))
+}
+
+func TestAuthorizeTopic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	broker := rest.NewBroker(10)
+	broker.Retain("gh-spec")
+	server := httptest.NewServer(rest.NewHandler(broker))
+	defer server.Close()
+
+	// the status can't be published before it's consumed
+	resp := publish(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-status.hub1"), newEvent(t, "status"))
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	events := broker.Subscribe(ctx, []string{"^gh-status.*"})
+	assert.Equal(t, []string{"gh-status.hub1"}, broker.StatusTopics("hub1"))
+
+	// the hub can only publish to its own status topic
+	resp = publish(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-status.hub2"), newEvent(t, "status"))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = publish(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-spec"), newEvent(t, "spec"))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// the hub can only poll the spec topic
+	resp = poll(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-status.hub2"), rest.OldestOffset, "")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp = poll(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-spec"), rest.OldestOffset, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the status is delivered to the subscriber without retaining it
+	statuses := make(chan int, 1)
+	go func() {
+		statuses <- publish(t, server.Client(), rest.TopicURL(server.URL, "hub1", "gh-status.hub1"),
+			newEvent(t, "status")).StatusCode
+	}()
+	select {
+	case received := <-events:
+		assert.Equal(t, "0", received.Extensions()[transport.KafkaOffsetKey])
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout to receive the status event")
+	}
+	assert.Equal(t, http.StatusAccepted, <-statuses)
+	assert.False(t, broker.Retained("gh-status.hub1"))
+}
+
+func publish(t *testing.T, client *http.Client, topicURL string, evt cloudevents.Event) *http.Response {
+	body, err := json.Marshal(evt)
+	require.NoError(t, err)
+	resp, err := client.Post(topicURL, cloudevents.ApplicationCloudEventsJSON, bytes.NewReader(body))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func poll(t *testing.T, client *http.Client, topicURL string, offset int64, epoch string) *http.Response {
+	query := url.Values{}
+	query.Set("offset", strconv.FormatInt(offset, 10))
+	query.Set("timeout", "100ms")
+	if epoch != "" {
+		query.Set("epoch", epoch)
+	}
+	resp, err := client.Get(topicURL + "?" + query.Encode())
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func TestLeaderEndpoints(t *testing.T) {
+	var applied *corev1.Endpoints
+	c := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch,
+			opts ...client.PatchOption,
+		) error {
+			applied = obj.(*corev1.Endpoints)
+			return nil
+		},
+	}).Build()
+
+	endpoints := rest.NewLeaderEndpoints(c, "multicluster-global-hub", "10.0.0.1", 9444)
+	assert.True(t, endpoints.NeedLeaderElection())
+	require.NoError(t, endpoints.Start(context.Background()))
+
+	// the service is routed to the pod of the leader
+	require.NotNil(t, applied)
+	assert.Equal(t, rest.ServiceName, applied.Name)
+	assert.Equal(t, "10.0.0.1", applied.Subsets[0].Addresses[0].IP)
+	assert.Equal(t, rest.ServicePortName, applied.Subsets[0].Ports[0].Name)
+	assert.Equal(t, int32(9444), applied.Subsets[0].Ports[0].Port)
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package rest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// HubsPath and EventsPath are the paths of the topics, the hub posts the events to or polls the events from
+	// '<host>/hubs/<hub>/events/<topic>'
+	HubsPath   = "/hubs/"
+	EventsPath = "/events/"
+	// NextOffsetHeader is the response header of the polling request, which is the offset for the next polling
+	NextOffsetHeader = "X-Transport-Next-Offset"
+	// EpochHeader is the response header of the polling request, which is the epoch of the offsets. The client
+	// polls with the epoch, so the offset of the previous broker is reset to the oldest retained event.
+	EpochHeader = "X-Transport-Epoch"
+
+	// DefaultPollTimeout is the max waiting time of the long polling request
+	DefaultPollTimeout = 30 * time.Second
+	defaultPollSize    = 100
+
+	secondsToFinishOnShutdown = 5
+)
+
+var log = logger.ZapLogger("rest-transport")
+
+// ServerConfig specifies the restful transport server in the global hub manager. The server requires the client
+// certificate signed by the ClientCAFile, and the common name of the certificate must be the hub in the path.
+type ServerConfig struct {
+	Address      string
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	Retention    int
+}
+
+// Server receives the events posted by the agents and serves the long polling requests from them
+type Server struct {
+	config *ServerConfig
+	broker *Broker
+}
+
+func NewServer(config *ServerConfig, broker *Broker) *Server {
+	return &Server{config: config, broker: broker}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the events should only be received by the
+// leader, since the local consumer only runs in the leader. The LeaderEndpoints routes the service to the leader.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the restful transport server within given context
+func (s *Server) Start(ctx context.Context) error {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+	svr := &http.Server{
+		Addr:              s.config.Address,
+		Handler:           NewHandler(s.broker),
+		ReadHeaderTimeout: time.Minute * 1,
+		TLSConfig:         tlsConfig,
+	}
+
+	go func() {
+		<-ctx.Done()
+		log.Info("shutting down the restful transport server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), secondsToFinishOnShutdown*time.Second)
+		defer cancel()
+		if err := svr.Shutdown(shutdownCtx); err != nil {
+			log.Errorw("error shutting down the restful transport server", "error", err)
+		}
+	}()
+
+	log.Infow("starting the restful transport server", "address", s.config.Address)
+	err = svr.ListenAndServeTLS(s.config.CertFile, s.config.KeyFile)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("restful transport server stopped with error: %w", err)
+	}
+	return nil
+}
+
+// tlsConfig requires the mTLS, so the hub is authenticated by the client certificate
+func (s *Server) tlsConfig() (*tls.Config, error) {
+	if s.config.CertFile == "" || s.config.KeyFile == "" || s.config.ClientCAFile == "" {
+		return nil, fmt.Errorf("the server certificate, key and client CA are required by the restful transport server")
+	}
+	// #nosec G402
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	caCert, err := os.ReadFile(s.config.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the client CA: %w", err)
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to append client CA certificate to pool")
+	}
+	tlsConfig.ClientCAs = caCertPool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	return tlsConfig, nil
+}
+
+// NewHandler returns the handler of the topics:
+//   - POST <HubsPath><hub><EventsPath><topic>: publish the cloudevent(binary or structured mode) of the hub to the
+//     status topic of the hub, the source of the event must be the hub
+//   - GET <HubsPath><hub><EventsPath><topic>?offset=<offset>&epoch=<epoch>&timeout=<duration>: long poll the events
+//     of the retained topic(the spec topic) from the offset, the events are returned in the batch mode, and the next
+//     offset and the epoch are set in the NextOffsetHeader and EpochHeader
+//
+// The client certificate of the TLS request must be issued for the hub.
+func NewHandler(broker *Broker) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HubsPath, func(w http.ResponseWriter, req *http.Request) {
+		hub, topic, found := strings.Cut(strings.TrimPrefix(req.URL.Path, HubsPath), EventsPath)
+		if !found || hub == "" || topic == "" || strings.Contains(hub, "/") || strings.Contains(topic, "/") {
+			http.Error(w, "invalid hub or topic", http.StatusNotFound)
+			return
+		}
+		if status, err := authorize(req, hub); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		switch req.Method {
+		case http.MethodPost:
+			handlePublish(broker, hub, topic, w, req)
+		case http.MethodGet:
+			handleFetch(broker, topic, w, req)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	return mux
+}
+
+// authorize verifies the common name of the client certificate is the hub, the request without TLS isn't verified
+// since the server always requires the mTLS, e.g. it's only handled without TLS by the testing server
+func authorize(req *http.Request, hub string) (int, error) {
+	if req.TLS == nil {
+		return http.StatusOK, nil
+	}
+	if len(req.TLS.PeerCertificates) == 0 {
+		return http.StatusUnauthorized, fmt.Errorf("the client certificate is required")
+	}
+	if cn := req.TLS.PeerCertificates[0].Subject.CommonName; cn != hub {
+		return http.StatusForbidden, fmt.Errorf("the client %s isn't allowed to access the hub %s", cn, hub)
+	}
+	return http.StatusOK, nil
+}
+
+func handlePublish(broker *Broker, hub, topic string, w http.ResponseWriter, req *http.Request) {
+	statusTopics := broker.StatusTopics(hub)
+	if len(statusTopics) == 0 {
+		http.Error(w, "the status topics aren't consumed yet", http.StatusServiceUnavailable)
+		return
+	}
+	if !slices.Contains(statusTopics, topic) {
+		http.Error(w, fmt.Sprintf("the topic %s isn't the status topic of the hub %s", topic, hub),
+			http.StatusForbidden)
+		return
+	}
+	evt, err := cehttp.NewEventFromHTTPRequest(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid cloudevent: %v", err), http.StatusBadRequest)
+		return
+	}
+	if evt.Source() != hub {
+		http.Error(w, fmt.Sprintf("the source %s of the event isn't the hub %s", evt.Source(), hub),
+			http.StatusForbidden)
+		return
+	}
+	if err := broker.Publish(req.Context(), topic, *evt); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func handleFetch(broker *Broker, topic string, w http.ResponseWriter, req *http.Request) {
+	if !broker.Retained(topic) {
+		http.Error(w, fmt.Sprintf("the topic %s can't be polled", topic), http.StatusForbidden)
+		return
+	}
+	offset := OldestOffset
+	if val := req.URL.Query().Get("offset"); val != "" {
+		parsed, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid offset: %s", val), http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+	// the offset of the other epoch is meaningless, e.g. the manager is restarted
+	if epoch := req.URL.Query().Get("epoch"); epoch != "" && epoch != broker.Epoch() {
+		offset = OldestOffset
+	}
+	timeout := DefaultPollTimeout
+	if val := req.URL.Query().Get("timeout"); val != "" {
+		parsed, err := time.ParseDuration(val)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid timeout: %s", val), http.StatusBadRequest)
+			return
+		}
+		if parsed < timeout {
+			timeout = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	events, next := broker.Fetch(ctx, topic, offset, defaultPollSize)
+	if events == nil {
+		events = []cloudevents.Event{}
+	}
+
+	body, err := json.Marshal(events)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", cloudevents.ApplicationCloudEventsBatchJSON)
+	w.Header().Set(NextOffsetHeader, strconv.FormatInt(next, 10))
+	w.Header().Set(EpochHeader, broker.Epoch())
+	if _, err := w.Write(body); err != nil {
+		log.Warnw("failed to write the polling response", "topic", topic, "error", err)
+	}
+}
@@ -3,12 +3,17 @@ package transport
 import "sigs.k8s.io/kustomize/kyaml/yaml"
 
 type RestfulConfig struct {
-	Host             string `yaml:"host"`
+	Host string `yaml:"host"`
+	// Hub is the hub of the agent for the rest transport, it must be the common name of the client certificate
+	Hub              string `yaml:"hub,omitempty"`
 	CACert           string `yaml:"ca.crt,omitempty"`
 	ClientCert       string `yaml:"client.crt,omitempty"`
 	ClientKey        string `yaml:"client.key,omitempty"`
 	CASecretName     string `yaml:"ca.secret,omitempty"`
 	ClientSecretName string `yaml:"client.secret,omitempty"`
+	// the topics are only required by the rest transport, the events are posted to or polled from the topics
+	SpecTopic   string `yaml:"spec.topic,omitempty"`
+	StatusTopic string `yaml:"status.topic,omitempty"`
 }
 
 // YamlMarshal marshal the connection credential object, rawCert specifies whether to keep the cert in the data directly
@@ -29,11 +34,14 @@ func (k *RestfulConfig) YamlMarshal(rawCert bool) ([]byte, error) {
 func (k *RestfulConfig) DeepCopy() *RestfulConfig {
 	return &RestfulConfig{
 		Host:             k.Host,
+		Hub:              k.Hub,
 		CACert:           k.CACert,
 		ClientCert:       k.ClientCert,
 		ClientKey:        k.ClientKey,
 		CASecretName:     k.CASecretName,
 		ClientSecretName: k.ClientSecretName,
+		SpecTopic:        k.SpecTopic,
+		StatusTopic:      k.StatusTopic,
 	}
 }
 
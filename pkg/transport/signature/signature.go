@@ -0,0 +1,330 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+// Package signature authenticates the origin of the status events. The agent signs the event with the per-hub
+// certificate issued by the global hub operator, and the manager verifies the certificate is issued by the signing CA
+// and its common name is the source(leaf hub name) of the event, so a principal who can write to the status topic
+// can't impersonate another hub. The id and time of the event are signed as well, so the manager rejects the stale
+// events which are captured and resent to the topic.
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// SignatureKey is the extension of the base64 encoded signature
+	SignatureKey = "extsignature"
+	// SignerKey is the extension of the base64 encoded DER certificate which signs the event
+	SignerKey = "extsigner"
+
+	// the signer reloads the key and certificate periodically, since they're renewed by the operator
+	signerReloadInterval  = 1 * time.Hour
+	maxCachedCertificates = 1000
+)
+
+// SignedExtensions are the extensions covered by the signature besides the type, source and data of the event.
+// The other extensions are either set by the transport, like the kafka offset and chunk size, or not trusted.
+var SignedExtensions = []string{version.ExtVersion, version.ExtDependencyVersion}
+
+var log = logger.ZapLogger("transport-signature")
+
+var (
+	ErrUnsigned           = errors.New("event isn't signed")
+	ErrInvalidCertificate = errors.New("invalid signer certificate")
+	ErrSourceMismatch     = errors.New("signer doesn't match the event source")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrStaleEvent         = errors.New("stale event")
+)
+
+// Reason returns the short reason of the verification error, which is used as the metric label
+func Reason(err error) string {
+	switch {
+	case errors.Is(err, ErrUnsigned):
+		return "unsigned"
+	case errors.Is(err, ErrInvalidCertificate):
+		return "invalid_certificate"
+	case errors.Is(err, ErrSourceMismatch):
+		return "source_mismatch"
+	case errors.Is(err, ErrInvalidSignature):
+		return "invalid_signature"
+	case errors.Is(err, ErrStaleEvent):
+		return "stale"
+	default:
+		return "unknown"
+	}
+}
+
+// signedContent is the canonical content of the event to be signed, the keys of the extensions are sorted by the
+// json marshaller, so the content is stable between the agent and manager
+type signedContent struct {
+	ID         string            `json:"id"`
+	Time       string            `json:"time"`
+	Type       string            `json:"type"`
+	Source     string            `json:"source"`
+	Extensions map[string]string `json:"extensions"`
+	DataDigest []byte            `json:"dataDigest"`
+}
+
+func contentToSign(evt *cloudevents.Event) ([]byte, error) {
+	content := signedContent{
+		ID:         evt.ID(),
+		Type:       evt.Type(),
+		Source:     evt.Source(),
+		Extensions: map[string]string{},
+	}
+	if !evt.Time().IsZero() {
+		content.Time = evt.Time().UTC().Format(time.RFC3339Nano)
+	}
+	for _, key := range SignedExtensions {
+		val, found := evt.Extensions()[key]
+		if !found {
+			continue
+		}
+		formatted, err := types.Format(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format the extension %s: %w", key, err)
+		}
+		content.Extensions[key] = formatted
+	}
+	dataDigest := sha256.Sum256(evt.Data())
+	content.DataDigest = dataDigest[:]
+	return json.Marshal(content)
+}
+
+// Signer signs the events with the private key of the hub
+type Signer struct {
+	keyFile  string
+	certFile string
+
+	mutex    sync.Mutex
+	key      crypto.Signer
+	certDER  string
+	loadTime time.Time
+}
+
+// NewSignerFromFiles loads the PEM encoded private key and certificate of the hub
+func NewSignerFromFiles(keyFile, certFile string) (*Signer, error) {
+	s := &Signer{keyFile: keyFile, certFile: certFile}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Signer) load() error {
+	keyPEM, err := os.ReadFile(s.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the signing key: %w", err)
+	}
+	certPEM, err := os.ReadFile(s.certFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the signing certificate: %w", err)
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return fmt.Errorf("failed to decode the signing certificate")
+	}
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("failed to parse the signing certificate: %w", err)
+	}
+	s.key = key
+	s.certDER = base64.StdEncoding.EncodeToString(block.Bytes)
+	s.loadTime = time.Now()
+	return nil
+}
+
+// Sign sets the signature and the certificate of the signer to the event, it must be invoked before the payload is
+// compressed or chunked
+func (s *Signer) Sign(evt *cloudevents.Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if time.Since(s.loadTime) > signerReloadInterval {
+		if err := s.load(); err != nil {
+			// keep signing with the previous key, the manager rejects the events once it's expired
+			log.Warnw("failed to reload the signing key and certificate", "error", err)
+		}
+	}
+
+	content, err := contentToSign(evt)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(content)
+	sig, err := s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("failed to sign the event: %w", err)
+	}
+	evt.SetExtension(SignatureKey, base64.StdEncoding.EncodeToString(sig))
+	evt.SetExtension(SignerKey, s.certDER)
+	return nil
+}
+
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode the signing key")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("the signing key type %T isn't supported", key)
+	}
+	return signer, nil
+}
+
+// Verifier verifies the events are signed by the certificates issued by the signing CA
+type Verifier struct {
+	roots *x509.CertPool
+	// maxAge rejects the events which are signed before it, it's disabled if it's zero
+	maxAge time.Duration
+
+	mutex sync.Mutex
+	// verified caches the certificates which are verified with the roots, the key is the SignerKey extension
+	verified map[string]*x509.Certificate
+}
+
+// NewVerifierFromFile loads the PEM encoded signing CA, the events older than the maxAge are rejected by the Verify
+func NewVerifierFromFile(caFile string, maxAge time.Duration) (*Verifier, error) {
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signing CA: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to append the signing CA to pool")
+	}
+	return &Verifier{roots: roots, maxAge: maxAge, verified: map[string]*x509.Certificate{}}, nil
+}
+
+// Verify returns error if the event isn't signed by the certificate of its source, or it's signed before the maxAge
+func (v *Verifier) Verify(evt *cloudevents.Event) error {
+	if err := v.VerifySignature(evt); err != nil {
+		return err
+	}
+	if v.maxAge <= 0 {
+		return nil
+	}
+	if evt.Time().IsZero() {
+		return fmt.Errorf("%w: the event time isn't set", ErrStaleEvent)
+	}
+	if age := time.Since(evt.Time()); age > v.maxAge {
+		return fmt.Errorf("%w: the event is created %s ago, which exceeds %s", ErrStaleEvent,
+			age.Truncate(time.Second), v.maxAge)
+	}
+	return nil
+}
+
+// VerifySignature returns error if the event isn't signed by the certificate of its source, it doesn't check the age
+// of the event, so it's used to verify the dead letters and the archived events which are replayed
+func (v *Verifier) VerifySignature(evt *cloudevents.Event) error {
+	sigVal, found := evt.Extensions()[SignatureKey]
+	if !found {
+		return ErrUnsigned
+	}
+	signerVal, found := evt.Extensions()[SignerKey]
+	if !found {
+		return ErrUnsigned
+	}
+	cert, err := v.certificate(fmt.Sprintf("%v", signerVal))
+	if err != nil {
+		return err
+	}
+	if cert.Subject.CommonName != evt.Source() {
+		return fmt.Errorf("%w: the signer is %s, but the source is %s", ErrSourceMismatch, cert.Subject.CommonName,
+			evt.Source())
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(fmt.Sprintf("%v", sigVal))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	content, err := contentToSign(evt)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	var algorithm x509.SignatureAlgorithm
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = x509.SHA256WithRSA
+	case *ecdsa.PublicKey:
+		algorithm = x509.ECDSAWithSHA256
+	default:
+		return fmt.Errorf("%w: the public key type %T isn't supported", ErrInvalidCertificate, cert.PublicKey)
+	}
+	if err := cert.CheckSignature(algorithm, content, sig); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+func (v *Verifier) certificate(encoded string) (*x509.Certificate, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	now := time.Now()
+	if cert, found := v.verified[encoded]; found {
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			delete(v.verified, encoded)
+			return nil, fmt.Errorf("%w: the certificate of %s is expired", ErrInvalidCertificate, cert.Subject.CommonName)
+		}
+		return cert, nil
+	}
+
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       v.roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+
+	// the certificates are renewed rarely, reset the cache instead of evicting one by one
+	if len(v.verified) >= maxCachedCertificates {
+		v.verified = map[string]*x509.Certificate{}
+	}
+	v.verified[encoded] = cert
+	return cert, nil
+}
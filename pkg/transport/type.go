@@ -2,12 +2,25 @@ package transport
 
 import (
 	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 )
 
 const (
 	Broadcast      = "broadcast" // Broadcast can be used as destination when a bundle should be broadcasted.
 	ChunkSizeKey   = "extsize"   // ChunkSizeKey is the key used for total bundle size header.
 	ChunkOffsetKey = "extoffset" // ChunkOffsetKey is the key used for message fragment offset header.
+	// CompressionKey is the key used for the compression type header, it's set only when the payload is compressed.
+	CompressionKey = "extcompression"
+	// ChecksumKey is the key used for the checksum header of the whole payload, it's set only when the payload is
+	// chunked, so that the consumer can verify the assembled payload.
+	ChecksumKey = "extchecksum"
+	// KafkaClusterKey is the key used for the identity of the kafka cluster which the event is consumed from, it's set
+	// only when the manager consumes from multiple kafka clusters
+	KafkaClusterKey = "extkafkacluster"
+	// EmitTimeKey is the key used for the time when the event is emitted by the agent, it's used by the manager to
+	// measure the latency from the emission to the database commit
+	EmitTimeKey = "extemittime"
 )
 
 // indicate the transport type, only support kafka or go chan
@@ -20,6 +33,15 @@ const (
 	Rest  TransportType = "rest"
 )
 
+// indicate the client library of the kafka transport, the sarama client is written in pure go, so the binary can be
+// built without cgo and librdkafka
+type KafkaClientType string
+
+const (
+	ConfluentKafkaClient KafkaClientType = "confluent"
+	SaramaKafkaClient    KafkaClientType = "sarama"
+)
+
 // transport protocol
 // indicate which kind of transport protocol, only support
 type TransportProtocol int
@@ -41,6 +63,34 @@ type TransportInternalConfig struct {
 	RestfulCredential *RestfulConfig
 	Extends           map[string]interface{}
 	FailureThreshold  int
+	// CompressionType is used by the producer to compress the payload before it's chunked, the consumer decides
+	// the decompressor by the CompressionKey extension of the received event
+	CompressionType compressor.CompressionType
+	// SigningKeyFile and SigningCertFile are used by the agent producer to sign the events with the certificate
+	// issued for the hub by the operator
+	SigningKeyFile  string
+	SigningCertFile string
+	// SigningCAFile is used by the manager to verify the events are signed by the certificates issued by the CA
+	SigningCAFile string
+	// SignatureMaxAge rejects the signed events which are created before it, so the captured events can't be resent
+	SignatureMaxAge time.Duration
+	// OutboxDir enables the agent producer to persist the status events into the outbox under the directory before
+	// delivering them, so the events survive the transport outages. OutboxMaxBytes limits the size of pending events.
+	OutboxDir      string
+	OutboxMaxBytes int
+	// KafkaClientType selects the client library of the kafka transport, it's confluent if it isn't specified
+	KafkaClientType KafkaClientType
+	// AdditionalKafkaSecrets are the transport secrets of the other kafka clusters that the manager consumes the status
+	// from, the credentials are loaded into the AdditionalKafkaCredentials by the transport controller
+	AdditionalKafkaSecrets     []string
+	AdditionalKafkaCredentials []*KafkaConfig
+	// StatusSharding shares the status partitions between the manager replicas with the consumer group rebalancing,
+	// instead of consuming all of them by the leader. It's only supported by the confluent kafka client
+	StatusSharding bool
+	// ChunkCollectionTTL and ChunkMemoryLimit limit the incomplete chunk collections buffered by the consumer, the
+	// defaults of the consumer are used if they're zero
+	ChunkCollectionTTL time.Duration
+	ChunkMemoryLimit   int
 }
 
 // KafkaInternalConfig specifics the configuration for the global hub manager, agent, or even inventory
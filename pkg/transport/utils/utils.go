@@ -1,19 +1,14 @@
 package utils
 
-import "github.com/confluentinc/confluent-kafka-go/v2/kafka"
+import (
+	"fmt"
+	"hash/crc32"
+)
 
-// FilterSensitiveKafkaConfig filters out sensitive data from Kafka ConfigMap for safe logging.
-// It replaces SSL certificate and key values with "[REDACTED]" to prevent exposure of
-// sensitive credentials in logs.
-func FilterSensitiveKafkaConfig(configMap *kafka.ConfigMap) map[string]interface{} {
-	safeConfig := make(map[string]interface{})
-	for key, value := range *configMap {
-		// Exclude sensitive SSL/TLS certificate and key data
-		if key == "ssl.ca.pem" || key == "ssl.certificate.pem" || key == "ssl.key.pem" {
-			safeConfig[key] = "[REDACTED]"
-		} else {
-			safeConfig[key] = value
-		}
-	}
-	return safeConfig
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// PayloadChecksum returns the CRC-32C checksum of the payload in hex, it's used to verify the payload assembled from
+// the chunks is the same as the one sent by the producer.
+func PayloadChecksum(payload []byte) string {
+	return fmt.Sprintf("%08x", crc32.Checksum(payload, crc32cTable))
 }
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// The sources of the certificates and the tokens scanned by the agent.
+const (
+	// CertificateSourceKlusterletBootstrap is the bootstrap kubeconfig of the klusterlet, it's either in the import
+	// secret of the managed cluster or in the klusterlet namespace of the hub itself.
+	CertificateSourceKlusterletBootstrap = "KlusterletBootstrap"
+
+	// CertificateSourceHubKubeconfig is the hub kubeconfig of the klusterlet of the hub itself.
+	CertificateSourceHubKubeconfig = "HubKubeconfig"
+
+	// CertificateSourceAPIServer is the serving certificate of the API server.
+	CertificateSourceAPIServer = "APIServer"
+
+	// CertificateSourceTransport is the client certificate and the CA of the transport of the agent.
+	CertificateSourceTransport = "Transport"
+)
+
+// The kinds of the scanned credentials.
+const (
+	CredentialKindCertificate = "Certificate"
+	CredentialKindToken       = "Token"
+)
+
+// CertificateExpiries contains the certificates and the tokens scanned by the agent on a hub. It's sent as a whole, so
+// the ones which aren't in the list are removed by the manager.
+type CertificateExpiries struct {
+	// Certificates is the list of the scanned certificates and tokens.
+	Certificates []CertificateExpiry `json:"certificates,omitempty"`
+}
+
+// CertificateExpiry is the validity of a certificate or a token. It's identified by the source, the namespace, the name
+// and the key, e.g. the source "KlusterletBootstrap" with the secret "cluster1/cluster1-import" and the key
+// "import.yaml/token".
+type CertificateExpiry struct {
+	// Source is where the certificate is used, e.g. "KlusterletBootstrap" or "APIServer".
+	Source string `json:"source"`
+
+	// Kind is either "Certificate" or "Token".
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the secret, it's empty for the API server.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the secret, the address of the API server or the type of the transport.
+	Name string `json:"name"`
+
+	// Key is the data key of the secret, the kubeconfig parts are suffixed by "/ca", "/client" or "/token", and the
+	// certificates after the first one of a bundle are suffixed by "#<index>".
+	Key string `json:"key"`
+
+	// Subject is the subject of the certificate or the token.
+	Subject string `json:"subject,omitempty"`
+
+	// Issuer is the issuer of the certificate or the token.
+	Issuer string `json:"issuer,omitempty"`
+
+	// NotBefore is the time when the certificate or the token becomes valid.
+	NotBefore time.Time `json:"notBefore,omitempty"`
+
+	// NotAfter is the time when the certificate or the token expires.
+	NotAfter time.Time `json:"notAfter"`
+}
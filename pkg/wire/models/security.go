@@ -24,3 +24,106 @@ type SecurityAlertCounts struct {
 	// This should follow the format: "<namespace>/<name>"
 	Source string `json:"source,omitempty"`
 }
+
+// SecurityClusterAlertCounts contains the number of the security alerts of each cluster secured by a Central instance
+// in the hub.
+type SecurityClusterAlertCounts struct {
+	// Clusters contains the alert counts of each secured cluster.
+	Clusters []ClusterAlertCounts `json:"clusters,omitempty"`
+
+	// DetailURL is the URL where the user can see the details of the alerts of the Central CR instance in the hub.
+	DetailURL string `json:"detail_url,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	// This should follow the format: "<namespace>/<name>"
+	Source string `json:"source,omitempty"`
+}
+
+// ClusterAlertCounts contains a summary of the security alerts of a secured cluster.
+type ClusterAlertCounts struct {
+	// Cluster is the name of the secured cluster, which is usually the name of the managed cluster.
+	Cluster string `json:"cluster"`
+
+	// Low is the total number of low severity alerts.
+	Low int `json:"low,omitempty"`
+
+	// Medium is the total number of medium severity alerts.
+	Medium int `json:"medium,omitempty"`
+
+	// High is the total number of high severity alerts.
+	High int `json:"high,omitempty"`
+
+	// Critical is the total number of critical severity alerts.
+	Critical int `json:"critical,omitempty"`
+}
+
+// SecurityPolicyViolations contains the most violated policies of each cluster secured by a Central instance in the
+// hub.
+type SecurityPolicyViolations struct {
+	// Violations contains the violated policies of the secured clusters.
+	Violations []PolicyViolation `json:"violations,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	// This should follow the format: "<namespace>/<name>"
+	Source string `json:"source,omitempty"`
+}
+
+// PolicyViolation contains the number of the active alerts of a policy in a secured cluster.
+type PolicyViolation struct {
+	// Cluster is the name of the secured cluster.
+	Cluster string `json:"cluster"`
+
+	// ClusterID is the identifier of the secured cluster in the Central.
+	ClusterID string `json:"cluster_id,omitempty"`
+
+	// PolicyID is the identifier of the policy in the Central.
+	PolicyID string `json:"policy_id"`
+
+	// PolicyName is the name of the policy.
+	PolicyName string `json:"policy_name,omitempty"`
+
+	// Severity is the severity of the policy: low, medium, high or critical.
+	Severity string `json:"severity,omitempty"`
+
+	// Categories are the categories of the policy, e.g. "Vulnerability Management".
+	Categories []string `json:"categories,omitempty"`
+
+	// Count is the number of the active alerts of the policy in the cluster.
+	Count int `json:"count"`
+}
+
+// SecurityImageVulnerabilities contains the number of the image vulnerabilities of each cluster secured by a Central
+// instance in the hub.
+type SecurityImageVulnerabilities struct {
+	// Clusters contains the image vulnerability counts of each secured cluster.
+	Clusters []ClusterImageVulnerabilities `json:"clusters,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	// This should follow the format: "<namespace>/<name>"
+	Source string `json:"source,omitempty"`
+}
+
+// ClusterImageVulnerabilities contains the number of the distinct CVEs of the images deployed in a secured cluster,
+// by the severity.
+type ClusterImageVulnerabilities struct {
+	// Cluster is the name of the secured cluster.
+	Cluster string `json:"cluster"`
+
+	// ClusterID is the identifier of the secured cluster in the Central.
+	ClusterID string `json:"cluster_id,omitempty"`
+
+	// Low is the number of low severity CVEs.
+	Low int `json:"low,omitempty"`
+
+	// Moderate is the number of moderate severity CVEs.
+	Moderate int `json:"moderate,omitempty"`
+
+	// Important is the number of important severity CVEs.
+	Important int `json:"important,omitempty"`
+
+	// Critical is the number of critical severity CVEs.
+	Critical int `json:"critical,omitempty"`
+
+	// Fixable is the number of CVEs which can be fixed by upgrading the images, of all the severities.
+	Fixable int `json:"fixable,omitempty"`
+}
@@ -87,6 +87,14 @@ var _ = Describe("data retention job", Ordered, func() {
 			Expect(err).ToNot(HaveOccurred())
 		}
 
+		By("Create the expired transport audit log in the database")
+		Expect(db.Create(&models.TransportAuditLog{
+			LeafHubName: "hub1",
+			EventType:   "io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster",
+			Reason:      "unsigned",
+			CreatedAt:   expirationTime,
+		}).Error).ToNot(HaveOccurred())
+
 		for _, table := range task.RetentionTables {
 			By(fmt.Sprintf("Check whether the record was created in table %s", table))
 			Eventually(func() error {
@@ -164,6 +172,23 @@ var _ = Describe("data retention job", Ordered, func() {
 		}
 	})
 
+	It("the data retention job should delete the expired appended records", func() {
+		for _, table := range task.AppendedTables {
+			By(fmt.Sprintf("Check whether the expired records were deleted in table %s", table))
+			Eventually(func() error {
+				var count int64
+				err := db.Table(table).Where("created_at < ?", minTime).Count(&count).Error
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					return fmt.Errorf("the expired records of table %s haven't been deleted", table)
+				}
+				return nil
+			}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+		}
+	})
+
 	It("the data retention should log the job execution", func() {
 		db := database.GetGorm()
 		logs := []models.DataRetentionJobLog{}
@@ -0,0 +1,134 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ArgoCDApplicationHandler"
+var _ = Describe("ArgoCDApplicationHandler", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		namespace   = "openshift-gitops"
+	)
+	version := eventversion.NewVersion()
+
+	newApplication := func(name, cluster, sync, health string) unstructured.Unstructured {
+		app := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"project":     "default",
+				"source":      map[string]interface{}{"repoURL": "https://github.com/argoproj/argocd-example-apps.git"},
+				"destination": map[string]interface{}{"name": cluster, "namespace": "guestbook"},
+			},
+			"status": map[string]interface{}{
+				"sync":   map[string]interface{}{"status": sync},
+				"health": map[string]interface{}{"status": health},
+			},
+		}}
+		app.SetAPIVersion("argoproj.io/v1alpha1")
+		app.SetKind("Application")
+		app.SetNamespace(namespace)
+		app.SetName(name)
+		app.SetUID(types.UID("uid-" + name))
+		return app
+	}
+
+	sendBundle := func(bundle generic.GenericBundle[unstructured.Unstructured]) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ArgoCDApplicationType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listApplications := func() ([]models.ArgoCDApplication, error) {
+		apps := []models.ArgoCDApplication{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("kind, name").Find(&apps).Error
+		return apps, err
+	}
+
+	It("should upsert the argocd applications", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Create: []unstructured.Unstructured{
+				newApplication("guestbook-cluster1", "cluster1", "Synced", "Healthy"),
+				newApplication("guestbook-cluster2", "cluster2", "Synced", "Healthy"),
+				newApplication("helm-guestbook", "cluster2", "Synced", "Healthy"),
+			},
+		})
+		Eventually(func() error {
+			apps, err := listApplications()
+			if err != nil {
+				return err
+			}
+			if len(apps) != 3 {
+				return fmt.Errorf("want 3 applications, but got %d", len(apps))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Update: []unstructured.Unstructured{
+				newApplication("guestbook-cluster2", "cluster2", "OutOfSync", "Degraded"),
+			},
+		})
+		Eventually(func() error {
+			outOfSync := []models.ArgoCDApplication{}
+			err := database.GetGorm().Where("sync_status = ?", "OutOfSync").Find(&outOfSync).Error
+			if err != nil {
+				return err
+			}
+			if len(outOfSync) != 1 || outOfSync[0].DestinationName != "cluster2" ||
+				outOfSync[0].HealthStatus != "Degraded" ||
+				outOfSync[0].RepoURL != "https://github.com/argoproj/argocd-example-apps.git" {
+				return fmt.Errorf("want the out of sync application of cluster2, but got %v", outOfSync)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the argocd applications", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Delete: []generic.ObjectMetadata{
+				{Namespace: namespace, Name: "helm-guestbook", GVK: "argoproj.io/v1alpha1/Application"},
+			},
+		})
+		Eventually(func() error {
+			apps, err := listApplications()
+			if err != nil {
+				return err
+			}
+			if len(apps) != 2 {
+				return fmt.Errorf("want 2 applications, but got %d", len(apps))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the stale argocd applications by the resync", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			ResyncMetadata: []generic.ObjectMetadata{
+				{Namespace: namespace, Name: "guestbook-cluster1", GVK: "argoproj.io/v1alpha1/Application"},
+			},
+		})
+		Eventually(func() error {
+			apps, err := listApplications()
+			if err != nil {
+				return err
+			}
+			if len(apps) != 1 || apps[0].Name != "guestbook-cluster1" {
+				return fmt.Errorf("want the application guestbook-cluster1, but got %v", apps)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
@@ -0,0 +1,91 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "CertificateExpiryHandler"
+var _ = Describe("CertificateExpiryHandler", Ordered, func() {
+	const leafHubName = "certificate-hub"
+	version := eventversion.NewVersion()
+	notAfter := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	bootstrap := wiremodels.CertificateExpiry{
+		Source:    wiremodels.CertificateSourceKlusterletBootstrap,
+		Kind:      wiremodels.CredentialKindCertificate,
+		Namespace: "cluster1",
+		Name:      "cluster1-import",
+		Key:       "import.yaml/bootstrap-hub-kubeconfig/kubeconfig/ca",
+		Subject:   "CN=kube-apiserver-lb-signer",
+		NotBefore: notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:  notAfter,
+	}
+	apiServer := wiremodels.CertificateExpiry{
+		Source:    wiremodels.CertificateSourceAPIServer,
+		Kind:      wiremodels.CredentialKindCertificate,
+		Name:      "api.hub.example.com:6443",
+		Subject:   "CN=api.hub.example.com",
+		NotBefore: notAfter.Add(-30 * 24 * time.Hour),
+		NotAfter:  notAfter.Add(30 * 24 * time.Hour),
+	}
+
+	sendCertificates := func(certs ...wiremodels.CertificateExpiry) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.CertificateExpiryType), version,
+			&wiremodels.CertificateExpiries{Certificates: certs})
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listCertificates := func() ([]models.CertificateExpiry, error) {
+		certs := []models.CertificateExpiry{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("not_after").Find(&certs).Error
+		return certs, err
+	}
+
+	It("should sync the certificate expiries of the hub", func() {
+		sendCertificates(bootstrap, apiServer)
+		Eventually(func() error {
+			certs, err := listCertificates()
+			if err != nil {
+				return err
+			}
+			if len(certs) != 2 {
+				return fmt.Errorf("want 2 certificates, but got %d", len(certs))
+			}
+			if certs[0].Source != bootstrap.Source || !certs[0].NotAfter.Equal(bootstrap.NotAfter) {
+				return fmt.Errorf("unexpected bootstrap certificate: %v", certs[0])
+			}
+			if certs[1].Source != apiServer.Source || certs[1].Namespace != "" {
+				return fmt.Errorf("unexpected api server certificate: %v", certs[1])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should update the renewed certificates and delete the ones not scanned", func() {
+		renewed := bootstrap
+		renewed.NotAfter = notAfter.Add(365 * 24 * time.Hour)
+		sendCertificates(renewed)
+		Eventually(func() error {
+			certs, err := listCertificates()
+			if err != nil {
+				return err
+			}
+			if len(certs) != 1 || certs[0].Source != renewed.Source || !certs[0].NotAfter.Equal(renewed.NotAfter) {
+				return fmt.Errorf("want the renewed bootstrap certificate, but got %v", certs)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
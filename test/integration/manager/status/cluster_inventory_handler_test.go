@@ -0,0 +1,134 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clusterinfov1beta1 "github.com/stolostron/cluster-lifecycle-api/clusterinfo/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ClusterInventoryHandler"
+var _ = Describe("ClusterInventoryHandler", Ordered, func() {
+	const leafHubName = "inventory-hub"
+	version := eventversion.NewVersion()
+
+	newClusterInfo := func(name, ocpVersion string, updates ...string) clusterinfov1beta1.ManagedClusterInfo {
+		return clusterinfov1beta1.ManagedClusterInfo{
+			ObjectMeta: metav1.ObjectMeta{Namespace: name, Name: name},
+			Status: clusterinfov1beta1.ClusterInfoStatus{
+				KubeVendor:  clusterinfov1beta1.KubeVendorOpenShift,
+				CloudVendor: clusterinfov1beta1.CloudVendorAWS,
+				DistributionInfo: clusterinfov1beta1.DistributionInfo{
+					Type: clusterinfov1beta1.DistributionTypeOCP,
+					OCP: clusterinfov1beta1.OCPDistributionInfo{
+						Version:          ocpVersion,
+						AvailableUpdates: updates,
+						Channel:          "stable-4.15",
+					},
+				},
+				NodeList: []clusterinfov1beta1.NodeStatus{{
+					Name:   "worker-0",
+					Labels: map[string]string{corev1.LabelTopologyRegion: "us-east-1"},
+					Capacity: clusterinfov1beta1.ResourceList{
+						clusterinfov1beta1.ResourceCPU:    resource.MustParse("4"),
+						clusterinfov1beta1.ResourceMemory: resource.MustParse("16Gi"),
+					},
+					Conditions: []clusterinfov1beta1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+				}},
+			},
+		}
+	}
+
+	sendBundle := func(bundle generic.GenericBundle[clusterinfov1beta1.ManagedClusterInfo]) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterInfoType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listInventory := func() ([]models.ClusterInventory, error) {
+		clusters := []models.ClusterInventory{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("cluster_name").Find(&clusters).Error
+		return clusters, err
+	}
+
+	It("should upsert the cluster inventory with the allocatable of the managed cluster", func() {
+		payload, err := json.Marshal(&clusterv1.ManagedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "inventory-cluster1"},
+			Status: clusterv1.ManagedClusterStatus{
+				Allocatable: clusterv1.ResourceList{
+					clusterv1.ResourceCPU:    resource.MustParse("3500m"),
+					clusterv1.ResourceMemory: resource.MustParse("15Gi"),
+				},
+			},
+		})
+		Expect(err).To(Succeed())
+		Expect(database.GetGorm().Create(&models.ManagedCluster{
+			LeafHubName: leafHubName,
+			ClusterID:   uuid.New().String(),
+			Error:       database.ErrorNone,
+			Payload:     payload,
+		}).Error).To(Succeed())
+
+		sendBundle(generic.GenericBundle[clusterinfov1beta1.ManagedClusterInfo]{
+			Create: []clusterinfov1beta1.ManagedClusterInfo{
+				newClusterInfo("inventory-cluster1", "4.14.8", "4.14.9", "4.15.2"),
+				newClusterInfo("inventory-cluster2", "4.15.2"),
+			},
+		})
+		Eventually(func() error {
+			clusters, err := listInventory()
+			if err != nil {
+				return err
+			}
+			if len(clusters) != 2 {
+				return fmt.Errorf("want 2 clusters, but got %d", len(clusters))
+			}
+			if clusters[0].OpenShiftVersion != "4.14.8" || clusters[0].CPUCapacity != 4000 ||
+				clusters[0].CPUAllocatable != 3500 || clusters[0].Region != "us-east-1" {
+				return fmt.Errorf("unexpected inventory of the cluster1: %v", clusters[0])
+			}
+			if clusters[1].CPUAllocatable != 0 || clusters[1].ReadyNodeCount != 1 {
+				return fmt.Errorf("unexpected inventory of the cluster2: %v", clusters[1])
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		upgradable := []models.ClusterInventory{}
+		Expect(database.GetGorm().Where("leaf_hub_name = ?", leafHubName).
+			Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(available_updates) AS u(version) "+
+				"WHERE u.version LIKE ?)", "4.15.%").Find(&upgradable).Error).To(Succeed())
+		Expect(upgradable).To(HaveLen(1))
+		Expect(upgradable[0].ClusterName).To(Equal("inventory-cluster1"))
+	})
+
+	It("should delete the stale cluster inventory by the resync", func() {
+		sendBundle(generic.GenericBundle[clusterinfov1beta1.ManagedClusterInfo]{
+			ResyncMetadata: []generic.ObjectMetadata{{Namespace: "inventory-cluster1", Name: "inventory-cluster1"}},
+		})
+		Eventually(func() error {
+			clusters, err := listInventory()
+			if err != nil {
+				return err
+			}
+			if len(clusters) != 1 || clusters[0].ClusterName != "inventory-cluster1" {
+				return fmt.Errorf("want the cluster inventory-cluster1, but got %v", clusters)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
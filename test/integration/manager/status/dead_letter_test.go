@@ -0,0 +1,54 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/deadletter"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// go test /test/integration/manager/status -ginkgo.focus "DeadLetter"
+var _ = Describe("DeadLetter", Ordered, func() {
+	leafHubName := "hub-dead-letter"
+	eventType := "io.open-cluster-management.operator.multiclusterglobalhubs.unregistered"
+	var deadLetter models.DeadLetter
+
+	It("save the unregistered event as dead letter", func() {
+		version := eventversion.NewVersion()
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, eventType, version, generic.GenericObjectBundle{})
+		Expect(producer.SendEvent(ctx, *evt)).Should(Succeed())
+
+		Eventually(func() error {
+			deadLetters, err := deadletter.List(deadletter.ListOptions{LeafHubName: leafHubName})
+			if err != nil {
+				return err
+			}
+			if len(deadLetters) != 1 {
+				return fmt.Errorf("expect 1 dead letter, but got %d", len(deadLetters))
+			}
+			deadLetter = deadLetters[0]
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).ShouldNot(HaveOccurred())
+
+		Expect(deadLetter.EventType).To(Equal(eventType))
+		Expect(deadLetter.EventVersion).To(Equal(version.String()))
+		Expect(deadLetter.Reason).To(Equal(deadletter.ReasonUnregistered))
+	})
+
+	It("reject replaying the unregistered dead letter", func() {
+		_, err := deadletter.Replay(deadLetter.ID)
+		Expect(errors.Is(err, deadletter.ErrReplayRejected)).To(BeTrue())
+
+		saved, err := deadletter.Get(deadLetter.ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.ReplayedAt).To(BeNil())
+	})
+})
@@ -0,0 +1,174 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	generichandler "github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "GenericResourceHandler"
+var _ = Describe("GenericResourceHandler", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		gvk         = "cert-manager.io/v1/Certificate"
+	)
+	version := eventversion.NewVersion()
+
+	newCertificate := func(name, ready string) unstructured.Unstructured {
+		cert := unstructured.Unstructured{Object: map[string]interface{}{
+			"fields": map[string]interface{}{"ready": ready},
+		}}
+		cert.SetAPIVersion("cert-manager.io/v1")
+		cert.SetKind("Certificate")
+		cert.SetNamespace("default")
+		cert.SetName(name)
+		cert.SetUID(types.UID("uid-" + name))
+		return cert
+	}
+
+	sendBundle := func(bundle generic.GenericBundle[unstructured.Unstructured]) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.GenericResourceType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listNames := func() ([]string, error) {
+		items := []models.GenericResource{}
+		if err := database.GetGorm().Where("leaf_hub_name = ? AND gvk = ?", leafHubName, gvk).
+			Order("name").Find(&items).Error; err != nil {
+			return nil, err
+		}
+		names := []string{}
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		return names, nil
+	}
+
+	It("should upsert the generic resources", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Update: []unstructured.Unstructured{
+				newCertificate("cert1", "False"), newCertificate("cert2", "True"), newCertificate("cert3", "True"),
+			},
+		})
+		Eventually(func() error {
+			names, err := listNames()
+			if err != nil {
+				return err
+			}
+			if len(names) != 3 {
+				return fmt.Errorf("want 3 certificates, but got %v", names)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Update: []unstructured.Unstructured{newCertificate("cert1", "True")},
+		})
+		Eventually(func() error {
+			item := models.GenericResource{}
+			if err := database.GetGorm().Where("uid = ?", "uid-cert1").First(&item).Error; err != nil {
+				return err
+			}
+			if !containsReady(item.Payload, "True") {
+				return fmt.Errorf("the certificate isn't updated: %s", item.Payload)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should apply the merge patches to the generic resources", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Patch: []generic.ObjectPatch{{
+				ObjectMetadata: generic.ObjectMetadata{ID: "uid-cert2", Namespace: "default", Name: "cert2", GVK: gvk},
+				Patch:          []byte(`{"fields":{"ready":"False"}}`),
+			}},
+		})
+		Eventually(func() error {
+			item := models.GenericResource{}
+			if err := database.GetGorm().Where("uid = ?", "uid-cert2").First(&item).Error; err != nil {
+				return err
+			}
+			if !containsReady(item.Payload, "False") {
+				return fmt.Errorf("the certificate isn't patched: %s", item.Payload)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should request the resync if the patch mismatches the stored object", func() {
+		resynced := make(chan string, 1)
+		generichandler.SetResyncFunc(func(ctx context.Context, hubName string, eventTypes ...string) error {
+			resynced <- hubName
+			return nil
+		})
+		defer generichandler.SetResyncFunc(nil)
+
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Patch: []generic.ObjectPatch{{
+				ObjectMetadata:      generic.ObjectMetadata{ID: "uid-cert2", Namespace: "default", Name: "cert2", GVK: gvk},
+				BaseResourceVersion: "100",
+				Patch:               []byte(`{"fields":{"ready":"True"}}`),
+			}},
+		})
+		Eventually(resynced, 30*time.Second).Should(Receive(Equal(leafHubName)))
+
+		item := models.GenericResource{}
+		Expect(database.GetGorm().Where("uid = ?", "uid-cert2").First(&item).Error).To(Succeed())
+		Expect(containsReady(item.Payload, "False")).To(BeTrue())
+	})
+
+	It("should delete the generic resources by the gvk and name", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			Delete: []generic.ObjectMetadata{{Namespace: "default", Name: "cert3", GVK: gvk}},
+		})
+		Eventually(func() error {
+			names, err := listNames()
+			if err != nil {
+				return err
+			}
+			if len(names) != 2 {
+				return fmt.Errorf("want 2 certificates, but got %v", names)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the stale generic resources by the resync", func() {
+		sendBundle(generic.GenericBundle[unstructured.Unstructured]{
+			ResyncMetadata: []generic.ObjectMetadata{{ID: "uid-cert2", Namespace: "default", Name: "cert2", GVK: gvk}},
+		})
+		Eventually(func() error {
+			names, err := listNames()
+			if err != nil {
+				return err
+			}
+			if len(names) != 1 || names[0] != "cert2" {
+				return fmt.Errorf("want the certificate cert2, but got %v", names)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
+
+func containsReady(payload []byte, ready string) bool {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(payload); err != nil {
+		return false
+	}
+	value, _, _ := unstructured.NestedString(obj.Object, "fields", "ready")
+	return value == ready
+}
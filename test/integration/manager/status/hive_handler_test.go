@@ -0,0 +1,150 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "HiveHandler"
+var _ = Describe("HiveHandler", Ordered, func() {
+	const leafHubName = "hub1"
+	cdVersion := eventversion.NewVersion()
+	poolVersion := eventversion.NewVersion()
+
+	newClusterDeployment := func(name string, installed bool, status map[string]interface{}) unstructured.Unstructured {
+		cd := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"clusterName": name,
+				"installed":   installed,
+				"platform":    map[string]interface{}{"aws": map[string]interface{}{"region": "us-east-1"}},
+			},
+			"status": status,
+		}}
+		cd.SetAPIVersion("hive.openshift.io/v1")
+		cd.SetKind("ClusterDeployment")
+		cd.SetNamespace(name)
+		cd.SetName(name)
+		cd.SetUID(types.UID("uid-" + name))
+		return cd
+	}
+
+	sendBundle := func(eventType enum.EventType, version *eventversion.Version,
+		bundle generic.GenericBundle[unstructured.Unstructured],
+	) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(eventType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listClusterDeployments := func() ([]models.ClusterDeployment, error) {
+		cds := []models.ClusterDeployment{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("name").Find(&cds).Error
+		return cds, err
+	}
+
+	It("should upsert the clusterdeployments with the provisioning phase", func() {
+		sendBundle(enum.ClusterDeploymentType, cdVersion, generic.GenericBundle[unstructured.Unstructured]{
+			Create: []unstructured.Unstructured{
+				newClusterDeployment("cluster1", true, map[string]interface{}{"powerState": "Running"}),
+				newClusterDeployment("cluster2", false, map[string]interface{}{
+					"provisionRef": map[string]interface{}{"name": "cluster2-0-abcde"},
+				}),
+				newClusterDeployment("cluster3", false, map[string]interface{}{}),
+			},
+		})
+		Eventually(func() error {
+			cds, err := listClusterDeployments()
+			if err != nil {
+				return err
+			}
+			if len(cds) != 3 {
+				return fmt.Errorf("want 3 clusterdeployments, but got %d", len(cds))
+			}
+			if cds[0].Phase != "Provisioned" || cds[1].Phase != "Provisioning" || cds[2].Phase != "Pending" {
+				return fmt.Errorf("unexpected phases of the clusterdeployments: %v", cds)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		sendBundle(enum.ClusterDeploymentType, cdVersion, generic.GenericBundle[unstructured.Unstructured]{
+			Update: []unstructured.Unstructured{
+				newClusterDeployment("cluster2", false, map[string]interface{}{
+					"provisionRef":    map[string]interface{}{"name": "cluster2-1-abcde"},
+					"installRestarts": int64(1),
+					"conditions": []interface{}{map[string]interface{}{
+						"type": "ProvisionFailed", "status": "True", "reason": "AWSInsufficientCapacity",
+					}},
+				}),
+			},
+		})
+		Eventually(func() error {
+			failed := []models.ClusterDeployment{}
+			err := database.GetGorm().Where("phase = ?", "ProvisionFailed").Find(&failed).Error
+			if err != nil {
+				return err
+			}
+			if len(failed) != 1 || failed[0].Name != "cluster2" || failed[0].InstallRestarts != 1 ||
+				failed[0].ProvisionName != "cluster2-1-abcde" || failed[0].FailureReason != "AWSInsufficientCapacity" {
+				return fmt.Errorf("want the failed clusterdeployment cluster2, but got %v", failed)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the stale clusterdeployments by the resync", func() {
+		sendBundle(enum.ClusterDeploymentType, cdVersion, generic.GenericBundle[unstructured.Unstructured]{
+			Delete: []generic.ObjectMetadata{{Namespace: "cluster3", Name: "cluster3"}},
+		})
+		sendBundle(enum.ClusterDeploymentType, cdVersion, generic.GenericBundle[unstructured.Unstructured]{
+			ResyncMetadata: []generic.ObjectMetadata{{Namespace: "cluster1", Name: "cluster1"}},
+		})
+		Eventually(func() error {
+			cds, err := listClusterDeployments()
+			if err != nil {
+				return err
+			}
+			if len(cds) != 1 || cds[0].Name != "cluster1" {
+				return fmt.Errorf("want the clusterdeployment cluster1, but got %v", cds)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should upsert the clusterpools with the capacity", func() {
+		pool := unstructured.Unstructured{Object: map[string]interface{}{
+			"spec":   map[string]interface{}{"size": int64(3), "maxSize": int64(5)},
+			"status": map[string]interface{}{"ready": int64(1), "standby": int64(2)},
+		}}
+		pool.SetAPIVersion("hive.openshift.io/v1")
+		pool.SetKind("ClusterPool")
+		pool.SetNamespace("pools")
+		pool.SetName("aws-pool")
+		sendBundle(enum.ClusterPoolType, poolVersion, generic.GenericBundle[unstructured.Unstructured]{
+			Create: []unstructured.Unstructured{pool},
+		})
+		Eventually(func() error {
+			pools := []models.ClusterPool{}
+			err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Find(&pools).Error
+			if err != nil {
+				return err
+			}
+			if len(pools) != 1 || pools[0].Size != 3 || pools[0].MaxSize == nil || *pools[0].MaxSize != 5 ||
+				pools[0].Ready != 1 || pools[0].Standby != 2 {
+				return fmt.Errorf("want the clusterpool aws-pool, but got %v", pools)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
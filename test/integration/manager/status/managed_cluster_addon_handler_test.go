@@ -0,0 +1,125 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ManagedClusterAddOnHandler"
+var _ = Describe("ManagedClusterAddOnHandler", Ordered, func() {
+	const leafHubName = "hub1"
+	version := eventversion.NewVersion()
+
+	newAddon := func(cluster, name string, degraded metav1.ConditionStatus) addonv1alpha1.ManagedClusterAddOn {
+		return addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster,
+				Name:      name,
+				UID:       types.UID(cluster + "-" + name),
+			},
+			Status: addonv1alpha1.ManagedClusterAddOnStatus{
+				Conditions: []metav1.Condition{
+					{Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue},
+					{Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: degraded},
+				},
+			},
+		}
+	}
+
+	sendBundle := func(bundle generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManagedClusterAddOnType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listAddons := func() ([]models.ManagedClusterAddOn, error) {
+		addons := []models.ManagedClusterAddOn{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("cluster_name, addon_name").
+			Find(&addons).Error
+		return addons, err
+	}
+
+	It("should upsert the managed cluster addons", func() {
+		sendBundle(generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{
+			Create: []addonv1alpha1.ManagedClusterAddOn{
+				newAddon("cluster1", "application-manager", metav1.ConditionFalse),
+				newAddon("cluster2", "application-manager", metav1.ConditionFalse),
+				newAddon("cluster2", "work-manager", metav1.ConditionFalse),
+			},
+		})
+		Eventually(func() error {
+			addons, err := listAddons()
+			if err != nil {
+				return err
+			}
+			if len(addons) != 3 {
+				return fmt.Errorf("want 3 addons, but got %d", len(addons))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		sendBundle(generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{
+			Update: []addonv1alpha1.ManagedClusterAddOn{
+				newAddon("cluster2", "application-manager", metav1.ConditionTrue),
+			},
+		})
+		Eventually(func() error {
+			degraded := []models.ManagedClusterAddOn{}
+			err := database.GetGorm().Where("addon_name = ? AND degraded = ?", "application-manager", "True").
+				Find(&degraded).Error
+			if err != nil {
+				return err
+			}
+			if len(degraded) != 1 || degraded[0].ClusterName != "cluster2" || degraded[0].Available != "True" ||
+				degraded[0].Progressing != "" {
+				return fmt.Errorf("want the degraded addon of cluster2, but got %v", degraded)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the managed cluster addons", func() {
+		sendBundle(generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{
+			Delete: []generic.ObjectMetadata{{Namespace: "cluster2", Name: "work-manager"}},
+		})
+		Eventually(func() error {
+			addons, err := listAddons()
+			if err != nil {
+				return err
+			}
+			if len(addons) != 2 {
+				return fmt.Errorf("want 2 addons, but got %d", len(addons))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the stale managed cluster addons by the resync", func() {
+		sendBundle(generic.GenericBundle[addonv1alpha1.ManagedClusterAddOn]{
+			ResyncMetadata: []generic.ObjectMetadata{{Namespace: "cluster1", Name: "application-manager"}},
+		})
+		Eventually(func() error {
+			addons, err := listAddons()
+			if err != nil {
+				return err
+			}
+			if len(addons) != 1 || addons[0].ClusterName != "cluster1" {
+				return fmt.Errorf("want the addon of cluster1, but got %v", addons)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
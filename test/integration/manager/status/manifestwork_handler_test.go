@@ -0,0 +1,134 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "ManifestWorkHandler"
+var _ = Describe("ManifestWorkHandler", Ordered, func() {
+	const leafHubName = "hub1"
+	version := eventversion.NewVersion()
+
+	newWork := func(cluster, name string, available metav1.ConditionStatus) workv1.ManifestWork {
+		return workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster,
+				Name:      name,
+				UID:       types.UID(cluster + "-" + name),
+				Labels: map[string]string{
+					"work.open-cluster-management.io/manifestworkreplicaset": "default." + name,
+					"work.open-cluster-management.io/placementname":          "placement1",
+				},
+			},
+			Status: workv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{
+					{Type: workv1.WorkApplied, Status: metav1.ConditionTrue},
+					{Type: workv1.WorkAvailable, Status: available},
+				},
+				ResourceStatus: workv1.ManifestResourceStatus{
+					Manifests: []workv1.ManifestCondition{{
+						ResourceMeta: workv1.ManifestResourceMeta{Kind: "Deployment", Namespace: "default", Name: name},
+					}},
+				},
+			},
+		}
+	}
+
+	sendBundle := func(bundle generic.GenericBundle[workv1.ManifestWork]) {
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(enum.ManifestWorkType), version, bundle)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	listWorks := func() ([]models.ManifestWork, error) {
+		works := []models.ManifestWork{}
+		err := database.GetGorm().Where("leaf_hub_name = ?", leafHubName).Order("cluster_name, work_name").
+			Find(&works).Error
+		return works, err
+	}
+
+	It("should upsert the manifestworks", func() {
+		sendBundle(generic.GenericBundle[workv1.ManifestWork]{
+			Create: []workv1.ManifestWork{
+				newWork("cluster1", "nginx", metav1.ConditionTrue),
+				newWork("cluster2", "nginx", metav1.ConditionTrue),
+				newWork("cluster2", "redis", metav1.ConditionTrue),
+			},
+		})
+		Eventually(func() error {
+			works, err := listWorks()
+			if err != nil {
+				return err
+			}
+			if len(works) != 3 {
+				return fmt.Errorf("want 3 manifestworks, but got %d", len(works))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		sendBundle(generic.GenericBundle[workv1.ManifestWork]{
+			Update: []workv1.ManifestWork{
+				newWork("cluster2", "nginx", metav1.ConditionFalse),
+			},
+		})
+		Eventually(func() error {
+			unavailable := []models.ManifestWork{}
+			err := database.GetGorm().Where("placement_name = ? AND available = ?", "placement1", "False").
+				Find(&unavailable).Error
+			if err != nil {
+				return err
+			}
+			if len(unavailable) != 1 || unavailable[0].ClusterName != "cluster2" ||
+				unavailable[0].Applied != "True" || unavailable[0].PlacementNamespace != "default" {
+				return fmt.Errorf("want the unavailable manifestwork of cluster2, but got %v", unavailable)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the manifestworks", func() {
+		sendBundle(generic.GenericBundle[workv1.ManifestWork]{
+			Delete: []generic.ObjectMetadata{{Namespace: "cluster2", Name: "redis"}},
+		})
+		Eventually(func() error {
+			works, err := listWorks()
+			if err != nil {
+				return err
+			}
+			if len(works) != 2 {
+				return fmt.Errorf("want 2 manifestworks, but got %d", len(works))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("should delete the stale manifestworks by the resync", func() {
+		sendBundle(generic.GenericBundle[workv1.ManifestWork]{
+			ResyncMetadata: []generic.ObjectMetadata{{Namespace: "cluster1", Name: "nginx"}},
+		})
+		Eventually(func() error {
+			works, err := listWorks()
+			if err != nil {
+				return err
+			}
+			if len(works) != 1 || works[0].ClusterName != "cluster1" {
+				return fmt.Errorf("want the manifestwork of cluster1, but got %v", works)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})
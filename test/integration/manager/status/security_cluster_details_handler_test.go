@@ -0,0 +1,138 @@
+package status
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "SecurityClusterDetailsHandler"
+var _ = Describe("SecurityClusterDetailsHandler", Ordered, func() {
+	const (
+		leafHubName = "security-hub"
+		source      = "rhacs-operator/stackrox-central-services"
+	)
+	versions := map[enum.EventType]*eventversion.Version{}
+
+	send := func(eventType enum.EventType, data interface{}) {
+		version, ok := versions[eventType]
+		if !ok {
+			version = eventversion.NewVersion()
+			versions[eventType] = version
+		}
+		version.Incr()
+		evt := ToCloudEvent(leafHubName, string(eventType), version, data)
+		Expect(producer.SendEvent(ctx, *evt)).To(Succeed())
+		version.Next()
+	}
+
+	It("Should sync the alert counts of the secured clusters and delete the removed clusters", func() {
+		send(enum.SecurityClusterAlertCountsType, &wiremodels.SecurityClusterAlertCounts{
+			Clusters: []wiremodels.ClusterAlertCounts{
+				{Cluster: "cluster1", Low: 1, Critical: 2},
+				{Cluster: "cluster2", High: 5},
+			},
+			DetailURL: "https://central/main/violations",
+			Source:    source,
+		})
+		Eventually(func() error {
+			counts := []models.SecurityClusterAlertCounts{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Order("cluster_name").Find(&counts).Error
+			if err != nil {
+				return err
+			}
+			if len(counts) != 2 || counts[0].Critical != 2 || counts[1].High != 5 {
+				return fmt.Errorf("unexpected cluster alert counts: %v", counts)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		send(enum.SecurityClusterAlertCountsType, &wiremodels.SecurityClusterAlertCounts{
+			Clusters:  []wiremodels.ClusterAlertCounts{{Cluster: "cluster1", Low: 3}},
+			DetailURL: "https://central/main/violations",
+			Source:    source,
+		})
+		Eventually(func() error {
+			counts := []models.SecurityClusterAlertCounts{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Find(&counts).Error
+			if err != nil {
+				return err
+			}
+			if len(counts) != 1 || counts[0].ClusterName != "cluster1" || counts[0].Low != 3 ||
+				counts[0].Critical != 0 {
+				return fmt.Errorf("want the updated cluster1, but got %v", counts)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("Should sync the most violated policies of the secured clusters", func() {
+		send(enum.SecurityPolicyViolationsType, &wiremodels.SecurityPolicyViolations{
+			Violations: []wiremodels.PolicyViolation{
+				{Cluster: "cluster1", PolicyID: "policy1", PolicyName: "Privileged Container", Severity: "medium", Count: 2},
+				{
+					Cluster: "cluster1", PolicyID: "policy2", PolicyName: "Fixable CVSS >= 7", Severity: "high",
+					Categories: []string{"Vulnerability Management"}, Count: 1,
+				},
+			},
+			Source: source,
+		})
+		Eventually(func() error {
+			violations := []models.SecurityPolicyViolation{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Order("count DESC").Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != 2 || violations[0].PolicyID != "policy1" || violations[1].Severity != "high" {
+				return fmt.Errorf("unexpected policy violations: %v", violations)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		send(enum.SecurityPolicyViolationsType, &wiremodels.SecurityPolicyViolations{
+			Violations: []wiremodels.PolicyViolation{
+				{Cluster: "cluster1", PolicyID: "policy2", PolicyName: "Fixable CVSS >= 7", Severity: "high", Count: 3},
+			},
+			Source: source,
+		})
+		Eventually(func() error {
+			violations := []models.SecurityPolicyViolation{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != 1 || violations[0].PolicyID != "policy2" || violations[0].Count != 3 {
+				return fmt.Errorf("want the policy2 only, but got %v", violations)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+
+	It("Should sync the image vulnerabilities of the secured clusters", func() {
+		send(enum.SecurityImageVulnerabilitiesType, &wiremodels.SecurityImageVulnerabilities{
+			Clusters: []wiremodels.ClusterImageVulnerabilities{
+				{Cluster: "cluster1", ClusterID: "id1", Low: 1, Moderate: 2, Important: 3, Critical: 4, Fixable: 6},
+			},
+			Source: source,
+		})
+		Eventually(func() error {
+			vulnerabilities := []models.SecurityImageVulnerabilities{}
+			err := database.GetGorm().Where("hub_name = ?", leafHubName).Find(&vulnerabilities).Error
+			if err != nil {
+				return err
+			}
+			if len(vulnerabilities) != 1 || vulnerabilities[0].Critical != 4 || vulnerabilities[0].Fixable != 6 {
+				return fmt.Errorf("unexpected image vulnerabilities: %v", vulnerabilities)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+	})
+})